@@ -70,6 +70,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "email is not verified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Failed to generate token",
                         "schema": {
@@ -79,9 +85,131 @@ const docTemplate = `{
                 }
             }
         },
+        "/auth/password-reset": {
+            "post": {
+                "description": "Sends a single-use reset token to the email if an account exists. The response is the same either way.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Request a password reset",
+                "parameters": [
+                    {
+                        "description": "Account email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.passwordResetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to request password reset",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password-reset/confirm": {
+            "get": {
+                "description": "HTML form opened from the link in the reset email. It submits the token and the new password to the same address as a regular form post.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Password reset page",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Reset token from the email",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "HTML form",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "token is required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Sets a new password using the token from the reset email. The token works only once. All previously issued access tokens are revoked. Accepts JSON or, from the reset page, a form post (then the answer is an HTML page).",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Confirm a password reset",
+                "parameters": [
+                    {
+                        "description": "Reset token and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.passwordResetConfirmRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid request body or token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to reset password",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/auth/register": {
             "post": {
-                "description": "Creates a new user account",
+                "description": "Creates a new user account and sends an email verification link. The password must be 8-72 characters; the email is trimmed and lower-cased. The response is the same whether or not the email is already taken, so it can't be used to check who has an account: the owner of a taken address gets an email instead. With guest_token the guest's answer and ballots are moved to the new account (or later via POST /me/guest-claim).",
                 "consumes": [
                     "application/json"
                 ],
@@ -99,7 +227,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/http.authRequest"
+                            "$ref": "#/definitions/http.registerRequest"
                         }
                     }
                 ],
@@ -108,7 +236,9 @@ const docTemplate = `{
                         "description": "Created",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -126,41 +256,53 @@ const docTemplate = `{
                 }
             }
         },
-        "/movies": {
+        "/auth/verify": {
             "get": {
-                "description": "Retrieves a list of all movies. This endpoint is public.",
+                "description": "Confirms the email address using the signed link from the verification email",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "movies"
+                    "auth"
+                ],
+                "summary": "Verify email address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Signed verification token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all movies",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/ports.Movie"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
+                    "400": {
+                        "description": "verification link is invalid or expired",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
-                        "description": "Failed to get movies",
+                        "description": "Failed to verify email",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/verify/resend": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Adds a new movie to the database. Requires authentication.",
+                "description": "Sends a new verification link if the account exists and is not verified yet. The response is the same either way.",
                 "consumes": [
                     "application/json"
                 ],
@@ -168,27 +310,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "movies"
+                    "auth"
                 ],
-                "summary": "Create a new movie",
+                "summary": "Resend the verification email",
                 "parameters": [
                     {
-                        "description": "Movie data to create",
-                        "name": "movie",
+                        "description": "Account email",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/http.SwaggerMovieRequest"
+                            "$ref": "#/definitions/http.resendVerificationRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "integer"
+                                "type": "string"
                             }
                         }
                     },
@@ -198,14 +340,49 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "500": {
+                        "description": "Failed to send verification email",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/calendar/{token}.ics": {
+            "get": {
+                "description": "iCalendar feed behind a secret link, for calendar apps that cannot send a token. Contains upcoming events the link owner hosts or has answered going or maybe to, including cancelled ones with STATUS:CANCELLED.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Calendar subscription feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Failed to create movie",
+                        "description": "Failed to build calendar feed",
                         "schema": {
                             "type": "string"
                         }
@@ -213,84 +390,102 @@ const docTemplate = `{
                 }
             }
         },
-        "/movies/{id}": {
+        "/events": {
             "get": {
-                "description": "Retrieves movie details for a given ID. This endpoint is public.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Events the current user hosts or is invited to, ordered by start time. By default starts from today. Recurring events are expanded into occurrences (with occurrence_id); without to, occurrences are listed one year ahead. Requires authentication.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "movies"
+                    "events"
                 ],
-                "summary": "Get a movie by ID",
+                "summary": "List my movie nights",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Movie ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "First day, inclusive (2006-01-02)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, inclusive (2006-01-02)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service.FinalMovieData"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ports.Event"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid movie ID",
+                        "description": "Invalid date range",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "the requested resource was not found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get events",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates an existing movie's details. Requires authentication.",
+                "description": "Creates an event hosted by the current user. Without timezone the host's profile timezone is used. Capacity counts the host too; 0 means unlimited. recurrence makes it a series: an RFC 5545 RRULE with FREQ=DAILY, WEEKLY or MONTHLY, INTERVAL, BYDAY, COUNT or UNTIL; starts_at must be its first occurrence. Invitations and answers apply to the whole series. Invited users get a notification. Requires authentication.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "movies"
+                    "events"
                 ],
-                "summary": "Update a movie",
+                "summary": "Create a movie night",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Movie ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Movie data to update",
-                        "name": "movie",
+                        "description": "Event",
+                        "name": "event",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/http.SwaggerMovieRequest"
+                            "$ref": "#/definitions/http.createEventRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Event"
+                        }
                     },
                     "400": {
-                        "description": "Invalid movie ID or request body",
+                        "description": "Invalid request body",
                         "schema": {
                             "type": "string"
                         }
@@ -301,40 +496,54 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
-                        "description": "Failed to update movie",
+                        "description": "Failed to create event",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/events/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes a movie from the database. Requires authentication.",
+                "description": "Returns the event with its invitations. Only the host and invited users can see it. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "movies"
+                    "events"
                 ],
-                "summary": "Delete a movie",
+                "summary": "Get a movie night",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Movie ID",
+                        "description": "Event ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Event"
+                        }
                     },
                     "400": {
-                        "description": "Invalid movie ID",
+                        "description": "Invalid event ID",
                         "schema": {
                             "type": "string"
                         }
@@ -345,20 +554,8859 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Failed to delete movie",
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get event",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marks the event as cancelled. It stays visible to invited users with status cancelled. Only the host can cancel. Requires authentication.",
+                "tags": [
+                    "events"
+                ],
+                "summary": "Cancel a movie night",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid event ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the event is already cancelled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to cancel event",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes only the passed fields. movie_id 0 removes the chosen movie. Raising the capacity moves people from the waitlist; it cannot go below the number of people going. For a series this changes all occurrences; an empty recurrence turns it into a single event. Moving a series to another weekday shifts BYDAY along, and drops changes made to single occurrences. Only the host can edit; cancelled events cannot be changed. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Update a movie night",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.updateEventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "a cancelled event cannot be changed or capacity is too low",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update event",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}.ics": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "RFC 5545 calendar file for one event, with a VTIMEZONE for the event's timezone. The end time is the start plus the chosen movie's runtime (2 hours without a movie). Cancelled events have STATUS:CANCELLED and a higher SEQUENCE, so re-importing updates the calendar entry. Requires authentication.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Download a movie night as .ics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid event ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to export event",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/guest-links": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Signed link for people without an account, valid for this event only. By default it expires when the event starts and it can't outlive the start. The token isn't stored, so the link is shown only in this response. Only the host can do this. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guests"
+                ],
+                "summary": "Create a guest invite link",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Expiry",
+                        "name": "link",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/http.createGuestLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ports.GuestLink"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the event is cancelled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create guest link",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "All guest links issued for the event stop working. Guests who already joined stay. Only the host can do this. Requires authentication.",
+                "tags": [
+                    "guests"
+                ],
+                "summary": "Revoke guest invite links",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid event ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to revoke guest links",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/guests/{guestID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a guest from the event. If they were going, the first person on the waitlist gets their spot. Only the host can do this. Requires authentication.",
+                "tags": [
+                    "guests"
+                ],
+                "summary": "Remove a guest",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Guest ID",
+                        "name": "guestID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid event or guest ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to remove guest",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/invitations": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Invites users and notifies them. Users who are already invited are skipped. Only the host can invite. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Invite users to a movie night",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Users to invite",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.inviteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "a cancelled event cannot be changed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to invite users",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/invitations/{userID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a user from the event's invitations. If they were going, the first person on the waitlist gets their spot. Only the host can do this. Requires authentication.",
+                "tags": [
+                    "events"
+                ],
+                "summary": "Withdraw an invitation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Invited user ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid event or user ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to remove invitation",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/occurrences": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Occurrences of the series within the date range, with changes to single occurrences applied; cancelled ones have status cancelled. By default from today and one year ahead. A single event is returned as is if it falls into the range. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "List occurrences of a recurring movie night",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day, inclusive (2006-01-02)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, inclusive (2006-01-02)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ports.Event"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date range",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get occurrences",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/occurrences/{occurrenceID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "scope=this (default) cancels only this occurrence. scope=following ends the series before it; for the first occurrence that cancels the whole series. Only the host can cancel. Requires authentication.",
+                "tags": [
+                    "events"
+                ],
+                "summary": "Cancel an occurrence of a recurring movie night",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Occurrence ID (original start in UTC, 20261023T150000Z)",
+                        "name": "occurrenceID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "this",
+                            "following"
+                        ],
+                        "type": "string",
+                        "description": "this or following",
+                        "name": "scope",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid event ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the occurrence is already cancelled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to cancel occurrence",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "scope=this (default) changes only this occurrence: title, description, starts_at, location and movie_id. scope=following ends the series before this occurrence and starts a new series from it with the changes applied (capacity, timezone and recurrence can be changed too); invitations and answers are copied, guests and polls stay with the old series. Returns the changed occurrence or the new series. Only the host can edit. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Update an occurrence of a recurring movie night",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Occurrence ID (original start in UTC, 20261023T150000Z)",
+                        "name": "occurrenceID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "this",
+                            "following"
+                        ],
+                        "type": "string",
+                        "description": "this or following",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.updateEventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the occurrence has already started",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update occurrence",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/polls": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Polls of the event with candidates and the caller's own ballot. Visible to the host and invited users. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "List polls of a movie night",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ports.Poll"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid event ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get polls",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a poll over candidate movies. Without opens_at the poll opens now; without closes_at it closes when the event starts. Only the host can create polls. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "Create a poll for a movie night",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Poll",
+                        "name": "poll",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.createPollRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Poll"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the event is cancelled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create poll",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/rsvp": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets the current user's answer. When the event is full, going puts the user on a FIFO waitlist instead (rsvp becomes waitlisted). Giving up a spot moves the first waitlisted user in and notifies them. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Respond to an invitation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Answer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.rsvpRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the event is cancelled or has already started",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to save answer",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/slots": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Proposed times for the event, best first: slots where every must-attend participant can come, then by how many participants can come (available + if_need_be), then by how many marked available, then earlier first. Participants are the host and all invitees who have not declined. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Rank proposed time slots",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated user IDs who must be able to attend",
+                        "name": "must_attend",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.SlotRanking"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid must_attend",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get time slots",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The host proposes candidate times: explicit slots, a date range, or both. A range produces, for every day from..to in the event's timezone, slots of duration_minutes starting at day_start every step_minutes while they fit before day_end (without day_end, one slot per day). duration_minutes defaults to the movie's runtime. Already proposed slots are skipped. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Propose time slots",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Slots and/or range",
+                        "name": "slots",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.proposeSlotsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/service.SlotRanking"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "a cancelled event cannot be changed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to propose time slots",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/slots/availability": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The current user marks proposed slots as available, if_need_be or unavailable. Slots not mentioned keep their previous answer. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Mark availability",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Answers per slot",
+                        "name": "answers",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.availabilityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.SlotRanking"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the event is cancelled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to save availability",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/slots/{slotID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the slot together with everyone's answers for it. Only the host can do this. Requires authentication.",
+                "tags": [
+                    "events"
+                ],
+                "summary": "Remove a proposed time slot",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Time slot ID",
+                        "name": "slotID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid event or slot ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete time slot",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/slots/{slotID}/choose": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves the event's start to the chosen slot. Only the host can do this. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Use a time slot as the event's start time",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Time slot ID",
+                        "name": "slotID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "starts_at must be in the future",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "a cancelled event cannot be changed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update event",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/stream": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events stream for one event: ` + "`" + `event.updated` + "`" + ` and ` + "`" + `event.cancelled` + "`" + ` (the event, including a newly chosen movie or time), ` + "`" + `rsvp` + "`" + ` (who answered and the new counts), and ` + "`" + `poll.created` + "`" + `, ` + "`" + `vote` + "`" + ` (who voted and the intermediate tally) and ` + "`" + `poll.closed` + "`" + ` for the event's polls. Every message has an id; after a reconnect the browser sends it in ` + "`" + `Last-Event-ID` + "`" + ` (or pass ` + "`" + `last_event_id` + "`" + `) and missed messages are replayed. If they are no longer in the history, a ` + "`" + `reset` + "`" + ` message tells the client to reload the state. Browsers can't set headers on EventSource, so the token may be passed in ` + "`" + `access_token` + "`" + `.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Live updates of a movie night",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT, instead of the Authorization header",
+                        "name": "access_token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Resume after this message id, instead of the Last-Event-ID header",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stream of server-sent events",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid event ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to open the stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Groups the current user belongs to, with their role in each. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "List my groups",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ports.Group"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get groups",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a group (household, friend circle) owned by the current user, with an invite link enabled. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Create a group",
+                "parameters": [
+                    {
+                        "description": "Group",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.createGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Group"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create group",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/join": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds the current user to the group as a member. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Join a group by invite link",
+                "parameters": [
+                    {
+                        "description": "Invite token",
+                        "name": "invite",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.joinGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Group"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "already a member of this group",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to join group",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the group with its members. The invite token is shown to owners and admins only. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get a group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Group"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid group ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get group",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes the group with its watchlist and polls. Only the owner can do this. Requires authentication.",
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Delete a group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid group ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete group",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the name and/or description. Only the owner and admins can do this. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Update a group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.updateGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Group"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update group",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/invite-link": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generates a new invite token; the previous link stops working. Only the owner and admins can do this. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Create a new invite link",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Group"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid group ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update invite link",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Nobody can join by link until a new one is created. Only the owner and admins can do this. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Disable the invite link",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Group"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid group ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update invite link",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/members/{userID}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Only the owner can change roles. Setting role owner transfers ownership; the previous owner becomes an admin. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Change a member's role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Member user ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.setGroupRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Group"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to change role",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The owner can remove anyone, admins can remove regular members. Removing yourself leaves the group; the owner has to transfer ownership first unless they are the last member, in which case the group is deleted. Requires authentication.",
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Remove a member or leave the group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Member user ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid group or user ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "transfer ownership before leaving the group",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to remove member",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/polls": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Polls of the group with candidates and the caller's own ballot. Visible to group members. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "List polls of a group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ports.Poll"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid group ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get polls",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a poll over candidate movies for the group. Any member can create one; closes_at is required. Without opens_at the poll opens now. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "Create a poll in a group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Poll",
+                        "name": "poll",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.createPollRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Poll"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create poll",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/watchlist": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Shared watchlist ordered by upvotes, then by when the title was added. seen_by lists members who have the movie in their diary; unseen=true keeps only titles no member has seen yet. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get the group watchlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only titles nobody in the group has seen",
+                        "name": "unseen",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ports.GroupWatchlistItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid group ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get group watchlist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Any member can add a title; it starts with the adder's upvote. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Add a movie to the group watchlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Movie and note",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.addGroupWatchlistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ports.GroupWatchlistItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the movie is already in the group watchlist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to add to group watchlist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/watchlist/{movieID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The member who added the title, the owner and admins can remove it. Requires authentication.",
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Remove a movie from the group watchlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "movieID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid group or movie ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to remove from group watchlist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/watchlist/{movieID}/vote": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "One upvote per member; repeating it changes nothing. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Upvote a title in the group watchlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "movieID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.GroupWatchlistItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid group or movie ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to save vote",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the current user's upvote from the title. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Withdraw an upvote",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "movieID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.GroupWatchlistItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid group or movie ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to remove vote",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest/event": {
+            "get": {
+                "security": [
+                    {
+                        "GuestToken": []
+                    }
+                ],
+                "description": "The guest and their event with attendance counts. Requires a guest token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guests"
+                ],
+                "summary": "The guest's event",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.GuestView"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get event",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest/invites/{token}": {
+            "get": {
+                "description": "The event behind a guest invite link (without the list of invitees), so the guest can see what they are joining. No authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guests"
+                ],
+                "summary": "Preview a guest invite",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invite token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "invite link is invalid or expired",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the event is cancelled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get invite",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest/join": {
+            "post": {
+                "description": "Joins the event from a guest invite link under a display name. Returns a guest token for the X-Guest-Token header; it is shown only once. The token works only for this event's guest endpoints. No account needed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guests"
+                ],
+                "summary": "Join an event as a guest",
+                "parameters": [
+                    {
+                        "description": "Invite token and display name",
+                        "name": "guest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.joinAsGuestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/service.GuestSession"
+                        }
+                    },
+                    "400": {
+                        "description": "invite link is invalid or expired",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the event is cancelled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to join the event",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest/polls": {
+            "get": {
+                "security": [
+                    {
+                        "GuestToken": []
+                    }
+                ],
+                "description": "With the guest's own ballot in my_ballot. Requires a guest token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guests"
+                ],
+                "summary": "Polls of the guest's event",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ports.Poll"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get polls",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest/polls/{id}/ballot": {
+            "put": {
+                "security": [
+                    {
+                        "GuestToken": []
+                    }
+                ],
+                "description": "Creates or replaces the guest's ballot while the poll is open. Same ballot rules as for users. Only polls of the guest's event. Requires a guest token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guests"
+                ],
+                "summary": "Vote as a guest",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Poll ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Chosen movies",
+                        "name": "ballot",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.ballotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Poll"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the poll is not open for voting",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to save ballot",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest/polls/{id}/results": {
+            "get": {
+                "security": [
+                    {
+                        "GuestToken": []
+                    }
+                ],
+                "description": "Same tally as GET /polls/{id}/results. Only polls of the guest's event. Requires a guest token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guests"
+                ],
+                "summary": "Poll results for a guest",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Poll ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.PollResults"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid poll ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get results",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest/rsvp": {
+            "put": {
+                "security": [
+                    {
+                        "GuestToken": []
+                    }
+                ],
+                "description": "Same rules as for invited users: guests share seats and the FIFO waitlist with them. Requires a guest token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guests"
+                ],
+                "summary": "Respond as a guest",
+                "parameters": [
+                    {
+                        "description": "Answer",
+                        "name": "rsvp",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.rsvpRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.GuestView"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the event has already started",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to save answer",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/lists": {
+            "get": {
+                "description": "Returns the public lists of the given user. The owner also sees private and unlisted lists. Without the user parameter returns the caller's own lists.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Get a user's lists",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Owner user ID",
+                        "name": "user",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ports.MovieList"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get lists",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a list owned by the current user. Visibility defaults to private. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Create a list",
+                "parameters": [
+                    {
+                        "description": "List data",
+                        "name": "list",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.createListRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ports.MovieList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create list",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/lists/popular": {
+            "get": {
+                "description": "Public lists ordered by how often they were cloned, then by last update",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Get popular lists",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of lists (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ports.MovieList"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get popular lists",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/lists/shared/{token}": {
+            "get": {
+                "description": "Opens an unlisted or public list by its share token",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Get a list by share link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.MovieList"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get list",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/lists/{id}": {
+            "get": {
+                "description": "Returns a list with its movies in order. Private and unlisted lists are only visible to the owner here; use the share link for unlisted lists.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Get a list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "List ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.MovieList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid list ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get list",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a list owned by the current user. Requires authentication.",
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Delete a list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "List ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid list ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete list",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes title, description and/or visibility. Only the owner can do this. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Update a list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "List ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "list",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.updateListRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.MovieList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid list ID or request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update list",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/lists/{id}/clone": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Copies a list with all its movies and notes into a new private list of the current user. Unlisted lists need their share token. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Clone a list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "List ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Share token for unlisted lists",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/http.cloneListRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ports.MovieList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid list ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to clone list",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/lists/{id}/entries": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Appends a movie to the end of the list with an optional note. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Add a movie to a list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "List ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Movie and note",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.addListEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ports.MovieList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid list ID or request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the resource already exists",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to add movie to list",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/lists/{id}/entries/{movieID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the movie; the movies after it move up one position. Requires authentication.",
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Remove a movie from a list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "List ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "movieID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to remove movie from list",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the note and/or moves the movie to a new position (1-based). Other movies keep their relative order. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Update a list entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "List ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "movieID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note and/or position",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.updateListEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.MovieList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update list entry",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/lists/{id}/share": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sends the user a notification with a link to the list. The list must be unlisted or public. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Share a list with a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "List ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Recipient",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.shareListRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid list ID or request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to share list",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the profile of the authenticated user. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get my profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get profile",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Schedules the account for deletion after a grace period. Until then the deletion can be cancelled with POST /me/restore. After it the account and all its data are removed and issued tokens are revoked. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete my account",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/http.deletionResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete account",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes display name, timezone, locale and/or avatar URL. Omitted fields stay unchanged. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update my profile",
+                "parameters": [
+                    {
+                        "description": "Fields to change",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.updateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update profile",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/calendar-feed": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a secret URL to subscribe to in a calendar app. The feed lists upcoming events the user hosts or has answered going or maybe to. Creating a new link disables the previous one; the link is shown only once. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Create a calendar subscription link",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ports.CalendarFeed"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create calendar feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The subscribed calendar stops receiving updates. Requires authentication.",
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Disable the calendar subscription link",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete calendar feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/diary": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the current user's diary entries in the date range, oldest first. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "diary"
+                ],
+                "summary": "List my diary entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day, inclusive (2006-01-02)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, inclusive (2006-01-02)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ports.DiaryEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date range",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get diary",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a diary entry. If rewatch is omitted it is set automatically when the movie is already in the diary. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "diary"
+                ],
+                "summary": "Log a watched movie",
+                "parameters": [
+                    {
+                        "description": "Diary entry",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.diaryEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ports.DiaryEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create diary entry",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/diary/calendar/{year}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the current user's diary entries for a year grouped by month, with totals. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "diary"
+                ],
+                "summary": "Diary overview for a year",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Year",
+                        "name": "year",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.DiaryYear"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid year",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get diary",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/diary/calendar/{year}/{month}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the current user's diary entries for a month grouped by day. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "diary"
+                ],
+                "summary": "Diary calendar for a month",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Year",
+                        "name": "year",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Month (1-12)",
+                        "name": "month",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.DiaryMonth"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid year or month",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get diary",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/diary/watch-counts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns how many times the current user watched each movie in the diary, most watched first. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "diary"
+                ],
+                "summary": "Per-movie watch counts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ports.MovieWatchCount"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get watch counts",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/diary/{entryID}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns one of the current user's diary entries. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "diary"
+                ],
+                "summary": "Get a diary entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Diary entry ID",
+                        "name": "entryID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.DiaryEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid entry ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get diary entry",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces a diary entry. If rewatch is omitted the previous value is kept. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "diary"
+                ],
+                "summary": "Update a diary entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Diary entry ID",
+                        "name": "entryID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Diary entry",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.diaryEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.DiaryEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid entry ID or request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update diary entry",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes one of the current user's diary entries. Requires authentication.",
+                "tags": [
+                    "diary"
+                ],
+                "summary": "Delete a diary entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Diary entry ID",
+                        "name": "entryID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid entry ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete diary entry",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a ZIP archive with JSON files of the profile, ratings and reviews, watchlist, diary, follows and lists. Requires authentication.",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Export my data",
+                "responses": {
+                    "200": {
+                        "description": "ZIP archive",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to export data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/feed": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ratings, reviews and diary entries of the users I follow, newest first. Pass next_cursor from the previous page as cursor to get the next one. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Get my activity feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.FeedPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid cursor or limit",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/guest-claim": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves a guest's answer and ballots to the current user, who becomes an invitee of the event; the guest is removed. The user's own answer and ballots win over the guest's. The same can be done at registration with guest_token. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guests"
+                ],
+                "summary": "Claim guest activity",
+                "parameters": [
+                    {
+                        "description": "Guest token",
+                        "name": "claim",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.claimGuestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "guest token is invalid",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the host can't claim a guest of their own event",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to claim guest activity",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Notifications newest first with the total unread count. Pass next_cursor as cursor for the next page. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Get my notifications",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only unread notifications",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.NotificationPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid cursor or limit",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get notifications",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/notifications/preferences": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delivery settings (in-app and email) for every notification type. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Get my notification settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ports.NotificationPreference"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get notification settings",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes delivery settings for the listed notification types; other types stay unchanged. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Update my notification settings",
+                "parameters": [
+                    {
+                        "description": "Settings per type",
+                        "name": "preferences",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ports.NotificationPreference"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ports.NotificationPreference"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update notification settings",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/notifications/read": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marks the given notifications as read, or all of them with \"all\": true. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark notifications as read",
+                "parameters": [
+                    {
+                        "description": "Notification IDs or all",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.markReadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to mark notifications as read",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/password": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets a new password (8-72 characters). The current password is required. All previously issued tokens are revoked and a new token is returned. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Change my password",
+                "parameters": [
+                    {
+                        "description": "Current and new password",
+                        "name": "passwords",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.changePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "current password is incorrect",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to change password",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/preferences": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the filters applied to movie lists, recommendations and the planner for the current user. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "preferences"
+                ],
+                "summary": "Get my viewing preferences",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.ViewingPreferences"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get preferences",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces all viewing preferences. Empty values mean no restriction. Movies with unknown runtime or language are not filtered by those fields. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "preferences"
+                ],
+                "summary": "Replace my viewing preferences",
+                "parameters": [
+                    {
+                        "description": "Viewing preferences",
+                        "name": "preferences",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ports.ViewingPreferences"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.ViewingPreferences"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update preferences",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/preferences/hidden-movies": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds the movie to the hidden list so it no longer appears in lists, recommendations and the planner. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "preferences"
+                ],
+                "summary": "Never show a movie again",
+                "parameters": [
+                    {
+                        "description": "Movie to hide",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.hideMovieRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.ViewingPreferences"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to hide movie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/preferences/hidden-movies/{movieID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the movie from the hidden list. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "preferences"
+                ],
+                "summary": "Show a hidden movie again",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "movieID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.ViewingPreferences"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid movie ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to unhide movie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/recommendations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns \"people who liked this also liked\" recommendations based on the user's ratings. Users with too few ratings get popular movies instead. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Get personal recommendations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Max number of movies (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service.RecommendedMovie"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get recommendations",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancels a pending account deletion during the grace period. Requires authentication.",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Cancel account deletion",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to cancel account deletion",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/watchlist": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the current user's watchlist. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Get my watchlist",
+                "parameters": [
+                    {
+                        "enum": [
+                            "added",
+                            "priority",
+                            "rating"
+                        ],
+                        "type": "string",
+                        "description": "Sort field",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order (default desc)",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ports.WatchlistItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid sort or order",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get watchlist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Saves a movie to the current user's watchlist with an optional priority (1-5, default 3) and notes. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Add a movie to my watchlist",
+                "parameters": [
+                    {
+                        "description": "Movie, priority and notes",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.addToWatchlistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ports.WatchlistItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the resource already exists",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to add to watchlist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/watchlist/{movieID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a movie from the current user's watchlist. Requires authentication.",
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Remove a movie from my watchlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "movieID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid movie ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to remove from watchlist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes priority and/or notes of a movie in the current user's watchlist. Omitted fields stay unchanged. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Update a watchlist entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "movieID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.updateWatchlistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.WatchlistItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid movie ID or request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update watchlist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies": {
+            "get": {
+                "description": "Retrieves a list of all movies, optionally filtered by title. This endpoint is public; with a Bearer token the caller's viewing preferences are applied and each movie also contains in_watchlist.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Get all movies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Case-insensitive title search",
+                        "name": "q",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service.MovieListItem"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get movies",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a new movie to the database. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Create a new movie",
+                "parameters": [
+                    {
+                        "description": "Movie data to create",
+                        "name": "movie",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.SwaggerMovieRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create movie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}": {
+            "get": {
+                "description": "Retrieves movie details for a given ID. This endpoint is public; with a Bearer token the response also contains in_watchlist.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Get a movie by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.FinalMovieData"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid movie ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates an existing movie's details. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Update a movie",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Movie data to update",
+                        "name": "movie",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.SwaggerMovieRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid movie ID or request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update movie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a movie from the database. Requires authentication.",
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Delete a movie",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid movie ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the movie is a candidate in a poll",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete movie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}/ratings": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the current user's rating and review for the movie. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ratings"
+                ],
+                "summary": "Update a movie rating",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rating and optional review",
+                        "name": "rating",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.ratingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Rating"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid movie ID or request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update rating",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds the current user's rating (0.5-10, step 0.5) with an optional review. One rating per user and movie. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ratings"
+                ],
+                "summary": "Rate a movie",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rating and optional review",
+                        "name": "rating",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.ratingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Rating"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid movie ID or request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the resource already exists",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to rate movie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the current user's rating and review for the movie. Requires authentication.",
+                "tags": [
+                    "ratings"
+                ],
+                "summary": "Delete a movie rating",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid movie ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete rating",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}/reviews": {
+            "get": {
+                "description": "Returns user reviews for the movie, newest first. This endpoint is public.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ratings"
+                ],
+                "summary": "Get movie reviews",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.ReviewPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid movie ID or pagination",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get reviews",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}/similar": {
+            "get": {
+                "description": "Returns movies liked by the same people who liked this movie, most similar first. Built from the rating similarity matrix, so movies with few ratings may have no similar movies yet. This endpoint is public; with a Bearer token movies excluded by the viewer's preferences are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Get similar movies",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of movies (default 10, max 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service.SimilarMovie"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid movie ID or limit",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get similar movies",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/plan/marathon": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Builds a back-to-back schedule for several movies with short breaks between them and meal breaks at the configured interval. Without ` + "`" + `ordered` + "`" + ` the movies are watched by release date. If the movies don't fit before ` + "`" + `ends_by` + "`" + `, the plan keeps the set with the highest total rating (or watchlist priority with ` + "`" + `optimize=priority` + "`" + `) and lists the rest in ` + "`" + `dropped` + "`" + `. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "planner"
+                ],
+                "summary": "Marathon schedule",
+                "parameters": [
+                    {
+                        "description": "Movies, start time, breaks and optional end time",
+                        "name": "plan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.planMarathonRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.MarathonPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Movie not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to build a schedule",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/plan/tonight": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a ranked shortlist of movies that fit into the time window, with per-participant fit scores and an explanation. The caller must be one of the participants, and every other participant must follow the caller or share a group with them. Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "planner"
+                ],
+                "summary": "Tonight's pick for a group",
+                "parameters": [
+                    {
+                        "description": "Participants, time window and constraints",
+                        "name": "plan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.planTonightRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.TonightPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Participant is not connected to the caller",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to build a plan",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/polls/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the poll with candidates and the caller's own ballot. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "Get a poll",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Poll ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Poll"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid poll ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get poll",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/polls/{id}/ballot": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates or replaces the caller's ballot while the poll is open. Plurality takes exactly one movie, approval any number of acceptable movies, irv and borda a ranking from most to least preferred (partial rankings are allowed). Requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "Vote in a poll",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Poll ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Chosen movies",
+                        "name": "ballot",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.ballotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.Poll"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ballot",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the poll is not open for voting",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to save ballot",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/polls/{id}/close": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stops voting now and returns the final results. Allowed for the poll author, the event host, and group owners and admins. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "Close a poll early",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Poll ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.PollResults"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid poll ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "you are not allowed to perform this action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the poll is already closed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to close poll",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/polls/{id}/results": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deterministic tally of the current ballots; final once the poll is closed. For irv every round is listed with the eliminated movie. Ties: plurality and approval prefer the movie listed earlier in the poll; borda prefers more first places, then the earlier movie; in irv the movie eliminated on a tie is the one with fewer votes in the previous rounds (latest round first), then the one listed later in the poll. Requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "Get poll results",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Poll ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.PollResults"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid poll ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get poll results",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/polls/{id}/stream": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events stream for one poll: ` + "`" + `vote` + "`" + ` (who voted and the intermediate tally) and ` + "`" + `poll.closed` + "`" + ` (the final tally). Resuming with ` + "`" + `Last-Event-ID` + "`" + `, ` + "`" + `reset` + "`" + ` and ` + "`" + `access_token` + "`" + ` work as for event streams.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "Live updates of a poll",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Poll ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT, instead of the Authorization header",
+                        "name": "access_token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Resume after this message id, instead of the Last-Event-ID header",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stream of server-sent events",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid poll ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to open the stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/follow": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Subscribes the current user to another user's activity. Requires authentication.",
+                "tags": [
+                    "social"
+                ],
+                "summary": "Follow a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the resource already exists",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to follow user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the subscription to another user. Requires authentication.",
+                "tags": [
+                    "social"
+                ],
+                "summary": "Unfollow a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to unfollow user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/followers": {
+            "get": {
+                "description": "Returns the users who follow the given user, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "List followers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ports.Follow"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get followers",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/following": {
+            "get": {
+                "description": "Returns the users the given user follows, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "List followed users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ports.Follow"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the requested resource was not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get followed users",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "http.SwaggerMovieRequest": {
+            "type": "object",
+            "properties": {
+                "certification": {
+                    "type": "string",
+                    "example": "PG-13"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Action",
+                        "Sci-Fi"
+                    ]
+                },
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "overview": {
+                    "type": "string",
+                    "example": "A thief who steals corporate secrets..."
+                },
+                "poster_url": {
+                    "type": "string",
+                    "example": "https://image.tmdb.org/..."
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 8.8
+                },
+                "recommendations": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "The Matrix",
+                        "Shutter Island"
+                    ]
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2010-07-16"
+                },
+                "runtime": {
+                    "type": "integer",
+                    "example": 148
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Inception"
+                }
+            }
+        },
+        "http.addGroupWatchlistRequest": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Someone said it's better than Heat"
+                }
+            }
+        },
+        "http.addListEntryRequest": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "note": {
+                    "type": "string",
+                    "example": "The one that started it all"
+                }
+            }
+        },
+        "http.addToWatchlistRequest": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Watch with Aigerim"
+                },
+                "priority": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "http.authRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.availabilityRequest": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.slotAnswer"
+                    }
+                }
+            }
+        },
+        "http.ballotRequest": {
+            "type": "object",
+            "properties": {
+                "choices": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        12,
+                        7
+                    ]
+                }
+            }
+        },
+        "http.changePasswordRequest": {
+            "type": "object",
+            "properties": {
+                "current_password": {
+                    "type": "string",
+                    "example": "old-secret"
+                },
+                "new_password": {
+                    "type": "string",
+                    "example": "new-secret-123"
+                }
+            }
+        },
+        "http.claimGuestRequest": {
+            "type": "object",
+            "properties": {
+                "guest_token": {
+                    "type": "string",
+                    "example": "Qm9yZWQ..."
+                }
+            }
+        },
+        "http.cloneListRequest": {
+            "type": "object",
+            "properties": {
+                "share_token": {
+                    "type": "string",
+                    "example": "3q2-7wEAAAA"
+                }
+            }
+        },
+        "http.createEventRequest": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Bring snacks"
+                },
+                "invitee_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        2,
+                        3
+                    ]
+                },
+                "location": {
+                    "type": "string",
+                    "example": "Aigerim's place, Abay ave 10"
+                },
+                "movie_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "recurrence": {
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=FR"
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2026-10-23T20:00:00+05:00"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Almaty"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Friday heist night"
+                }
+            }
+        },
+        "http.createGroupRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Fridays after 19:00"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Office lounge crew"
+                }
+            }
+        },
+        "http.createGuestLinkRequest": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "пусто -\u003e до начала вечера",
+                    "type": "string",
+                    "example": "2026-10-23T18:00:00+05:00"
+                }
+            }
+        },
+        "http.createListRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Ocean's and friends"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Best heist films"
+                },
+                "visibility": {
+                    "enum": [
+                        "private",
+                        "unlisted",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ports.ListVisibility"
+                        }
+                    ],
+                    "example": "public"
+                }
+            }
+        },
+        "http.createPollRequest": {
+            "type": "object",
+            "properties": {
+                "closes_at": {
+                    "type": "string"
+                },
+                "method": {
+                    "enum": [
+                        "plurality",
+                        "approval",
+                        "irv",
+                        "borda"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ports.VotingMethod"
+                        }
+                    ],
+                    "example": "irv"
+                },
+                "movie_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        12,
+                        7,
+                        31
+                    ]
+                },
+                "opens_at": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "What do we watch on Friday?"
+                }
+            }
+        },
+        "http.deletionResponse": {
+            "type": "object",
+            "properties": {
+                "deletion_scheduled_at": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Account deletion scheduled"
+                }
+            }
+        },
+        "http.diaryEntryRequest": {
+            "type": "object",
+            "properties": {
+                "location": {
+                    "type": "string",
+                    "example": "Office lounge, Friday movie night"
+                },
+                "movie_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Popcorn ran out halfway"
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 8.5
+                },
+                "rewatch": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "watched_on": {
+                    "$ref": "#/definitions/ports.CustomDate"
+                }
+            }
+        },
+        "http.hideMovieRequest": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "http.inviteRequest": {
+            "type": "object",
+            "properties": {
+                "user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        2,
+                        3
+                    ]
+                }
+            }
+        },
+        "http.joinAsGuestRequest": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string",
+                    "example": "Dana (Aigerim's sister)"
+                },
+                "invite_token": {
+                    "type": "string",
+                    "example": "eyJwIjp7..."
+                }
+            }
+        },
+        "http.joinGroupRequest": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "3q2-7wEAAAA"
+                }
+            }
+        },
+        "http.markReadRequest": {
+            "type": "object",
+            "properties": {
+                "all": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        17,
+                        18
+                    ]
+                }
+            }
+        },
+        "http.passwordResetConfirmRequest": {
+            "type": "object",
+            "properties": {
+                "new_password": {
+                    "type": "string",
+                    "example": "new-secret-123"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "q1w2e3..."
+                }
+            }
+        },
+        "http.passwordResetRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "aigerim@example.com"
+                }
+            }
+        },
+        "http.planMarathonRequest": {
+            "type": "object",
+            "properties": {
+                "break_minutes": {
+                    "type": "integer",
+                    "example": 15
+                },
+                "ends_by": {
+                    "type": "string",
+                    "example": "2026-10-31T23:30:00+05:00"
+                },
+                "meal_every_minutes": {
+                    "description": "0 -\u003e без перерывов на еду",
+                    "type": "integer",
+                    "example": 300
+                },
+                "meal_minutes": {
+                    "type": "integer",
+                    "example": 45
+                },
+                "movie_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        12,
+                        7,
+                        31
+                    ]
+                },
+                "optimize": {
+                    "type": "string",
+                    "enum": [
+                        "rating",
+                        "priority"
+                    ],
+                    "example": "rating"
+                },
+                "ordered": {
+                    "description": "false -\u003e по дате выхода",
+                    "type": "boolean",
+                    "example": false
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2026-10-31T14:00:00+05:00"
+                }
+            }
+        },
+        "http.planTonightRequest": {
+            "type": "object",
+            "properties": {
+                "exclude_genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Horror"
+                    ]
+                },
+                "include_genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Comedy",
+                        "Drama"
+                    ]
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "min_rating": {
+                    "type": "number",
+                    "example": 7
+                },
+                "no_rewatches": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "participant_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
+                },
+                "window_end": {
+                    "type": "string",
+                    "example": "2026-10-30T21:30:00+05:00"
+                },
+                "window_start": {
+                    "type": "string",
+                    "example": "2026-10-30T19:00:00+05:00"
+                }
+            }
+        },
+        "http.proposeSlotsRequest": {
+            "type": "object",
+            "properties": {
+                "range": {
+                    "$ref": "#/definitions/http.slotRangeRequest"
+                },
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.slotRequest"
+                    }
+                }
+            }
+        },
+        "http.ratingRequest": {
+            "type": "object",
+            "properties": {
+                "rating": {
+                    "type": "number",
+                    "example": 8.5
+                },
+                "review": {
+                    "type": "string",
+                    "example": "Great soundtrack, a bit too long."
+                }
+            }
+        },
+        "http.registerRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "guest_token": {
+                    "description": "GuestToken -\u003e токен гостя, чью активность перенести в новый аккаунт",
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.resendVerificationRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "aigerim@example.com"
+                }
+            }
+        },
+        "http.rsvpRequest": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "enum": [
+                        "going",
+                        "maybe",
+                        "declined"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ports.RSVPStatus"
+                        }
+                    ],
+                    "example": "going"
+                }
+            }
+        },
+        "http.setGroupRoleRequest": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "enum": [
+                        "owner",
+                        "admin",
+                        "member"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ports.GroupRole"
+                        }
+                    ],
+                    "example": "admin"
+                }
+            }
+        },
+        "http.shareListRequest": {
+            "type": "object",
+            "properties": {
+                "user_id": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "http.slotAnswer": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "enum": [
+                        "available",
+                        "if_need_be",
+                        "unavailable"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ports.Availability"
+                        }
+                    ],
+                    "example": "available"
+                },
+                "slot_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "http.slotRangeRequest": {
+            "type": "object",
+            "properties": {
+                "day_end": {
+                    "type": "string",
+                    "example": "23:30"
+                },
+                "day_start": {
+                    "type": "string",
+                    "example": "19:00"
+                },
+                "duration_minutes": {
+                    "type": "integer",
+                    "example": 180
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2026-10-20"
+                },
+                "step_minutes": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2026-10-26"
+                }
+            }
+        },
+        "http.slotRequest": {
+            "type": "object",
+            "properties": {
+                "ends_at": {
+                    "type": "string",
+                    "example": "2026-10-23T23:00:00+05:00"
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2026-10-23T20:00:00+05:00"
+                }
+            }
+        },
+        "http.updateEventRequest": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Bring snacks"
+                },
+                "location": {
+                    "type": "string",
+                    "example": "Aigerim's place, Abay ave 10"
+                },
+                "movie_id": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "recurrence": {
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=FR;COUNT=10"
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2026-10-23T21:00:00+05:00"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Almaty"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Friday heist night"
+                }
+            }
+        },
+        "http.updateGroupRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Fridays after 19:00"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Office lounge crew"
+                }
+            }
+        },
+        "http.updateListEntryRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "example": "Watch this one first"
+                },
+                "position": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "http.updateListRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Scary but not too scary"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Halloween 2026 lineup"
+                },
+                "visibility": {
+                    "enum": [
+                        "private",
+                        "unlisted",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ports.ListVisibility"
+                        }
+                    ],
+                    "example": "unlisted"
+                }
+            }
+        },
+        "http.updateProfileRequest": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string",
+                    "example": "https://example.com/avatar.png"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "Aigerim"
+                },
+                "locale": {
+                    "type": "string",
+                    "example": "ru-KZ"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Almaty"
+                }
+            }
+        },
+        "http.updateWatchlistRequest": {
+            "type": "object",
+            "properties": {
+                "notes": {
+                    "type": "string",
+                    "example": "Before the sequel comes out"
+                },
+                "priority": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "ports.Activity": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "$ref": "#/definitions/ports.UserSummary"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1042
+                },
+                "movie_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "movie_title": {
+                    "type": "string",
+                    "example": "Inception"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "reviewed"
+                }
+            }
+        },
+        "ports.Availability": {
+            "type": "string",
+            "enum": [
+                "available",
+                "if_need_be",
+                "unavailable"
+            ],
+            "x-enum-comments": {
+                "AvailabilityIfNeedBe": "может, но неудобно"
+            },
+            "x-enum-descriptions": [
+                "",
+                "может, но неудобно",
+                ""
+            ],
+            "x-enum-varnames": [
+                "AvailabilityAvailable",
+                "AvailabilityIfNeedBe",
+                "AvailabilityUnavailable"
+            ]
+        },
+        "ports.CalendarFeed": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://movie-planner.example/calendar/3q2-7wEAAAA.ics"
+                }
+            }
+        },
+        "ports.CustomDate": {
+            "type": "object",
+            "properties": {
+                "time.Time": {
+                    "type": "string"
+                }
+            }
+        },
+        "ports.DiaryEntry": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "location": {
+                    "type": "string",
+                    "example": "Office lounge, Friday movie night"
+                },
+                "movie": {
+                    "$ref": "#/definitions/ports.Movie"
+                },
+                "movie_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Popcorn ran out halfway"
+                },
+                "rating": {
+                    "description": "оценка на момент просмотра",
+                    "type": "number",
+                    "example": 8.5
+                },
+                "rewatch": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "watched_on": {
+                    "$ref": "#/definitions/ports.CustomDate"
+                }
+            }
+        },
+        "ports.Event": {
+            "type": "object",
+            "properties": {
+                "cancelled_at": {
+                    "type": "string"
+                },
+                "capacity": {
+                    "description": "0 -\u003e без ограничения, хост тоже занимает место",
+                    "type": "integer",
+                    "example": 8
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "declined_count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Bring snacks"
+                },
+                "going_count": {
+                    "description": "приглашенные и гости со статусом going, без хоста",
+                    "type": "integer",
+                    "example": 5
+                },
+                "guests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ports.EventGuest"
+                    }
+                },
+                "host": {
+                    "$ref": "#/definitions/ports.UserSummary"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "invitations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ports.EventInvitation"
+                    }
+                },
+                "location": {
+                    "type": "string",
+                    "example": "Aigerim's place, Abay ave 10"
+                },
+                "maybe_count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "movie_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "movie_runtime": {
+                    "description": "в минутах, 0 -\u003e неизвестна",
+                    "type": "integer",
+                    "example": 170
+                },
+                "movie_title": {
+                    "type": "string",
+                    "example": "Heat"
+                },
+                "occurrence_id": {
+                    "description": "OccurrenceID -\u003e у одного повторения серии: его исходное начало по правилу (UTC),\nпо нему повторение меняют и отменяют отдельно",
+                    "type": "string",
+                    "example": "20261023T150000Z"
+                },
+                "recurrence": {
+                    "description": "Recurrence -\u003e RRULE серии (FREQ=WEEKLY;BYDAY=FR), пусто -\u003e разовый вечер",
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=FR"
+                },
+                "recurrence_end": {
+                    "description": "RecurrenceEnd -\u003e начало последнего повторения, nil -\u003e серия бесконечна",
+                    "type": "string"
+                },
+                "spots_available": {
+                    "description": "nil -\u003e без ограничения",
+                    "type": "integer",
+                    "example": 2
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2026-10-23T20:00:00+05:00"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ports.EventStatus"
+                        }
+                    ],
+                    "example": "scheduled"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Almaty"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Friday heist night"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "waitlist_count": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "ports.EventGuest": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string",
+                    "example": "Dana (Aigerim's sister)"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "joined_at": {
+                    "type": "string"
+                },
+                "responded_at": {
+                    "type": "string"
+                },
+                "rsvp": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ports.RSVPStatus"
+                        }
+                    ],
+                    "example": "going"
+                },
+                "waitlist_position": {
+                    "description": "место в общей очереди вечера",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "ports.EventInvitation": {
+            "type": "object",
+            "properties": {
+                "invited_at": {
+                    "type": "string"
+                },
+                "responded_at": {
+                    "type": "string"
+                },
+                "rsvp": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ports.RSVPStatus"
+                        }
+                    ],
+                    "example": "going"
+                },
+                "user": {
+                    "$ref": "#/definitions/ports.UserSummary"
+                },
+                "waitlist_position": {
+                    "description": "1 -\u003e следующий в очереди",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "ports.EventStatus": {
+            "type": "string",
+            "enum": [
+                "scheduled",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "EventScheduled",
+                "EventCancelled"
+            ]
+        },
+        "ports.Follow": {
+            "type": "object",
+            "properties": {
+                "followed_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/ports.UserSummary"
+                }
+            }
+        },
+        "ports.Group": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Fridays after 19:00"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "invite_token": {
+                    "description": "только для owner и admin",
+                    "type": "string",
+                    "example": "3q2-7wEAAAA"
+                },
+                "member_count": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ports.GroupMember"
+                    }
+                },
+                "my_role": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ports.GroupRole"
+                        }
+                    ],
+                    "example": "admin"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Office lounge crew"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "ports.GroupMember": {
+            "type": "object",
+            "properties": {
+                "joined_at": {
+                    "type": "string"
+                },
+                "role": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ports.GroupRole"
+                        }
+                    ],
+                    "example": "member"
+                },
+                "user": {
+                    "$ref": "#/definitions/ports.UserSummary"
+                }
+            }
+        },
+        "ports.GroupRole": {
+            "type": "string",
+            "enum": [
+                "owner",
+                "admin",
+                "member"
+            ],
+            "x-enum-comments": {
+                "GroupRoleAdmin": "ссылка-приглашение, настройки группы, удаление участников",
+                "GroupRoleMember": "общий список и опросы",
+                "GroupRoleOwner": "все права, включая роли и удаление группы"
+            },
+            "x-enum-descriptions": [
+                "все права, включая роли и удаление группы",
+                "ссылка-приглашение, настройки группы, удаление участников",
+                "общий список и опросы"
+            ],
+            "x-enum-varnames": [
+                "GroupRoleOwner",
+                "GroupRoleAdmin",
+                "GroupRoleMember"
+            ]
+        },
+        "ports.GroupWatchlistItem": {
+            "type": "object",
+            "properties": {
+                "added_at": {
+                    "type": "string"
+                },
+                "added_by": {
+                    "description": "nil -\u003e автор удалил аккаунт",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ports.UserSummary"
+                        }
+                    ]
+                },
+                "movie": {
+                    "$ref": "#/definitions/ports.Movie"
+                },
+                "movie_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Someone said it's better than Heat"
+                },
+                "seen_by": {
+                    "description": "SeenBy -\u003e участники, у которых фильм есть в дневнике",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        2
+                    ]
+                },
+                "voted_by_me": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "votes": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "ports.GuestLink": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJwIjp7..."
+                },
+                "url": {
+                    "type": "string",
+                    "example": "http://localhost:8080/guest/invites/eyJwIjp7..."
+                }
+            }
+        },
+        "ports.ListEntry": {
+            "type": "object",
+            "properties": {
+                "added_at": {
+                    "type": "string"
+                },
+                "movie": {
+                    "$ref": "#/definitions/ports.Movie"
+                },
+                "movie_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "note": {
+                    "type": "string",
+                    "example": "The one that started it all"
+                },
+                "position": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "ports.ListVisibility": {
+            "type": "string",
+            "enum": [
+                "private",
+                "unlisted",
+                "public"
+            ],
+            "x-enum-comments": {
+                "ListPrivate": "только владелец",
+                "ListPublic": "все, список виден в поиске и в популярных",
+                "ListUnlisted": "владелец и все, у кого есть ссылка с share_token"
+            },
+            "x-enum-descriptions": [
+                "только владелец",
+                "владелец и все, у кого есть ссылка с share_token",
+                "все, список виден в поиске и в популярных"
+            ],
+            "x-enum-varnames": [
+                "ListPrivate",
+                "ListUnlisted",
+                "ListPublic"
+            ]
+        },
+        "ports.Movie": {
+            "type": "object",
+            "properties": {
+                "certification": {
+                    "description": "возрастной рейтинг",
+                    "type": "string",
+                    "example": "PG-13"
+                },
+                "community_rating": {
+                    "description": "средняя оценка пользователей",
+                    "type": "number",
+                    "example": 8.4
+                },
+                "community_votes": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Action",
+                        "Sci-Fi"
+                    ]
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "language": {
+                    "description": "ISO 639-1, язык оригинала",
+                    "type": "string",
+                    "example": "en"
+                },
+                "overview": {
+                    "type": "string",
+                    "example": "A thief who steals corporate secrets..."
+                },
+                "poster_url": {
+                    "type": "string",
+                    "example": "https://image.tmdb.org/..."
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 8.8
+                },
+                "recommendations": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "The Matrix",
+                        "Shutter Island"
+                    ]
+                },
+                "release_date": {
+                    "$ref": "#/definitions/ports.CustomDate"
+                },
+                "runtime": {
+                    "description": "в минутах",
+                    "type": "integer",
+                    "example": 148
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Inception"
+                }
+            }
+        },
+        "ports.MovieList": {
+            "type": "object",
+            "properties": {
+                "clone_count": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "cloned_from_id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Ocean's and friends"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ports.ListEntry"
+                    }
+                },
+                "entry_count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "owner": {
+                    "$ref": "#/definitions/ports.UserSummary"
+                },
+                "share_token": {
+                    "description": "только для владельца",
+                    "type": "string",
+                    "example": "3q2-7wEAAAA"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Best heist films"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "visibility": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ports.ListVisibility"
+                        }
+                    ],
+                    "example": "public"
+                }
+            }
+        },
+        "ports.MovieWatchCount": {
+            "type": "object",
+            "properties": {
+                "first_watched_on": {
+                    "$ref": "#/definitions/ports.CustomDate"
+                },
+                "last_watched_on": {
+                    "$ref": "#/definitions/ports.CustomDate"
+                },
+                "movie_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Inception"
+                },
+                "watch_count": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "ports.Notification": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 17
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Aigerim started following you."
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "read": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "read_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "new_follower"
+                }
+            }
+        },
+        "ports.NotificationPreference": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "in_app": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "type": {
+                    "type": "string",
+                    "example": "event_invitation"
+                }
+            }
+        },
+        "ports.Poll": {
+            "type": "object",
+            "properties": {
+                "ballot_count": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ports.PollCandidate"
+                    }
+                },
+                "closes_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "event_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "group_id": {
+                    "description": "опрос либо вечера, либо группы",
+                    "type": "integer",
+                    "example": 2
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "method": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ports.VotingMethod"
+                        }
+                    ],
+                    "example": "irv"
+                },
+                "my_ballot": {
+                    "description": "MyBallot -\u003e бюллетень текущего пользователя, если он уже голосовал",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        12,
+                        7
+                    ]
+                },
+                "opens_at": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "What do we watch on Friday?"
+                }
+            }
+        },
+        "ports.PollCandidate": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "position": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Heat"
+                }
+            }
+        },
+        "ports.RSVPStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "going",
+                "maybe",
+                "declined",
+                "waitlisted"
+            ],
+            "x-enum-varnames": [
+                "RSVPPending",
+                "RSVPGoing",
+                "RSVPMaybe",
+                "RSVPDeclined",
+                "RSVPWaitlisted"
+            ]
+        },
+        "ports.Rating": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "movie_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 8.5
+                },
+                "review": {
+                    "type": "string",
+                    "example": "Great soundtrack, a bit too long."
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "ports.SlotResponse": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ports.Availability"
+                        }
+                    ],
+                    "example": "available"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "ports.User": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string",
+                    "example": "https://example.com/avatar.png"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deletion_scheduled_at": {
+                    "description": "DeletionScheduledAt -\u003e пользователь запросил удаление аккаунта, данные будут удалены в этот момент",
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "Aigerim"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "aigerim@example.com"
+                },
+                "email_verified": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "locale": {
+                    "type": "string",
+                    "example": "ru-KZ"
+                },
+                "timezone": {
+                    "description": "имя из базы IANA",
+                    "type": "string",
+                    "example": "Asia/Almaty"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "ports.UserSummary": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string",
+                    "example": "https://example.com/avatar.png"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "Aigerim"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "ports.ViewingPreferences": {
+            "type": "object",
+            "properties": {
+                "disliked_genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Horror",
+                        "Musical"
+                    ]
+                },
+                "hidden_certifications": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "NC-17"
+                    ]
+                },
+                "hidden_movie_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        12,
+                        40
+                    ]
+                },
+                "max_runtime_minutes": {
+                    "type": "integer",
+                    "example": 150
+                },
+                "min_rating": {
+                    "type": "number",
+                    "example": 6.5
+                },
+                "preferred_languages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "en",
+                        "fr"
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "ports.VotingMethod": {
+            "type": "string",
+            "enum": [
+                "plurality",
+                "approval",
+                "irv",
+                "borda"
+            ],
+            "x-enum-comments": {
+                "VotingApproval": "любое число подходящих фильмов",
+                "VotingBorda": "рейтинг фильмов, очки по местам",
+                "VotingIRV": "рейтинг фильмов, instant-runoff",
+                "VotingPlurality": "один фильм в бюллетене"
+            },
+            "x-enum-descriptions": [
+                "один фильм в бюллетене",
+                "любое число подходящих фильмов",
+                "рейтинг фильмов, instant-runoff",
+                "рейтинг фильмов, очки по местам"
+            ],
+            "x-enum-varnames": [
+                "VotingPlurality",
+                "VotingApproval",
+                "VotingIRV",
+                "VotingBorda"
+            ]
+        },
+        "ports.WatchlistItem": {
+            "type": "object",
+            "properties": {
+                "added_at": {
+                    "type": "string"
+                },
+                "movie": {
+                    "$ref": "#/definitions/ports.Movie"
+                },
+                "movie_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Watch with Aigerim"
+                },
+                "priority": {
+                    "description": "1 (низкий) .. 5 (высокий)",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "service.CandidateScore": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "rank": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "score": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "service.DiaryDay": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "$ref": "#/definitions/ports.CustomDate"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ports.DiaryEntry"
+                    }
+                }
+            }
+        },
+        "service.DiaryMonth": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.DiaryDay"
+                    }
+                },
+                "month": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "rewatches": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "year": {
+                    "type": "integer",
+                    "example": 2026
+                }
+            }
+        },
+        "service.DiaryMonthSummary": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ports.DiaryEntry"
+                    }
+                },
+                "month": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "service.DiaryYear": {
+            "type": "object",
+            "properties": {
+                "months": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.DiaryMonthSummary"
+                    }
+                },
+                "rewatches": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 84
+                },
+                "unique_movies": {
+                    "type": "integer",
+                    "example": 70
+                },
+                "year": {
+                    "type": "integer",
+                    "example": 2026
+                }
+            }
+        },
+        "service.FeedPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ports.Activity"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "1031"
+                }
+            }
+        },
+        "service.FinalMovieData": {
+            "type": "object",
+            "properties": {
+                "advice": {
+                    "type": "string",
+                    "example": "It is a very good choice! A high rated movie, which is recommended to watch."
+                },
+                "certification": {
+                    "description": "возрастной рейтинг",
+                    "type": "string",
+                    "example": "PG-13"
+                },
+                "community_rating": {
+                    "description": "средняя оценка пользователей",
+                    "type": "number",
+                    "example": 8.4
+                },
+                "community_votes": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Action",
+                        "Sci-Fi"
+                    ]
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "in_watchlist": {
+                    "description": "InWatchlist -\u003e только для авторизованных запросов, поэтому указатель",
+                    "type": "boolean",
+                    "example": true
+                },
+                "language": {
+                    "description": "ISO 639-1, язык оригинала",
+                    "type": "string",
+                    "example": "en"
+                },
+                "overview": {
+                    "type": "string",
+                    "example": "A thief who steals corporate secrets..."
+                },
+                "poster_url": {
+                    "type": "string",
+                    "example": "https://image.tmdb.org/..."
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 8.8
+                },
+                "reasons": {
+                    "description": "структурированная версия advice для UI",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.Reason"
+                    }
+                },
+                "recommendations": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "The Matrix",
+                        "Shutter Island"
+                    ]
+                },
+                "release_date": {
+                    "$ref": "#/definitions/ports.CustomDate"
+                },
+                "runtime": {
+                    "description": "в минутах",
+                    "type": "integer",
+                    "example": 148
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Inception"
+                }
+            }
+        },
+        "service.GuestSession": {
+            "type": "object",
+            "properties": {
+                "event": {
+                    "$ref": "#/definitions/ports.Event"
+                },
+                "guest": {
+                    "$ref": "#/definitions/ports.EventGuest"
+                },
+                "guest_token": {
+                    "type": "string",
+                    "example": "Qm9yZWQ..."
+                }
+            }
+        },
+        "service.GuestView": {
+            "type": "object",
+            "properties": {
+                "event": {
+                    "$ref": "#/definitions/ports.Event"
+                },
+                "guest": {
+                    "$ref": "#/definitions/ports.EventGuest"
+                }
+            }
+        },
+        "service.MarathonDrop": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "runtime": {
+                    "type": "integer",
+                    "example": 122
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Ronin"
+                },
+                "value": {
+                    "description": "рейтинг или приоритет, смотря что оптимизировали",
+                    "type": "number",
+                    "example": 7.3
+                }
+            }
+        },
+        "service.MarathonItem": {
+            "type": "object",
+            "properties": {
+                "ends_at": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "movie",
+                        "break",
+                        "meal"
+                    ],
+                    "example": "movie"
+                },
+                "minutes": {
+                    "type": "integer",
+                    "example": 170
+                },
+                "movie_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Heat"
+                }
+            }
+        },
+        "service.MarathonPlan": {
+            "type": "object",
+            "properties": {
+                "dropped": {
+                    "description": "Dropped -\u003e что предлагаем не смотреть, чтобы успеть к ends_by. Пусто -\u003e поместилось все",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.MarathonDrop"
+                    }
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.MarathonItem"
+                    }
+                },
+                "optimize": {
+                    "type": "string",
+                    "example": "rating"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "total_minutes": {
+                    "type": "integer",
+                    "example": 415
+                },
+                "total_value": {
+                    "type": "number",
+                    "example": 23.1
+                }
+            }
+        },
+        "service.MovieListItem": {
+            "type": "object",
+            "properties": {
+                "certification": {
+                    "description": "возрастной рейтинг",
+                    "type": "string",
+                    "example": "PG-13"
+                },
+                "community_rating": {
+                    "description": "средняя оценка пользователей",
+                    "type": "number",
+                    "example": 8.4
+                },
+                "community_votes": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Action",
+                        "Sci-Fi"
+                    ]
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "in_watchlist": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "language": {
+                    "description": "ISO 639-1, язык оригинала",
+                    "type": "string",
+                    "example": "en"
+                },
+                "overview": {
+                    "type": "string",
+                    "example": "A thief who steals corporate secrets..."
+                },
+                "poster_url": {
+                    "type": "string",
+                    "example": "https://image.tmdb.org/..."
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 8.8
+                },
+                "recommendations": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "The Matrix",
+                        "Shutter Island"
+                    ]
+                },
+                "release_date": {
+                    "$ref": "#/definitions/ports.CustomDate"
+                },
+                "runtime": {
+                    "description": "в минутах",
+                    "type": "integer",
+                    "example": 148
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Inception"
+                }
+            }
+        },
+        "service.NotificationPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ports.Notification"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "12"
+                },
+                "unread_count": {
+                    "type": "integer",
+                    "example": 3
+                }
             }
-        }
-    },
-    "definitions": {
-        "http.SwaggerMovieRequest": {
+        },
+        "service.ParticipantFit": {
+            "type": "object",
+            "properties": {
+                "fit": {
+                    "type": "number",
+                    "example": 0.82
+                },
+                "source": {
+                    "type": "string",
+                    "example": "predicted"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "service.PlanCandidate": {
             "type": "object",
             "properties": {
+                "certification": {
+                    "description": "возрастной рейтинг",
+                    "type": "string",
+                    "example": "PG-13"
+                },
+                "community_rating": {
+                    "description": "средняя оценка пользователей",
+                    "type": "number",
+                    "example": 8.4
+                },
+                "community_votes": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "explanation": {
+                    "type": "string",
+                    "example": "Runs 148 min and ends at 21:28, within your 150 min window. Average fit 82%, lowest 70% (user 3)."
+                },
+                "fits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.ParticipantFit"
+                    }
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Action",
+                        "Sci-Fi"
+                    ]
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "language": {
+                    "description": "ISO 639-1, язык оригинала",
+                    "type": "string",
+                    "example": "en"
+                },
                 "overview": {
                     "type": "string",
                     "example": "A thief who steals corporate secrets..."
@@ -371,6 +9419,12 @@ const docTemplate = `{
                     "type": "number",
                     "example": 8.8
                 },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.Reason"
+                    }
+                },
                 "recommendations": {
                     "type": "array",
                     "items": {
@@ -382,8 +9436,16 @@ const docTemplate = `{
                     ]
                 },
                 "release_date": {
-                    "type": "string",
-                    "example": "2010-07-16"
+                    "$ref": "#/definitions/ports.CustomDate"
+                },
+                "runtime": {
+                    "description": "в минутах",
+                    "type": "integer",
+                    "example": 148
+                },
+                "score": {
+                    "type": "number",
+                    "example": 0.78
                 },
                 "title": {
                     "type": "string",
@@ -391,32 +9453,181 @@ const docTemplate = `{
                 }
             }
         },
-        "http.authRequest": {
+        "service.PollResults": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
+                "ballots": {
+                    "type": "integer",
+                    "example": 9
                 },
-                "password": {
-                    "type": "string"
+                "closed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "method": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ports.VotingMethod"
+                        }
+                    ],
+                    "example": "irv"
+                },
+                "poll_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "rounds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.TallyRound"
+                    }
+                },
+                "standings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.CandidateScore"
+                    }
+                },
+                "tie_break": {
+                    "type": "string",
+                    "example": "candidate_order"
+                },
+                "winner": {
+                    "type": "integer",
+                    "example": 12
                 }
             }
         },
-        "ports.CustomDate": {
+        "service.RankedSlot": {
             "type": "object",
             "properties": {
-                "time.Time": {
+                "attendance": {
+                    "description": "Attendance -\u003e сколько участников смогут прийти (available + if_need_be)",
+                    "type": "integer",
+                    "example": 5
+                },
+                "available": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "created_at": {
                     "type": "string"
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2026-10-23T23:00:00+05:00"
+                },
+                "event_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "if_need_be": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "missing_must_attend": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3
+                    ]
+                },
+                "must_attend_met": {
+                    "description": "MustAttendMet -\u003e все обязательные участники отметили available или if_need_be",
+                    "type": "boolean",
+                    "example": true
+                },
+                "my_availability": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ports.Availability"
+                        }
+                    ],
+                    "example": "available"
+                },
+                "no_response": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "rank": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "responses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ports.SlotResponse"
+                    }
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2026-10-23T20:00:00+05:00"
+                },
+                "unavailable": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "ports.Movie": {
+        "service.Reason": {
             "type": "object",
             "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "HIGH_RATING"
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Highly rated: 8.8 out of 10."
+                }
+            }
+        },
+        "service.RecommendedMovie": {
+            "type": "object",
+            "properties": {
+                "certification": {
+                    "description": "возрастной рейтинг",
+                    "type": "string",
+                    "example": "PG-13"
+                },
+                "community_rating": {
+                    "description": "средняя оценка пользователей",
+                    "type": "number",
+                    "example": 8.4
+                },
+                "community_votes": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Action",
+                        "Sci-Fi"
+                    ]
+                },
                 "id": {
                     "type": "integer",
                     "example": 1
                 },
+                "language": {
+                    "description": "ISO 639-1, язык оригинала",
+                    "type": "string",
+                    "example": "en"
+                },
                 "overview": {
                     "type": "string",
                     "example": "A thief who steals corporate secrets..."
@@ -429,6 +9640,12 @@ const docTemplate = `{
                     "type": "number",
                     "example": 8.8
                 },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.Reason"
+                    }
+                },
                 "recommendations": {
                     "type": "array",
                     "items": {
@@ -442,23 +9659,84 @@ const docTemplate = `{
                 "release_date": {
                     "$ref": "#/definitions/ports.CustomDate"
                 },
+                "runtime": {
+                    "description": "в минутах",
+                    "type": "integer",
+                    "example": 148
+                },
+                "score": {
+                    "type": "number",
+                    "example": 8.1
+                },
+                "source": {
+                    "type": "string",
+                    "example": "collaborative"
+                },
                 "title": {
                     "type": "string",
                     "example": "Inception"
                 }
             }
         },
-        "service.FinalMovieData": {
+        "service.ReviewPage": {
             "type": "object",
             "properties": {
-                "advice": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ports.Rating"
+                    }
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "service.SimilarMovie": {
+            "type": "object",
+            "properties": {
+                "certification": {
+                    "description": "возрастной рейтинг",
                     "type": "string",
-                    "example": "It is a very good choice! A high rated movie, which is recommended to watch."
+                    "example": "PG-13"
+                },
+                "community_rating": {
+                    "description": "средняя оценка пользователей",
+                    "type": "number",
+                    "example": 8.4
+                },
+                "community_votes": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Action",
+                        "Sci-Fi"
+                    ]
                 },
                 "id": {
                     "type": "integer",
                     "example": 1
                 },
+                "language": {
+                    "description": "ISO 639-1, язык оригинала",
+                    "type": "string",
+                    "example": "en"
+                },
                 "overview": {
                     "type": "string",
                     "example": "A thief who steals corporate secrets..."
@@ -471,6 +9749,12 @@ const docTemplate = `{
                     "type": "number",
                     "example": 8.8
                 },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.Reason"
+                    }
+                },
                 "recommendations": {
                     "type": "array",
                     "items": {
@@ -484,11 +9768,103 @@ const docTemplate = `{
                 "release_date": {
                     "$ref": "#/definitions/ports.CustomDate"
                 },
+                "runtime": {
+                    "description": "в минутах",
+                    "type": "integer",
+                    "example": 148
+                },
+                "similarity": {
+                    "type": "number",
+                    "example": 0.42
+                },
                 "title": {
                     "type": "string",
                     "example": "Inception"
                 }
             }
+        },
+        "service.SlotRanking": {
+            "type": "object",
+            "properties": {
+                "event_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "must_attend": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3
+                    ]
+                },
+                "participants": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
+                },
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.RankedSlot"
+                    }
+                }
+            }
+        },
+        "service.TallyRound": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "description": "Counts -\u003e первые предпочтения среди оставшихся кандидатов, по убыванию",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.CandidateScore"
+                    }
+                },
+                "eliminated": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "exhausted": {
+                    "description": "бюллетени, где не осталось живых кандидатов",
+                    "type": "integer",
+                    "example": 1
+                },
+                "round": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "tie_break": {
+                    "type": "string",
+                    "example": "previous_rounds"
+                },
+                "winner": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "service.TonightPlan": {
+            "type": "object",
+            "properties": {
+                "shortlist": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.PlanCandidate"
+                    }
+                },
+                "window_minutes": {
+                    "type": "integer",
+                    "example": 150
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -497,6 +9873,12 @@ const docTemplate = `{
             "type": "apiKey",
             "name": "Authorization",
             "in": "header"
+        },
+        "GuestToken": {
+            "description": "Guest session token from POST /guest/join.",
+            "type": "apiKey",
+            "name": "X-Guest-Token",
+            "in": "header"
         }
     }
 }`
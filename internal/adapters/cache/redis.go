@@ -71,12 +71,7 @@ func (a *RedisCacheAdapter) UpdateMovie(ctx context.Context, id int, movie *port
 	}
 
 	// Если обновление в базе прошло успешно -> инвалидируем кэш
-	key := fmt.Sprintf("movie:%d", id)
-	if err := a.client.Del(ctx, key).Err(); err != nil {
-		log.Printf("Warning: failed to invalidate cache for movie ID %d: %v", id, err)
-	} else {
-		log.Printf("Cache invalidated for movie ID: %d", id)
-	}
+	a.InvalidateMovie(ctx, id)
 	return nil
 }
 
@@ -85,15 +80,21 @@ func (a *RedisCacheAdapter) DeleteMovie(ctx context.Context, id int) error {
 	if err != nil {
 		return err
 	}
-	key := fmt.Sprintf("movie:%d", id)
 
 	// Если усмешно -> инвалидируем кэш
+	a.InvalidateMovie(ctx, id)
+	return nil
+}
+
+// InvalidateMovie -> удаляет фильм из кэша. Ошибку только логируем:
+// в худшем случае клиент увидит старые данные до истечения ttl
+func (a *RedisCacheAdapter) InvalidateMovie(ctx context.Context, id int) {
+	key := fmt.Sprintf("movie:%d", id)
 	if err := a.client.Del(ctx, key).Err(); err != nil {
 		log.Printf("Warning: failed to invalidate cache for movie ID %d: %v", id, err)
 	} else {
 		log.Printf("Cache invalidated for movie ID: %d", id)
 	}
-	return nil
 }
 
 // Для этих методов мы кидаем вызов дальше, не добавляя логику кэширования
@@ -104,3 +105,7 @@ func (a *RedisCacheAdapter) CreateMovie(ctx context.Context, movie *ports.Movie)
 func (a *RedisCacheAdapter) GetAllMovies(ctx context.Context) ([]*ports.Movie, error) {
 	return a.next.GetAllMovies(ctx)
 }
+
+func (a *RedisCacheAdapter) GetMoviesByIDs(ctx context.Context, ids []int) ([]*ports.Movie, error) {
+	return a.next.GetMoviesByIDs(ctx, ids)
+}
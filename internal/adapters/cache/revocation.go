@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisTokenRevoker -> хранит момент отзыва токенов пользователя на время жизни JWT.
+// Дольше хранить не нужно: к этому моменту все выданные до отзыва токены истекут сами
+type RedisTokenRevoker struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewRedisTokenRevoker(client *redis.Client, tokenTTL time.Duration) *RedisTokenRevoker {
+	return &RedisTokenRevoker{
+		client: client,
+		ttl:    tokenTTL,
+	}
+}
+
+func (r *RedisTokenRevoker) RevokeUserTokens(ctx context.Context, userID int, at time.Time) error {
+	return r.client.Set(ctx, revokedUserKey(userID), at.UnixMilli(), r.ttl).Err()
+}
+
+func (r *RedisTokenRevoker) TokensRevokedAt(ctx context.Context, userID int) (time.Time, error) {
+	ms, err := r.client.Get(ctx, revokedUserKey(userID)).Int64()
+	if err != nil {
+		if err == redis.Nil {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	return time.UnixMilli(ms), nil
+}
+
+func revokedUserKey(userID int) string {
+	return fmt.Sprintf("revoked-user:%d", userID)
+}
@@ -0,0 +1,282 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	roomHistoryPrefix = "room-history:" // stream с последними сообщениями комнаты
+	roomChannelPrefix = "room:"         // канал pub/sub комнаты
+	// roomBuffer -> сколько сообщений может ждать медленный клиент, дальше его отключаем
+	roomBuffer = 64
+)
+
+// publishRoomScript -> XADD и PUBLISH одним скриптом, чтобы подписчики получали сообщения
+// в том же порядке, в каком они лежат в истории
+var publishRoomScript = redis.NewScript(`
+local id = redis.call('XADD', KEYS[1], 'MAXLEN', '~', ARGV[1], '*', 'type', ARGV[2], 'data', ARGV[3])
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+redis.call('PUBLISH', KEYS[2], id .. '\n' .. ARGV[2] .. '\n' .. ARGV[3])
+return id
+`)
+
+// RedisRoomBroker -> комнаты на Redis. Живая рассылка идет через pub/sub: каждый инстанс
+// держит одну подписку на все комнаты и раздает сообщения своим клиентам. Последние сообщения
+// комнаты лежат в stream, из него догоняют клиенты, переподключившиеся с Last-Event-ID
+type RedisRoomBroker struct {
+	client  *redis.Client
+	history int64         // сколько сообщений хранить в комнате
+	ttl     time.Duration // комната без новых сообщений удаляется через ttl
+
+	mu   sync.Mutex
+	subs map[string]map[*roomSubscriber]struct{}
+}
+
+type roomSubscriber struct {
+	live chan ports.RoomMessage
+}
+
+func NewRedisRoomBroker(client *redis.Client, history int64, ttl time.Duration) *RedisRoomBroker {
+	return &RedisRoomBroker{
+		client:  client,
+		history: history,
+		ttl:     ttl,
+		subs:    make(map[string]map[*roomSubscriber]struct{}),
+	}
+}
+
+func (b *RedisRoomBroker) Publish(ctx context.Context, room, msgType string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal room message: %w", err)
+	}
+
+	keys := []string{roomHistoryPrefix + room, roomChannelPrefix + room}
+	err = publishRoomScript.Run(ctx, b.client, keys, b.history, msgType, payload, b.ttl.Milliseconds()).Err()
+	if err != nil {
+		log.Printf("Error publishing to room %s: %v", room, err)
+		return err
+	}
+	return nil
+}
+
+func (b *RedisRoomBroker) Subscribe(ctx context.Context, room, lastID string) (<-chan ports.RoomMessage, error) {
+	// Сначала подписываемся, потом читаем историю -> сообщение между этими шагами не потеряется,
+	// а повтор отсечется по ID
+	sub := &roomSubscriber{live: make(chan ports.RoomMessage, roomBuffer)}
+	b.add(room, sub)
+
+	var backlog []ports.RoomMessage
+	if lastID != "" {
+		var err error
+		if backlog, err = b.since(ctx, room, lastID); err != nil {
+			b.remove(room, sub)
+			return nil, err
+		}
+	}
+
+	out := make(chan ports.RoomMessage)
+	go func() {
+		defer close(out)
+		defer b.remove(room, sub)
+
+		last := lastID
+		for _, m := range backlog {
+			select {
+			case out <- m:
+				// у reset в пустой комнате ID нет -> дальше отдаем все новые сообщения
+				last = m.ID
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		for {
+			select {
+			case m, ok := <-sub.live:
+				if !ok {
+					return
+				}
+				if last != "" && !streamIDAfter(m.ID, last) {
+					continue
+				}
+				select {
+				case out <- m:
+					last = m.ID
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// Run -> читает pub/sub и раздает сообщения клиентам этого инстанса, пока не отменен ctx.
+// Если соединение с Redis рвется, клиенты отключаются: пропущенное они догонят из истории
+func (b *RedisRoomBroker) Run(ctx context.Context) {
+	pubsub := b.client.PSubscribe(ctx, roomChannelPrefix+"*")
+	defer pubsub.Close()
+
+	for {
+		msg, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Error receiving room messages: %v", err)
+			b.dropAll()
+
+			select {
+			case <-time.After(time.Second):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+
+		if m, ok := msg.(*redis.Message); ok {
+			b.dispatch(strings.TrimPrefix(m.Channel, roomChannelPrefix), m.Payload)
+		}
+	}
+}
+
+// since -> сообщения после lastID. Если lastID уже вытеснен из истории (или комнату удалили
+// по ttl), клиент получает reset и должен перечитать состояние через API
+func (b *RedisRoomBroker) since(ctx context.Context, room, lastID string) ([]ports.RoomMessage, error) {
+	key := roomHistoryPrefix + room
+
+	if _, _, ok := parseStreamID(lastID); ok {
+		found, err := b.client.XRange(ctx, key, lastID, lastID).Result()
+		if err != nil {
+			log.Printf("Error reading room history %s: %v", room, err)
+			return nil, err
+		}
+		if len(found) > 0 {
+			entries, err := b.client.XRange(ctx, key, "("+lastID, "+").Result()
+			if err != nil {
+				log.Printf("Error reading room history %s: %v", room, err)
+				return nil, err
+			}
+			messages := make([]ports.RoomMessage, 0, len(entries))
+			for _, e := range entries {
+				msgType, _ := e.Values["type"].(string)
+				data, _ := e.Values["data"].(string)
+				messages = append(messages, ports.RoomMessage{ID: e.ID, Type: msgType, Data: json.RawMessage(data)})
+			}
+			return messages, nil
+		}
+	}
+
+	// reset получает ID последнего сообщения, чтобы следующий Last-Event-ID снова был в истории
+	latest, err := b.client.XRevRangeN(ctx, key, "+", "-", 1).Result()
+	if err != nil {
+		log.Printf("Error reading room history %s: %v", room, err)
+		return nil, err
+	}
+	reset := ports.RoomMessage{Type: ports.RoomReset, Data: json.RawMessage("{}")}
+	if len(latest) > 0 {
+		reset.ID = latest[0].ID
+	}
+	return []ports.RoomMessage{reset}, nil
+}
+
+func (b *RedisRoomBroker) dispatch(room, payload string) {
+	parts := strings.SplitN(payload, "\n", 3)
+	if len(parts) != 3 {
+		log.Printf("Warning: malformed message in room %s", room)
+		return
+	}
+	m := ports.RoomMessage{ID: parts[0], Type: parts[1], Data: json.RawMessage(parts[2])}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs[room] {
+		select {
+		case sub.live <- m:
+		default:
+			// Клиент не успевает читать -> отключаем, он переподключится с Last-Event-ID
+			b.closeLocked(room, sub)
+		}
+	}
+}
+
+func (b *RedisRoomBroker) add(room string, sub *roomSubscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subs[room] == nil {
+		b.subs[room] = make(map[*roomSubscriber]struct{})
+	}
+	b.subs[room][sub] = struct{}{}
+}
+
+func (b *RedisRoomBroker) remove(room string, sub *roomSubscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.subs[room], sub)
+	if len(b.subs[room]) == 0 {
+		delete(b.subs, room)
+	}
+}
+
+func (b *RedisRoomBroker) dropAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for room, subs := range b.subs {
+		for sub := range subs {
+			b.closeLocked(room, sub)
+		}
+	}
+}
+
+// closeLocked -> канал закрывается только здесь и только один раз: подписчик сразу убирается из комнаты
+func (b *RedisRoomBroker) closeLocked(room string, sub *roomSubscriber) {
+	close(sub.live)
+	delete(b.subs[room], sub)
+	if len(b.subs[room]) == 0 {
+		delete(b.subs, room)
+	}
+}
+
+// streamIDAfter -> ID сообщений в stream имеют вид "<ms>-<seq>" и сравниваются по частям
+func streamIDAfter(id, other string) bool {
+	ms, seq, ok := parseStreamID(id)
+	otherMs, otherSeq, otherOk := parseStreamID(other)
+	if !ok || !otherOk {
+		return true
+	}
+	return ms > otherMs || (ms == otherMs && seq > otherSeq)
+}
+
+func parseStreamID(id string) (ms, seq uint64, ok bool) {
+	msPart, seqPart, found := strings.Cut(id, "-")
+	if !found {
+		return 0, 0, false
+	}
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	seq, err = strconv.ParseUint(seqPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return ms, seq, true
+}
@@ -0,0 +1,64 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+// LogMailer -> "отправляет" письмо в лог. Удобно для локальной разработки
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg ports.MailMessage) error {
+	log.Printf("Mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// SpoolMailer -> складывает каждое письмо отдельным .eml файлом в папку.
+// Так письма можно открыть обычным почтовым клиентом или прочитать в тестах
+type SpoolMailer struct {
+	dir     string
+	counter atomic.Int64
+}
+
+func NewSpoolMailer(dir string) (*SpoolMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mail spool dir: %w", err)
+	}
+	return &SpoolMailer{dir: dir}, nil
+}
+
+func (m *SpoolMailer) Send(ctx context.Context, msg ports.MailMessage) error {
+	now := time.Now().UTC()
+
+	// Счетчик нужен, чтобы два письма в одну наносекунду не перезаписали друг друга
+	name := fmt.Sprintf("%s-%06d.eml", now.Format("20060102T150405.000000000"), m.counter.Add(1))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "To: %s\r\n", headerValue(msg.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", headerValue(msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(msg.Body)
+
+	if err := os.WriteFile(filepath.Join(m.dir, name), []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write mail to spool: %w", err)
+	}
+	return nil
+}
+
+// headerValue -> убирает переводы строк, чтобы нельзя было подставить свои заголовки
+func headerValue(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
@@ -0,0 +1,49 @@
+package notifier
+
+import (
+	"context"
+	"log"
+
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+// FanoutNotifier -> декоратор над основным Notifier (Postgres): после сохранения
+// уведомления ставит письмо в очередь, если пользователь включил email для этого типа.
+// Само письмо отправляет фоновая задача, запрос его не ждет.
+// Ошибка постановки в очередь только логируется: уведомление уже сохранено
+type FanoutNotifier struct {
+	next   ports.Notifier
+	prefs  ports.NotificationRepository
+	emails ports.NotificationEmailQueue
+}
+
+func NewFanoutNotifier(next ports.Notifier, prefs ports.NotificationRepository, emails ports.NotificationEmailQueue) *FanoutNotifier {
+	return &FanoutNotifier{
+		next:   next,
+		prefs:  prefs,
+		emails: emails,
+	}
+}
+
+func (f *FanoutNotifier) Notify(ctx context.Context, n *ports.Notification) error {
+	if err := f.next.Notify(ctx, n); err != nil {
+		return err
+	}
+	if err := f.queueEmail(ctx, n); err != nil {
+		log.Printf("Warning: failed to queue %s notification email to user %d: %v", n.Type, n.UserID, err)
+	}
+
+	return nil
+}
+
+func (f *FanoutNotifier) queueEmail(ctx context.Context, n *ports.Notification) error {
+	pref, err := f.prefs.GetNotificationPreference(ctx, n.UserID, n.Type)
+	if err != nil {
+		return err
+	}
+	if !pref.Email {
+		return nil
+	}
+
+	return f.emails.EnqueueNotificationEmail(ctx, n)
+}
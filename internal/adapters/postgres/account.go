@@ -0,0 +1,219 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+func (a *PostgresAdapter) ScheduleAccountDeletion(ctx context.Context, userID int, at time.Time) error {
+	query := `UPDATE users SET
+                  deletion_requested_at = CURRENT_TIMESTAMP,
+                  deletion_scheduled_at = $2,
+                  updated_at = CURRENT_TIMESTAMP
+              WHERE id = $1`
+
+	tag, err := a.pool.Exec(ctx, query, userID, at)
+	if err != nil {
+		log.Printf("Error scheduling account deletion: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) CancelAccountDeletion(ctx context.Context, userID int) error {
+	query := `UPDATE users SET
+                  deletion_requested_at = NULL,
+                  deletion_scheduled_at = NULL,
+                  updated_at = CURRENT_TIMESTAMP
+              WHERE id = $1 AND deletion_scheduled_at > CURRENT_TIMESTAMP`
+
+	tag, err := a.pool.Exec(ctx, query, userID)
+	if err != nil {
+		log.Printf("Error cancelling account deletion: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) GetAccountsDueForDeletion(ctx context.Context, now time.Time, limit int) ([]int, error) {
+	query := `SELECT id FROM users
+              WHERE deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= $1
+              ORDER BY deletion_scheduled_at, id
+              LIMIT $2`
+
+	rows, err := a.pool.Query(ctx, query, now, limit)
+	if err != nil {
+		log.Printf("Error getting accounts due for deletion: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			log.Printf("Error scanning user ID: %v", err)
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (a *PostgresAdapter) DeleteAccount(ctx context.Context, userID int) (*ports.DeletedAccount, error) {
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Места пользователя на вечерах после удаления достанутся листу ожидания.
+	// Вечера блокируем до удаления, в порядке ID, как и отдельные ответы через withEventLock
+	rows, err := tx.Query(ctx, `SELECT event_id FROM event_invitations
+                                WHERE user_id = $1 AND rsvp = 'going'
+                                ORDER BY event_id`, userID)
+	if err != nil {
+		log.Printf("Error getting events the user is going to: %v", err)
+		return nil, err
+	}
+	eventIDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		log.Printf("Error scanning events the user is going to: %v", err)
+		return nil, err
+	}
+	events := make([]lockedEvent, 0, len(eventIDs))
+	for _, id := range eventIDs {
+		e, err := lockEvent(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	// Блокируем фильмы, которые оценивал пользователь, в порядке ID (как и withMovieLock,
+	// чтобы не словить deadlock с конкурентными оценками), и после удаления
+	// пересчитываем их community_rating
+	rows, err = tx.Query(ctx, `SELECT m.id FROM movies m
+                                JOIN user_ratings r ON r.movie_id = m.id
+                                WHERE r.user_id = $1
+                                ORDER BY m.id
+                                FOR UPDATE OF m`, userID)
+	if err != nil {
+		log.Printf("Error locking rated movies: %v", err)
+		return nil, err
+	}
+	movieIDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		log.Printf("Error scanning rated movies: %v", err)
+		return nil, err
+	}
+
+	// Группы, которыми владеет пользователь, переходят к самому давнему admin,
+	// а если админов нет -> к самому давнему участнику. Сначала понижаем владельца:
+	// уникальный индекс допускает только одного owner в группе
+	rows, err = tx.Query(ctx, `UPDATE group_members SET role = 'member'
+                               WHERE user_id = $1 AND role = 'owner'
+                               RETURNING group_id`, userID)
+	if err != nil {
+		log.Printf("Error demoting group owner: %v", err)
+		return nil, err
+	}
+	groupIDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		log.Printf("Error scanning owned groups: %v", err)
+		return nil, err
+	}
+
+	if len(groupIDs) > 0 {
+		query := `UPDATE group_members m SET role = 'owner'
+                  FROM (SELECT DISTINCT ON (group_id) group_id, user_id
+                        FROM group_members
+                        WHERE group_id = ANY($1) AND user_id <> $2
+                        ORDER BY group_id, role = 'admin' DESC, joined_at, user_id) heir
+                  WHERE m.group_id = heir.group_id AND m.user_id = heir.user_id`
+		if _, err := tx.Exec(ctx, query, groupIDs, userID); err != nil {
+			log.Printf("Error transferring group ownership: %v", err)
+			return nil, err
+		}
+	}
+
+	// Опросы пользователя к этому моменту закрыты сервисом (о закрытии уже объявлено).
+	// Удаляем их явно, не полагаясь на каскад от users
+	if _, err := tx.Exec(ctx, `DELETE FROM polls WHERE created_by = $1`, userID); err != nil {
+		log.Printf("Error deleting polls of user %d: %v", userID, err)
+		return nil, err
+	}
+
+	// Оценки, список, дневник, токены и остальное удаляются каскадом.
+	// Вечера пользователя к этому моменту уже отменены сервисом
+	tag, err := tx.Exec(ctx, `DELETE FROM users WHERE id = $1`, userID)
+	if err != nil {
+		log.Printf("Error deleting user %d: %v", userID, err)
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, errs.ErrNotFound
+	}
+
+	// Группы, где пользователь был единственным участником, остались пустыми
+	if len(groupIDs) > 0 {
+		_, err := tx.Exec(ctx, `DELETE FROM groups g
+                                WHERE g.id = ANY($1)
+                                  AND NOT EXISTS (SELECT 1 FROM group_members m WHERE m.group_id = g.id)`, groupIDs)
+		if err != nil {
+			log.Printf("Error deleting empty groups: %v", err)
+			return nil, err
+		}
+	}
+
+	promoted := make(map[int][]int)
+	for _, e := range events {
+		if e.status != ports.EventScheduled {
+			continue
+		}
+		ids, err := promoteFromWaitlist(ctx, tx, e.id, seatsToPromote(e.capacity, e.going-1))
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) > 0 {
+			promoted[e.id] = ids
+		}
+	}
+
+	if len(movieIDs) > 0 {
+		query := `UPDATE movies SET
+                      community_rating = COALESCE(agg.avg_rating, 0),
+                      community_votes = COALESCE(agg.votes, 0)
+                  FROM (SELECT m.id, AVG(r.rating) AS avg_rating, COUNT(r.movie_id) AS votes
+                        FROM movies m LEFT JOIN user_ratings r ON r.movie_id = m.id
+                        WHERE m.id = ANY($1)
+                        GROUP BY m.id) agg
+                  WHERE movies.id = agg.id`
+		if _, err := tx.Exec(ctx, query, movieIDs); err != nil {
+			log.Printf("Error updating community ratings: %v", err)
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("Error committing account deletion: %v", err)
+		return nil, err
+	}
+
+	return &ports.DeletedAccount{RatedMovieIDs: movieIDs, Promoted: promoted}, nil
+}
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turysbekovg/movie-planner/internal/errs"
+)
+
+func (a *PostgresAdapter) SaveCalendarFeed(ctx context.Context, userID int, tokenHash string) (time.Time, error) {
+	query := `INSERT INTO calendar_feeds (user_id, token_hash) VALUES ($1, $2)
+              ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = CURRENT_TIMESTAMP
+              RETURNING created_at`
+
+	var createdAt time.Time
+	if err := a.pool.QueryRow(ctx, query, userID, tokenHash).Scan(&createdAt); err != nil {
+		log.Printf("Error saving calendar feed: %v", err)
+		return time.Time{}, err
+	}
+
+	return createdAt, nil
+}
+
+func (a *PostgresAdapter) DeleteCalendarFeed(ctx context.Context, userID int) error {
+	tag, err := a.pool.Exec(ctx, `DELETE FROM calendar_feeds WHERE user_id = $1`, userID)
+	if err != nil {
+		log.Printf("Error deleting calendar feed: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) GetCalendarFeedUser(ctx context.Context, tokenHash string) (int, error) {
+	var userID int
+	err := a.pool.QueryRow(ctx, `SELECT user_id FROM calendar_feeds WHERE token_hash = $1`, tokenHash).Scan(&userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, errs.ErrNotFound
+		}
+		log.Printf("Error getting calendar feed: %v", err)
+		return 0, err
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const diaryColumns = `d.id, d.user_id, d.movie_id, d.watched_on, d.rating, d.rewatch, d.location, d.notes, d.created_at, d.updated_at`
+
+func scanDiaryEntry(row pgx.Row) (*ports.DiaryEntry, error) {
+	var e ports.DiaryEntry
+	var mr movieRow
+
+	dest := append([]any{
+		&e.ID, &e.UserID, &e.MovieID, &e.WatchedOn, &e.Rating,
+		&e.Rewatch, &e.Location, &e.Notes, &e.CreatedAt, &e.UpdatedAt,
+	}, mr.dest()...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	e.Movie = mr.movie()
+
+	return &e, nil
+}
+
+func (a *PostgresAdapter) CreateDiaryEntry(ctx context.Context, entry *ports.DiaryEntry) error {
+	query := `INSERT INTO diary_entries (user_id, movie_id, watched_on, rating, rewatch, location, notes)
+              VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at, updated_at`
+
+	err := a.pool.QueryRow(ctx, query,
+		entry.UserID,
+		entry.MovieID,
+		entry.WatchedOn.Time,
+		entry.Rating,
+		entry.Rewatch,
+		entry.Location,
+		entry.Notes,
+	).Scan(&entry.ID, &entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		if hasPgCode(err, pgForeignKeyViolation) {
+			return errs.ErrNotFound
+		}
+		log.Printf("Error creating diary entry: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) GetDiaryEntry(ctx context.Context, userID, id int) (*ports.DiaryEntry, error) {
+	query := `SELECT ` + diaryColumns + `, ` + movieColumns + `
+              FROM diary_entries d JOIN movies ON movies.id = d.movie_id
+              WHERE d.user_id = $1 AND d.id = $2`
+
+	e, err := scanDiaryEntry(a.pool.QueryRow(ctx, query, userID, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.ErrNotFound
+		}
+		log.Printf("Error getting diary entry: %v", err)
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (a *PostgresAdapter) UpdateDiaryEntry(ctx context.Context, entry *ports.DiaryEntry) error {
+	query := `UPDATE diary_entries SET
+                  movie_id = $3,
+                  watched_on = $4,
+                  rating = $5,
+                  rewatch = $6,
+                  location = $7,
+                  notes = $8,
+                  updated_at = CURRENT_TIMESTAMP
+              WHERE user_id = $1 AND id = $2
+              RETURNING created_at, updated_at`
+
+	err := a.pool.QueryRow(ctx, query,
+		entry.UserID,
+		entry.ID,
+		entry.MovieID,
+		entry.WatchedOn.Time,
+		entry.Rating,
+		entry.Rewatch,
+		entry.Location,
+		entry.Notes,
+	).Scan(&entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows || hasPgCode(err, pgForeignKeyViolation) {
+			return errs.ErrNotFound
+		}
+		log.Printf("Error updating diary entry: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) DeleteDiaryEntry(ctx context.Context, userID, id int) error {
+	tag, err := a.pool.Exec(ctx, `DELETE FROM diary_entries WHERE user_id = $1 AND id = $2`, userID, id)
+	if err != nil {
+		log.Printf("Error deleting diary entry: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) GetDiaryEntries(ctx context.Context, userID int, from, to time.Time) ([]*ports.DiaryEntry, error) {
+	// Условие по диапазону дат покрывается индексом (user_id, watched_on)
+	query := `SELECT ` + diaryColumns + `, ` + movieColumns + `
+              FROM diary_entries d JOIN movies ON movies.id = d.movie_id
+              WHERE d.user_id = $1 AND d.watched_on >= $2 AND d.watched_on < $3
+              ORDER BY d.watched_on, d.id`
+
+	entries := make([]*ports.DiaryEntry, 0)
+
+	rows, err := a.pool.Query(ctx, query, userID, from, to)
+	if err != nil {
+		log.Printf("Error querying diary entries: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e, err := scanDiaryEntry(rows)
+		if err != nil {
+			log.Printf("Error scanning diary entry row: %v", err)
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating diary entry rows: %v", err)
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (a *PostgresAdapter) GetMovieWatchCounts(ctx context.Context, userID int) ([]*ports.MovieWatchCount, error) {
+	query := `SELECT d.movie_id, movies.title, COUNT(*), MIN(d.watched_on), MAX(d.watched_on)
+              FROM diary_entries d JOIN movies ON movies.id = d.movie_id
+              WHERE d.user_id = $1
+              GROUP BY d.movie_id, movies.title
+              ORDER BY COUNT(*) DESC, MAX(d.watched_on) DESC, d.movie_id`
+
+	counts := make([]*ports.MovieWatchCount, 0)
+
+	rows, err := a.pool.Query(ctx, query, userID)
+	if err != nil {
+		log.Printf("Error querying movie watch counts: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c ports.MovieWatchCount
+		if err := rows.Scan(&c.MovieID, &c.Title, &c.WatchCount, &c.FirstWatchedOn, &c.LastWatchedOn); err != nil {
+			log.Printf("Error scanning movie watch count row: %v", err)
+			return nil, err
+		}
+		counts = append(counts, &c)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating movie watch count rows: %v", err)
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (a *PostgresAdapter) CountMovieWatches(ctx context.Context, userID, movieID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM diary_entries WHERE user_id = $1 AND movie_id = $2`
+
+	if err := a.pool.QueryRow(ctx, query, userID, movieID).Scan(&count); err != nil {
+		log.Printf("Error counting movie watches: %v", err)
+		return 0, err
+	}
+
+	return count, nil
+}
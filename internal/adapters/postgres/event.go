@@ -0,0 +1,559 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const eventSelect = `SELECT e.id, u.id, u.display_name, u.avatar_url, e.title, e.description, e.starts_at, e.timezone,
+                            e.location, e.capacity, e.movie_id, COALESCE(m.title, ''), COALESCE(m.runtime_minutes, 0),
+                            e.status, e.cancelled_at, e.created_at, e.updated_at, e.sequence, e.guest_link_version,
+                            e.recurrence, e.recurrence_end,
+                            COALESCE(c.going, 0), COALESCE(c.maybe, 0), COALESCE(c.declined, 0), COALESCE(c.waitlisted, 0)
+                     FROM events e
+                     JOIN users u ON u.id = e.host_id
+                     LEFT JOIN movies m ON m.id = e.movie_id
+                     LEFT JOIN LATERAL (
+                         SELECT COUNT(*) FILTER (WHERE rsvp = 'going') AS going,
+                                COUNT(*) FILTER (WHERE rsvp = 'maybe') AS maybe,
+                                COUNT(*) FILTER (WHERE rsvp = 'declined') AS declined,
+                                COUNT(*) FILTER (WHERE rsvp = 'waitlisted') AS waitlisted
+                         FROM (SELECT rsvp FROM event_invitations WHERE event_id = e.id
+                               UNION ALL
+                               SELECT rsvp FROM event_guests WHERE event_id = e.id) r
+                     ) c ON TRUE`
+
+// eventAttendees -> ответы приглашенных и гостей вместе: места и очередь у них общие.
+// kind -> 'user' или 'guest', id -> ID пользователя или гостя
+const eventAttendees = `(SELECT 'user' AS kind, user_id AS id, event_id, rsvp, waitlisted_at FROM event_invitations
+                         UNION ALL
+                         SELECT 'guest', id, event_id, rsvp, waitlisted_at FROM event_guests)`
+
+func scanEvent(row pgx.Row) (*ports.Event, error) {
+	var e ports.Event
+	err := row.Scan(&e.ID, &e.Host.ID, &e.Host.DisplayName, &e.Host.AvatarURL, &e.Title, &e.Description, &e.StartsAt,
+		&e.Timezone, &e.Location, &e.Capacity, &e.MovieID, &e.MovieTitle, &e.MovieRuntime, &e.Status, &e.CancelledAt,
+		&e.CreatedAt, &e.UpdatedAt, &e.Sequence, &e.GuestLinkVersion, &e.Recurrence, &e.RecurrenceEnd, &e.GoingCount, &e.MaybeCount, &e.DeclinedCount, &e.WaitlistCount)
+	if err != nil {
+		return nil, err
+	}
+	if e.Capacity > 0 {
+		spots := max(freeSeats(e.Capacity, e.GoingCount), 0)
+		e.SpotsAvailable = &spots
+	}
+	return &e, nil
+}
+
+// freeSeats -> сколько еще человек может пойти. Хост всегда идет и занимает одно место
+func freeSeats(capacity, going int) int {
+	return capacity - 1 - going
+}
+
+// lockedEvent -> то, что нужно для проверок вместимости под блокировкой
+type lockedEvent struct {
+	id       int
+	capacity int
+	status   ports.EventStatus
+	going    int
+}
+
+// withEventLock -> выполняет fn в транзакции под блокировкой строки вечера.
+// Все изменения rsvp идут через нее, поэтому число идущих не превышает вместимость
+// даже при одновременных ответах
+func (a *PostgresAdapter) withEventLock(ctx context.Context, eventID int, fn func(tx pgx.Tx, e lockedEvent) error) error {
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	e, err := lockEvent(ctx, tx, eventID)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx, e); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// lockEvent -> блокирует строку вечера в уже открытой транзакции и считает идущих.
+// Несколько вечеров блокируем по возрастанию ID, чтобы не словить deadlock
+func lockEvent(ctx context.Context, tx pgx.Tx, eventID int) (lockedEvent, error) {
+	e := lockedEvent{id: eventID}
+	err := tx.QueryRow(ctx, `SELECT capacity, status FROM events WHERE id = $1 FOR UPDATE`, eventID).Scan(&e.capacity, &e.status)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return e, errs.ErrNotFound
+		}
+		log.Printf("Error locking event %d: %v", eventID, err)
+		return e, err
+	}
+	// Считаем уже под блокировкой: другие транзакции ждут ее, чтобы поменять rsvp
+	err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM `+eventAttendees+` a WHERE event_id = $1 AND rsvp = 'going'`, eventID).Scan(&e.going)
+	if err != nil {
+		log.Printf("Error counting event attendees: %v", err)
+		return e, err
+	}
+
+	return e, nil
+}
+
+// waitlistEntry -> место в общей очереди приглашенных и гостей
+type waitlistEntry struct {
+	kind         string // 'user' или 'guest', как в eventAttendees
+	id           int
+	waitlistedAt time.Time
+}
+
+// nextFromWaitlist -> первые n из очереди (n < 0 -> все): раньше вставшие в очередь идут раньше,
+// при одинаковом времени порядок фиксирован по kind и id
+func nextFromWaitlist(waiting []waitlistEntry, n int) []waitlistEntry {
+	sorted := slices.Clone(waiting)
+	slices.SortFunc(sorted, func(a, b waitlistEntry) int {
+		if c := a.waitlistedAt.Compare(b.waitlistedAt); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.kind, b.kind); c != 0 {
+			return c
+		}
+		return a.id - b.id
+	})
+	if n >= 0 && n < len(sorted) {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// promoteFromWaitlist -> отдает n мест первым из общей очереди приглашенных и гостей (n < 0 -> всем).
+// Возвращает только пользователей, в порядке очереди: гостям уведомления не отправляются
+func promoteFromWaitlist(ctx context.Context, tx pgx.Tx, eventID, n int) ([]int, error) {
+	if n == 0 {
+		return []int{}, nil
+	}
+
+	rows, err := tx.Query(ctx, `SELECT kind, id, waitlisted_at FROM `+eventAttendees+` a
+                                WHERE event_id = $1 AND rsvp = 'waitlisted'`, eventID)
+	if err != nil {
+		log.Printf("Error querying waitlist: %v", err)
+		return nil, err
+	}
+	waiting, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (waitlistEntry, error) {
+		var w waitlistEntry
+		err := row.Scan(&w.kind, &w.id, &w.waitlistedAt)
+		return w, err
+	})
+	if err != nil {
+		log.Printf("Error scanning waitlist: %v", err)
+		return nil, err
+	}
+
+	promoted := make([]int, 0)
+	guests := make([]int, 0)
+	for _, w := range nextFromWaitlist(waiting, n) {
+		if w.kind == "guest" {
+			guests = append(guests, w.id)
+		} else {
+			promoted = append(promoted, w.id)
+		}
+	}
+
+	if len(guests) > 0 {
+		query := `UPDATE event_guests SET rsvp = 'going', waitlisted_at = NULL, responded_at = CURRENT_TIMESTAMP
+                  WHERE event_id = $1 AND id = ANY($2)`
+		if _, err := tx.Exec(ctx, query, eventID, guests); err != nil {
+			log.Printf("Error promoting guests from waitlist: %v", err)
+			return nil, err
+		}
+	}
+	if len(promoted) > 0 {
+		query := `UPDATE event_invitations SET rsvp = 'going', waitlisted_at = NULL, responded_at = CURRENT_TIMESTAMP
+                  WHERE event_id = $1 AND user_id = ANY($2)`
+		if _, err := tx.Exec(ctx, query, eventID, promoted); err != nil {
+			log.Printf("Error promoting from waitlist: %v", err)
+			return nil, err
+		}
+	}
+
+	return promoted, nil
+}
+
+// seatsToPromote -> сколько людей можно поднять из очереди; -1 -> всех (вместимость не ограничена)
+func seatsToPromote(capacity, going int) int {
+	if capacity == 0 {
+		return -1
+	}
+	return max(freeSeats(capacity, going), 0)
+}
+
+const insertEvent = `INSERT INTO events (host_id, title, description, starts_at, timezone, location, capacity, movie_id,
+                                         recurrence, recurrence_end)
+                     VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+                     RETURNING id, status, created_at, updated_at`
+
+func (a *PostgresAdapter) CreateEvent(ctx context.Context, e *ports.Event, inviteeIDs []int) error {
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, insertEvent, e.Host.ID, e.Title, e.Description, e.StartsAt, e.Timezone, e.Location, e.Capacity,
+		e.MovieID, e.Recurrence, e.RecurrenceEnd).Scan(&e.ID, &e.Status, &e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		// Единственный внешний ключ, который может не сойтись, -> фильм
+		if hasPgCode(err, pgForeignKeyViolation) {
+			return errs.ErrNotFound
+		}
+		log.Printf("Error creating event: %v", err)
+		return err
+	}
+
+	// Нет кого-то из приглашенных -> откатываем и сам вечер, чтобы повтор запроса не создал дубль
+	if len(inviteeIDs) > 0 {
+		query := `INSERT INTO event_invitations (event_id, user_id, invited_by)
+                  SELECT $1, id, $2 FROM unnest($3::int[]) AS id`
+		if _, err := tx.Exec(ctx, query, e.ID, e.Host.ID, inviteeIDs); err != nil {
+			if hasPgCode(err, pgForeignKeyViolation) {
+				return errs.ErrNotFound
+			}
+			log.Printf("Error adding event invitations: %v", err)
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (a *PostgresAdapter) GetEvent(ctx context.Context, id int) (*ports.Event, error) {
+	e, err := scanEvent(a.pool.QueryRow(ctx, eventSelect+` WHERE e.id = $1`, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.ErrNotFound
+		}
+		log.Printf("Error getting event: %v", err)
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (a *PostgresAdapter) UpdateEvent(ctx context.Context, e *ports.Event) ([]int, error) {
+	var promoted []int
+	err := a.withEventLock(ctx, e.ID, func(tx pgx.Tx, locked lockedEvent) error {
+		if e.Capacity > 0 && freeSeats(e.Capacity, locked.going) < 0 {
+			return fmt.Errorf("%w: capacity is lower than the number of people going", errs.ErrConflict)
+		}
+
+		query := `UPDATE events SET
+                      title = $2,
+                      description = $3,
+                      starts_at = $4,
+                      timezone = $5,
+                      location = $6,
+                      capacity = $7,
+                      movie_id = $8,
+                      recurrence = $9,
+                      recurrence_end = $10,
+                      sequence = sequence + 1,
+                      updated_at = CURRENT_TIMESTAMP
+                  WHERE id = $1
+                  RETURNING updated_at`
+
+		err := tx.QueryRow(ctx, query, e.ID, e.Title, e.Description, e.StartsAt, e.Timezone, e.Location, e.Capacity, e.MovieID,
+			e.Recurrence, e.RecurrenceEnd).Scan(&e.UpdatedAt)
+		if err != nil {
+			if hasPgCode(err, pgForeignKeyViolation) {
+				return errs.ErrNotFound
+			}
+			log.Printf("Error updating event: %v", err)
+			return err
+		}
+
+		// Вместимость могла вырасти -> освободившиеся места отдаем очереди
+		promoted, err = promoteFromWaitlist(ctx, tx, e.ID, seatsToPromote(e.Capacity, locked.going))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return promoted, nil
+}
+
+func (a *PostgresAdapter) CancelEvent(ctx context.Context, id int) error {
+	query := `UPDATE events SET
+                  status = 'cancelled',
+                  cancelled_at = CURRENT_TIMESTAMP,
+                  sequence = sequence + 1,
+                  updated_at = CURRENT_TIMESTAMP
+              WHERE id = $1 AND status <> 'cancelled'`
+
+	tag, err := a.pool.Exec(ctx, query, id)
+	if err != nil {
+		log.Printf("Error cancelling event: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		// Вечер мог отменить параллельный запрос: это конфликт, а не отсутствие вечера
+		var exists bool
+		if err := a.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM events WHERE id = $1)`, id).Scan(&exists); err != nil {
+			log.Printf("Error checking event: %v", err)
+			return err
+		}
+		if exists {
+			return fmt.Errorf("%w: the event is already cancelled", errs.ErrConflict)
+		}
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) GetEventsForUser(ctx context.Context, userID int, from, to time.Time) ([]*ports.Event, error) {
+	query := eventSelect + `
+              WHERE (e.host_id = $1 OR EXISTS (SELECT 1 FROM event_invitations i WHERE i.event_id = e.id AND i.user_id = $1))
+                AND (e.starts_at >= $2 AND e.starts_at < $3
+                     OR e.recurrence <> '' AND e.starts_at < $3 AND (e.recurrence_end IS NULL OR e.recurrence_end >= $2)
+                     OR EXISTS (SELECT 1 FROM event_occurrences o
+                                WHERE o.event_id = e.id AND o.starts_at >= $2 AND o.starts_at < $3))
+              ORDER BY e.starts_at, e.id`
+
+	return a.queryEvents(ctx, query, userID, from, to)
+}
+
+func (a *PostgresAdapter) GetCalendarEvents(ctx context.Context, userID int, from time.Time) ([]*ports.Event, error) {
+	query := eventSelect + `
+              WHERE (e.host_id = $1 OR EXISTS (SELECT 1 FROM event_invitations i
+                                               WHERE i.event_id = e.id AND i.user_id = $1 AND i.rsvp IN ('going', 'maybe')))
+                AND (e.starts_at >= $2
+                     OR e.recurrence <> '' AND (e.recurrence_end IS NULL OR e.recurrence_end >= $2)
+                     OR EXISTS (SELECT 1 FROM event_occurrences o WHERE o.event_id = e.id AND o.starts_at >= $2))
+              ORDER BY e.starts_at, e.id`
+
+	return a.queryEvents(ctx, query, userID, from)
+}
+
+func (a *PostgresAdapter) queryEvents(ctx context.Context, query string, args ...any) ([]*ports.Event, error) {
+	rows, err := a.pool.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("Error querying events: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*ports.Event, 0)
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			log.Printf("Error scanning event row: %v", err)
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating event rows: %v", err)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (a *PostgresAdapter) GetEventInvitations(ctx context.Context, eventID int) ([]*ports.EventInvitation, error) {
+	query := `SELECT u.id, u.display_name, u.avatar_url, i.invited_at, i.rsvp, i.responded_at,
+                     CASE WHEN i.rsvp = 'waitlisted' THEN
+                         (SELECT COUNT(*) FROM ` + eventAttendees + ` w
+                          WHERE w.event_id = i.event_id AND w.rsvp = 'waitlisted'
+                            AND (w.waitlisted_at, w.kind, w.id) <= (i.waitlisted_at, 'user', i.user_id))
+                     ELSE 0 END
+              FROM event_invitations i JOIN users u ON u.id = i.user_id
+              WHERE i.event_id = $1
+              ORDER BY i.invited_at, u.id`
+
+	rows, err := a.pool.Query(ctx, query, eventID)
+	if err != nil {
+		log.Printf("Error querying event invitations: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := make([]*ports.EventInvitation, 0)
+	for rows.Next() {
+		var i ports.EventInvitation
+		err := rows.Scan(&i.User.ID, &i.User.DisplayName, &i.User.AvatarURL, &i.InvitedAt, &i.RSVP, &i.RespondedAt, &i.WaitlistPosition)
+		if err != nil {
+			log.Printf("Error scanning event invitation: %v", err)
+			return nil, err
+		}
+		invitations = append(invitations, &i)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating event invitations: %v", err)
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+func (a *PostgresAdapter) IsInvitedToEvent(ctx context.Context, eventID, userID int) (bool, error) {
+	var invited bool
+	err := a.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM event_invitations WHERE event_id = $1 AND user_id = $2)`,
+		eventID, userID).Scan(&invited)
+	if err != nil {
+		log.Printf("Error checking event invitation: %v", err)
+		return false, err
+	}
+	return invited, nil
+}
+
+func (a *PostgresAdapter) AddEventInvitations(ctx context.Context, eventID, invitedBy int, userIDs []int) ([]int, error) {
+	query := `INSERT INTO event_invitations (event_id, user_id, invited_by)
+              SELECT $1, id, $2 FROM unnest($3::int[]) AS id
+              ON CONFLICT (event_id, user_id) DO NOTHING
+              RETURNING user_id`
+
+	rows, err := a.pool.Query(ctx, query, eventID, invitedBy, userIDs)
+	if err != nil {
+		log.Printf("Error adding event invitations: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	added := make([]int, 0, len(userIDs))
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			log.Printf("Error scanning event invitation: %v", err)
+			return nil, err
+		}
+		added = append(added, id)
+	}
+
+	// Ошибка внешнего ключа приходит при чтении результата, а не в Query
+	if err := rows.Err(); err != nil {
+		if hasPgCode(err, pgForeignKeyViolation) {
+			return nil, errs.ErrNotFound
+		}
+		log.Printf("Error adding event invitations: %v", err)
+		return nil, err
+	}
+
+	return added, nil
+}
+
+func (a *PostgresAdapter) RemoveEventInvitation(ctx context.Context, eventID, userID int) ([]int, error) {
+	var promoted []int
+	err := a.withEventLock(ctx, eventID, func(tx pgx.Tx, e lockedEvent) error {
+		var previous ports.RSVPStatus
+		err := tx.QueryRow(ctx, `DELETE FROM event_invitations WHERE event_id = $1 AND user_id = $2 RETURNING rsvp`,
+			eventID, userID).Scan(&previous)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return errs.ErrNotFound
+			}
+			log.Printf("Error removing event invitation: %v", err)
+			return err
+		}
+
+		if previous == ports.RSVPGoing && e.status == ports.EventScheduled {
+			promoted, err = promoteFromWaitlist(ctx, tx, eventID, seatsToPromote(e.capacity, e.going-1))
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return promoted, nil
+}
+
+func (a *PostgresAdapter) SetEventRSVP(ctx context.Context, eventID, userID int, rsvp ports.RSVPStatus) (*ports.RSVPResult, error) {
+	var result *ports.RSVPResult
+	err := a.withEventLock(ctx, eventID, func(tx pgx.Tx, e lockedEvent) error {
+		var err error
+		result, err = setRSVP(ctx, tx, eventID, e, invitedUser(userID), rsvp)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// attendee -> чей ответ меняем: приглашение пользователя или гость.
+// table и column -> только константы из invitedUser и eventGuest
+type attendee struct {
+	table  string
+	column string
+	id     int
+}
+
+func invitedUser(userID int) attendee {
+	return attendee{table: "event_invitations", column: "user_id", id: userID}
+}
+
+func eventGuest(guestID int) attendee {
+	return attendee{table: "event_guests", column: "id", id: guestID}
+}
+
+// setRSVP -> меняет ответ под блокировкой вечера (withEventLock)
+func setRSVP(ctx context.Context, tx pgx.Tx, eventID int, e lockedEvent, who attendee, rsvp ports.RSVPStatus) (*ports.RSVPResult, error) {
+	result := &ports.RSVPResult{Status: rsvp, Promoted: []int{}}
+
+	if e.status == ports.EventCancelled {
+		return nil, fmt.Errorf("%w: the event is cancelled", errs.ErrConflict)
+	}
+
+	var previous ports.RSVPStatus
+	err := tx.QueryRow(ctx, `SELECT rsvp FROM `+who.table+` WHERE event_id = $1 AND `+who.column+` = $2`,
+		eventID, who.id).Scan(&previous)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.ErrNotFound
+		}
+		log.Printf("Error getting rsvp: %v", err)
+		return nil, err
+	}
+
+	// Повторное going не должно отправлять человека в конец очереди
+	if rsvp == ports.RSVPGoing && (previous == ports.RSVPGoing || previous == ports.RSVPWaitlisted) {
+		result.Status = previous
+		return result, nil
+	}
+	if rsvp == ports.RSVPGoing && e.capacity > 0 && freeSeats(e.capacity, e.going) <= 0 {
+		result.Status = ports.RSVPWaitlisted
+	}
+
+	query := `UPDATE ` + who.table + ` SET
+                  rsvp = $3,
+                  responded_at = CURRENT_TIMESTAMP,
+                  waitlisted_at = CASE WHEN $3 = 'waitlisted' THEN clock_timestamp() ELSE NULL END
+              WHERE event_id = $1 AND ` + who.column + ` = $2`
+	if _, err := tx.Exec(ctx, query, eventID, who.id, result.Status); err != nil {
+		log.Printf("Error setting rsvp: %v", err)
+		return nil, err
+	}
+
+	// Человек отказался от своего места -> отдаем его первому в очереди
+	if previous == ports.RSVPGoing && result.Status != ports.RSVPGoing {
+		if result.Promoted, err = promoteFromWaitlist(ctx, tx, eventID, seatsToPromote(e.capacity, e.going-1)); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFreeSeats(t *testing.T) {
+	tests := []struct {
+		name      string
+		capacity  int
+		going     int
+		free      int
+		toPromote int
+	}{
+		{name: "host takes a seat", capacity: 4, going: 0, free: 3, toPromote: 3},
+		{name: "last seat", capacity: 4, going: 2, free: 1, toPromote: 1},
+		{name: "full", capacity: 4, going: 3, free: 0, toPromote: 0},
+		{name: "only the host fits", capacity: 1, going: 0, free: 0, toPromote: 0},
+		{name: "capacity lowered below going", capacity: 2, going: 3, free: -2, toPromote: 0},
+		{name: "unlimited -> promote everyone", capacity: 0, going: 10, free: -11, toPromote: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := freeSeats(tt.capacity, tt.going); got != tt.free {
+				t.Errorf("freeSeats(%d, %d) = %d, want %d", tt.capacity, tt.going, got, tt.free)
+			}
+			if got := seatsToPromote(tt.capacity, tt.going); got != tt.toPromote {
+				t.Errorf("seatsToPromote(%d, %d) = %d, want %d", tt.capacity, tt.going, got, tt.toPromote)
+			}
+		})
+	}
+}
+
+func TestNextFromWaitlist(t *testing.T) {
+	base := time.Date(2026, 10, 18, 19, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return base.Add(time.Duration(seconds) * time.Second) }
+
+	// Гости и пользователи в одной очереди, перемешаны относительно порядка ответа
+	waiting := []waitlistEntry{
+		{kind: "user", id: 7, waitlistedAt: at(3)},
+		{kind: "guest", id: 2, waitlistedAt: at(1)},
+		{kind: "user", id: 5, waitlistedAt: at(2)},
+		{kind: "user", id: 9, waitlistedAt: at(1)},
+		{kind: "guest", id: 1, waitlistedAt: at(4)},
+	}
+
+	tests := []struct {
+		name string
+		n    int
+		want []waitlistEntry
+	}{
+		{
+			name: "one seat -> earliest, guest before user at the same time",
+			n:    1,
+			want: []waitlistEntry{{kind: "guest", id: 2, waitlistedAt: at(1)}},
+		},
+		{
+			name: "three seats -> first three in FIFO order",
+			n:    3,
+			want: []waitlistEntry{
+				{kind: "guest", id: 2, waitlistedAt: at(1)},
+				{kind: "user", id: 9, waitlistedAt: at(1)},
+				{kind: "user", id: 5, waitlistedAt: at(2)},
+			},
+		},
+		{
+			name: "more seats than waiting -> everyone",
+			n:    10,
+			want: []waitlistEntry{
+				{kind: "guest", id: 2, waitlistedAt: at(1)},
+				{kind: "user", id: 9, waitlistedAt: at(1)},
+				{kind: "user", id: 5, waitlistedAt: at(2)},
+				{kind: "user", id: 7, waitlistedAt: at(3)},
+				{kind: "guest", id: 1, waitlistedAt: at(4)},
+			},
+		},
+		{
+			name: "unlimited -> everyone",
+			n:    -1,
+			want: []waitlistEntry{
+				{kind: "guest", id: 2, waitlistedAt: at(1)},
+				{kind: "user", id: 9, waitlistedAt: at(1)},
+				{kind: "user", id: 5, waitlistedAt: at(2)},
+				{kind: "user", id: 7, waitlistedAt: at(3)},
+				{kind: "guest", id: 1, waitlistedAt: at(4)},
+			},
+		},
+		{
+			name: "no seats",
+			n:    0,
+			want: []waitlistEntry{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextFromWaitlist(waiting, tt.n)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nextFromWaitlist(%d) = %+v, want %+v", tt.n, got, tt.want)
+			}
+		})
+	}
+
+	// Исходная очередь не меняется
+	if waiting[0].id != 7 {
+		t.Errorf("waitlist was reordered in place: %+v", waiting)
+	}
+}
+
+func TestNextFromWaitlistDeclineFreesSeatForNext(t *testing.T) {
+	// Вместимость 3: хост и двое идущих, двое в очереди. Один из идущих отказывается ->
+	// освобождается ровно одно место, и его получает первый в очереди
+	base := time.Date(2026, 10, 18, 19, 0, 0, 0, time.UTC)
+	waiting := []waitlistEntry{
+		{kind: "user", id: 4, waitlistedAt: base.Add(time.Minute)},
+		{kind: "guest", id: 3, waitlistedAt: base},
+	}
+
+	capacity, going := 3, 2
+	if n := seatsToPromote(capacity, going); n != 0 {
+		t.Fatalf("seats before decline = %d, want 0", n)
+	}
+
+	got := nextFromWaitlist(waiting, seatsToPromote(capacity, going-1))
+	want := []waitlistEntry{{kind: "guest", id: 3, waitlistedAt: base}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("promoted = %+v, want %+v", got, want)
+	}
+}
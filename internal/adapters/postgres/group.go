@@ -0,0 +1,407 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const groupSelect = `SELECT g.id, g.name, g.description, COALESCE(g.invite_token, ''),
+                            (SELECT COUNT(*) FROM group_members m WHERE m.group_id = g.id),
+                            g.created_at, g.updated_at
+                     FROM groups g`
+
+func scanGroup(row pgx.Row, extra ...any) (*ports.Group, error) {
+	var g ports.Group
+	dest := append([]any{&g.ID, &g.Name, &g.Description, &g.InviteToken, &g.MemberCount, &g.CreatedAt, &g.UpdatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// groupWatchlistSelect -> seen_by считаем по дневникам тех, кто сейчас в группе
+const groupWatchlistSelect = `SELECT w.movie_id, w.note, w.added_at, u.id, u.display_name, u.avatar_url,
+                                     (SELECT COUNT(*) FROM group_watchlist_votes v
+                                      WHERE v.group_id = w.group_id AND v.movie_id = w.movie_id),
+                                     EXISTS (SELECT 1 FROM group_watchlist_votes v
+                                             WHERE v.group_id = w.group_id AND v.movie_id = w.movie_id AND v.user_id = $2),
+                                     ARRAY(SELECT DISTINCT d.user_id FROM diary_entries d
+                                           JOIN group_members gm ON gm.user_id = d.user_id AND gm.group_id = w.group_id
+                                           WHERE d.movie_id = w.movie_id
+                                           ORDER BY d.user_id),
+                                     ` + movieColumns + `
+                              FROM group_watchlist_items w
+                              JOIN movies ON movies.id = w.movie_id
+                              LEFT JOIN users u ON u.id = w.added_by`
+
+func scanGroupWatchlistItem(row pgx.Row) (*ports.GroupWatchlistItem, error) {
+	var it ports.GroupWatchlistItem
+	var addedByID *int
+	var addedByName, addedByAvatar *string
+	var mr movieRow
+	dest := append([]any{&it.MovieID, &it.Note, &it.AddedAt, &addedByID, &addedByName, &addedByAvatar,
+		&it.Votes, &it.VotedByMe, &it.SeenBy}, mr.dest()...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if addedByID != nil {
+		it.AddedBy = &ports.UserSummary{ID: *addedByID, DisplayName: *addedByName, AvatarURL: *addedByAvatar}
+	}
+	it.Movie = mr.movie()
+	return &it, nil
+}
+
+func (a *PostgresAdapter) CreateGroup(ctx context.Context, g *ports.Group, ownerID int) error {
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `INSERT INTO groups (name, description, invite_token)
+              VALUES ($1, $2, NULLIF($3, ''))
+              RETURNING id, created_at, updated_at`
+
+	if err := tx.QueryRow(ctx, query, g.Name, g.Description, g.InviteToken).Scan(&g.ID, &g.CreatedAt, &g.UpdatedAt); err != nil {
+		log.Printf("Error creating group: %v", err)
+		return err
+	}
+	if _, err := tx.Exec(ctx, `INSERT INTO group_members (group_id, user_id, role) VALUES ($1, $2, 'owner')`, g.ID, ownerID); err != nil {
+		log.Printf("Error adding group owner: %v", err)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (a *PostgresAdapter) GetGroup(ctx context.Context, id int) (*ports.Group, error) {
+	g, err := scanGroup(a.pool.QueryRow(ctx, groupSelect+` WHERE g.id = $1`, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.ErrNotFound
+		}
+		log.Printf("Error getting group: %v", err)
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func (a *PostgresAdapter) GetGroupByInviteToken(ctx context.Context, token string) (*ports.Group, error) {
+	g, err := scanGroup(a.pool.QueryRow(ctx, groupSelect+` WHERE g.invite_token = $1`, token))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.ErrNotFound
+		}
+		log.Printf("Error getting group by invite token: %v", err)
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func (a *PostgresAdapter) UpdateGroup(ctx context.Context, g *ports.Group) error {
+	query := `UPDATE groups SET
+                  name = $2,
+                  description = $3,
+                  invite_token = NULLIF($4, ''),
+                  updated_at = CURRENT_TIMESTAMP
+              WHERE id = $1
+              RETURNING updated_at`
+
+	err := a.pool.QueryRow(ctx, query, g.ID, g.Name, g.Description, g.InviteToken).Scan(&g.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return errs.ErrNotFound
+		}
+		log.Printf("Error updating group: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) DeleteGroup(ctx context.Context, id int) error {
+	tag, err := a.pool.Exec(ctx, `DELETE FROM groups WHERE id = $1`, id)
+	if err != nil {
+		log.Printf("Error deleting group: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) GetUserGroups(ctx context.Context, userID int) ([]*ports.Group, error) {
+	query := `SELECT g.id, g.name, g.description, COALESCE(g.invite_token, ''),
+                     (SELECT COUNT(*) FROM group_members m WHERE m.group_id = g.id),
+                     g.created_at, g.updated_at, me.role
+              FROM groups g JOIN group_members me ON me.group_id = g.id AND me.user_id = $1
+              ORDER BY g.name, g.id`
+
+	rows, err := a.pool.Query(ctx, query, userID)
+	if err != nil {
+		log.Printf("Error querying user groups: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := make([]*ports.Group, 0)
+	for rows.Next() {
+		var role ports.GroupRole
+		g, err := scanGroup(rows, &role)
+		if err != nil {
+			log.Printf("Error scanning group row: %v", err)
+			return nil, err
+		}
+		g.MyRole = role
+		groups = append(groups, g)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating group rows: %v", err)
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func (a *PostgresAdapter) GetGroupMembers(ctx context.Context, groupID int) ([]*ports.GroupMember, error) {
+	// owner, затем admin, затем member; внутри роли -> по дате вступления
+	query := `SELECT u.id, u.display_name, u.avatar_url, m.role, m.joined_at
+              FROM group_members m JOIN users u ON u.id = m.user_id
+              WHERE m.group_id = $1
+              ORDER BY CASE m.role WHEN 'owner' THEN 0 WHEN 'admin' THEN 1 ELSE 2 END, m.joined_at, u.id`
+
+	rows, err := a.pool.Query(ctx, query, groupID)
+	if err != nil {
+		log.Printf("Error querying group members: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := make([]*ports.GroupMember, 0)
+	for rows.Next() {
+		var m ports.GroupMember
+		if err := rows.Scan(&m.User.ID, &m.User.DisplayName, &m.User.AvatarURL, &m.Role, &m.JoinedAt); err != nil {
+			log.Printf("Error scanning group member: %v", err)
+			return nil, err
+		}
+		members = append(members, &m)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating group members: %v", err)
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (a *PostgresAdapter) GetGroupRole(ctx context.Context, groupID, userID int) (ports.GroupRole, error) {
+	var role ports.GroupRole
+	err := a.pool.QueryRow(ctx, `SELECT role FROM group_members WHERE group_id = $1 AND user_id = $2`, groupID, userID).Scan(&role)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", errs.ErrNotFound
+		}
+		log.Printf("Error getting group role: %v", err)
+		return "", err
+	}
+
+	return role, nil
+}
+
+func (a *PostgresAdapter) AddGroupMember(ctx context.Context, groupID, userID int, role ports.GroupRole) error {
+	_, err := a.pool.Exec(ctx, `INSERT INTO group_members (group_id, user_id, role) VALUES ($1, $2, $3)`, groupID, userID, role)
+	if err != nil {
+		if hasPgCode(err, pgUniqueViolation) {
+			return fmt.Errorf("%w: already a member of this group", errs.ErrConflict)
+		}
+		if hasPgCode(err, pgForeignKeyViolation) {
+			return errs.ErrNotFound
+		}
+		log.Printf("Error adding group member: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) SetGroupRole(ctx context.Context, groupID, userID int, role ports.GroupRole) error {
+	tag, err := a.pool.Exec(ctx, `UPDATE group_members SET role = $3 WHERE group_id = $1 AND user_id = $2`, groupID, userID, role)
+	if err != nil {
+		if hasPgCode(err, pgUniqueViolation) {
+			return fmt.Errorf("%w: the group already has an owner", errs.ErrConflict)
+		}
+		log.Printf("Error setting group role: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) TransferGroupOwnership(ctx context.Context, groupID, fromID, toID int) error {
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Сначала понижаем прежнего владельца: уникальный индекс допускает только одного owner
+	tag, err := tx.Exec(ctx, `UPDATE group_members SET role = 'admin' WHERE group_id = $1 AND user_id = $2 AND role = 'owner'`, groupID, fromID)
+	if err != nil {
+		log.Printf("Error demoting group owner: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrForbidden
+	}
+
+	tag, err = tx.Exec(ctx, `UPDATE group_members SET role = 'owner' WHERE group_id = $1 AND user_id = $2`, groupID, toID)
+	if err != nil {
+		log.Printf("Error promoting group owner: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (a *PostgresAdapter) RemoveGroupMember(ctx context.Context, groupID, userID int) error {
+	tag, err := a.pool.Exec(ctx, `DELETE FROM group_members WHERE group_id = $1 AND user_id = $2`, groupID, userID)
+	if err != nil {
+		log.Printf("Error removing group member: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) GetGroupWatchlist(ctx context.Context, groupID, viewerID int) ([]*ports.GroupWatchlistItem, error) {
+	query := groupWatchlistSelect + ` WHERE w.group_id = $1 ORDER BY 7 DESC, w.added_at, w.movie_id`
+
+	rows, err := a.pool.Query(ctx, query, groupID, viewerID)
+	if err != nil {
+		log.Printf("Error querying group watchlist: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*ports.GroupWatchlistItem, 0)
+	for rows.Next() {
+		it, err := scanGroupWatchlistItem(rows)
+		if err != nil {
+			log.Printf("Error scanning group watchlist item: %v", err)
+			return nil, err
+		}
+		items = append(items, it)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating group watchlist: %v", err)
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (a *PostgresAdapter) GetGroupWatchlistItem(ctx context.Context, groupID, movieID, viewerID int) (*ports.GroupWatchlistItem, error) {
+	it, err := scanGroupWatchlistItem(a.pool.QueryRow(ctx, groupWatchlistSelect+` WHERE w.group_id = $1 AND w.movie_id = $3`,
+		groupID, viewerID, movieID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.ErrNotFound
+		}
+		log.Printf("Error getting group watchlist item: %v", err)
+		return nil, err
+	}
+
+	return it, nil
+}
+
+func (a *PostgresAdapter) AddGroupWatchlistItem(ctx context.Context, groupID, userID, movieID int, note string) error {
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `INSERT INTO group_watchlist_items (group_id, movie_id, added_by, note) VALUES ($1, $2, $3, $4)`,
+		groupID, movieID, userID, note)
+	if err != nil {
+		if hasPgCode(err, pgUniqueViolation) {
+			return fmt.Errorf("%w: the movie is already in the group watchlist", errs.ErrConflict)
+		}
+		if hasPgCode(err, pgForeignKeyViolation) {
+			return errs.ErrNotFound
+		}
+		log.Printf("Error adding group watchlist item: %v", err)
+		return err
+	}
+
+	// Кто добавил фильм, тот за него и голосует
+	if _, err := tx.Exec(ctx, `INSERT INTO group_watchlist_votes (group_id, movie_id, user_id) VALUES ($1, $2, $3)`,
+		groupID, movieID, userID); err != nil {
+		log.Printf("Error adding group watchlist vote: %v", err)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (a *PostgresAdapter) RemoveGroupWatchlistItem(ctx context.Context, groupID, movieID int) error {
+	tag, err := a.pool.Exec(ctx, `DELETE FROM group_watchlist_items WHERE group_id = $1 AND movie_id = $2`, groupID, movieID)
+	if err != nil {
+		log.Printf("Error removing group watchlist item: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) VoteGroupWatchlistItem(ctx context.Context, groupID, movieID, userID int) error {
+	_, err := a.pool.Exec(ctx, `INSERT INTO group_watchlist_votes (group_id, movie_id, user_id) VALUES ($1, $2, $3)
+                                ON CONFLICT DO NOTHING`, groupID, movieID, userID)
+	if err != nil {
+		if hasPgCode(err, pgForeignKeyViolation) {
+			return errs.ErrNotFound
+		}
+		log.Printf("Error voting for group watchlist item: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) UnvoteGroupWatchlistItem(ctx context.Context, groupID, movieID, userID int) error {
+	_, err := a.pool.Exec(ctx, `DELETE FROM group_watchlist_votes WHERE group_id = $1 AND movie_id = $2 AND user_id = $3`,
+		groupID, movieID, userID)
+	if err != nil {
+		log.Printf("Error removing group watchlist vote: %v", err)
+		return err
+	}
+
+	return nil
+}
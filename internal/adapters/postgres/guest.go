@@ -0,0 +1,259 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const guestSelect = `SELECT g.id, g.event_id, g.display_name, g.rsvp, g.responded_at, g.joined_at,
+                            CASE WHEN g.rsvp = 'waitlisted' THEN
+                                (SELECT COUNT(*) FROM ` + eventAttendees + ` w
+                                 WHERE w.event_id = g.event_id AND w.rsvp = 'waitlisted'
+                                   AND (w.waitlisted_at, w.kind, w.id) <= (g.waitlisted_at, 'guest', g.id))
+                            ELSE 0 END
+                     FROM event_guests g`
+
+func scanGuest(row pgx.Row) (*ports.EventGuest, error) {
+	var g ports.EventGuest
+	err := row.Scan(&g.ID, &g.EventID, &g.DisplayName, &g.RSVP, &g.RespondedAt, &g.JoinedAt, &g.WaitlistPosition)
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (a *PostgresAdapter) CreateEventGuest(ctx context.Context, g *ports.EventGuest, tokenHash string) error {
+	// Проверка статуса в том же запросе, чтобы гость не появился у только что отмененного вечера
+	query := `INSERT INTO event_guests (event_id, display_name, token_hash)
+              SELECT id, $2, $3 FROM events WHERE id = $1 AND status = 'scheduled'
+              RETURNING id, rsvp, joined_at`
+
+	err := a.pool.QueryRow(ctx, query, g.EventID, g.DisplayName, tokenHash).Scan(&g.ID, &g.RSVP, &g.JoinedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("%w: the event is cancelled", errs.ErrConflict)
+		}
+		log.Printf("Error creating event guest: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) GetEventGuest(ctx context.Context, id int) (*ports.EventGuest, error) {
+	return a.getEventGuest(ctx, guestSelect+` WHERE g.id = $1`, id)
+}
+
+func (a *PostgresAdapter) GetEventGuestByToken(ctx context.Context, tokenHash string) (*ports.EventGuest, error) {
+	return a.getEventGuest(ctx, guestSelect+` WHERE g.token_hash = $1`, tokenHash)
+}
+
+func (a *PostgresAdapter) getEventGuest(ctx context.Context, query string, arg any) (*ports.EventGuest, error) {
+	g, err := scanGuest(a.pool.QueryRow(ctx, query, arg))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.ErrNotFound
+		}
+		log.Printf("Error getting event guest: %v", err)
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func (a *PostgresAdapter) GetEventGuests(ctx context.Context, eventID int) ([]*ports.EventGuest, error) {
+	rows, err := a.pool.Query(ctx, guestSelect+` WHERE g.event_id = $1 ORDER BY g.joined_at, g.id`, eventID)
+	if err != nil {
+		log.Printf("Error querying event guests: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	guests := make([]*ports.EventGuest, 0)
+	for rows.Next() {
+		g, err := scanGuest(rows)
+		if err != nil {
+			log.Printf("Error scanning event guest: %v", err)
+			return nil, err
+		}
+		guests = append(guests, g)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating event guests: %v", err)
+		return nil, err
+	}
+
+	return guests, nil
+}
+
+func (a *PostgresAdapter) RemoveEventGuest(ctx context.Context, eventID, guestID int) ([]int, error) {
+	var promoted []int
+	err := a.withEventLock(ctx, eventID, func(tx pgx.Tx, e lockedEvent) error {
+		var previous ports.RSVPStatus
+		err := tx.QueryRow(ctx, `DELETE FROM event_guests WHERE event_id = $1 AND id = $2 RETURNING rsvp`,
+			eventID, guestID).Scan(&previous)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return errs.ErrNotFound
+			}
+			log.Printf("Error removing event guest: %v", err)
+			return err
+		}
+
+		if previous == ports.RSVPGoing && e.status == ports.EventScheduled {
+			promoted, err = promoteFromWaitlist(ctx, tx, eventID, seatsToPromote(e.capacity, e.going-1))
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return promoted, nil
+}
+
+func (a *PostgresAdapter) SetGuestRSVP(ctx context.Context, eventID, guestID int, rsvp ports.RSVPStatus) (*ports.RSVPResult, error) {
+	var result *ports.RSVPResult
+	err := a.withEventLock(ctx, eventID, func(tx pgx.Tx, e lockedEvent) error {
+		var err error
+		result, err = setRSVP(ctx, tx, eventID, e, eventGuest(guestID), rsvp)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (a *PostgresAdapter) RevokeGuestLinks(ctx context.Context, eventID int) error {
+	tag, err := a.pool.Exec(ctx, `UPDATE events SET guest_link_version = guest_link_version + 1 WHERE id = $1`, eventID)
+	if err != nil {
+		log.Printf("Error revoking guest links: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) GetGuestBallot(ctx context.Context, pollID, guestID int) (*ports.Ballot, error) {
+	var b ports.Ballot
+	err := a.pool.QueryRow(ctx, `SELECT poll_id, choices, cast_at, updated_at FROM poll_guest_ballots WHERE poll_id = $1 AND guest_id = $2`,
+		pollID, guestID).Scan(&b.PollID, &b.Choices, &b.CastAt, &b.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.ErrNotFound
+		}
+		log.Printf("Error getting guest ballot: %v", err)
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+func (a *PostgresAdapter) SaveGuestBallot(ctx context.Context, pollID, guestID int, choices []int) error {
+	query := `INSERT INTO poll_guest_ballots (poll_id, guest_id, choices)
+              SELECT $1, $2, $3 FROM polls
+              WHERE id = $1 AND opens_at <= CURRENT_TIMESTAMP AND closes_at > CURRENT_TIMESTAMP
+              ON CONFLICT (poll_id, guest_id) DO UPDATE SET choices = EXCLUDED.choices, updated_at = CURRENT_TIMESTAMP`
+
+	tag, err := a.pool.Exec(ctx, query, pollID, guestID, choices)
+	if err != nil {
+		log.Printf("Error saving guest ballot: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: the poll is not open for voting", errs.ErrConflict)
+	}
+
+	return nil
+}
+
+// ClaimEventGuest -> под блокировкой вечера: ответ гостя становится ответом пользователя
+// (с тем же местом в очереди), бюллетени переносятся, гость удаляется
+func (a *PostgresAdapter) ClaimEventGuest(ctx context.Context, guestID, userID int) ([]int, error) {
+	var eventID int
+	err := a.pool.QueryRow(ctx, `SELECT event_id FROM event_guests WHERE id = $1`, guestID).Scan(&eventID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.ErrNotFound
+		}
+		log.Printf("Error getting event guest: %v", err)
+		return nil, err
+	}
+
+	var promoted []int
+	err = a.withEventLock(ctx, eventID, func(tx pgx.Tx, e lockedEvent) error {
+		// Гостя могли удалить, пока ждали блокировку
+		var hostID int
+		err := tx.QueryRow(ctx, `SELECT e.host_id FROM events e JOIN event_guests g ON g.event_id = e.id WHERE g.id = $1`,
+			guestID).Scan(&hostID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return errs.ErrNotFound
+			}
+			log.Printf("Error getting event host: %v", err)
+			return err
+		}
+		if hostID == userID {
+			return fmt.Errorf("%w: the host can't claim a guest of their own event", errs.ErrConflict)
+		}
+
+		// Пользователь еще не приглашен -> приглашение с ответом гостя.
+		// Уже приглашен, но не отвечал -> берем ответ гостя. Уже ответил сам -> его ответ важнее
+		query := `INSERT INTO event_invitations (event_id, user_id, invited_by, invited_at, rsvp, responded_at, waitlisted_at)
+                  SELECT g.event_id, $2, $3, g.joined_at, g.rsvp, g.responded_at, g.waitlisted_at
+                  FROM event_guests g WHERE g.id = $1
+                  ON CONFLICT (event_id, user_id) DO UPDATE SET
+                      rsvp = EXCLUDED.rsvp,
+                      responded_at = EXCLUDED.responded_at,
+                      waitlisted_at = EXCLUDED.waitlisted_at
+                  WHERE event_invitations.rsvp = 'pending'`
+		if _, err := tx.Exec(ctx, query, guestID, userID, hostID); err != nil {
+			if hasPgCode(err, pgForeignKeyViolation) {
+				return errs.ErrNotFound
+			}
+			log.Printf("Error claiming guest rsvp: %v", err)
+			return err
+		}
+
+		query = `INSERT INTO poll_ballots (poll_id, user_id, choices, cast_at, updated_at)
+                 SELECT poll_id, $2, choices, cast_at, updated_at FROM poll_guest_ballots WHERE guest_id = $1
+                 ON CONFLICT (poll_id, user_id) DO NOTHING`
+		if _, err := tx.Exec(ctx, query, guestID, userID); err != nil {
+			log.Printf("Error claiming guest ballots: %v", err)
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM event_guests WHERE id = $1`, guestID); err != nil {
+			log.Printf("Error deleting claimed guest: %v", err)
+			return err
+		}
+
+		// Если и гость, и пользователь шли, одно место освободилось
+		var going int
+		err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM `+eventAttendees+` a WHERE event_id = $1 AND rsvp = 'going'`, eventID).Scan(&going)
+		if err != nil {
+			log.Printf("Error counting event attendees: %v", err)
+			return err
+		}
+		if e.status == ports.EventScheduled {
+			promoted, err = promoteFromWaitlist(ctx, tx, eventID, seatsToPromote(e.capacity, going))
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return promoted, nil
+}
@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"context"
+	"log"
+)
+
+// GetWatchedMovieIDs -> фильм считается просмотренным, если он есть в дневнике или пользователь его оценил
+func (a *PostgresAdapter) GetWatchedMovieIDs(ctx context.Context, userID int) ([]int, error) {
+	ids := make([]int, 0)
+
+	query := `SELECT movie_id FROM user_ratings WHERE user_id = $1
+              UNION
+              SELECT movie_id FROM diary_entries WHERE user_id = $1
+              ORDER BY movie_id`
+
+	rows, err := a.pool.Query(ctx, query, userID)
+	if err != nil {
+		log.Printf("Error querying watch history: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			log.Printf("Error scanning watch history row: %v", err)
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating watch history rows: %v", err)
+		return nil, err
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,350 @@
+package postgres
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const listSelect = `SELECT l.id, u.id, u.display_name, u.avatar_url, l.title, l.description, l.visibility,
+                           COALESCE(l.share_token, ''), l.cloned_from_id, l.clone_count,
+                           (SELECT COUNT(*) FROM list_entries e WHERE e.list_id = l.id),
+                           l.created_at, l.updated_at
+                    FROM user_lists l JOIN users u ON u.id = l.owner_id`
+
+func scanList(row pgx.Row) (*ports.MovieList, error) {
+	var l ports.MovieList
+	err := row.Scan(&l.ID, &l.Owner.ID, &l.Owner.DisplayName, &l.Owner.AvatarURL, &l.Title, &l.Description, &l.Visibility,
+		&l.ShareToken, &l.ClonedFromID, &l.CloneCount, &l.EntryCount, &l.CreatedAt, &l.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// withListLock -> выполняет fn в транзакции под блокировкой строки списка.
+// Все изменения позиций идут через нее, поэтому позиции всегда остаются 1..N
+func (a *PostgresAdapter) withListLock(ctx context.Context, listID int, fn func(tx pgx.Tx) error) error {
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var id int
+	if err := tx.QueryRow(ctx, `SELECT id FROM user_lists WHERE id = $1 FOR UPDATE`, listID).Scan(&id); err != nil {
+		if err == pgx.ErrNoRows {
+			return errs.ErrNotFound
+		}
+		log.Printf("Error locking list %d: %v", listID, err)
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE user_lists SET updated_at = CURRENT_TIMESTAMP WHERE id = $1`, listID); err != nil {
+		log.Printf("Error touching list %d: %v", listID, err)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (a *PostgresAdapter) CreateList(ctx context.Context, list *ports.MovieList) error {
+	query := `INSERT INTO user_lists (owner_id, title, description, visibility, share_token)
+              VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+              RETURNING id, created_at, updated_at`
+
+	err := a.pool.QueryRow(ctx, query, list.Owner.ID, list.Title, list.Description, list.Visibility, list.ShareToken).
+		Scan(&list.ID, &list.CreatedAt, &list.UpdatedAt)
+	if err != nil {
+		log.Printf("Error creating list: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) GetList(ctx context.Context, id int) (*ports.MovieList, error) {
+	l, err := scanList(a.pool.QueryRow(ctx, listSelect+` WHERE l.id = $1`, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.ErrNotFound
+		}
+		log.Printf("Error getting list: %v", err)
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func (a *PostgresAdapter) GetListByShareToken(ctx context.Context, token string) (*ports.MovieList, error) {
+	l, err := scanList(a.pool.QueryRow(ctx, listSelect+` WHERE l.share_token = $1`, token))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.ErrNotFound
+		}
+		log.Printf("Error getting list by share token: %v", err)
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func (a *PostgresAdapter) UpdateList(ctx context.Context, list *ports.MovieList) error {
+	query := `UPDATE user_lists SET
+                  title = $2,
+                  description = $3,
+                  visibility = $4,
+                  share_token = NULLIF($5, ''),
+                  updated_at = CURRENT_TIMESTAMP
+              WHERE id = $1
+              RETURNING updated_at`
+
+	err := a.pool.QueryRow(ctx, query, list.ID, list.Title, list.Description, list.Visibility, list.ShareToken).
+		Scan(&list.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return errs.ErrNotFound
+		}
+		log.Printf("Error updating list: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) DeleteList(ctx context.Context, id int) error {
+	tag, err := a.pool.Exec(ctx, `DELETE FROM user_lists WHERE id = $1`, id)
+	if err != nil {
+		log.Printf("Error deleting list: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) GetListsByOwner(ctx context.Context, ownerID int, publicOnly bool) ([]*ports.MovieList, error) {
+	query := listSelect + ` WHERE l.owner_id = $1 AND (NOT $2 OR l.visibility = 'public')
+                            ORDER BY l.updated_at DESC, l.id DESC`
+	return a.queryLists(ctx, query, ownerID, publicOnly)
+}
+
+func (a *PostgresAdapter) GetPopularLists(ctx context.Context, limit int) ([]*ports.MovieList, error) {
+	query := listSelect + ` WHERE l.visibility = 'public'
+                            ORDER BY l.clone_count DESC, l.updated_at DESC, l.id DESC
+                            LIMIT $1`
+	return a.queryLists(ctx, query, limit)
+}
+
+func (a *PostgresAdapter) queryLists(ctx context.Context, query string, args ...any) ([]*ports.MovieList, error) {
+	rows, err := a.pool.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("Error querying lists: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	lists := make([]*ports.MovieList, 0)
+	for rows.Next() {
+		l, err := scanList(rows)
+		if err != nil {
+			log.Printf("Error scanning list row: %v", err)
+			return nil, err
+		}
+		lists = append(lists, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating list rows: %v", err)
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+func (a *PostgresAdapter) GetListEntries(ctx context.Context, listID int) ([]*ports.ListEntry, error) {
+	query := `SELECT e.movie_id, e.position, e.note, e.added_at, ` + movieColumns + `
+              FROM list_entries e JOIN movies ON movies.id = e.movie_id
+              WHERE e.list_id = $1
+              ORDER BY e.position`
+
+	rows, err := a.pool.Query(ctx, query, listID)
+	if err != nil {
+		log.Printf("Error querying list entries: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]*ports.ListEntry, 0)
+	for rows.Next() {
+		var e ports.ListEntry
+		var mr movieRow
+		dest := append([]any{&e.MovieID, &e.Position, &e.Note, &e.AddedAt}, mr.dest()...)
+		if err := rows.Scan(dest...); err != nil {
+			log.Printf("Error scanning list entry: %v", err)
+			return nil, err
+		}
+		e.Movie = mr.movie()
+		entries = append(entries, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating list entries: %v", err)
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (a *PostgresAdapter) AddListEntry(ctx context.Context, listID int, entry *ports.ListEntry) error {
+	return a.withListLock(ctx, listID, func(tx pgx.Tx) error {
+		query := `INSERT INTO list_entries (list_id, movie_id, position, note)
+                  SELECT $1, $2, COALESCE(MAX(position), 0) + 1, $3 FROM list_entries WHERE list_id = $1
+                  RETURNING position, added_at`
+
+		err := tx.QueryRow(ctx, query, listID, entry.MovieID, entry.Note).Scan(&entry.Position, &entry.AddedAt)
+		if err != nil {
+			if hasPgCode(err, pgUniqueViolation) {
+				return errs.ErrConflict
+			}
+			if hasPgCode(err, pgForeignKeyViolation) {
+				return errs.ErrNotFound
+			}
+			log.Printf("Error adding list entry: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (a *PostgresAdapter) UpdateListEntryNote(ctx context.Context, listID, movieID int, note string) error {
+	return a.withListLock(ctx, listID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `UPDATE list_entries SET note = $3 WHERE list_id = $1 AND movie_id = $2`, listID, movieID, note)
+		if err != nil {
+			log.Printf("Error updating list entry: %v", err)
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return errs.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (a *PostgresAdapter) MoveListEntry(ctx context.Context, listID, movieID, position int) error {
+	return a.withListLock(ctx, listID, func(tx pgx.Tx) error {
+		var current, count int
+		err := tx.QueryRow(ctx, `SELECT position, (SELECT COUNT(*) FROM list_entries WHERE list_id = $1)
+                                 FROM list_entries WHERE list_id = $1 AND movie_id = $2`, listID, movieID).
+			Scan(&current, &count)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return errs.ErrNotFound
+			}
+			log.Printf("Error reading list entry position: %v", err)
+			return err
+		}
+
+		// Позиция за пределами списка -> ставим в начало или в конец
+		if position < 1 {
+			position = 1
+		}
+		if position > count {
+			position = count
+		}
+		if position == current {
+			return nil
+		}
+
+		// Сдвигаем только записи между старой и новой позицией, порядок остальных не меняется
+		shift := `UPDATE list_entries SET position = position + 1
+                  WHERE list_id = $1 AND position >= $2 AND position < $3`
+		if position > current {
+			shift = `UPDATE list_entries SET position = position - 1
+                     WHERE list_id = $1 AND position > $3 AND position <= $2`
+		}
+		if _, err := tx.Exec(ctx, shift, listID, position, current); err != nil {
+			log.Printf("Error shifting list entries: %v", err)
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, `UPDATE list_entries SET position = $3 WHERE list_id = $1 AND movie_id = $2`, listID, movieID, position); err != nil {
+			log.Printf("Error moving list entry: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (a *PostgresAdapter) RemoveListEntry(ctx context.Context, listID, movieID int) error {
+	return a.withListLock(ctx, listID, func(tx pgx.Tx) error {
+		var position int
+		err := tx.QueryRow(ctx, `DELETE FROM list_entries WHERE list_id = $1 AND movie_id = $2 RETURNING position`, listID, movieID).
+			Scan(&position)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return errs.ErrNotFound
+			}
+			log.Printf("Error removing list entry: %v", err)
+			return err
+		}
+
+		// Закрываем "дырку", чтобы позиции остались 1..N
+		if _, err := tx.Exec(ctx, `UPDATE list_entries SET position = position - 1 WHERE list_id = $1 AND position > $2`, listID, position); err != nil {
+			log.Printf("Error compacting list positions: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (a *PostgresAdapter) CloneList(ctx context.Context, sourceID int, clone *ports.MovieList) error {
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Блокируем исходный список, чтобы скопировать согласованный набор фильмов
+	// и не потерять инкремент clone_count. updated_at исходника не трогаем: он не менялся
+	tag, err := tx.Exec(ctx, `UPDATE user_lists SET clone_count = clone_count + 1 WHERE id = $1`, sourceID)
+	if err != nil {
+		log.Printf("Error updating clone count: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNotFound
+	}
+
+	query := `INSERT INTO user_lists (owner_id, title, description, visibility, cloned_from_id)
+              VALUES ($1, $2, $3, $4, $5)
+              RETURNING id, created_at, updated_at`
+	err = tx.QueryRow(ctx, query, clone.Owner.ID, clone.Title, clone.Description, clone.Visibility, sourceID).
+		Scan(&clone.ID, &clone.CreatedAt, &clone.UpdatedAt)
+	if err != nil {
+		log.Printf("Error creating list clone: %v", err)
+		return err
+	}
+
+	tag, err = tx.Exec(ctx, `INSERT INTO list_entries (list_id, movie_id, position, note)
+                             SELECT $1, movie_id, position, note FROM list_entries WHERE list_id = $2`, clone.ID, sourceID)
+	if err != nil {
+		log.Printf("Error copying list entries: %v", err)
+		return err
+	}
+	clone.EntryCount = int(tag.RowsAffected())
+	clone.ClonedFromID = &sourceID
+
+	return tx.Commit(ctx)
+}
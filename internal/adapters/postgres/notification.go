@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+// Notify -> реализация ports.Notifier: сохраняет уведомление в базе,
+// если пользователь не отключил этот тип внутри приложения
+func (a *PostgresAdapter) Notify(ctx context.Context, n *ports.Notification) error {
+	payload := "{}"
+	if len(n.Payload) > 0 {
+		payload = string(n.Payload)
+	}
+	def := ports.DefaultNotificationPreference(n.Type)
+
+	query := `INSERT INTO notifications (user_id, type, message, payload)
+              SELECT $1, $2, $3, $4
+              WHERE COALESCE((SELECT in_app FROM notification_preferences WHERE user_id = $1 AND type = $2), $5)
+              RETURNING id, created_at`
+
+	err := a.pool.QueryRow(ctx, query, n.UserID, n.Type, n.Message, payload, def.InApp).Scan(&n.ID, &n.CreatedAt)
+	if err != nil {
+		// Пользователь отключил этот тип -> ничего не вставлено, это не ошибка
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		log.Printf("Error creating notification: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) GetNotifications(ctx context.Context, userID int, unreadOnly bool, before int64, limit int) ([]*ports.Notification, error) {
+	query := `SELECT id, user_id, type, message, payload, read_at, created_at
+              FROM notifications
+              WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL) AND ($3 = 0 OR id < $3)
+              ORDER BY id DESC
+              LIMIT $4`
+
+	rows, err := a.pool.Query(ctx, query, userID, unreadOnly, before, limit)
+	if err != nil {
+		log.Printf("Error querying notifications: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := make([]*ports.Notification, 0)
+	for rows.Next() {
+		var n ports.Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Type, &n.Message, &n.Payload, &n.ReadAt, &n.CreatedAt); err != nil {
+			log.Printf("Error scanning notification: %v", err)
+			return nil, err
+		}
+		n.Read = n.ReadAt != nil
+		notifications = append(notifications, &n)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating notifications: %v", err)
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (a *PostgresAdapter) CountUnreadNotifications(ctx context.Context, userID int) (int, error) {
+	var count int
+	err := a.pool.QueryRow(ctx, `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&count)
+	if err != nil {
+		log.Printf("Error counting unread notifications: %v", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+func (a *PostgresAdapter) MarkNotificationsRead(ctx context.Context, userID int, ids []int64) (int, error) {
+	query := `UPDATE notifications SET read_at = CURRENT_TIMESTAMP
+              WHERE user_id = $1 AND read_at IS NULL AND (cardinality($2::bigint[]) = 0 OR id = ANY($2))`
+
+	if ids == nil {
+		ids = []int64{}
+	}
+	tag, err := a.pool.Exec(ctx, query, userID, ids)
+	if err != nil {
+		log.Printf("Error marking notifications read: %v", err)
+		return 0, err
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+func (a *PostgresAdapter) GetNotificationPreference(ctx context.Context, userID int, notificationType string) (ports.NotificationPreference, error) {
+	pref := ports.NotificationPreference{Type: notificationType}
+
+	err := a.pool.QueryRow(ctx, `SELECT in_app, email FROM notification_preferences WHERE user_id = $1 AND type = $2`,
+		userID, notificationType).Scan(&pref.InApp, &pref.Email)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ports.DefaultNotificationPreference(notificationType), nil
+		}
+		log.Printf("Error getting notification preference: %v", err)
+		return pref, err
+	}
+
+	return pref, nil
+}
+
+func (a *PostgresAdapter) GetNotificationPreferences(ctx context.Context, userID int) ([]ports.NotificationPreference, error) {
+	rows, err := a.pool.Query(ctx, `SELECT type, in_app, email FROM notification_preferences WHERE user_id = $1`, userID)
+	if err != nil {
+		log.Printf("Error querying notification preferences: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	saved := make(map[string]ports.NotificationPreference)
+	for rows.Next() {
+		var p ports.NotificationPreference
+		if err := rows.Scan(&p.Type, &p.InApp, &p.Email); err != nil {
+			log.Printf("Error scanning notification preference: %v", err)
+			return nil, err
+		}
+		saved[p.Type] = p
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Отдаем все известные типы, недостающие -> по умолчанию
+	prefs := make([]ports.NotificationPreference, 0, len(ports.NotificationTypes))
+	for _, t := range ports.NotificationTypes {
+		if p, ok := saved[t]; ok {
+			prefs = append(prefs, p)
+		} else {
+			prefs = append(prefs, ports.DefaultNotificationPreference(t))
+		}
+	}
+
+	return prefs, nil
+}
+
+func (a *PostgresAdapter) SetNotificationPreference(ctx context.Context, userID int, pref ports.NotificationPreference) error {
+	query := `INSERT INTO notification_preferences (user_id, type, in_app, email)
+              VALUES ($1, $2, $3, $4)
+              ON CONFLICT (user_id, type) DO UPDATE SET in_app = EXCLUDED.in_app, email = EXCLUDED.email`
+
+	if _, err := a.pool.Exec(ctx, query, userID, pref.Type, pref.InApp, pref.Email); err != nil {
+		log.Printf("Error saving notification preference: %v", err)
+		return err
+	}
+
+	return nil
+}
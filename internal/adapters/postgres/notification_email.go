@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+func (a *PostgresAdapter) EnqueueNotificationEmail(ctx context.Context, n *ports.Notification) error {
+	query := `INSERT INTO notification_emails (user_id, type, message) VALUES ($1, $2, $3)`
+	if _, err := a.pool.Exec(ctx, query, n.UserID, n.Type, n.Message); err != nil {
+		if hasPgCode(err, pgForeignKeyViolation) {
+			return errs.ErrNotFound
+		}
+		log.Printf("Error enqueueing notification email: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (a *PostgresAdapter) ClaimNotificationEmails(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*ports.NotificationEmail, error) {
+	// SKIP LOCKED -> строки, которые сейчас забирает другой экземпляр, пропускаем.
+	// Сама отправка идет уже после этого запроса, блокировки на время доставки не держим
+	query := `UPDATE notification_emails e SET status = 'sending', locked_until = $3, attempts = e.attempts + 1
+              FROM users u
+              WHERE u.id = e.user_id AND e.id IN (
+                  SELECT id FROM notification_emails
+                  WHERE (status = 'pending' AND due_at <= $1) OR (status = 'sending' AND locked_until <= $1)
+                  ORDER BY due_at
+                  LIMIT $2
+                  FOR UPDATE SKIP LOCKED
+              )
+              RETURNING e.id, e.user_id, u.email, e.type, e.message, e.attempts`
+
+	rows, err := a.pool.Query(ctx, query, now, limit, now.Add(lease))
+	if err != nil {
+		log.Printf("Error claiming notification emails: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	claimed := make([]*ports.NotificationEmail, 0)
+	for rows.Next() {
+		var e ports.NotificationEmail
+		if err := rows.Scan(&e.ID, &e.UserID, &e.To, &e.Type, &e.Message, &e.Attempts); err != nil {
+			log.Printf("Error scanning notification email: %v", err)
+			return nil, err
+		}
+		claimed = append(claimed, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating claimed notification emails: %v", err)
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+func (a *PostgresAdapter) MarkNotificationEmailSent(ctx context.Context, id int64, attempt int) error {
+	query := `UPDATE notification_emails SET status = 'sent', locked_until = NULL, sent_at = CURRENT_TIMESTAMP
+              WHERE id = $1 AND status = 'sending' AND attempts = $2`
+	if _, err := a.pool.Exec(ctx, query, id, attempt); err != nil {
+		log.Printf("Error marking notification email sent: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (a *PostgresAdapter) RetryNotificationEmail(ctx context.Context, id int64, attempt int, lastError string, retryAt time.Time, maxAttempts int) error {
+	query := `UPDATE notification_emails SET
+                  status = CASE WHEN attempts >= $5 THEN 'failed' ELSE 'pending' END,
+                  locked_until = NULL,
+                  last_error = $3,
+                  due_at = $4
+              WHERE id = $1 AND status = 'sending' AND attempts = $2`
+	if _, err := a.pool.Exec(ctx, query, id, attempt, lastError, retryAt, maxAttempts); err != nil {
+		log.Printf("Error rescheduling notification email: %v", err)
+		return err
+	}
+	return nil
+}
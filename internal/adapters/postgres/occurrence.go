@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+func (a *PostgresAdapter) GetOccurrenceOverrides(ctx context.Context, eventIDs []int) ([]*ports.OccurrenceOverride, error) {
+	query := `SELECT o.event_id, o.original_starts_at, o.cancelled_at, o.title, o.description, o.location, o.starts_at,
+                     o.movie_set, o.movie_id, COALESCE(m.title, ''), COALESCE(m.runtime_minutes, 0), o.sequence, o.updated_at
+              FROM event_occurrences o
+              LEFT JOIN movies m ON m.id = o.movie_id
+              WHERE o.event_id = ANY($1)
+              ORDER BY o.event_id, o.original_starts_at`
+
+	rows, err := a.pool.Query(ctx, query, eventIDs)
+	if err != nil {
+		log.Printf("Error querying occurrence overrides: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	overrides := make([]*ports.OccurrenceOverride, 0)
+	for rows.Next() {
+		var o ports.OccurrenceOverride
+		err := rows.Scan(&o.EventID, &o.OriginalStartsAt, &o.CancelledAt, &o.Title, &o.Description, &o.Location, &o.StartsAt,
+			&o.MovieSet, &o.MovieID, &o.MovieTitle, &o.MovieRuntime, &o.Sequence, &o.UpdatedAt)
+		if err != nil {
+			log.Printf("Error scanning occurrence override: %v", err)
+			return nil, err
+		}
+		overrides = append(overrides, &o)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating occurrence overrides: %v", err)
+		return nil, err
+	}
+
+	return overrides, nil
+}
+
+func (a *PostgresAdapter) SaveOccurrenceOverride(ctx context.Context, o *ports.OccurrenceOverride) error {
+	query := `INSERT INTO event_occurrences (event_id, original_starts_at, cancelled_at, title, description, location, starts_at,
+                                             movie_set, movie_id)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+              ON CONFLICT (event_id, original_starts_at) DO UPDATE SET
+                  cancelled_at = EXCLUDED.cancelled_at,
+                  title = EXCLUDED.title,
+                  description = EXCLUDED.description,
+                  location = EXCLUDED.location,
+                  starts_at = EXCLUDED.starts_at,
+                  movie_set = EXCLUDED.movie_set,
+                  movie_id = EXCLUDED.movie_id,
+                  sequence = event_occurrences.sequence + 1,
+                  updated_at = CURRENT_TIMESTAMP
+              RETURNING sequence, updated_at`
+
+	err := a.pool.QueryRow(ctx, query, o.EventID, o.OriginalStartsAt, o.CancelledAt, o.Title, o.Description, o.Location,
+		o.StartsAt, o.MovieSet, o.MovieID).Scan(&o.Sequence, &o.UpdatedAt)
+	if err != nil {
+		if hasPgCode(err, pgForeignKeyViolation) {
+			return errs.ErrNotFound
+		}
+		log.Printf("Error saving occurrence override: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) DeleteOccurrenceOverrides(ctx context.Context, eventID int, from time.Time) error {
+	_, err := a.pool.Exec(ctx, `DELETE FROM event_occurrences WHERE event_id = $1 AND original_starts_at >= $2`, eventID, from)
+	if err != nil {
+		log.Printf("Error deleting occurrence overrides: %v", err)
+		return err
+	}
+	return nil
+}
+
+// SplitEventSeries -> под блокировкой старой серии, чтобы ответы не менялись, пока копируем приглашения
+func (a *PostgresAdapter) SplitEventSeries(ctx context.Context, old, next *ports.Event, from time.Time, moveOverrides bool) ([]int, error) {
+	var promoted []int
+	err := a.withEventLock(ctx, old.ID, func(tx pgx.Tx, locked lockedEvent) error {
+		if locked.status == ports.EventCancelled {
+			return fmt.Errorf("%w: a cancelled event cannot be changed", errs.ErrConflict)
+		}
+
+		query := `UPDATE events SET
+                      recurrence = $2,
+                      recurrence_end = $3,
+                      sequence = sequence + 1,
+                      updated_at = CURRENT_TIMESTAMP
+                  WHERE id = $1
+                  RETURNING updated_at`
+		if err := tx.QueryRow(ctx, query, old.ID, old.Recurrence, old.RecurrenceEnd).Scan(&old.UpdatedAt); err != nil {
+			log.Printf("Error truncating event series: %v", err)
+			return err
+		}
+
+		err := tx.QueryRow(ctx, insertEvent, next.Host.ID, next.Title, next.Description, next.StartsAt, next.Timezone, next.Location,
+			next.Capacity, next.MovieID, next.Recurrence, next.RecurrenceEnd).Scan(&next.ID, &next.Status, &next.CreatedAt, &next.UpdatedAt)
+		if err != nil {
+			if hasPgCode(err, pgForeignKeyViolation) {
+				return errs.ErrNotFound
+			}
+			log.Printf("Error creating event series: %v", err)
+			return err
+		}
+
+		// Гости остаются у старой серии, поэтому считаем только приглашенных
+		var going int
+		query = `INSERT INTO event_invitations (event_id, user_id, invited_by, invited_at, rsvp, responded_at, waitlisted_at)
+                 SELECT $2, user_id, invited_by, invited_at, rsvp, responded_at, waitlisted_at
+                 FROM event_invitations WHERE event_id = $1`
+		if _, err := tx.Exec(ctx, query, old.ID, next.ID); err != nil {
+			log.Printf("Error copying event invitations: %v", err)
+			return err
+		}
+		err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM event_invitations WHERE event_id = $1 AND rsvp = 'going'`, next.ID).Scan(&going)
+		if err != nil {
+			log.Printf("Error counting event attendees: %v", err)
+			return err
+		}
+		if next.Capacity > 0 && freeSeats(next.Capacity, going) < 0 {
+			return fmt.Errorf("%w: capacity is lower than the number of people going", errs.ErrConflict)
+		}
+
+		if moveOverrides {
+			_, err := tx.Exec(ctx, `UPDATE event_occurrences SET event_id = $2 WHERE event_id = $1 AND original_starts_at > $3`,
+				old.ID, next.ID, from)
+			if err != nil {
+				log.Printf("Error moving occurrence overrides: %v", err)
+				return err
+			}
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM event_occurrences WHERE event_id = $1 AND original_starts_at >= $2`, old.ID, from); err != nil {
+			log.Printf("Error deleting occurrence overrides: %v", err)
+			return err
+		}
+
+		promoted, err = promoteFromWaitlist(ctx, tx, next.ID, seatsToPromote(next.Capacity, going))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return promoted, nil
+}
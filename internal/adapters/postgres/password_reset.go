@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turysbekovg/movie-planner/internal/errs"
+)
+
+func (a *PostgresAdapter) CreatePasswordResetToken(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	query := `INSERT INTO password_reset_tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3)`
+
+	if _, err := a.pool.Exec(ctx, query, userID, tokenHash, expiresAt); err != nil {
+		log.Printf("Error creating password reset token: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) ResetPasswordWithToken(ctx context.Context, tokenHash, passwordHash string) (int, error) {
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// UPDATE ... WHERE used_at IS NULL гарантирует, что токен сработает только один раз,
+	// даже если два запроса придут одновременно
+	var userID int
+	query := `UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP
+              WHERE token_hash = $1 AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+              RETURNING user_id`
+	if err := tx.QueryRow(ctx, query, tokenHash).Scan(&userID); err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, errs.ErrNotFound
+		}
+		log.Printf("Error consuming password reset token: %v", err)
+		return 0, err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE users SET password_hash = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`, passwordHash, userID); err != nil {
+		log.Printf("Error resetting password: %v", err)
+		return 0, err
+	}
+
+	// Остальные выданные этому пользователю токены больше не нужны
+	if _, err := tx.Exec(ctx, `UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND used_at IS NULL`, userID); err != nil {
+		log.Printf("Error revoking password reset tokens: %v", err)
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,294 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const pollSelect = `SELECT p.id, p.event_id, p.group_id, p.created_by, p.title, p.method, p.opens_at, p.closes_at, p.created_at,
+                           (SELECT COUNT(*) FROM poll_ballots b WHERE b.poll_id = p.id) +
+                           (SELECT COUNT(*) FROM poll_guest_ballots g WHERE g.poll_id = p.id)
+                    FROM polls p`
+
+func scanPoll(row pgx.Row) (*ports.Poll, error) {
+	var p ports.Poll
+	err := row.Scan(&p.ID, &p.EventID, &p.GroupID, &p.CreatedBy, &p.Title, &p.Method, &p.OpensAt, &p.ClosesAt, &p.CreatedAt, &p.BallotCount)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (a *PostgresAdapter) CreatePoll(ctx context.Context, p *ports.Poll) error {
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `INSERT INTO polls (event_id, group_id, created_by, title, method, opens_at, closes_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)
+              RETURNING id, created_at`
+
+	err = tx.QueryRow(ctx, query, p.EventID, p.GroupID, p.CreatedBy, p.Title, p.Method, p.OpensAt, p.ClosesAt).Scan(&p.ID, &p.CreatedAt)
+	if err != nil {
+		log.Printf("Error creating poll: %v", err)
+		return err
+	}
+
+	for i, c := range p.Candidates {
+		c.Position = i + 1
+		_, err := tx.Exec(ctx, `INSERT INTO poll_candidates (poll_id, movie_id, position) VALUES ($1, $2, $3)`,
+			p.ID, c.MovieID, c.Position)
+		if err != nil {
+			if hasPgCode(err, pgForeignKeyViolation) {
+				return fmt.Errorf("%w: movie %d", errs.ErrNotFound, c.MovieID)
+			}
+			log.Printf("Error adding poll candidate: %v", err)
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (a *PostgresAdapter) GetPoll(ctx context.Context, id int) (*ports.Poll, error) {
+	p, err := scanPoll(a.pool.QueryRow(ctx, pollSelect+` WHERE p.id = $1`, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.ErrNotFound
+		}
+		log.Printf("Error getting poll: %v", err)
+		return nil, err
+	}
+
+	if p.Candidates, err = a.getPollCandidates(ctx, id); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (a *PostgresAdapter) GetEventPolls(ctx context.Context, eventID int) ([]*ports.Poll, error) {
+	return a.queryPolls(ctx, pollSelect+` WHERE p.event_id = $1 ORDER BY p.created_at, p.id`, eventID)
+}
+
+func (a *PostgresAdapter) GetGroupPolls(ctx context.Context, groupID int) ([]*ports.Poll, error) {
+	return a.queryPolls(ctx, pollSelect+` WHERE p.group_id = $1 ORDER BY p.created_at, p.id`, groupID)
+}
+
+func (a *PostgresAdapter) queryPolls(ctx context.Context, query string, args ...any) ([]*ports.Poll, error) {
+	rows, err := a.pool.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("Error querying polls: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	polls := make([]*ports.Poll, 0)
+	for rows.Next() {
+		p, err := scanPoll(rows)
+		if err != nil {
+			log.Printf("Error scanning poll row: %v", err)
+			return nil, err
+		}
+		polls = append(polls, p)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating poll rows: %v", err)
+		return nil, err
+	}
+
+	for _, p := range polls {
+		if p.Candidates, err = a.getPollCandidates(ctx, p.ID); err != nil {
+			return nil, err
+		}
+	}
+	return polls, nil
+}
+
+func (a *PostgresAdapter) getPollCandidates(ctx context.Context, pollID int) ([]*ports.PollCandidate, error) {
+	query := `SELECT c.movie_id, m.title, c.position
+              FROM poll_candidates c JOIN movies m ON m.id = c.movie_id
+              WHERE c.poll_id = $1
+              ORDER BY c.position`
+
+	rows, err := a.pool.Query(ctx, query, pollID)
+	if err != nil {
+		log.Printf("Error querying poll candidates: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	candidates := make([]*ports.PollCandidate, 0)
+	for rows.Next() {
+		var c ports.PollCandidate
+		if err := rows.Scan(&c.MovieID, &c.Title, &c.Position); err != nil {
+			log.Printf("Error scanning poll candidate: %v", err)
+			return nil, err
+		}
+		candidates = append(candidates, &c)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating poll candidates: %v", err)
+		return nil, err
+	}
+
+	return candidates, nil
+}
+
+func (a *PostgresAdapter) ClosePoll(ctx context.Context, id int) error {
+	// Еще не открытый опрос закрывается "пустым": opens_at сдвигаем назад, чтобы выполнялось closes_at > opens_at
+	query := `UPDATE polls SET
+                  closes_at = CURRENT_TIMESTAMP,
+                  opens_at = LEAST(opens_at, CURRENT_TIMESTAMP - INTERVAL '1 microsecond'),
+                  closed_announced_at = CURRENT_TIMESTAMP
+              WHERE id = $1 AND closes_at > CURRENT_TIMESTAMP`
+
+	tag, err := a.pool.Exec(ctx, query, id)
+	if err != nil {
+		log.Printf("Error closing poll: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		if _, err := a.GetPoll(ctx, id); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: the poll is already closed", errs.ErrConflict)
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) ClaimClosedPolls(ctx context.Context, limit int) ([]int, error) {
+	query := `UPDATE polls SET closed_announced_at = CURRENT_TIMESTAMP
+              WHERE id IN (
+                  SELECT id FROM polls
+                  WHERE closed_announced_at IS NULL AND closes_at <= CURRENT_TIMESTAMP
+                  ORDER BY closes_at
+                  LIMIT $1
+                  FOR UPDATE SKIP LOCKED
+              )
+              RETURNING id`
+
+	rows, err := a.pool.Query(ctx, query, limit)
+	if err != nil {
+		log.Printf("Error claiming closed polls: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			log.Printf("Error scanning closed poll: %v", err)
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating closed polls: %v", err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (a *PostgresAdapter) GetOpenPollsByCreator(ctx context.Context, userID int) ([]int, error) {
+	query := `SELECT id FROM polls
+              WHERE created_by = $1 AND closes_at > CURRENT_TIMESTAMP
+              ORDER BY id`
+
+	rows, err := a.pool.Query(ctx, query, userID)
+	if err != nil {
+		log.Printf("Error getting open polls by creator: %v", err)
+		return nil, err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		log.Printf("Error scanning open polls: %v", err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (a *PostgresAdapter) SaveBallot(ctx context.Context, pollID, userID int, choices []int) error {
+	// Проверка "опрос открыт" внутри того же запроса, чтобы бюллетень не проскочил после закрытия
+	query := `INSERT INTO poll_ballots (poll_id, user_id, choices)
+              SELECT $1, $2, $3 FROM polls
+              WHERE id = $1 AND opens_at <= CURRENT_TIMESTAMP AND closes_at > CURRENT_TIMESTAMP
+              ON CONFLICT (poll_id, user_id) DO UPDATE SET choices = EXCLUDED.choices, updated_at = CURRENT_TIMESTAMP`
+
+	tag, err := a.pool.Exec(ctx, query, pollID, userID, choices)
+	if err != nil {
+		log.Printf("Error saving ballot: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: the poll is not open for voting", errs.ErrConflict)
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) GetBallot(ctx context.Context, pollID, userID int) (*ports.Ballot, error) {
+	var b ports.Ballot
+	err := a.pool.QueryRow(ctx, `SELECT poll_id, user_id, choices, cast_at, updated_at FROM poll_ballots WHERE poll_id = $1 AND user_id = $2`,
+		pollID, userID).Scan(&b.PollID, &b.UserID, &b.Choices, &b.CastAt, &b.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.ErrNotFound
+		}
+		log.Printf("Error getting ballot: %v", err)
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+func (a *PostgresAdapter) GetBallots(ctx context.Context, pollID int) ([]*ports.Ballot, error) {
+	return a.queryBallots(ctx, `SELECT poll_id, user_id, choices, cast_at, updated_at FROM poll_ballots WHERE poll_id = $1
+                                UNION ALL
+                                SELECT poll_id, 0, choices, cast_at, updated_at FROM poll_guest_ballots WHERE poll_id = $1
+                                ORDER BY 2, 4`, pollID)
+}
+
+func (a *PostgresAdapter) GetBallotsByUser(ctx context.Context, userID int) ([]*ports.Ballot, error) {
+	return a.queryBallots(ctx, `SELECT poll_id, user_id, choices, cast_at, updated_at FROM poll_ballots
+                                WHERE user_id = $1 ORDER BY poll_id`, userID)
+}
+
+func (a *PostgresAdapter) queryBallots(ctx context.Context, query string, args ...any) ([]*ports.Ballot, error) {
+	rows, err := a.pool.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("Error querying ballots: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	ballots := make([]*ports.Ballot, 0)
+	for rows.Next() {
+		var b ports.Ballot
+		if err := rows.Scan(&b.PollID, &b.UserID, &b.Choices, &b.CastAt, &b.UpdatedAt); err != nil {
+			log.Printf("Error scanning ballot: %v", err)
+			return nil, err
+		}
+		ballots = append(ballots, &b)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating ballots: %v", err)
+		return nil, err
+	}
+
+	return ballots, nil
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"strings"
 
@@ -21,12 +22,69 @@ func NewPostgresAdapter(pool *pgxpool.Pool) *PostgresAdapter {
 	return &PostgresAdapter{pool: pool}
 }
 
+// movieColumns -> колонки фильма в том порядке, в котором их читает scanMovie.
+// Колонки с префиксом таблицы, чтобы их можно было использовать и в JOIN
+const movieColumns = `movies.id, movies.title, movies.overview, movies.release_date, movies.rating, movies.poster_url,
+       movies.recommendations, movies.runtime_minutes, movies.genres, movies.community_rating, movies.community_votes,
+       movies.original_language, movies.certification`
+
+// movieRow -> приемник для колонок movieColumns. Нужен, когда фильм читается
+// вместе с другими колонками (например, в JOIN со списком пользователя)
+type movieRow struct {
+	m               ports.Movie
+	recommendations string
+	genres          string
+}
+
+func (r *movieRow) dest() []any {
+	return []any{
+		&r.m.ID,
+		&r.m.Title,
+		&r.m.Overview,
+		&r.m.ReleaseDate,
+		&r.m.Rating,
+		&r.m.PosterURL,
+		&r.recommendations, // Сначала читаем в строку
+		&r.m.Runtime,
+		&r.genres,
+		&r.m.CommunityRating,
+		&r.m.CommunityVotes,
+		&r.m.Language,
+		&r.m.Certification,
+	}
+}
+
+func (r *movieRow) movie() *ports.Movie {
+	m := r.m
+	m.Recommendations = strings.Split(r.recommendations, ",")
+	m.Genres = splitList(r.genres)
+	return &m
+}
+
+// scanMovie -> читает одну строку с колонками movieColumns (подходит и для QueryRow, и для Rows)
+func scanMovie(row pgx.Row) (*ports.Movie, error) {
+	var mr movieRow
+	if err := row.Scan(mr.dest()...); err != nil {
+		return nil, err
+	}
+	return mr.movie(), nil
+}
+
+// splitList -> как strings.Split, но пустая строка дает пустой список, а не [""]
+func splitList(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, ",")
+}
+
 func (a *PostgresAdapter) CreateMovie(ctx context.Context, movie *ports.Movie) (int, error) {
 	var id int
 
 	// $1, $2, -> это плейсхолдеры для сейф вставки переменных в запрос (защита от SQL-инъекций)
-	query := `INSERT INTO movies (title, overview, release_date, rating, poster_url, recommendations) 
-              VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	query := `INSERT INTO movies (title, overview, release_date, rating, poster_url, recommendations, runtime_minutes, genres,
+                                  original_language, certification) 
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
 
 	err := a.pool.QueryRow(ctx, query,
 		movie.Title,
@@ -35,6 +93,10 @@ func (a *PostgresAdapter) CreateMovie(ctx context.Context, movie *ports.Movie) (
 		movie.Rating,
 		movie.PosterURL,
 		strings.Join(movie.Recommendations, ","),
+		movie.Runtime,
+		strings.Join(movie.Genres, ","),
+		movie.Language,
+		movie.Certification,
 	).Scan(&id) // Для чтения и записи id
 
 	if err != nil {
@@ -46,22 +108,9 @@ func (a *PostgresAdapter) CreateMovie(ctx context.Context, movie *ports.Movie) (
 }
 
 func (a *PostgresAdapter) GetMovieByID(ctx context.Context, id int) (*ports.Movie, error) {
-	var m ports.Movie
-	var recommendations string
-
-	query := `SELECT id, title, overview, release_date, rating, poster_url, recommendations 
-              FROM movies WHERE id = $1`
-
-	err := a.pool.QueryRow(ctx, query, id).Scan(
-		&m.ID,
-		&m.Title,
-		&m.Overview,
-		&m.ReleaseDate,
-		&m.Rating,
-		&m.PosterURL,
-		&recommendations, // Сначала читаем в строку
-	)
+	query := `SELECT ` + movieColumns + ` FROM movies WHERE id = $1`
 
+	m, err := scanMovie(a.pool.QueryRow(ctx, query, id))
 	if err != nil {
 		// pgx.ErrNoRows -> ошибка, которую pgx возвращает, если SELECT не нашел ни одной строки
 		if err == pgx.ErrNoRows {
@@ -71,9 +120,7 @@ func (a *PostgresAdapter) GetMovieByID(ctx context.Context, id int) (*ports.Movi
 		return nil, err
 	}
 
-	m.Recommendations = strings.Split(recommendations, ",")
-
-	return &m, nil
+	return m, nil
 }
 
 func (a *PostgresAdapter) UpdateMovie(ctx context.Context, id int, movie *ports.Movie) error {
@@ -84,8 +131,12 @@ func (a *PostgresAdapter) UpdateMovie(ctx context.Context, id int, movie *ports.
                   rating = $4, 
                   poster_url = $5, 
                   recommendations = $6,
+                  runtime_minutes = $7,
+                  genres = $8,
+                  original_language = $9,
+                  certification = $10,
                   updated_at = CURRENT_TIMESTAMP
-              WHERE id = $7`
+              WHERE id = $11`
 
 	// a.pool.Exec -> выполняет запрос, который не возвращает строк (как UPDATE и тп)
 	_, err := a.pool.Exec(ctx, query,
@@ -95,6 +146,10 @@ func (a *PostgresAdapter) UpdateMovie(ctx context.Context, id int, movie *ports.
 		movie.Rating,
 		movie.PosterURL,
 		strings.Join(movie.Recommendations, ","),
+		movie.Runtime,
+		strings.Join(movie.Genres, ","),
+		movie.Language,
+		movie.Certification,
 		id,
 	)
 
@@ -107,52 +162,101 @@ func (a *PostgresAdapter) UpdateMovie(ctx context.Context, id int, movie *ports.
 }
 
 func (a *PostgresAdapter) DeleteMovie(ctx context.Context, id int) error {
-	query := `DELETE FROM movies WHERE id = $1`
-
-	_, err := a.pool.Exec(ctx, query, id)
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
 
+	// Сначала блокируем фильм: пока он заблокирован, в списки его уже не добавят.
+	// Затем -> списки с этим фильмом, как при любом изменении позиций (по id, чтобы не было взаимных блокировок)
+	if _, err := tx.Exec(ctx, `SELECT id FROM movies WHERE id = $1 FOR UPDATE`, id); err != nil {
+		log.Printf("Error locking movie %d: %v", id, err)
+		return err
+	}
+	rows, err := tx.Query(ctx, `SELECT id FROM user_lists
+                                WHERE id IN (SELECT list_id FROM list_entries WHERE movie_id = $1)
+                                ORDER BY id FOR UPDATE`, id)
 	if err != nil {
+		log.Printf("Error locking lists of movie %d: %v", id, err)
+		return err
+	}
+	listIDs := make([]int, 0)
+	for rows.Next() {
+		var listID int
+		if err := rows.Scan(&listID); err != nil {
+			rows.Close()
+			log.Printf("Error scanning list id: %v", err)
+			return err
+		}
+		listIDs = append(listIDs, listID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("Error locking lists of movie %d: %v", id, err)
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM movies WHERE id = $1`, id); err != nil {
+		// Кандидатов опроса не удаляем каскадом, иначе изменится подсчет уже отданных голосов
+		if hasPgCode(err, pgForeignKeyViolation) {
+			return fmt.Errorf("%w: the movie is a candidate in a poll", errs.ErrConflict)
+		}
 		log.Printf("Error deleting movie: %v", err)
 		return err
 	}
 
-	return nil
+	// Записи списков удалились каскадом -> закрываем "дырки", чтобы позиции остались 1..N
+	if len(listIDs) > 0 {
+		query := `UPDATE list_entries e SET position = r.position
+                  FROM (SELECT list_id, movie_id, ROW_NUMBER() OVER (PARTITION BY list_id ORDER BY position) AS position
+                        FROM list_entries WHERE list_id = ANY($1)) r
+                  WHERE e.list_id = r.list_id AND e.movie_id = r.movie_id AND e.position <> r.position`
+		if _, err := tx.Exec(ctx, query, listIDs); err != nil {
+			log.Printf("Error compacting list positions: %v", err)
+			return err
+		}
+		if _, err := tx.Exec(ctx, `UPDATE user_lists SET updated_at = CURRENT_TIMESTAMP WHERE id = ANY($1)`, listIDs); err != nil {
+			log.Printf("Error touching lists: %v", err)
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
 }
 
 func (a *PostgresAdapter) GetAllMovies(ctx context.Context) ([]*ports.Movie, error) {
+	query := `SELECT ` + movieColumns + ` FROM movies`
 
-	movies := make([]*ports.Movie, 0)
+	return a.queryMovies(ctx, query)
+}
+
+func (a *PostgresAdapter) GetMoviesByIDs(ctx context.Context, ids []int) ([]*ports.Movie, error) {
+	query := `SELECT ` + movieColumns + ` FROM movies WHERE id = ANY($1) ORDER BY id`
 
-	query := `SELECT id, title, overview, release_date, rating, poster_url, recommendations FROM movies`
+	return a.queryMovies(ctx, query, ids)
+}
+
+// queryMovies -> выполняет запрос, который возвращает строки с колонками movieColumns
+func (a *PostgresAdapter) queryMovies(ctx context.Context, query string, args ...any) ([]*ports.Movie, error) {
+	movies := make([]*ports.Movie, 0)
 
-	rows, err := a.pool.Query(ctx, query)
+	rows, err := a.pool.Query(ctx, query, args...)
 	if err != nil {
-		log.Printf("Error querying all movies: %v", err)
+		log.Printf("Error querying movies: %v", err)
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var m ports.Movie
-		var recommendations string
-
-		err := rows.Scan(
-			&m.ID,
-			&m.Title,
-			&m.Overview,
-			&m.ReleaseDate,
-			&m.Rating,
-			&m.PosterURL,
-			&recommendations,
-		)
+		m, err := scanMovie(rows)
 		if err != nil {
 			log.Printf("Error scanning movie row: %v", err)
 			return nil, err
 		}
 
-		m.Recommendations = strings.Split(recommendations, ",")
-
-		movies = append(movies, &m)
+		movies = append(movies, m)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -169,6 +273,9 @@ func (a *PostgresAdapter) CreateUser(ctx context.Context, user *ports.User) (int
 
 	err := a.pool.QueryRow(ctx, query, user.Email, user.PasswordHash).Scan(&id)
 	if err != nil {
+		if hasPgCode(err, pgUniqueViolation) {
+			return 0, errs.ErrConflict
+		}
 		log.Printf("Error creating user: %v", err)
 		return 0, err
 	}
@@ -176,17 +283,33 @@ func (a *PostgresAdapter) CreateUser(ctx context.Context, user *ports.User) (int
 	return id, nil
 }
 
-func (a *PostgresAdapter) GetUserByEmail(ctx context.Context, email string) (*ports.User, error) {
-	var u ports.User
-	query := `SELECT id, email, password_hash, created_at FROM users WHERE email = $1`
+const userColumns = `id, email, password_hash, email_verified, display_name, timezone, locale, avatar_url, created_at, updated_at, deletion_scheduled_at`
 
-	err := a.pool.QueryRow(ctx, query, email).Scan(
+func scanUser(row pgx.Row) (*ports.User, error) {
+	var u ports.User
+	err := row.Scan(
 		&u.ID,
 		&u.Email,
 		&u.PasswordHash,
+		&u.EmailVerified,
+		&u.DisplayName,
+		&u.Timezone,
+		&u.Locale,
+		&u.AvatarURL,
 		&u.CreatedAt,
+		&u.UpdatedAt,
+		&u.DeletionScheduledAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (a *PostgresAdapter) GetUserByEmail(ctx context.Context, email string) (*ports.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
 
+	u, err := scanUser(a.pool.QueryRow(ctx, query, email))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, errs.ErrNotFound
@@ -195,5 +318,82 @@ func (a *PostgresAdapter) GetUserByEmail(ctx context.Context, email string) (*po
 		return nil, err
 	}
 
-	return &u, nil
+	return u, nil
+}
+
+func (a *PostgresAdapter) GetUserByID(ctx context.Context, id int) (*ports.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+
+	u, err := scanUser(a.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.ErrNotFound
+		}
+		log.Printf("Error getting user by ID: %v", err)
+		return nil, err
+	}
+
+	return u, nil
+}
+
+func (a *PostgresAdapter) UpdateUser(ctx context.Context, user *ports.User) error {
+	query := `UPDATE users SET
+                  display_name = $1,
+                  timezone = $2,
+                  locale = $3,
+                  avatar_url = $4,
+                  updated_at = CURRENT_TIMESTAMP
+              WHERE id = $5
+              RETURNING updated_at`
+
+	err := a.pool.QueryRow(ctx, query,
+		user.DisplayName,
+		user.Timezone,
+		user.Locale,
+		user.AvatarURL,
+		user.ID,
+	).Scan(&user.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return errs.ErrNotFound
+		}
+		log.Printf("Error updating user: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) UpdatePassword(ctx context.Context, userID int, passwordHash string) error {
+	query := `UPDATE users SET password_hash = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+
+	tag, err := a.pool.Exec(ctx, query, passwordHash, userID)
+	if err != nil {
+		log.Printf("Error updating password: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) SetEmailVerified(ctx context.Context, userID int, email string) error {
+	query := `UPDATE users SET
+                  email_verified = TRUE,
+                  email_verified_at = COALESCE(email_verified_at, CURRENT_TIMESTAMP),
+                  updated_at = CURRENT_TIMESTAMP
+              WHERE id = $1 AND email = $2`
+
+	tag, err := a.pool.Exec(ctx, query, userID, email)
+	if err != nil {
+		log.Printf("Error verifying email: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
 }
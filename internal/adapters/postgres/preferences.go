@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+func (a *PostgresAdapter) GetViewingPreferences(ctx context.Context, userID int) (*ports.ViewingPreferences, error) {
+	query := `SELECT disliked_genres, max_runtime_minutes, min_rating, preferred_languages,
+                     hidden_certifications, hidden_movie_ids, updated_at
+              FROM user_viewing_preferences
+              WHERE user_id = $1`
+
+	var p ports.ViewingPreferences
+	err := a.pool.QueryRow(ctx, query, userID).Scan(
+		&p.DislikedGenres,
+		&p.MaxRuntime,
+		&p.MinRating,
+		&p.PreferredLanguages,
+		&p.HiddenCertifications,
+		&p.HiddenMovieIDs,
+		&p.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return emptyViewingPreferences(), nil
+		}
+		log.Printf("Error getting viewing preferences: %v", err)
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func (a *PostgresAdapter) SaveViewingPreferences(ctx context.Context, userID int, p *ports.ViewingPreferences) error {
+	query := `INSERT INTO user_viewing_preferences (user_id, disliked_genres, max_runtime_minutes, min_rating,
+                                                    preferred_languages, hidden_certifications, hidden_movie_ids)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)
+              ON CONFLICT (user_id) DO UPDATE SET
+                  disliked_genres = EXCLUDED.disliked_genres,
+                  max_runtime_minutes = EXCLUDED.max_runtime_minutes,
+                  min_rating = EXCLUDED.min_rating,
+                  preferred_languages = EXCLUDED.preferred_languages,
+                  hidden_certifications = EXCLUDED.hidden_certifications,
+                  hidden_movie_ids = EXCLUDED.hidden_movie_ids,
+                  updated_at = CURRENT_TIMESTAMP
+              RETURNING updated_at`
+
+	err := a.pool.QueryRow(ctx, query, userID,
+		nonNilStrings(p.DislikedGenres),
+		p.MaxRuntime,
+		p.MinRating,
+		nonNilStrings(p.PreferredLanguages),
+		nonNilStrings(p.HiddenCertifications),
+		nonNilInts(p.HiddenMovieIDs),
+	).Scan(&p.UpdatedAt)
+	if err != nil {
+		log.Printf("Error saving viewing preferences: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func emptyViewingPreferences() *ports.ViewingPreferences {
+	return &ports.ViewingPreferences{
+		DislikedGenres:       []string{},
+		PreferredLanguages:   []string{},
+		HiddenCertifications: []string{},
+		HiddenMovieIDs:       []int{},
+	}
+}
+
+// Колонки NOT NULL, а nil-срез pgx отправит как NULL
+func nonNilStrings(v []string) []string {
+	if v == nil {
+		return []string{}
+	}
+	return v
+}
+
+func nonNilInts(v []int) []int {
+	if v == nil {
+		return []int{}
+	}
+	return v
+}
@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const ratingColumns = `user_id, movie_id, rating, review, created_at, updated_at`
+
+func scanRating(row pgx.Row) (*ports.Rating, error) {
+	var r ports.Rating
+	if err := row.Scan(&r.UserID, &r.MovieID, &r.Value, &r.Review, &r.CreatedAt, &r.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// withMovieLock -> выполняет fn в транзакции, заблокировав строку фильма,
+// и затем пересчитывает community_rating. Блокировка сериализует
+// конкурентные изменения оценок одного фильма, поэтому агрегат всегда сходится.
+func (a *PostgresAdapter) withMovieLock(ctx context.Context, movieID int, fn func(tx pgx.Tx) error) error {
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var id int
+	err = tx.QueryRow(ctx, `SELECT id FROM movies WHERE id = $1 FOR UPDATE`, movieID).Scan(&id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return errs.ErrNotFound
+		}
+		log.Printf("Error locking movie %d: %v", movieID, err)
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	query := `UPDATE movies SET
+                  community_rating = COALESCE(agg.avg_rating, 0),
+                  community_votes = agg.votes
+              FROM (SELECT AVG(rating) AS avg_rating, COUNT(*) AS votes
+                    FROM user_ratings WHERE movie_id = $1) agg
+              WHERE movies.id = $1`
+	if _, err := tx.Exec(ctx, query, movieID); err != nil {
+		log.Printf("Error updating community rating for movie %d: %v", movieID, err)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (a *PostgresAdapter) CreateRating(ctx context.Context, rating *ports.Rating) error {
+	return a.withMovieLock(ctx, rating.MovieID, func(tx pgx.Tx) error {
+		query := `INSERT INTO user_ratings (user_id, movie_id, rating, review)
+                  VALUES ($1, $2, $3, $4)
+                  ON CONFLICT (user_id, movie_id) DO NOTHING
+                  RETURNING created_at, updated_at`
+
+		err := tx.QueryRow(ctx, query, rating.UserID, rating.MovieID, rating.Value, rating.Review).
+			Scan(&rating.CreatedAt, &rating.UpdatedAt)
+		if err != nil {
+			// ON CONFLICT DO NOTHING -> строка не вернулась, значит оценка уже есть
+			if err == pgx.ErrNoRows {
+				return errs.ErrConflict
+			}
+			log.Printf("Error creating rating: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (a *PostgresAdapter) UpdateRating(ctx context.Context, rating *ports.Rating) error {
+	return a.withMovieLock(ctx, rating.MovieID, func(tx pgx.Tx) error {
+		query := `UPDATE user_ratings SET rating = $3, review = $4, updated_at = CURRENT_TIMESTAMP
+                  WHERE user_id = $1 AND movie_id = $2
+                  RETURNING created_at, updated_at`
+
+		err := tx.QueryRow(ctx, query, rating.UserID, rating.MovieID, rating.Value, rating.Review).
+			Scan(&rating.CreatedAt, &rating.UpdatedAt)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return errs.ErrNotFound
+			}
+			log.Printf("Error updating rating: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (a *PostgresAdapter) DeleteRating(ctx context.Context, userID, movieID int) error {
+	return a.withMovieLock(ctx, movieID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `DELETE FROM user_ratings WHERE user_id = $1 AND movie_id = $2`, userID, movieID)
+		if err != nil {
+			log.Printf("Error deleting rating: %v", err)
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return errs.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (a *PostgresAdapter) GetRating(ctx context.Context, userID, movieID int) (*ports.Rating, error) {
+	query := `SELECT ` + ratingColumns + ` FROM user_ratings WHERE user_id = $1 AND movie_id = $2`
+
+	r, err := scanRating(a.pool.QueryRow(ctx, query, userID, movieID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.ErrNotFound
+		}
+		log.Printf("Error getting rating: %v", err)
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// GetReviews -> страница оценок с непустым отзывом (новые сверху) и общее число отзывов
+func (a *PostgresAdapter) GetReviews(ctx context.Context, movieID, limit, offset int) ([]*ports.Rating, int, error) {
+	var total int
+	err := a.pool.QueryRow(ctx, `SELECT COUNT(*) FROM user_ratings WHERE movie_id = $1 AND review <> ''`, movieID).Scan(&total)
+	if err != nil {
+		log.Printf("Error counting reviews: %v", err)
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + ratingColumns + ` FROM user_ratings
+              WHERE movie_id = $1 AND review <> ''
+              ORDER BY created_at DESC, user_id
+              LIMIT $2 OFFSET $3`
+
+	reviews, err := a.queryRatings(ctx, query, movieID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return reviews, total, nil
+}
+
+func (a *PostgresAdapter) GetAllRatings(ctx context.Context) ([]*ports.Rating, error) {
+	query := `SELECT ` + ratingColumns + ` FROM user_ratings ORDER BY user_id, movie_id`
+
+	return a.queryRatings(ctx, query)
+}
+
+func (a *PostgresAdapter) GetRatingsByUser(ctx context.Context, userID int) ([]*ports.Rating, error) {
+	query := `SELECT ` + ratingColumns + ` FROM user_ratings WHERE user_id = $1 ORDER BY movie_id`
+
+	return a.queryRatings(ctx, query, userID)
+}
+
+func (a *PostgresAdapter) queryRatings(ctx context.Context, query string, args ...any) ([]*ports.Rating, error) {
+	ratings := make([]*ports.Rating, 0)
+
+	rows, err := a.pool.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("Error querying ratings: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		r, err := scanRating(rows)
+		if err != nil {
+			log.Printf("Error scanning rating row: %v", err)
+			return nil, err
+		}
+		ratings = append(ratings, r)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating rating rows: %v", err)
+		return nil, err
+	}
+
+	return ratings, nil
+}
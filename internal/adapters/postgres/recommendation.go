@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+// movieNeighboursLock -> ключ advisory-блокировки пересборки movie_neighbours
+const movieNeighboursLock = 26001
+
+func (a *PostgresAdapter) ReplaceMovieNeighbours(ctx context.Context, neighbours map[int][]ports.MovieNeighbour) error {
+	// Старую матрицу удаляем и пишем новую в одной транзакции,
+	// чтобы читатели никогда не видели полупустую таблицу
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Задача пересчета работает на каждом экземпляре. Без блокировки два одновременных
+	// DELETE + COPY вставили бы одни и те же ключи, и более поздний упал бы на первичном ключе
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, movieNeighboursLock); err != nil {
+		log.Printf("Error locking movie neighbours: %v", err)
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM movie_neighbours`); err != nil {
+		log.Printf("Error clearing movie neighbours: %v", err)
+		return err
+	}
+
+	rows := make([][]any, 0)
+	for _, list := range neighbours {
+		for _, n := range list {
+			rows = append(rows, []any{n.MovieID, n.NeighbourID, n.Rank, n.Score, n.CoRatings})
+		}
+	}
+
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"movie_neighbours"},
+		[]string{"movie_id", "neighbour_id", "rank", "score", "co_ratings"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		log.Printf("Error copying movie neighbours: %v", err)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (a *PostgresAdapter) GetMovieNeighbours(ctx context.Context, movieIDs []int) ([]*ports.MovieNeighbour, error) {
+	neighbours := make([]*ports.MovieNeighbour, 0)
+
+	query := `SELECT movie_id, neighbour_id, rank, score, co_ratings
+              FROM movie_neighbours WHERE movie_id = ANY($1)
+              ORDER BY movie_id, rank`
+
+	rows, err := a.pool.Query(ctx, query, movieIDs)
+	if err != nil {
+		log.Printf("Error querying movie neighbours: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var n ports.MovieNeighbour
+		if err := rows.Scan(&n.MovieID, &n.NeighbourID, &n.Rank, &n.Score, &n.CoRatings); err != nil {
+			log.Printf("Error scanning movie neighbour row: %v", err)
+			return nil, err
+		}
+		neighbours = append(neighbours, &n)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating movie neighbour rows: %v", err)
+		return nil, err
+	}
+
+	return neighbours, nil
+}
+
+// GetPopularMovies -> самые оцениваемые фильмы, используются как фолбэк для cold-start пользователей
+func (a *PostgresAdapter) GetPopularMovies(ctx context.Context, limit int) ([]*ports.Movie, error) {
+	query := `SELECT ` + movieColumns + ` FROM movies
+              LEFT JOIN (
+                  SELECT movie_id, COUNT(*) AS votes, AVG(rating) AS avg_rating
+                  FROM user_ratings GROUP BY movie_id
+              ) r ON r.movie_id = movies.id
+              ORDER BY COALESCE(r.votes, 0) DESC, r.avg_rating DESC NULLS LAST, movies.rating DESC, movies.id
+              LIMIT $1`
+
+	return a.queryMovies(ctx, query, limit)
+}
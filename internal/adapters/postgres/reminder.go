@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+func (a *PostgresAdapter) ReplaceEventReminders(ctx context.Context, eventID int, reminders []*ports.Reminder) error {
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Блокируем вечер, чтобы две одновременные пересборки не оставили лишних напоминаний.
+	// Вечера уже нет -> его напоминания удалились каскадом
+	rows, err := tx.Query(ctx, `SELECT id FROM events WHERE id = $1 FOR UPDATE`, eventID)
+	if err != nil {
+		log.Printf("Error locking event %d: %v", eventID, err)
+		return err
+	}
+	found := rows.Next()
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("Error locking event %d: %v", eventID, err)
+		return err
+	}
+	if !found {
+		return nil
+	}
+
+	// Отменяем и уже забранные на отправку (sending): иначе после переноса вечера уйдет напоминание
+	// со старым временем. Забранное напоминание на тот же момент тому же пользователю оставляем,
+	// чтобы оно не ушло второй раз новой строкой
+	userIDs := make([]int, 0, len(reminders))
+	targets := make([]time.Time, 0, len(reminders))
+	for _, r := range reminders {
+		userIDs = append(userIDs, r.UserID)
+		targets = append(targets, r.TargetAt)
+	}
+	query := `UPDATE reminders SET status = 'cancelled', locked_until = NULL
+              WHERE event_id = $1
+                AND (status = 'pending'
+                     OR status = 'sending' AND (user_id, target_at) NOT IN (SELECT * FROM unnest($2::int[], $3::timestamptz[])))`
+	_, err = tx.Exec(ctx, query, eventID, userIDs, targets)
+	if err != nil {
+		log.Printf("Error cancelling event reminders: %v", err)
+		return err
+	}
+
+	// ON CONFLICT -> такое напоминание уже отправлено или отправляется, второй раз не нужно
+	query = `INSERT INTO reminders (user_id, kind, event_id, title, target_at, due_at, expires_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)
+              ON CONFLICT DO NOTHING`
+	for _, r := range reminders {
+		if _, err := tx.Exec(ctx, query, r.UserID, r.Kind, eventID, r.Title, r.TargetAt, r.DueAt, r.ExpiresAt); err != nil {
+			if hasPgCode(err, pgForeignKeyViolation) {
+				return errs.ErrNotFound
+			}
+			log.Printf("Error scheduling event reminder: %v", err)
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (a *PostgresAdapter) ScheduleReleaseReminders(ctx context.Context, days, dueHour int) error {
+	// День выхода считаем по часовому поясу пользователя
+	const releaseStart = `m.release_date::timestamp AT TIME ZONE u.timezone`
+
+	query := `UPDATE reminders r SET status = 'cancelled'
+              WHERE r.kind = 'watchlist_release' AND r.status = 'pending'
+                AND NOT EXISTS (
+                    SELECT 1 FROM watchlist_items w
+                    JOIN movies m ON m.id = w.movie_id
+                    JOIN users u ON u.id = w.user_id
+                    WHERE w.user_id = r.user_id AND w.movie_id = r.movie_id AND ` + releaseStart + ` = r.target_at
+                )`
+	if _, err := a.pool.Exec(ctx, query); err != nil {
+		log.Printf("Error cancelling release reminders: %v", err)
+		return err
+	}
+
+	query = `INSERT INTO reminders (user_id, kind, movie_id, title, target_at, due_at, expires_at)
+             SELECT w.user_id, 'watchlist_release', m.id, m.title, t.target_at,
+                    t.target_at + make_interval(hours => $2::int), t.target_at + INTERVAL '1 day'
+             FROM watchlist_items w
+             JOIN movies m ON m.id = w.movie_id
+             JOIN users u ON u.id = w.user_id
+             CROSS JOIN LATERAL (SELECT ` + releaseStart + ` AS target_at) t
+             WHERE m.release_date >= (CURRENT_TIMESTAMP AT TIME ZONE u.timezone)::date
+               AND m.release_date < (CURRENT_TIMESTAMP AT TIME ZONE u.timezone)::date + $1::int
+             ON CONFLICT DO NOTHING`
+	if _, err := a.pool.Exec(ctx, query, days, dueHour); err != nil {
+		log.Printf("Error scheduling release reminders: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) ClaimDueReminders(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*ports.Reminder, error) {
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// SKIP LOCKED -> строки, которые сейчас забирает другой экземпляр, пропускаем.
+	// Сама отправка идет уже после коммита, блокировки на время доставки не держим
+	query := `SELECT id, user_id, kind, event_id, movie_id, title, target_at, due_at, expires_at, attempts
+              FROM reminders
+              WHERE (status = 'pending' AND due_at <= $1) OR (status = 'sending' AND locked_until <= $1)
+              ORDER BY due_at
+              LIMIT $2
+              FOR UPDATE SKIP LOCKED`
+
+	rows, err := tx.Query(ctx, query, now, limit)
+	if err != nil {
+		log.Printf("Error querying due reminders: %v", err)
+		return nil, err
+	}
+	due := make([]*ports.Reminder, 0)
+	for rows.Next() {
+		var r ports.Reminder
+		err := rows.Scan(&r.ID, &r.UserID, &r.Kind, &r.EventID, &r.MovieID, &r.Title, &r.TargetAt, &r.DueAt, &r.ExpiresAt, &r.Attempts)
+		if err != nil {
+			rows.Close()
+			log.Printf("Error scanning reminder: %v", err)
+			return nil, err
+		}
+		due = append(due, &r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating due reminders: %v", err)
+		return nil, err
+	}
+
+	expired := make([]int64, 0)
+	claimed := make([]*ports.Reminder, 0, len(due))
+	ids := make([]int64, 0, len(due))
+	for _, r := range due {
+		if !r.ExpiresAt.After(now) {
+			expired = append(expired, r.ID)
+			continue
+		}
+		r.Attempts++
+		claimed = append(claimed, r)
+		ids = append(ids, r.ID)
+	}
+
+	if len(expired) > 0 {
+		_, err := tx.Exec(ctx, `UPDATE reminders SET status = 'cancelled', locked_until = NULL WHERE id = ANY($1)`, expired)
+		if err != nil {
+			log.Printf("Error cancelling expired reminders: %v", err)
+			return nil, err
+		}
+	}
+	if len(ids) > 0 {
+		query := `UPDATE reminders SET status = 'sending', locked_until = $2, attempts = attempts + 1 WHERE id = ANY($1)`
+		if _, err := tx.Exec(ctx, query, ids, now.Add(lease)); err != nil {
+			log.Printf("Error claiming reminders: %v", err)
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("Error committing claimed reminders: %v", err)
+		return nil, err
+	}
+	return claimed, nil
+}
+
+func (a *PostgresAdapter) IsReminderClaimed(ctx context.Context, id int64, attempt int) (bool, error) {
+	var claimed bool
+	query := `SELECT EXISTS (SELECT 1 FROM reminders WHERE id = $1 AND status = 'sending' AND attempts = $2)`
+	if err := a.pool.QueryRow(ctx, query, id, attempt).Scan(&claimed); err != nil {
+		log.Printf("Error checking reminder claim: %v", err)
+		return false, err
+	}
+	return claimed, nil
+}
+
+// MarkReminderSent и RetryReminder трогают строку, только пока она забрана этой же попыткой:
+// отмененное напоминание не должно ожить, а забранное заново после аренды -> достается другому экземпляру
+func (a *PostgresAdapter) MarkReminderSent(ctx context.Context, id int64, attempt int) error {
+	query := `UPDATE reminders SET status = 'sent', locked_until = NULL, sent_at = CURRENT_TIMESTAMP
+              WHERE id = $1 AND status = 'sending' AND attempts = $2`
+	if _, err := a.pool.Exec(ctx, query, id, attempt); err != nil {
+		log.Printf("Error marking reminder sent: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (a *PostgresAdapter) RetryReminder(ctx context.Context, id int64, attempt int, lastError string, retryAt time.Time, maxAttempts int) error {
+	query := `UPDATE reminders SET
+                  status = CASE WHEN attempts >= $5 THEN 'failed' ELSE 'pending' END,
+                  locked_until = NULL,
+                  last_error = $3,
+                  due_at = $4
+              WHERE id = $1 AND status = 'sending' AND attempts = $2`
+	if _, err := a.pool.Exec(ctx, query, id, attempt, lastError, retryAt, maxAttempts); err != nil {
+		log.Printf("Error rescheduling reminder: %v", err)
+		return err
+	}
+	return nil
+}
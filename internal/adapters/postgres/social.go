@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+func (a *PostgresAdapter) Follow(ctx context.Context, followerID, followeeID int) error {
+	query := `INSERT INTO user_follows (follower_id, followee_id) VALUES ($1, $2)`
+
+	if _, err := a.pool.Exec(ctx, query, followerID, followeeID); err != nil {
+		if hasPgCode(err, pgUniqueViolation) {
+			return errs.ErrConflict
+		}
+		if hasPgCode(err, pgForeignKeyViolation) {
+			return errs.ErrNotFound
+		}
+		log.Printf("Error following user: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) Unfollow(ctx context.Context, followerID, followeeID int) error {
+	tag, err := a.pool.Exec(ctx, `DELETE FROM user_follows WHERE follower_id = $1 AND followee_id = $2`, followerID, followeeID)
+	if err != nil {
+		log.Printf("Error unfollowing user: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) GetFollowers(ctx context.Context, userID int) ([]*ports.Follow, error) {
+	query := `SELECT u.id, u.display_name, u.avatar_url, f.created_at
+              FROM user_follows f JOIN users u ON u.id = f.follower_id
+              WHERE f.followee_id = $1
+              ORDER BY f.created_at DESC, u.id`
+	return a.queryFollows(ctx, query, userID)
+}
+
+func (a *PostgresAdapter) GetFollowing(ctx context.Context, userID int) ([]*ports.Follow, error) {
+	query := `SELECT u.id, u.display_name, u.avatar_url, f.created_at
+              FROM user_follows f JOIN users u ON u.id = f.followee_id
+              WHERE f.follower_id = $1
+              ORDER BY f.created_at DESC, u.id`
+	return a.queryFollows(ctx, query, userID)
+}
+
+func (a *PostgresAdapter) queryFollows(ctx context.Context, query string, args ...any) ([]*ports.Follow, error) {
+	rows, err := a.pool.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("Error querying follows: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	follows := make([]*ports.Follow, 0)
+	for rows.Next() {
+		var f ports.Follow
+		if err := rows.Scan(&f.User.ID, &f.User.DisplayName, &f.User.AvatarURL, &f.FollowedAt); err != nil {
+			log.Printf("Error scanning follow row: %v", err)
+			return nil, err
+		}
+		follows = append(follows, &f)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating follow rows: %v", err)
+		return nil, err
+	}
+
+	return follows, nil
+}
+
+func (a *PostgresAdapter) CreateActivity(ctx context.Context, activity *ports.Activity) error {
+	query := `INSERT INTO activities (actor_id, type, movie_id, ref_id, payload)
+              VALUES ($1, $2, $3, $4, $5)
+              RETURNING id, created_at`
+
+	payload := "{}"
+	if len(activity.Payload) > 0 {
+		payload = string(activity.Payload)
+	}
+
+	err := a.pool.QueryRow(ctx, query, activity.Actor.ID, activity.Type, activity.MovieID, activity.RefID, payload).
+		Scan(&activity.ID, &activity.CreatedAt)
+	if err != nil {
+		log.Printf("Error creating activity: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) DeleteActivities(ctx context.Context, actorID int, types []string, refID int) error {
+	query := `DELETE FROM activities WHERE actor_id = $1 AND type = ANY($2) AND ref_id = $3`
+
+	if _, err := a.pool.Exec(ctx, query, actorID, types, refID); err != nil {
+		log.Printf("Error deleting activities: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) GetFeed(ctx context.Context, userID int, before int64, limit int) ([]*ports.Activity, error) {
+	// Курсор -> id последней показанной записи. id монотонно растет,
+	// поэтому страницы не "съезжают", даже если за это время появились новые записи
+	query := `SELECT a.id, u.id, u.display_name, u.avatar_url, a.type, a.movie_id, COALESCE(m.title, ''),
+                     a.ref_id, a.payload, a.created_at
+              FROM activities a
+              JOIN user_follows f ON f.followee_id = a.actor_id AND f.follower_id = $1
+              JOIN users u ON u.id = a.actor_id
+              LEFT JOIN movies m ON m.id = a.movie_id
+              WHERE ($2 = 0 OR a.id < $2)
+              ORDER BY a.id DESC
+              LIMIT $3`
+
+	rows, err := a.pool.Query(ctx, query, userID, before, limit)
+	if err != nil {
+		log.Printf("Error getting feed: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*ports.Activity, error) {
+		var act ports.Activity
+		err := row.Scan(&act.ID, &act.Actor.ID, &act.Actor.DisplayName, &act.Actor.AvatarURL, &act.Type,
+			&act.MovieID, &act.MovieTitle, &act.RefID, &act.Payload, &act.CreatedAt)
+		if err != nil {
+			log.Printf("Error scanning activity row: %v", err)
+		}
+		return &act, err
+	})
+}
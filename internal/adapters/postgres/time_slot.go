@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+func (a *PostgresAdapter) AddEventTimeSlots(ctx context.Context, eventID int, slots []*ports.TimeSlot) error {
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `INSERT INTO event_time_slots (event_id, starts_at, ends_at) VALUES ($1, $2, $3)
+              ON CONFLICT (event_id, starts_at, ends_at) DO NOTHING`
+	for _, slot := range slots {
+		if _, err := tx.Exec(ctx, query, eventID, slot.StartsAt, slot.EndsAt); err != nil {
+			if hasPgCode(err, pgForeignKeyViolation) {
+				return errs.ErrNotFound
+			}
+			log.Printf("Error adding event time slot: %v", err)
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (a *PostgresAdapter) GetEventTimeSlots(ctx context.Context, eventID int) ([]*ports.TimeSlot, error) {
+	rows, err := a.pool.Query(ctx, `SELECT id, event_id, starts_at, ends_at, created_at FROM event_time_slots
+                                    WHERE event_id = $1 ORDER BY starts_at, ends_at, id`, eventID)
+	if err != nil {
+		log.Printf("Error querying event time slots: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	slots := make([]*ports.TimeSlot, 0)
+	byID := make(map[int]*ports.TimeSlot)
+	for rows.Next() {
+		slot := &ports.TimeSlot{Responses: make([]*ports.SlotResponse, 0)}
+		if err := rows.Scan(&slot.ID, &slot.EventID, &slot.StartsAt, &slot.EndsAt, &slot.CreatedAt); err != nil {
+			log.Printf("Error scanning event time slot: %v", err)
+			return nil, err
+		}
+		slots = append(slots, slot)
+		byID[slot.ID] = slot
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating event time slots: %v", err)
+		return nil, err
+	}
+
+	query := `SELECT a.slot_id, a.user_id, a.availability, a.updated_at
+              FROM event_slot_availability a JOIN event_time_slots s ON s.id = a.slot_id
+              WHERE s.event_id = $1
+              ORDER BY a.slot_id, a.user_id`
+	rows, err = a.pool.Query(ctx, query, eventID)
+	if err != nil {
+		log.Printf("Error querying slot availability: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var slotID int
+		var r ports.SlotResponse
+		if err := rows.Scan(&slotID, &r.UserID, &r.Availability, &r.UpdatedAt); err != nil {
+			log.Printf("Error scanning slot availability: %v", err)
+			return nil, err
+		}
+		// Вариант мог появиться между двумя запросами -> такие отметки пропускаем
+		if slot, ok := byID[slotID]; ok {
+			slot.Responses = append(slot.Responses, &r)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating slot availability: %v", err)
+		return nil, err
+	}
+
+	return slots, nil
+}
+
+func (a *PostgresAdapter) DeleteEventTimeSlot(ctx context.Context, eventID, slotID int) error {
+	tag, err := a.pool.Exec(ctx, `DELETE FROM event_time_slots WHERE id = $1 AND event_id = $2`, slotID, eventID)
+	if err != nil {
+		log.Printf("Error deleting event time slot: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) SetSlotAvailability(ctx context.Context, eventID, userID int, answers map[int]ports.Availability) error {
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Вариант другого вечера не найдется в SELECT -> ни одной строки не вставится
+	query := `INSERT INTO event_slot_availability (slot_id, user_id, availability)
+              SELECT id, $3, $4 FROM event_time_slots WHERE id = $1 AND event_id = $2
+              ON CONFLICT (slot_id, user_id) DO UPDATE SET availability = EXCLUDED.availability, updated_at = CURRENT_TIMESTAMP`
+	for slotID, availability := range answers {
+		tag, err := tx.Exec(ctx, query, slotID, eventID, userID, availability)
+		if err != nil {
+			log.Printf("Error saving slot availability: %v", err)
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("%w: time slot %d", errs.ErrNotFound, slotID)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (a *PostgresAdapter) GetSlotAvailabilityByUser(ctx context.Context, userID int) ([]*ports.UserSlotAvailability, error) {
+	query := `SELECT s.event_id, s.id, s.starts_at, s.ends_at, a.availability, a.updated_at
+              FROM event_slot_availability a JOIN event_time_slots s ON s.id = a.slot_id
+              WHERE a.user_id = $1
+              ORDER BY s.event_id, s.starts_at, s.id`
+
+	rows, err := a.pool.Query(ctx, query, userID)
+	if err != nil {
+		log.Printf("Error querying user availability: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	answers := make([]*ports.UserSlotAvailability, 0)
+	for rows.Next() {
+		var u ports.UserSlotAvailability
+		if err := rows.Scan(&u.EventID, &u.SlotID, &u.StartsAt, &u.EndsAt, &u.Availability, &u.UpdatedAt); err != nil {
+			log.Printf("Error scanning user availability: %v", err)
+			return nil, err
+		}
+		answers = append(answers, &u)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating user availability: %v", err)
+		return nil, err
+	}
+
+	return answers, nil
+}
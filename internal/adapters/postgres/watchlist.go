@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+// watchlistOrder -> белый список сортировок, в SQL подставляется только значение отсюда
+var watchlistOrder = map[ports.WatchlistSort]string{
+	ports.WatchlistSortAdded:    "w.added_at",
+	ports.WatchlistSortPriority: "w.priority",
+	ports.WatchlistSortRating:   "movies.rating",
+}
+
+func (a *PostgresAdapter) AddToWatchlist(ctx context.Context, item *ports.WatchlistItem) error {
+	query := `INSERT INTO watchlist_items (user_id, movie_id, priority, notes)
+              VALUES ($1, $2, $3, $4) RETURNING added_at`
+
+	err := a.pool.QueryRow(ctx, query, item.UserID, item.MovieID, item.Priority, item.Notes).Scan(&item.AddedAt)
+	if err != nil {
+		if hasPgCode(err, pgUniqueViolation) {
+			return errs.ErrConflict
+		}
+		if hasPgCode(err, pgForeignKeyViolation) {
+			return errs.ErrNotFound
+		}
+		log.Printf("Error adding to watchlist: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) UpdateWatchlistItem(ctx context.Context, item *ports.WatchlistItem) error {
+	query := `UPDATE watchlist_items SET priority = $3, notes = $4
+              WHERE user_id = $1 AND movie_id = $2 RETURNING added_at`
+
+	err := a.pool.QueryRow(ctx, query, item.UserID, item.MovieID, item.Priority, item.Notes).Scan(&item.AddedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return errs.ErrNotFound
+		}
+		log.Printf("Error updating watchlist item: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) RemoveFromWatchlist(ctx context.Context, userID, movieID int) error {
+	tag, err := a.pool.Exec(ctx, `DELETE FROM watchlist_items WHERE user_id = $1 AND movie_id = $2`, userID, movieID)
+	if err != nil {
+		log.Printf("Error removing from watchlist: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+func (a *PostgresAdapter) GetWatchlistItem(ctx context.Context, userID, movieID int) (*ports.WatchlistItem, error) {
+	query := `SELECT w.user_id, w.movie_id, w.priority, w.notes, w.added_at, ` + movieColumns + `
+              FROM watchlist_items w JOIN movies ON movies.id = w.movie_id
+              WHERE w.user_id = $1 AND w.movie_id = $2`
+
+	item, err := scanWatchlistItem(a.pool.QueryRow(ctx, query, userID, movieID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.ErrNotFound
+		}
+		log.Printf("Error getting watchlist item: %v", err)
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (a *PostgresAdapter) GetWatchlist(ctx context.Context, userID int, sort ports.WatchlistSort, desc bool) ([]*ports.WatchlistItem, error) {
+	order, ok := watchlistOrder[sort]
+	if !ok {
+		order = watchlistOrder[ports.WatchlistSortAdded]
+	}
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+
+	query := `SELECT w.user_id, w.movie_id, w.priority, w.notes, w.added_at, ` + movieColumns + `
+              FROM watchlist_items w JOIN movies ON movies.id = w.movie_id
+              WHERE w.user_id = $1
+              ORDER BY ` + order + ` ` + direction + `, w.movie_id`
+
+	items := make([]*ports.WatchlistItem, 0)
+
+	rows, err := a.pool.Query(ctx, query, userID)
+	if err != nil {
+		log.Printf("Error querying watchlist: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanWatchlistItem(rows)
+		if err != nil {
+			log.Printf("Error scanning watchlist row: %v", err)
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating watchlist rows: %v", err)
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (a *PostgresAdapter) FilterWatchlisted(ctx context.Context, userID int, movieIDs []int) ([]int, error) {
+	ids := make([]int, 0)
+
+	query := `SELECT movie_id FROM watchlist_items WHERE user_id = $1 AND movie_id = ANY($2)`
+
+	rows, err := a.pool.Query(ctx, query, userID, movieIDs)
+	if err != nil {
+		log.Printf("Error filtering watchlisted movies: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			log.Printf("Error scanning watchlisted movie: %v", err)
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func scanWatchlistItem(row pgx.Row) (*ports.WatchlistItem, error) {
+	var item ports.WatchlistItem
+	var mr movieRow
+
+	dest := append([]any{&item.UserID, &item.MovieID, &item.Priority, &item.Notes, &item.AddedAt}, mr.dest()...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	item.Movie = mr.movie()
+
+	return &item, nil
+}
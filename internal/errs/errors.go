@@ -8,3 +8,16 @@ var ErrNotFound = errors.New("the requested resource was not found")
 // ErrProviderFailure будет возвращаться, когда внешний сервис (провайдер)
 // не отвечает или возвращает ошибку, не связанную с "не найдено"
 var ErrProviderFailure = errors.New("the external provider failed to respond")
+
+// ErrInvalidInput будет возвращаться, когда входные данные не прошли валидацию в сервисе
+var ErrInvalidInput = errors.New("invalid input")
+
+// ErrConflict будет возвращаться, когда ресурс уже существует (например, повторная оценка фильма)
+var ErrConflict = errors.New("the resource already exists")
+
+// ErrUnauthorized будет возвращаться, когда в запросе нет авторизованного пользователя
+var ErrUnauthorized = errors.New("unauthorized")
+
+// ErrForbidden будет возвращаться, когда пользователь авторизован, но действие ему не разрешено
+// (например, редактирование чужого списка)
+var ErrForbidden = errors.New("you are not allowed to perform this action")
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+type AccountHandler struct {
+	service *service.AccountService
+}
+
+func NewAccountHandler(s *service.AccountService) *AccountHandler {
+	return &AccountHandler{service: s}
+}
+
+type deletionResponse struct {
+	Message             string    `json:"message" example:"Account deletion scheduled"`
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+}
+
+// ExportMyData godoc
+// @Summary      Export my data
+// @Description  Returns a ZIP archive with JSON files of the profile, ratings and reviews, watchlist, diary, follows and lists. Requires authentication.
+// @Tags         users
+// @Produce      application/zip
+// @Success      200 {file} file "ZIP archive"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to export data"
+// @Security     BearerAuth
+// @Router       /me/export [get]
+func (h *AccountHandler) ExportMyData(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Собираем архив в памяти, чтобы при ошибке отдать нормальный статус, а не обрезанный файл
+	var buf bytes.Buffer
+	if err := h.service.ExportData(r.Context(), userID, &buf); err != nil {
+		writeError(w, err, "Failed to export data")
+		return
+	}
+
+	filename := fmt.Sprintf("movie-planner-export-%d-%s.zip", userID, time.Now().Format("20060102"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	buf.WriteTo(w)
+}
+
+// DeleteMe godoc
+// @Summary      Delete my account
+// @Description  Schedules the account for deletion after a grace period. Until then the deletion can be cancelled with POST /me/restore. After it the account and all its data are removed and issued tokens are revoked. Requires authentication.
+// @Tags         users
+// @Produce      json
+// @Success      202 {object} deletionResponse
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to delete account"
+// @Security     BearerAuth
+// @Router       /me [delete]
+func (h *AccountHandler) DeleteMe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	at, err := h.service.RequestDeletion(r.Context(), userID)
+	if err != nil {
+		writeError(w, err, "Failed to delete account")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(deletionResponse{Message: "Account deletion scheduled", DeletionScheduledAt: at})
+}
+
+// RestoreMe godoc
+// @Summary      Cancel account deletion
+// @Description  Cancels a pending account deletion during the grace period. Requires authentication.
+// @Tags         users
+// @Success      204 "No Content"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to cancel account deletion"
+// @Security     BearerAuth
+// @Router       /me/restore [post]
+func (h *AccountHandler) RestoreMe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.CancelDeletion(r.Context(), userID); err != nil {
+		writeError(w, err, "Failed to cancel account deletion")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,20 +2,27 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 
+	"github.com/turysbekovg/movie-planner/internal/errs"
 	"github.com/turysbekovg/movie-planner/internal/service"
 )
 
 type AuthHandler struct {
-	userSvc *service.UserService
-	authSvc *service.AuthSvc
+	userSvc         *service.UserService
+	authSvc         *service.AuthSvc
+	verificationSvc *service.EmailVerificationService
+	guestSvc        *service.GuestService
 }
 
-func NewAuthHandler(userSvc *service.UserService, authSvc *service.AuthSvc) *AuthHandler {
+func NewAuthHandler(userSvc *service.UserService, authSvc *service.AuthSvc, verificationSvc *service.EmailVerificationService, guestSvc *service.GuestService) *AuthHandler {
 	return &AuthHandler{
-		userSvc: userSvc,
-		authSvc: authSvc,
+		userSvc:         userSvc,
+		authSvc:         authSvc,
+		verificationSvc: verificationSvc,
+		guestSvc:        guestSvc,
 	}
 }
 
@@ -24,34 +31,62 @@ type authRequest struct {
 	Password string `json:"password"`
 }
 
+type registerRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	// GuestToken -> токен гостя, чью активность перенести в новый аккаунт
+	GuestToken string `json:"guest_token,omitempty"`
+}
+
 // Register godoc
 // @Summary      Register a new user
-// @Description  Creates a new user account
+// @Description  Creates a new user account and sends an email verification link. The password must be 8-72 characters; the email is trimmed and lower-cased. The response is the same whether or not the email is already taken, so it can't be used to check who has an account: the owner of a taken address gets an email instead. With guest_token the guest's answer and ballots are moved to the new account (or later via POST /me/guest-claim).
 // @Tags         auth
 // @Accept       json
 // @Produce      json
-// @Param        user body authRequest true "User Registration Info"
-// @Success      201 {object} map[string]interface{}
+// @Param        user body registerRequest true "User Registration Info"
+// @Success      201 {object} map[string]string
 // @Failure      400 {string} string "Invalid request body"
 // @Failure      500 {string} string "Failed to register user"
 // @Router       /auth/register [post]
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
-	var req authRequest
+	var req registerRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	id, err := h.userSvc.RegisterUser(r.Context(), req.Email, req.Password)
-	if err != nil {
-		http.Error(w, "Failed to register user", http.StatusInternalServerError)
+	switch {
+	case errors.Is(err, errs.ErrConflict):
+		// Адрес занят: отвечаем как при успехе, как сброс пароля и повторное письмо,
+		// а владельцу адреса сообщаем о попытке
+		if err := h.verificationSvc.SendAlreadyRegistered(r.Context(), req.Email); err != nil {
+			log.Printf("Error sending already-registered email: %v", err)
+		}
+	case err != nil:
+		writeError(w, err, "Failed to register user")
 		return
+	default:
+		// Аккаунт уже создан, поэтому ошибку почты только логируем:
+		// письмо можно запросить повторно через /auth/verify/resend
+		if user, err := h.userSvc.GetUserByID(r.Context(), id); err != nil {
+			log.Printf("Error loading registered user %d: %v", id, err)
+		} else if err := h.verificationSvc.SendVerification(r.Context(), user); err != nil {
+			log.Printf("Error sending verification email to user %d: %v", id, err)
+		}
+		// Как и с письмом: при ошибке активность можно перенести через /me/guest-claim.
+		// Результат в ответ не попадает, иначе по нему было бы видно, создан ли аккаунт
+		if req.GuestToken != "" {
+			if _, err := h.guestSvc.Claim(r.Context(), id, req.GuestToken); err != nil {
+				log.Printf("Error claiming guest activity for user %d: %v", id, err)
+			}
+		}
 	}
 
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"message": "User registered successfully",
-		"id":      id,
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Check your email to confirm the address",
 	})
 }
 
@@ -65,6 +100,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 // @Success      200 {object} map[string]string
 // @Failure      400 {string} string "Invalid request body"
 // @Failure      401 {string} string "Invalid credentials"
+// @Failure      403 {string} string "email is not verified"
 // @Failure      500 {string} string "Failed to generate token"
 // @Router       /auth/login [post]
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
@@ -76,6 +112,10 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.userSvc.LoginUser(r.Context(), req.Email, req.Password)
 	if err != nil {
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		http.Error(w, "Invalid credentials", http.StatusUnauthorized)
 		return
 	}
@@ -89,3 +129,61 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"token": token})
 }
+
+type resendVerificationRequest struct {
+	Email string `json:"email" example:"aigerim@example.com"`
+}
+
+// VerifyEmail godoc
+// @Summary      Verify email address
+// @Description  Confirms the email address using the signed link from the verification email
+// @Tags         auth
+// @Produce      json
+// @Param        token query string true "Signed verification token"
+// @Success      200 {object} map[string]string
+// @Failure      400 {string} string "verification link is invalid or expired"
+// @Failure      500 {string} string "Failed to verify email"
+// @Router       /auth/verify [get]
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		http.Error(w, "token is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.verificationSvc.Verify(r.Context(), token); err != nil {
+		writeError(w, err, "Failed to verify email")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Email verified successfully"})
+}
+
+// ResendVerification godoc
+// @Summary      Resend the verification email
+// @Description  Sends a new verification link if the account exists and is not verified yet. The response is the same either way.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body resendVerificationRequest true "Account email"
+// @Success      202 {object} map[string]string
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      500 {string} string "Failed to send verification email"
+// @Router       /auth/verify/resend [post]
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	var req resendVerificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.verificationSvc.ResendVerification(r.Context(), req.Email); err != nil {
+		writeError(w, err, "Failed to send verification email")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"message": "If the account exists and is not verified, a new link has been sent"})
+}
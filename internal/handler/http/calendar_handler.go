@@ -0,0 +1,134 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+type CalendarHandler struct {
+	service *service.CalendarService
+}
+
+func NewCalendarHandler(s *service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{service: s}
+}
+
+// GetEventICS godoc
+// @Summary      Download a movie night as .ics
+// @Description  RFC 5545 calendar file for one event, with a VTIMEZONE for the event's timezone. The end time is the start plus the chosen movie's runtime (2 hours without a movie). Cancelled events have STATUS:CANCELLED and a higher SEQUENCE, so re-importing updates the calendar entry. Requires authentication.
+// @Tags         calendar
+// @Produce      text/calendar
+// @Param        id path int true "Event ID"
+// @Success      200 {string} string "iCalendar file"
+// @Failure      400 {string} string "Invalid event ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to export event"
+// @Security     BearerAuth
+// @Router       /events/{id}.ics [get]
+func (h *CalendarHandler) GetEventICS(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid event ID", http.StatusBadRequest)
+		return
+	}
+
+	ics, err := h.service.EventICS(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, err, "Failed to export event")
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d.ics"`, id))
+	writeICS(w, ics)
+}
+
+// CreateCalendarFeed godoc
+// @Summary      Create a calendar subscription link
+// @Description  Returns a secret URL to subscribe to in a calendar app. The feed lists upcoming events the user hosts or has answered going or maybe to. Creating a new link disables the previous one; the link is shown only once. Requires authentication.
+// @Tags         calendar
+// @Produce      json
+// @Success      201 {object} ports.CalendarFeed
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to create calendar feed"
+// @Security     BearerAuth
+// @Router       /me/calendar-feed [post]
+func (h *CalendarHandler) CreateCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	feed, err := h.service.CreateFeed(r.Context(), userID)
+	if err != nil {
+		writeError(w, err, "Failed to create calendar feed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(feed)
+}
+
+// DeleteCalendarFeed godoc
+// @Summary      Disable the calendar subscription link
+// @Description  The subscribed calendar stops receiving updates. Requires authentication.
+// @Tags         calendar
+// @Success      204 "No Content"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to delete calendar feed"
+// @Security     BearerAuth
+// @Router       /me/calendar-feed [delete]
+func (h *CalendarHandler) DeleteCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.DeleteFeed(r.Context(), userID); err != nil {
+		writeError(w, err, "Failed to delete calendar feed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetCalendarFeed godoc
+// @Summary      Calendar subscription feed
+// @Description  iCalendar feed behind a secret link, for calendar apps that cannot send a token. Contains upcoming events the link owner hosts or has answered going or maybe to, including cancelled ones with STATUS:CANCELLED.
+// @Tags         calendar
+// @Produce      text/calendar
+// @Param        token path string true "Feed token"
+// @Success      200 {string} string "iCalendar feed"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to build calendar feed"
+// @Router       /calendar/{token}.ics [get]
+func (h *CalendarHandler) GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	ics, err := h.service.FeedICS(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		writeError(w, err, "Failed to build calendar feed")
+		return
+	}
+
+	writeICS(w, ics)
+}
+
+func writeICS(w http.ResponseWriter, ics []byte) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(ics)))
+	w.Write(ics)
+}
@@ -0,0 +1,334 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+type DiaryHandler struct {
+	service *service.DiaryService
+}
+
+func NewDiaryHandler(s *service.DiaryService) *DiaryHandler {
+	return &DiaryHandler{service: s}
+}
+
+type diaryEntryRequest struct {
+	MovieID   int              `json:"movie_id" example:"1"`
+	WatchedOn ports.CustomDate `json:"watched_on"`
+	Rating    *float64         `json:"rating" example:"8.5"`
+	Rewatch   *bool            `json:"rewatch" example:"false"`
+	Location  string           `json:"location" example:"Office lounge, Friday movie night"`
+	Notes     string           `json:"notes" example:"Popcorn ran out halfway"`
+}
+
+func (req diaryEntryRequest) toInput() service.DiaryInput {
+	return service.DiaryInput{
+		MovieID:   req.MovieID,
+		WatchedOn: req.WatchedOn.Time,
+		Rating:    req.Rating,
+		Rewatch:   req.Rewatch,
+		Location:  req.Location,
+		Notes:     req.Notes,
+	}
+}
+
+// GetEntries godoc
+// @Summary      List my diary entries
+// @Description  Returns the current user's diary entries in the date range, oldest first. Requires authentication.
+// @Tags         diary
+// @Produce      json
+// @Param        from query string false "First day, inclusive (2006-01-02)"
+// @Param        to query string false "Last day, inclusive (2006-01-02)"
+// @Success      200 {array} ports.DiaryEntry
+// @Failure      400 {string} string "Invalid date range"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to get diary"
+// @Security     BearerAuth
+// @Router       /me/diary [get]
+func (h *DiaryHandler) GetEntries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	from, err := queryDate(r, "from")
+	if err != nil {
+		http.Error(w, "Invalid from date", http.StatusBadRequest)
+		return
+	}
+	to, err := queryDate(r, "to")
+	if err != nil {
+		http.Error(w, "Invalid to date", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.service.GetEntries(r.Context(), userID, from, to)
+	if err != nil {
+		writeError(w, err, "Failed to get diary")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// CreateEntry godoc
+// @Summary      Log a watched movie
+// @Description  Adds a diary entry. If rewatch is omitted it is set automatically when the movie is already in the diary. Requires authentication.
+// @Tags         diary
+// @Accept       json
+// @Produce      json
+// @Param        entry body diaryEntryRequest true "Diary entry"
+// @Success      201 {object} ports.DiaryEntry
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to create diary entry"
+// @Security     BearerAuth
+// @Router       /me/diary [post]
+func (h *DiaryHandler) CreateEntry(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req diaryEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.service.CreateEntry(r.Context(), userID, req.toInput())
+	if err != nil {
+		writeError(w, err, "Failed to create diary entry")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+// GetEntry godoc
+// @Summary      Get a diary entry
+// @Description  Returns one of the current user's diary entries. Requires authentication.
+// @Tags         diary
+// @Produce      json
+// @Param        entryID path int true "Diary entry ID"
+// @Success      200 {object} ports.DiaryEntry
+// @Failure      400 {string} string "Invalid entry ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to get diary entry"
+// @Security     BearerAuth
+// @Router       /me/diary/{entryID} [get]
+func (h *DiaryHandler) GetEntry(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "entryID"))
+	if err != nil {
+		http.Error(w, "Invalid entry ID", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.service.GetEntry(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, err, "Failed to get diary entry")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entry)
+}
+
+// UpdateEntry godoc
+// @Summary      Update a diary entry
+// @Description  Replaces a diary entry. If rewatch is omitted the previous value is kept. Requires authentication.
+// @Tags         diary
+// @Accept       json
+// @Produce      json
+// @Param        entryID path int true "Diary entry ID"
+// @Param        entry body diaryEntryRequest true "Diary entry"
+// @Success      200 {object} ports.DiaryEntry
+// @Failure      400 {string} string "Invalid entry ID or request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to update diary entry"
+// @Security     BearerAuth
+// @Router       /me/diary/{entryID} [put]
+func (h *DiaryHandler) UpdateEntry(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "entryID"))
+	if err != nil {
+		http.Error(w, "Invalid entry ID", http.StatusBadRequest)
+		return
+	}
+
+	var req diaryEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.service.UpdateEntry(r.Context(), userID, id, req.toInput())
+	if err != nil {
+		writeError(w, err, "Failed to update diary entry")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entry)
+}
+
+// DeleteEntry godoc
+// @Summary      Delete a diary entry
+// @Description  Removes one of the current user's diary entries. Requires authentication.
+// @Tags         diary
+// @Param        entryID path int true "Diary entry ID"
+// @Success      204 "No Content"
+// @Failure      400 {string} string "Invalid entry ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to delete diary entry"
+// @Security     BearerAuth
+// @Router       /me/diary/{entryID} [delete]
+func (h *DiaryHandler) DeleteEntry(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "entryID"))
+	if err != nil {
+		http.Error(w, "Invalid entry ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteEntry(r.Context(), userID, id); err != nil {
+		writeError(w, err, "Failed to delete diary entry")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetMonth godoc
+// @Summary      Diary calendar for a month
+// @Description  Returns the current user's diary entries for a month grouped by day. Requires authentication.
+// @Tags         diary
+// @Produce      json
+// @Param        year path int true "Year"
+// @Param        month path int true "Month (1-12)"
+// @Success      200 {object} service.DiaryMonth
+// @Failure      400 {string} string "Invalid year or month"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to get diary"
+// @Security     BearerAuth
+// @Router       /me/diary/calendar/{year}/{month} [get]
+func (h *DiaryHandler) GetMonth(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	year, err := strconv.Atoi(chi.URLParam(r, "year"))
+	if err != nil {
+		http.Error(w, "Invalid year", http.StatusBadRequest)
+		return
+	}
+	month, err := strconv.Atoi(chi.URLParam(r, "month"))
+	if err != nil {
+		http.Error(w, "Invalid month", http.StatusBadRequest)
+		return
+	}
+
+	calendar, err := h.service.GetMonth(r.Context(), userID, year, month)
+	if err != nil {
+		writeError(w, err, "Failed to get diary")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(calendar)
+}
+
+// GetYear godoc
+// @Summary      Diary overview for a year
+// @Description  Returns the current user's diary entries for a year grouped by month, with totals. Requires authentication.
+// @Tags         diary
+// @Produce      json
+// @Param        year path int true "Year"
+// @Success      200 {object} service.DiaryYear
+// @Failure      400 {string} string "Invalid year"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to get diary"
+// @Security     BearerAuth
+// @Router       /me/diary/calendar/{year} [get]
+func (h *DiaryHandler) GetYear(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	year, err := strconv.Atoi(chi.URLParam(r, "year"))
+	if err != nil {
+		http.Error(w, "Invalid year", http.StatusBadRequest)
+		return
+	}
+
+	overview, err := h.service.GetYear(r.Context(), userID, year)
+	if err != nil {
+		writeError(w, err, "Failed to get diary")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(overview)
+}
+
+// GetWatchCounts godoc
+// @Summary      Per-movie watch counts
+// @Description  Returns how many times the current user watched each movie in the diary, most watched first. Requires authentication.
+// @Tags         diary
+// @Produce      json
+// @Success      200 {array} ports.MovieWatchCount
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to get watch counts"
+// @Security     BearerAuth
+// @Router       /me/diary/watch-counts [get]
+func (h *DiaryHandler) GetWatchCounts(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	counts, err := h.service.GetWatchCounts(r.Context(), userID)
+	if err != nil {
+		writeError(w, err, "Failed to get watch counts")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(counts)
+}
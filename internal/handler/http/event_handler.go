@@ -0,0 +1,374 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+type EventHandler struct {
+	service *service.EventService
+}
+
+func NewEventHandler(s *service.EventService) *EventHandler {
+	return &EventHandler{service: s}
+}
+
+type createEventRequest struct {
+	Title       string    `json:"title" example:"Friday heist night"`
+	Description string    `json:"description" example:"Bring snacks"`
+	StartsAt    time.Time `json:"starts_at" example:"2026-10-23T20:00:00+05:00"`
+	Timezone    string    `json:"timezone" example:"Asia/Almaty"`
+	Location    string    `json:"location" example:"Aigerim's place, Abay ave 10"`
+	Capacity    int       `json:"capacity" example:"8"`
+	MovieID     *int      `json:"movie_id" example:"12"`
+	Recurrence  string    `json:"recurrence" example:"FREQ=WEEKLY;BYDAY=FR"`
+	InviteeIDs  []int     `json:"invitee_ids" example:"2,3"`
+}
+
+type updateEventRequest struct {
+	Title       *string    `json:"title" example:"Friday heist night"`
+	Description *string    `json:"description" example:"Bring snacks"`
+	StartsAt    *time.Time `json:"starts_at" example:"2026-10-23T21:00:00+05:00"`
+	Timezone    *string    `json:"timezone" example:"Asia/Almaty"`
+	Location    *string    `json:"location" example:"Aigerim's place, Abay ave 10"`
+	Capacity    *int       `json:"capacity" example:"10"`
+	MovieID     *int       `json:"movie_id" example:"0"`
+	Recurrence  *string    `json:"recurrence" example:"FREQ=WEEKLY;BYDAY=FR;COUNT=10"`
+}
+
+type inviteRequest struct {
+	UserIDs []int `json:"user_ids" example:"2,3"`
+}
+
+type rsvpRequest struct {
+	Status ports.RSVPStatus `json:"status" example:"going" enums:"going,maybe,declined"`
+}
+
+// GetMyEvents godoc
+// @Summary      List my movie nights
+// @Description  Events the current user hosts or is invited to, ordered by start time. By default starts from today. Recurring events are expanded into occurrences (with occurrence_id); without to, occurrences are listed one year ahead. Requires authentication.
+// @Tags         events
+// @Produce      json
+// @Param        from query string false "First day, inclusive (2006-01-02)"
+// @Param        to query string false "Last day, inclusive (2006-01-02)"
+// @Success      200 {array} ports.Event
+// @Failure      400 {string} string "Invalid date range"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to get events"
+// @Security     BearerAuth
+// @Router       /events [get]
+func (h *EventHandler) GetMyEvents(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	from, err := queryDate(r, "from")
+	if err != nil {
+		http.Error(w, "Invalid from date", http.StatusBadRequest)
+		return
+	}
+	to, err := queryDate(r, "to")
+	if err != nil {
+		http.Error(w, "Invalid to date", http.StatusBadRequest)
+		return
+	}
+
+	events, err := h.service.GetMyEvents(r.Context(), userID, from, to)
+	if err != nil {
+		writeError(w, err, "Failed to get events")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+// CreateEvent godoc
+// @Summary      Create a movie night
+// @Description  Creates an event hosted by the current user. Without timezone the host's profile timezone is used. Capacity counts the host too; 0 means unlimited. recurrence makes it a series: an RFC 5545 RRULE with FREQ=DAILY, WEEKLY or MONTHLY, INTERVAL, BYDAY, COUNT or UNTIL; starts_at must be its first occurrence. Invitations and answers apply to the whole series. Invited users get a notification. Requires authentication.
+// @Tags         events
+// @Accept       json
+// @Produce      json
+// @Param        event body createEventRequest true "Event"
+// @Success      201 {object} ports.Event
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to create event"
+// @Security     BearerAuth
+// @Router       /events [post]
+func (h *EventHandler) CreateEvent(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req createEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	event, err := h.service.CreateEvent(r.Context(), userID, service.EventInput{
+		Title:       req.Title,
+		Description: req.Description,
+		StartsAt:    req.StartsAt,
+		Timezone:    req.Timezone,
+		Location:    req.Location,
+		Capacity:    req.Capacity,
+		MovieID:     req.MovieID,
+		Recurrence:  req.Recurrence,
+		InviteeIDs:  req.InviteeIDs,
+	})
+	if err != nil {
+		writeError(w, err, "Failed to create event")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(event)
+}
+
+// GetEvent godoc
+// @Summary      Get a movie night
+// @Description  Returns the event with its invitations. Only the host and invited users can see it. Requires authentication.
+// @Tags         events
+// @Produce      json
+// @Param        id path int true "Event ID"
+// @Success      200 {object} ports.Event
+// @Failure      400 {string} string "Invalid event ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to get event"
+// @Security     BearerAuth
+// @Router       /events/{id} [get]
+func (h *EventHandler) GetEvent(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	event, err := h.service.GetEvent(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, err, "Failed to get event")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(event)
+}
+
+// UpdateEvent godoc
+// @Summary      Update a movie night
+// @Description  Changes only the passed fields. movie_id 0 removes the chosen movie. Raising the capacity moves people from the waitlist; it cannot go below the number of people going. For a series this changes all occurrences; an empty recurrence turns it into a single event. Moving a series to another weekday shifts BYDAY along, and drops changes made to single occurrences. Only the host can edit; cancelled events cannot be changed. Requires authentication.
+// @Tags         events
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Event ID"
+// @Param        event body updateEventRequest true "Fields to change"
+// @Success      200 {object} ports.Event
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "a cancelled event cannot be changed or capacity is too low"
+// @Failure      500 {string} string "Failed to update event"
+// @Security     BearerAuth
+// @Router       /events/{id} [patch]
+func (h *EventHandler) UpdateEvent(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req updateEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	event, err := h.service.UpdateEvent(r.Context(), userID, id, service.EventUpdate{
+		Title:       req.Title,
+		Description: req.Description,
+		StartsAt:    req.StartsAt,
+		Timezone:    req.Timezone,
+		Location:    req.Location,
+		Capacity:    req.Capacity,
+		MovieID:     req.MovieID,
+		Recurrence:  req.Recurrence,
+	})
+	if err != nil {
+		writeError(w, err, "Failed to update event")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(event)
+}
+
+// CancelEvent godoc
+// @Summary      Cancel a movie night
+// @Description  Marks the event as cancelled. It stays visible to invited users with status cancelled. Only the host can cancel. Requires authentication.
+// @Tags         events
+// @Param        id path int true "Event ID"
+// @Success      204 "No Content"
+// @Failure      400 {string} string "Invalid event ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "the event is already cancelled"
+// @Failure      500 {string} string "Failed to cancel event"
+// @Security     BearerAuth
+// @Router       /events/{id} [delete]
+func (h *EventHandler) CancelEvent(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.CancelEvent(r.Context(), userID, id); err != nil {
+		writeError(w, err, "Failed to cancel event")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// InviteToEvent godoc
+// @Summary      Invite users to a movie night
+// @Description  Invites users and notifies them. Users who are already invited are skipped. Only the host can invite. Requires authentication.
+// @Tags         events
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Event ID"
+// @Param        request body inviteRequest true "Users to invite"
+// @Success      200 {object} ports.Event
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "a cancelled event cannot be changed"
+// @Failure      500 {string} string "Failed to invite users"
+// @Security     BearerAuth
+// @Router       /events/{id}/invitations [post]
+func (h *EventHandler) InviteToEvent(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req inviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	event, err := h.service.InviteUsers(r.Context(), userID, id, req.UserIDs)
+	if err != nil {
+		writeError(w, err, "Failed to invite users")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(event)
+}
+
+// RemoveInvitation godoc
+// @Summary      Withdraw an invitation
+// @Description  Removes a user from the event's invitations. If they were going, the first person on the waitlist gets their spot. Only the host can do this. Requires authentication.
+// @Tags         events
+// @Param        id path int true "Event ID"
+// @Param        userID path int true "Invited user ID"
+// @Success      204 "No Content"
+// @Failure      400 {string} string "Invalid event or user ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to remove invitation"
+// @Security     BearerAuth
+// @Router       /events/{id}/invitations/{userID} [delete]
+func (h *EventHandler) RemoveInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	inviteeID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.RemoveInvitation(r.Context(), userID, id, inviteeID); err != nil {
+		writeError(w, err, "Failed to remove invitation")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RSVPEvent godoc
+// @Summary      Respond to an invitation
+// @Description  Sets the current user's answer. When the event is full, going puts the user on a FIFO waitlist instead (rsvp becomes waitlisted). Giving up a spot moves the first waitlisted user in and notifies them. Requires authentication.
+// @Tags         events
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Event ID"
+// @Param        request body rsvpRequest true "Answer"
+// @Success      200 {object} ports.Event
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "the event is cancelled or has already started"
+// @Failure      500 {string} string "Failed to save answer"
+// @Security     BearerAuth
+// @Router       /events/{id}/rsvp [put]
+func (h *EventHandler) RSVPEvent(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req rsvpRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	event, err := h.service.RSVP(r.Context(), userID, id, req.Status)
+	if err != nil {
+		writeError(w, err, "Failed to save answer")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(event)
+}
+
+// parseEventRequest -> ID пользователя из контекста и ID вечера из пути.
+// При ошибке уже записывает ответ и возвращает ok == false
+func parseEventRequest(w http.ResponseWriter, r *http.Request) (userID, eventID int, ok bool) {
+	userID, ok = UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return 0, 0, false
+	}
+
+	eventID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid event ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+
+	return userID, eventID, true
+}
@@ -0,0 +1,576 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+type GroupHandler struct {
+	service *service.GroupService
+}
+
+func NewGroupHandler(s *service.GroupService) *GroupHandler {
+	return &GroupHandler{service: s}
+}
+
+type createGroupRequest struct {
+	Name        string `json:"name" example:"Office lounge crew"`
+	Description string `json:"description" example:"Fridays after 19:00"`
+}
+
+type updateGroupRequest struct {
+	Name        *string `json:"name" example:"Office lounge crew"`
+	Description *string `json:"description" example:"Fridays after 19:00"`
+}
+
+type joinGroupRequest struct {
+	Token string `json:"token" example:"3q2-7wEAAAA"`
+}
+
+type setGroupRoleRequest struct {
+	Role ports.GroupRole `json:"role" example:"admin" enums:"owner,admin,member"`
+}
+
+type addGroupWatchlistRequest struct {
+	MovieID int    `json:"movie_id" example:"12"`
+	Note    string `json:"note" example:"Someone said it's better than Heat"`
+}
+
+// GetMyGroups godoc
+// @Summary      List my groups
+// @Description  Groups the current user belongs to, with their role in each. Requires authentication.
+// @Tags         groups
+// @Produce      json
+// @Success      200 {array} ports.Group
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to get groups"
+// @Security     BearerAuth
+// @Router       /groups [get]
+func (h *GroupHandler) GetMyGroups(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	groups, err := h.service.GetMyGroups(r.Context(), userID)
+	if err != nil {
+		writeError(w, err, "Failed to get groups")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(groups)
+}
+
+// CreateGroup godoc
+// @Summary      Create a group
+// @Description  Creates a group (household, friend circle) owned by the current user, with an invite link enabled. Requires authentication.
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        group body createGroupRequest true "Group"
+// @Success      201 {object} ports.Group
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to create group"
+// @Security     BearerAuth
+// @Router       /groups [post]
+func (h *GroupHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req createGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	group, err := h.service.CreateGroup(r.Context(), userID, service.GroupInput{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		writeError(w, err, "Failed to create group")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(group)
+}
+
+// JoinGroup godoc
+// @Summary      Join a group by invite link
+// @Description  Adds the current user to the group as a member. Requires authentication.
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        invite body joinGroupRequest true "Invite token"
+// @Success      200 {object} ports.Group
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "already a member of this group"
+// @Failure      500 {string} string "Failed to join group"
+// @Security     BearerAuth
+// @Router       /groups/join [post]
+func (h *GroupHandler) JoinGroup(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req joinGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	group, err := h.service.JoinGroup(r.Context(), userID, req.Token)
+	if err != nil {
+		writeError(w, err, "Failed to join group")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(group)
+}
+
+// GetGroup godoc
+// @Summary      Get a group
+// @Description  Returns the group with its members. The invite token is shown to owners and admins only. Requires authentication.
+// @Tags         groups
+// @Produce      json
+// @Param        id path int true "Group ID"
+// @Success      200 {object} ports.Group
+// @Failure      400 {string} string "Invalid group ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to get group"
+// @Security     BearerAuth
+// @Router       /groups/{id} [get]
+func (h *GroupHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseGroupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	group, err := h.service.GetGroup(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, err, "Failed to get group")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(group)
+}
+
+// UpdateGroup godoc
+// @Summary      Update a group
+// @Description  Changes the name and/or description. Only the owner and admins can do this. Requires authentication.
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Group ID"
+// @Param        group body updateGroupRequest true "Fields to change"
+// @Success      200 {object} ports.Group
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to update group"
+// @Security     BearerAuth
+// @Router       /groups/{id} [patch]
+func (h *GroupHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseGroupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req updateGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	group, err := h.service.UpdateGroup(r.Context(), userID, id, service.GroupUpdate{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		writeError(w, err, "Failed to update group")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(group)
+}
+
+// DeleteGroup godoc
+// @Summary      Delete a group
+// @Description  Deletes the group with its watchlist and polls. Only the owner can do this. Requires authentication.
+// @Tags         groups
+// @Param        id path int true "Group ID"
+// @Success      204 "No Content"
+// @Failure      400 {string} string "Invalid group ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to delete group"
+// @Security     BearerAuth
+// @Router       /groups/{id} [delete]
+func (h *GroupHandler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseGroupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteGroup(r.Context(), userID, id); err != nil {
+		writeError(w, err, "Failed to delete group")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RotateInviteLink godoc
+// @Summary      Create a new invite link
+// @Description  Generates a new invite token; the previous link stops working. Only the owner and admins can do this. Requires authentication.
+// @Tags         groups
+// @Produce      json
+// @Param        id path int true "Group ID"
+// @Success      200 {object} ports.Group
+// @Failure      400 {string} string "Invalid group ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to update invite link"
+// @Security     BearerAuth
+// @Router       /groups/{id}/invite-link [post]
+func (h *GroupHandler) RotateInviteLink(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseGroupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	group, err := h.service.RotateInviteLink(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, err, "Failed to update invite link")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(group)
+}
+
+// DisableInviteLink godoc
+// @Summary      Disable the invite link
+// @Description  Nobody can join by link until a new one is created. Only the owner and admins can do this. Requires authentication.
+// @Tags         groups
+// @Produce      json
+// @Param        id path int true "Group ID"
+// @Success      200 {object} ports.Group
+// @Failure      400 {string} string "Invalid group ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to update invite link"
+// @Security     BearerAuth
+// @Router       /groups/{id}/invite-link [delete]
+func (h *GroupHandler) DisableInviteLink(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseGroupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	group, err := h.service.DisableInviteLink(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, err, "Failed to update invite link")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(group)
+}
+
+// SetMemberRole godoc
+// @Summary      Change a member's role
+// @Description  Only the owner can change roles. Setting role owner transfers ownership; the previous owner becomes an admin. Requires authentication.
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Group ID"
+// @Param        userID path int true "Member user ID"
+// @Param        role body setGroupRoleRequest true "New role"
+// @Success      200 {object} ports.Group
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to change role"
+// @Security     BearerAuth
+// @Router       /groups/{id}/members/{userID} [put]
+func (h *GroupHandler) SetMemberRole(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseGroupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req setGroupRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	group, err := h.service.SetMemberRole(r.Context(), userID, id, memberID, req.Role)
+	if err != nil {
+		writeError(w, err, "Failed to change role")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(group)
+}
+
+// RemoveMember godoc
+// @Summary      Remove a member or leave the group
+// @Description  The owner can remove anyone, admins can remove regular members. Removing yourself leaves the group; the owner has to transfer ownership first unless they are the last member, in which case the group is deleted. Requires authentication.
+// @Tags         groups
+// @Param        id path int true "Group ID"
+// @Param        userID path int true "Member user ID"
+// @Success      204 "No Content"
+// @Failure      400 {string} string "Invalid group or user ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "transfer ownership before leaving the group"
+// @Failure      500 {string} string "Failed to remove member"
+// @Security     BearerAuth
+// @Router       /groups/{id}/members/{userID} [delete]
+func (h *GroupHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseGroupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.RemoveMember(r.Context(), userID, id, memberID); err != nil {
+		writeError(w, err, "Failed to remove member")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetGroupWatchlist godoc
+// @Summary      Get the group watchlist
+// @Description  Shared watchlist ordered by upvotes, then by when the title was added. seen_by lists members who have the movie in their diary; unseen=true keeps only titles no member has seen yet. Requires authentication.
+// @Tags         groups
+// @Produce      json
+// @Param        id path int true "Group ID"
+// @Param        unseen query bool false "Only titles nobody in the group has seen"
+// @Success      200 {array} ports.GroupWatchlistItem
+// @Failure      400 {string} string "Invalid group ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to get group watchlist"
+// @Security     BearerAuth
+// @Router       /groups/{id}/watchlist [get]
+func (h *GroupHandler) GetGroupWatchlist(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseGroupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	unseen := r.URL.Query().Get("unseen") == "true"
+	items, err := h.service.GetWatchlist(r.Context(), userID, id, unseen)
+	if err != nil {
+		writeError(w, err, "Failed to get group watchlist")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
+
+// AddToGroupWatchlist godoc
+// @Summary      Add a movie to the group watchlist
+// @Description  Any member can add a title; it starts with the adder's upvote. Requires authentication.
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Group ID"
+// @Param        item body addGroupWatchlistRequest true "Movie and note"
+// @Success      201 {object} ports.GroupWatchlistItem
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "the movie is already in the group watchlist"
+// @Failure      500 {string} string "Failed to add to group watchlist"
+// @Security     BearerAuth
+// @Router       /groups/{id}/watchlist [post]
+func (h *GroupHandler) AddToGroupWatchlist(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseGroupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req addGroupWatchlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.service.AddToWatchlist(r.Context(), userID, id, req.MovieID, req.Note)
+	if err != nil {
+		writeError(w, err, "Failed to add to group watchlist")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(item)
+}
+
+// RemoveFromGroupWatchlist godoc
+// @Summary      Remove a movie from the group watchlist
+// @Description  The member who added the title, the owner and admins can remove it. Requires authentication.
+// @Tags         groups
+// @Param        id path int true "Group ID"
+// @Param        movieID path int true "Movie ID"
+// @Success      204 "No Content"
+// @Failure      400 {string} string "Invalid group or movie ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to remove from group watchlist"
+// @Security     BearerAuth
+// @Router       /groups/{id}/watchlist/{movieID} [delete]
+func (h *GroupHandler) RemoveFromGroupWatchlist(w http.ResponseWriter, r *http.Request) {
+	userID, id, movieID, ok := parseGroupMovieRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveFromWatchlist(r.Context(), userID, id, movieID); err != nil {
+		writeError(w, err, "Failed to remove from group watchlist")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpvoteGroupWatchlistItem godoc
+// @Summary      Upvote a title in the group watchlist
+// @Description  One upvote per member; repeating it changes nothing. Requires authentication.
+// @Tags         groups
+// @Produce      json
+// @Param        id path int true "Group ID"
+// @Param        movieID path int true "Movie ID"
+// @Success      200 {object} ports.GroupWatchlistItem
+// @Failure      400 {string} string "Invalid group or movie ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to save vote"
+// @Security     BearerAuth
+// @Router       /groups/{id}/watchlist/{movieID}/vote [put]
+func (h *GroupHandler) UpvoteGroupWatchlistItem(w http.ResponseWriter, r *http.Request) {
+	userID, id, movieID, ok := parseGroupMovieRequest(w, r)
+	if !ok {
+		return
+	}
+
+	item, err := h.service.Upvote(r.Context(), userID, id, movieID)
+	if err != nil {
+		writeError(w, err, "Failed to save vote")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
+}
+
+// RemoveGroupWatchlistVote godoc
+// @Summary      Withdraw an upvote
+// @Description  Removes the current user's upvote from the title. Requires authentication.
+// @Tags         groups
+// @Produce      json
+// @Param        id path int true "Group ID"
+// @Param        movieID path int true "Movie ID"
+// @Success      200 {object} ports.GroupWatchlistItem
+// @Failure      400 {string} string "Invalid group or movie ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to remove vote"
+// @Security     BearerAuth
+// @Router       /groups/{id}/watchlist/{movieID}/vote [delete]
+func (h *GroupHandler) RemoveGroupWatchlistVote(w http.ResponseWriter, r *http.Request) {
+	userID, id, movieID, ok := parseGroupMovieRequest(w, r)
+	if !ok {
+		return
+	}
+
+	item, err := h.service.RemoveUpvote(r.Context(), userID, id, movieID)
+	if err != nil {
+		writeError(w, err, "Failed to remove vote")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
+}
+
+// parseGroupRequest -> ID пользователя из контекста и ID группы из пути.
+// При ошибке уже записывает ответ и возвращает ok == false
+func parseGroupRequest(w http.ResponseWriter, r *http.Request) (userID, groupID int, ok bool) {
+	userID, ok = UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return 0, 0, false
+	}
+
+	groupID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+
+	return userID, groupID, true
+}
+
+// parseGroupMovieRequest -> то же, что parseGroupRequest, плюс ID фильма из пути
+func parseGroupMovieRequest(w http.ResponseWriter, r *http.Request) (userID, groupID, movieID int, ok bool) {
+	userID, groupID, ok = parseGroupRequest(w, r)
+	if !ok {
+		return 0, 0, 0, false
+	}
+
+	movieID, err := strconv.Atoi(chi.URLParam(r, "movieID"))
+	if err != nil {
+		http.Error(w, "Invalid movie ID", http.StatusBadRequest)
+		return 0, 0, 0, false
+	}
+
+	return userID, groupID, movieID, true
+}
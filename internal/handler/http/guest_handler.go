@@ -0,0 +1,399 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+type GuestHandler struct {
+	service *service.GuestService
+}
+
+func NewGuestHandler(s *service.GuestService) *GuestHandler {
+	return &GuestHandler{service: s}
+}
+
+type createGuestLinkRequest struct {
+	ExpiresAt time.Time `json:"expires_at" example:"2026-10-23T18:00:00+05:00"` // пусто -> до начала вечера
+}
+
+type joinAsGuestRequest struct {
+	InviteToken string `json:"invite_token" example:"eyJwIjp7..."`
+	DisplayName string `json:"display_name" example:"Dana (Aigerim's sister)"`
+}
+
+type claimGuestRequest struct {
+	GuestToken string `json:"guest_token" example:"Qm9yZWQ..."`
+}
+
+// CreateGuestLink godoc
+// @Summary      Create a guest invite link
+// @Description  Signed link for people without an account, valid for this event only. By default it expires when the event starts and it can't outlive the start. The token isn't stored, so the link is shown only in this response. Only the host can do this. Requires authentication.
+// @Tags         guests
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Event ID"
+// @Param        link body createGuestLinkRequest false "Expiry"
+// @Success      201 {object} ports.GuestLink
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "the event is cancelled"
+// @Failure      500 {string} string "Failed to create guest link"
+// @Security     BearerAuth
+// @Router       /events/{id}/guest-links [post]
+func (h *GuestHandler) CreateGuestLink(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req createGuestLinkRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	link, err := h.service.CreateLink(r.Context(), userID, id, req.ExpiresAt)
+	if err != nil {
+		writeError(w, err, "Failed to create guest link")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(link)
+}
+
+// RevokeGuestLinks godoc
+// @Summary      Revoke guest invite links
+// @Description  All guest links issued for the event stop working. Guests who already joined stay. Only the host can do this. Requires authentication.
+// @Tags         guests
+// @Param        id path int true "Event ID"
+// @Success      204 "No Content"
+// @Failure      400 {string} string "Invalid event ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to revoke guest links"
+// @Security     BearerAuth
+// @Router       /events/{id}/guest-links [delete]
+func (h *GuestHandler) RevokeGuestLinks(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RevokeLinks(r.Context(), userID, id); err != nil {
+		writeError(w, err, "Failed to revoke guest links")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveGuest godoc
+// @Summary      Remove a guest
+// @Description  Removes a guest from the event. If they were going, the first person on the waitlist gets their spot. Only the host can do this. Requires authentication.
+// @Tags         guests
+// @Param        id path int true "Event ID"
+// @Param        guestID path int true "Guest ID"
+// @Success      204 "No Content"
+// @Failure      400 {string} string "Invalid event or guest ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to remove guest"
+// @Security     BearerAuth
+// @Router       /events/{id}/guests/{guestID} [delete]
+func (h *GuestHandler) RemoveGuest(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	guestID, err := strconv.Atoi(chi.URLParam(r, "guestID"))
+	if err != nil {
+		http.Error(w, "Invalid guest ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.RemoveGuest(r.Context(), userID, id, guestID); err != nil {
+		writeError(w, err, "Failed to remove guest")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PreviewInvite godoc
+// @Summary      Preview a guest invite
+// @Description  The event behind a guest invite link (without the list of invitees), so the guest can see what they are joining. No authentication.
+// @Tags         guests
+// @Produce      json
+// @Param        token path string true "Invite token"
+// @Success      200 {object} ports.Event
+// @Failure      400 {string} string "invite link is invalid or expired"
+// @Failure      409 {string} string "the event is cancelled"
+// @Failure      500 {string} string "Failed to get invite"
+// @Router       /guest/invites/{token} [get]
+func (h *GuestHandler) PreviewInvite(w http.ResponseWriter, r *http.Request) {
+	e, err := h.service.PreviewInvite(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		writeError(w, err, "Failed to get invite")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(e)
+}
+
+// JoinAsGuest godoc
+// @Summary      Join an event as a guest
+// @Description  Joins the event from a guest invite link under a display name. Returns a guest token for the X-Guest-Token header; it is shown only once. The token works only for this event's guest endpoints. No account needed.
+// @Tags         guests
+// @Accept       json
+// @Produce      json
+// @Param        guest body joinAsGuestRequest true "Invite token and display name"
+// @Success      201 {object} service.GuestSession
+// @Failure      400 {string} string "invite link is invalid or expired"
+// @Failure      409 {string} string "the event is cancelled"
+// @Failure      500 {string} string "Failed to join the event"
+// @Router       /guest/join [post]
+func (h *GuestHandler) JoinAsGuest(w http.ResponseWriter, r *http.Request) {
+	var req joinAsGuestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.service.Join(r.Context(), req.InviteToken, req.DisplayName)
+	if err != nil {
+		writeError(w, err, "Failed to join the event")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(session)
+}
+
+// GetGuestEvent godoc
+// @Summary      The guest's event
+// @Description  The guest and their event with attendance counts. Requires a guest token.
+// @Tags         guests
+// @Produce      json
+// @Success      200 {object} service.GuestView
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to get event"
+// @Security     GuestToken
+// @Router       /guest/event [get]
+func (h *GuestHandler) GetGuestEvent(w http.ResponseWriter, r *http.Request) {
+	guest, ok := GuestFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	view, err := h.service.GetView(r.Context(), guest)
+	if err != nil {
+		writeError(w, err, "Failed to get event")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(view)
+}
+
+// GuestRSVP godoc
+// @Summary      Respond as a guest
+// @Description  Same rules as for invited users: guests share seats and the FIFO waitlist with them. Requires a guest token.
+// @Tags         guests
+// @Accept       json
+// @Produce      json
+// @Param        rsvp body rsvpRequest true "Answer"
+// @Success      200 {object} service.GuestView
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      409 {string} string "the event has already started"
+// @Failure      500 {string} string "Failed to save answer"
+// @Security     GuestToken
+// @Router       /guest/rsvp [put]
+func (h *GuestHandler) GuestRSVP(w http.ResponseWriter, r *http.Request) {
+	guest, ok := GuestFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req rsvpRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	view, err := h.service.RSVP(r.Context(), guest, req.Status)
+	if err != nil {
+		writeError(w, err, "Failed to save answer")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(view)
+}
+
+// GetGuestPolls godoc
+// @Summary      Polls of the guest's event
+// @Description  With the guest's own ballot in my_ballot. Requires a guest token.
+// @Tags         guests
+// @Produce      json
+// @Success      200 {array} ports.Poll
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to get polls"
+// @Security     GuestToken
+// @Router       /guest/polls [get]
+func (h *GuestHandler) GetGuestPolls(w http.ResponseWriter, r *http.Request) {
+	guest, ok := GuestFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	polls, err := h.service.GetPolls(r.Context(), guest)
+	if err != nil {
+		writeError(w, err, "Failed to get polls")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(polls)
+}
+
+// GuestCastBallot godoc
+// @Summary      Vote as a guest
+// @Description  Creates or replaces the guest's ballot while the poll is open. Same ballot rules as for users. Only polls of the guest's event. Requires a guest token.
+// @Tags         guests
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Poll ID"
+// @Param        ballot body ballotRequest true "Chosen movies"
+// @Success      200 {object} ports.Poll
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "the poll is not open for voting"
+// @Failure      500 {string} string "Failed to save ballot"
+// @Security     GuestToken
+// @Router       /guest/polls/{id}/ballot [put]
+func (h *GuestHandler) GuestCastBallot(w http.ResponseWriter, r *http.Request) {
+	guest, pollID, ok := parseGuestPollRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req ballotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	poll, err := h.service.CastBallot(r.Context(), guest, pollID, req.Choices)
+	if err != nil {
+		writeError(w, err, "Failed to save ballot")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(poll)
+}
+
+// GetGuestPollResults godoc
+// @Summary      Poll results for a guest
+// @Description  Same tally as GET /polls/{id}/results. Only polls of the guest's event. Requires a guest token.
+// @Tags         guests
+// @Produce      json
+// @Param        id path int true "Poll ID"
+// @Success      200 {object} service.PollResults
+// @Failure      400 {string} string "Invalid poll ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to get results"
+// @Security     GuestToken
+// @Router       /guest/polls/{id}/results [get]
+func (h *GuestHandler) GetGuestPollResults(w http.ResponseWriter, r *http.Request) {
+	guest, pollID, ok := parseGuestPollRequest(w, r)
+	if !ok {
+		return
+	}
+
+	results, err := h.service.GetResults(r.Context(), guest, pollID)
+	if err != nil {
+		writeError(w, err, "Failed to get results")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// ClaimGuest godoc
+// @Summary      Claim guest activity
+// @Description  Moves a guest's answer and ballots to the current user, who becomes an invitee of the event; the guest is removed. The user's own answer and ballots win over the guest's. The same can be done at registration with guest_token. Requires authentication.
+// @Tags         guests
+// @Accept       json
+// @Produce      json
+// @Param        claim body claimGuestRequest true "Guest token"
+// @Success      200 {object} ports.Event
+// @Failure      400 {string} string "guest token is invalid"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      409 {string} string "the host can't claim a guest of their own event"
+// @Failure      500 {string} string "Failed to claim guest activity"
+// @Security     BearerAuth
+// @Router       /me/guest-claim [post]
+func (h *GuestHandler) ClaimGuest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req claimGuestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	e, err := h.service.Claim(r.Context(), userID, req.GuestToken)
+	if err != nil {
+		writeError(w, err, "Failed to claim guest activity")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(e)
+}
+
+func parseGuestPollRequest(w http.ResponseWriter, r *http.Request) (guest *ports.EventGuest, pollID int, ok bool) {
+	guest, ok = GuestFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, 0, false
+	}
+
+	pollID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid poll ID", http.StatusBadRequest)
+		return nil, 0, false
+	}
+
+	return guest, pollID, true
+}
@@ -21,6 +21,10 @@ type SwaggerMovieRequest struct {
 	Rating          float64  `json:"rating" example:"8.8"`
 	PosterURL       string   `json:"poster_url" example:"https://image.tmdb.org/..."`
 	Recommendations []string `json:"recommendations" example:"The Matrix,Shutter Island"`
+	Runtime         int      `json:"runtime" example:"148"`
+	Genres          []string `json:"genres" example:"Action,Sci-Fi"`
+	Language        string   `json:"language" example:"en"`
+	Certification   string   `json:"certification" example:"PG-13"`
 }
 
 type MovieHandler struct {
@@ -71,7 +75,7 @@ func (h *MovieHandler) CreateMovie(w http.ResponseWriter, r *http.Request) {
 
 // GetMovieByID godoc
 // @Summary      Get a movie by ID
-// @Description  Retrieves movie details for a given ID. This endpoint is public.
+// @Description  Retrieves movie details for a given ID. This endpoint is public; with a Bearer token the response also contains in_watchlist.
 // @Tags         movies
 // @Produce      json
 // @Param        id path int true "Movie ID"
@@ -87,7 +91,10 @@ func (h *MovieHandler) GetMovieByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	movieData, err := h.service.GetMovieByID(r.Context(), id)
+	// viewerID == 0, если запрос анонимный
+	viewerID, _ := UserIDFromContext(r.Context())
+
+	movieData, err := h.service.GetMovieByID(r.Context(), id, viewerID)
 	if err != nil {
 		if errors.Is(err, errs.ErrNotFound) {
 			http.Error(w, err.Error(), http.StatusNotFound)
@@ -147,6 +154,7 @@ func (h *MovieHandler) UpdateMovie(w http.ResponseWriter, r *http.Request) {
 // @Success      204 "No Content"
 // @Failure      400 {string} string "Invalid movie ID"
 // @Failure      401 {string} string "Unauthorized"
+// @Failure      409 {string} string "the movie is a candidate in a poll"
 // @Failure      500 {string} string "Failed to delete movie"
 // @Security     BearerAuth
 // @Router       /movies/{id} [delete]
@@ -160,7 +168,7 @@ func (h *MovieHandler) DeleteMovie(w http.ResponseWriter, r *http.Request) {
 
 	err = h.service.DeleteMovie(r.Context(), id)
 	if err != nil {
-		http.Error(w, "Failed to delete movie", http.StatusInternalServerError)
+		writeError(w, err, "Failed to delete movie")
 		return
 	}
 
@@ -169,14 +177,17 @@ func (h *MovieHandler) DeleteMovie(w http.ResponseWriter, r *http.Request) {
 
 // GetAllMovies godoc
 // @Summary      Get all movies
-// @Description  Retrieves a list of all movies. This endpoint is public.
+// @Description  Retrieves a list of all movies, optionally filtered by title. This endpoint is public; with a Bearer token the caller's viewing preferences are applied and each movie also contains in_watchlist.
 // @Tags         movies
 // @Produce      json
-// @Success      200 {array} ports.Movie
+// @Param        q query string false "Case-insensitive title search"
+// @Success      200 {array} service.MovieListItem
 // @Failure      500 {string} string "Failed to get movies"
 // @Router       /movies [get]
 func (h *MovieHandler) GetAllMovies(w http.ResponseWriter, r *http.Request) {
-	movies, err := h.service.GetAllMovies(r.Context())
+	viewerID, _ := UserIDFromContext(r.Context())
+
+	movies, err := h.service.ListMovies(r.Context(), viewerID, r.URL.Query().Get("q"))
 	if err != nil {
 		http.Error(w, "Failed to get movies", http.StatusInternalServerError)
 		return
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+)
+
+// writeError -> переводит ошибки сервиса в HTTP-статусы.
+// Для неизвестных ошибок клиенту отдаем internalMsg, а саму ошибку только логируем.
+func writeError(w http.ResponseWriter, err error, internalMsg string) {
+	switch {
+	case errors.Is(err, errs.ErrInvalidInput):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, errs.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, errs.ErrConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, errs.ErrUnauthorized):
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, errs.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		log.Printf("Internal error: %v", err)
+		http.Error(w, internalMsg, http.StatusInternalServerError)
+	}
+}
+
+// queryInt -> читает целое число из query-параметра, если параметра нет -> def
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// queryDate -> читает дату в формате 2006-01-02 из query-параметра, если параметра нет -> нулевое время
+func queryDate(r *http.Request, name string) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// queryInts -> список целых чисел через запятую ("1,2,3"), если параметра нет -> nil
+func queryInts(r *http.Request, name string) ([]int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	parts := strings.Split(value, ",")
+	ids := make([]int, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
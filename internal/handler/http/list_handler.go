@@ -0,0 +1,483 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+type ListHandler struct {
+	service *service.ListService
+}
+
+func NewListHandler(s *service.ListService) *ListHandler {
+	return &ListHandler{service: s}
+}
+
+type createListRequest struct {
+	Title       string               `json:"title" example:"Best heist films"`
+	Description string               `json:"description" example:"Ocean's and friends"`
+	Visibility  ports.ListVisibility `json:"visibility" example:"public" enums:"private,unlisted,public"`
+}
+
+type updateListRequest struct {
+	Title       *string               `json:"title" example:"Halloween 2026 lineup"`
+	Description *string               `json:"description" example:"Scary but not too scary"`
+	Visibility  *ports.ListVisibility `json:"visibility" example:"unlisted" enums:"private,unlisted,public"`
+}
+
+type addListEntryRequest struct {
+	MovieID int    `json:"movie_id" example:"1"`
+	Note    string `json:"note" example:"The one that started it all"`
+}
+
+type updateListEntryRequest struct {
+	Note     *string `json:"note" example:"Watch this one first"`
+	Position *int    `json:"position" example:"1"`
+}
+
+type shareListRequest struct {
+	UserID int `json:"user_id" example:"2"`
+}
+
+type cloneListRequest struct {
+	ShareToken string `json:"share_token" example:"3q2-7wEAAAA"`
+}
+
+// GetLists godoc
+// @Summary      Get a user's lists
+// @Description  Returns the public lists of the given user. The owner also sees private and unlisted lists. Without the user parameter returns the caller's own lists.
+// @Tags         lists
+// @Produce      json
+// @Param        user query int false "Owner user ID"
+// @Success      200 {array} ports.MovieList
+// @Failure      400 {string} string "Invalid user ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to get lists"
+// @Router       /lists [get]
+func (h *ListHandler) GetLists(w http.ResponseWriter, r *http.Request) {
+	viewerID, _ := UserIDFromContext(r.Context())
+
+	ownerID, err := queryInt(r, "user", viewerID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	if ownerID == 0 {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	lists, err := h.service.GetUserLists(r.Context(), ownerID, viewerID)
+	if err != nil {
+		writeError(w, err, "Failed to get lists")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lists)
+}
+
+// GetPopularLists godoc
+// @Summary      Get popular lists
+// @Description  Public lists ordered by how often they were cloned, then by last update
+// @Tags         lists
+// @Produce      json
+// @Param        limit query int false "Number of lists (default 20, max 100)"
+// @Success      200 {array} ports.MovieList
+// @Failure      400 {string} string "Invalid limit"
+// @Failure      500 {string} string "Failed to get popular lists"
+// @Router       /lists/popular [get]
+func (h *ListHandler) GetPopularLists(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit", 0)
+	if err != nil {
+		http.Error(w, "Invalid limit", http.StatusBadRequest)
+		return
+	}
+
+	lists, err := h.service.GetPopularLists(r.Context(), limit)
+	if err != nil {
+		writeError(w, err, "Failed to get popular lists")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lists)
+}
+
+// GetList godoc
+// @Summary      Get a list
+// @Description  Returns a list with its movies in order. Private and unlisted lists are only visible to the owner here; use the share link for unlisted lists.
+// @Tags         lists
+// @Produce      json
+// @Param        id path int true "List ID"
+// @Success      200 {object} ports.MovieList
+// @Failure      400 {string} string "Invalid list ID"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to get list"
+// @Router       /lists/{id} [get]
+func (h *ListHandler) GetList(w http.ResponseWriter, r *http.Request) {
+	viewerID, _ := UserIDFromContext(r.Context())
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid list ID", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.service.GetList(r.Context(), id, viewerID)
+	if err != nil {
+		writeError(w, err, "Failed to get list")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// GetSharedList godoc
+// @Summary      Get a list by share link
+// @Description  Opens an unlisted or public list by its share token
+// @Tags         lists
+// @Produce      json
+// @Param        token path string true "Share token"
+// @Success      200 {object} ports.MovieList
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to get list"
+// @Router       /lists/shared/{token} [get]
+func (h *ListHandler) GetSharedList(w http.ResponseWriter, r *http.Request) {
+	viewerID, _ := UserIDFromContext(r.Context())
+
+	list, err := h.service.GetSharedList(r.Context(), chi.URLParam(r, "token"), viewerID)
+	if err != nil {
+		writeError(w, err, "Failed to get list")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// CreateList godoc
+// @Summary      Create a list
+// @Description  Creates a list owned by the current user. Visibility defaults to private. Requires authentication.
+// @Tags         lists
+// @Accept       json
+// @Produce      json
+// @Param        list body createListRequest true "List data"
+// @Success      201 {object} ports.MovieList
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to create list"
+// @Security     BearerAuth
+// @Router       /lists [post]
+func (h *ListHandler) CreateList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req createListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.service.CreateList(r.Context(), userID, service.ListInput{
+		Title:       req.Title,
+		Description: req.Description,
+		Visibility:  req.Visibility,
+	})
+	if err != nil {
+		writeError(w, err, "Failed to create list")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(list)
+}
+
+// UpdateList godoc
+// @Summary      Update a list
+// @Description  Changes title, description and/or visibility. Only the owner can do this. Requires authentication.
+// @Tags         lists
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "List ID"
+// @Param        list body updateListRequest true "Fields to change"
+// @Success      200 {object} ports.MovieList
+// @Failure      400 {string} string "Invalid list ID or request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to update list"
+// @Security     BearerAuth
+// @Router       /lists/{id} [patch]
+func (h *ListHandler) UpdateList(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseListRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req updateListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.service.UpdateList(r.Context(), userID, id, service.ListUpdate{
+		Title:       req.Title,
+		Description: req.Description,
+		Visibility:  req.Visibility,
+	})
+	if err != nil {
+		writeError(w, err, "Failed to update list")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// DeleteList godoc
+// @Summary      Delete a list
+// @Description  Deletes a list owned by the current user. Requires authentication.
+// @Tags         lists
+// @Param        id path int true "List ID"
+// @Success      204 "No Content"
+// @Failure      400 {string} string "Invalid list ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to delete list"
+// @Security     BearerAuth
+// @Router       /lists/{id} [delete]
+func (h *ListHandler) DeleteList(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseListRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteList(r.Context(), userID, id); err != nil {
+		writeError(w, err, "Failed to delete list")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CloneList godoc
+// @Summary      Clone a list
+// @Description  Copies a list with all its movies and notes into a new private list of the current user. Unlisted lists need their share token. Requires authentication.
+// @Tags         lists
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "List ID"
+// @Param        request body cloneListRequest false "Share token for unlisted lists"
+// @Success      201 {object} ports.MovieList
+// @Failure      400 {string} string "Invalid list ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to clone list"
+// @Security     BearerAuth
+// @Router       /lists/{id}/clone [post]
+func (h *ListHandler) CloneList(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseListRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// Тело необязательное: для публичных списков токен не нужен
+	var req cloneListRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	list, err := h.service.CloneList(r.Context(), userID, id, req.ShareToken)
+	if err != nil {
+		writeError(w, err, "Failed to clone list")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(list)
+}
+
+// ShareList godoc
+// @Summary      Share a list with a user
+// @Description  Sends the user a notification with a link to the list. The list must be unlisted or public. Requires authentication.
+// @Tags         lists
+// @Accept       json
+// @Param        id path int true "List ID"
+// @Param        request body shareListRequest true "Recipient"
+// @Success      204 "No Content"
+// @Failure      400 {string} string "Invalid list ID or request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to share list"
+// @Security     BearerAuth
+// @Router       /lists/{id}/share [post]
+func (h *ListHandler) ShareList(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseListRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req shareListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.ShareList(r.Context(), userID, id, req.UserID); err != nil {
+		writeError(w, err, "Failed to share list")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddListEntry godoc
+// @Summary      Add a movie to a list
+// @Description  Appends a movie to the end of the list with an optional note. Requires authentication.
+// @Tags         lists
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "List ID"
+// @Param        entry body addListEntryRequest true "Movie and note"
+// @Success      201 {object} ports.MovieList
+// @Failure      400 {string} string "Invalid list ID or request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "the resource already exists"
+// @Failure      500 {string} string "Failed to add movie to list"
+// @Security     BearerAuth
+// @Router       /lists/{id}/entries [post]
+func (h *ListHandler) AddListEntry(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseListRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req addListEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.service.AddEntry(r.Context(), userID, id, req.MovieID, req.Note)
+	if err != nil {
+		writeError(w, err, "Failed to add movie to list")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(list)
+}
+
+// UpdateListEntry godoc
+// @Summary      Update a list entry
+// @Description  Changes the note and/or moves the movie to a new position (1-based). Other movies keep their relative order. Requires authentication.
+// @Tags         lists
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "List ID"
+// @Param        movieID path int true "Movie ID"
+// @Param        entry body updateListEntryRequest true "Note and/or position"
+// @Success      200 {object} ports.MovieList
+// @Failure      400 {string} string "Invalid ID or request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to update list entry"
+// @Security     BearerAuth
+// @Router       /lists/{id}/entries/{movieID} [patch]
+func (h *ListHandler) UpdateListEntry(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseListRequest(w, r)
+	if !ok {
+		return
+	}
+	movieID, err := strconv.Atoi(chi.URLParam(r, "movieID"))
+	if err != nil {
+		http.Error(w, "Invalid movie ID", http.StatusBadRequest)
+		return
+	}
+
+	var req updateListEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.service.UpdateEntry(r.Context(), userID, id, movieID, service.ListEntryUpdate{Note: req.Note, Position: req.Position})
+	if err != nil {
+		writeError(w, err, "Failed to update list entry")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// RemoveListEntry godoc
+// @Summary      Remove a movie from a list
+// @Description  Removes the movie; the movies after it move up one position. Requires authentication.
+// @Tags         lists
+// @Param        id path int true "List ID"
+// @Param        movieID path int true "Movie ID"
+// @Success      204 "No Content"
+// @Failure      400 {string} string "Invalid ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to remove movie from list"
+// @Security     BearerAuth
+// @Router       /lists/{id}/entries/{movieID} [delete]
+func (h *ListHandler) RemoveListEntry(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseListRequest(w, r)
+	if !ok {
+		return
+	}
+	movieID, err := strconv.Atoi(chi.URLParam(r, "movieID"))
+	if err != nil {
+		http.Error(w, "Invalid movie ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.RemoveEntry(r.Context(), userID, id, movieID); err != nil {
+		writeError(w, err, "Failed to remove movie from list")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseListRequest -> ID пользователя из контекста и ID списка из пути.
+// При ошибке уже записывает ответ и возвращает ok == false
+func parseListRequest(w http.ResponseWriter, r *http.Request) (userID, listID int, ok bool) {
+	userID, ok = UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return 0, 0, false
+	}
+
+	listID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid list ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+
+	return userID, listID, true
+}
@@ -2,16 +2,28 @@ package http
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
 	"github.com/turysbekovg/movie-planner/internal/service"
 )
 
 // Ключ, по которому будем сохранять ID пользователя в контексте запроса
 type contextKey string
 
-const userContextKey = contextKey("userID")
+const (
+	userContextKey       = contextKey("userID")
+	guestContextKey      = contextKey("guest")
+	queryTokenContextKey = contextKey("queryToken")
+)
+
+var (
+	errInvalidAuthHeader = errors.New("Invalid Authorization header format")
+	errInvalidToken      = errors.New("Invalid token")
+)
 
 func AuthMiddleware(authSvc *service.AuthSvc) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -23,25 +35,163 @@ func AuthMiddleware(authSvc *service.AuthSvc) func(http.Handler) http.Handler {
 				return
 			}
 
-			// Проверяем, что заголовок имеет формат Bearer <token>.
-			headerParts := strings.Split(authHeader, " ")
-			if len(headerParts) != 2 || headerParts[0] != "Bearer" {
-				http.Error(w, "Invalid Authorization header format", http.StatusUnauthorized)
+			userID, err := authenticate(r.Context(), authSvc, authHeader)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			// Если токен валиден -> добавляем в него ID пользователя
+			ctx := context.WithValue(r.Context(), userContextKey, userID)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// OptionalAuthMiddleware -> для публичных роутов, которые отдают персональные данные
+// авторизованным пользователям. Без заголовка запрос проходит анонимно,
+// но неправильный токен -> 401, чтобы клиент не получал молча "чужой" ответ.
+func OptionalAuthMiddleware(authSvc *service.AuthSvc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				next.ServeHTTP(w, r)
 				return
 			}
-			tokenString := headerParts[1]
 
-			// Проверяем токен с помощью authSvc.
-			userID, err := authSvc.ValidateToken(tokenString)
+			userID, err := authenticate(r.Context(), authSvc, authHeader)
 			if err != nil {
-				http.Error(w, "Invalid token", http.StatusUnauthorized)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
 				return
 			}
 
-			// Если токен валиден -> добавляем в него ID пользователя
 			ctx := context.WithValue(r.Context(), userContextKey, userID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RedactAccessToken -> убирает ?access_token= из URL до логгера, чтобы токен не попадал в логи.
+// Сам токен остается в контексте запроса, принимает его только AccessTokenFromQuery.
+// Ставится перед middleware.Logger
+func RedactAccessToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if token := query.Get("access_token"); token != "" {
+			query.Del("access_token")
+			r = r.WithContext(context.WithValue(r.Context(), queryTokenContextKey, token))
+			r.URL.RawQuery = query.Encode()
+			r.RequestURI = r.URL.RequestURI()
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AccessTokenFromQuery -> для потоков SSE: EventSource в браузере не умеет ставить заголовки,
+// поэтому токен можно передать в ?access_token= (его забирает RedactAccessToken). Ставится перед AuthMiddleware
+func AccessTokenFromQuery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token, _ := r.Context().Value(queryTokenContextKey).(string); token != "" && r.Header.Get("Authorization") == "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func authenticate(ctx context.Context, authSvc *service.AuthSvc, authHeader string) (int, error) {
+	// Проверяем, что заголовок имеет формат Bearer <token>.
+	headerParts := strings.Split(authHeader, " ")
+	if len(headerParts) != 2 || headerParts[0] != "Bearer" {
+		return 0, errInvalidAuthHeader
+	}
+	tokenString := headerParts[1]
+
+	// Проверяем токен с помощью authSvc (подпись, срок и отзыв).
+	userID, err := authSvc.Authenticate(ctx, tokenString)
+	if err != nil {
+		if errors.Is(err, service.ErrTokenRevoked) {
+			return 0, err
+		}
+		return 0, errInvalidToken
+	}
+
+	return userID, nil
+}
 
+// GuestMiddleware -> для роутов гостя: токен из заголовка X-Guest-Token.
+// JWT здесь не принимается, а токен гостя не принимается нигде, кроме этих роутов
+func GuestMiddleware(guests *service.GuestService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.Header.Get("X-Guest-Token")
+			if token == "" {
+				http.Error(w, "X-Guest-Token header is required", http.StatusUnauthorized)
+				return
+			}
+
+			guest, err := guests.Authenticate(r.Context(), token)
+			if err != nil {
+				writeError(w, err, "Failed to authenticate guest")
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), guestContextKey, guest)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// GuestFromContext -> гость, которого положил GuestMiddleware
+func GuestFromContext(ctx context.Context) (*ports.EventGuest, bool) {
+	guest, ok := ctx.Value(guestContextKey).(*ports.EventGuest)
+	return guest, ok
+}
+
+// UserIDFromContext -> достает ID пользователя, который положил AuthMiddleware
+// (или OptionalAuthMiddleware, если запрос был с токеном)
+func UserIDFromContext(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value(userContextKey).(int)
+	return userID, ok
+}
+
+// CurrentUser -> загружает пользователя, от имени которого выполняется запрос.
+// Если в контексте нет ID -> errs.ErrUnauthorized
+func CurrentUser(ctx context.Context, users *service.UserService) (*ports.User, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errs.ErrUnauthorized
+	}
+	return users.GetUserByID(ctx, userID)
+}
+
+// RequireVerifiedEmail -> ставится после AuthMiddleware. В режиме read-only
+// неподтвержденный пользователь может только читать, в режиме block -> ничего
+func RequireVerifiedEmail(users *service.UserService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			policy := users.UnverifiedPolicy()
+			if policy == service.UnverifiedAllow || (policy == service.UnverifiedReadOnly && isReadOnlyMethod(r.Method)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			user, err := CurrentUser(r.Context(), users)
+			if err != nil {
+				writeError(w, err, "Failed to load user")
+				return
+			}
+			if !user.EmailVerified {
+				http.Error(w, service.ErrEmailNotVerified.Error(), http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
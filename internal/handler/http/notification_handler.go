@@ -0,0 +1,167 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/turysbekovg/movie-planner/internal/ports"
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+type NotificationHandler struct {
+	service *service.NotificationService
+}
+
+func NewNotificationHandler(s *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: s}
+}
+
+type markReadRequest struct {
+	IDs []int64 `json:"ids" example:"17,18"`
+	All bool    `json:"all" example:"false"`
+}
+
+// GetMyNotifications godoc
+// @Summary      Get my notifications
+// @Description  Notifications newest first with the total unread count. Pass next_cursor as cursor for the next page. Requires authentication.
+// @Tags         notifications
+// @Produce      json
+// @Param        unread query bool false "Only unread notifications"
+// @Param        cursor query string false "Cursor from the previous page"
+// @Param        limit query int false "Page size (default 20, max 100)"
+// @Success      200 {object} service.NotificationPage
+// @Failure      400 {string} string "Invalid cursor or limit"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to get notifications"
+// @Security     BearerAuth
+// @Router       /me/notifications [get]
+func (h *NotificationHandler) GetMyNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit, err := queryInt(r, "limit", 0)
+	if err != nil {
+		http.Error(w, "Invalid limit", http.StatusBadRequest)
+		return
+	}
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+
+	page, err := h.service.GetNotifications(r.Context(), userID, unreadOnly, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		writeError(w, err, "Failed to get notifications")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// MarkNotificationsRead godoc
+// @Summary      Mark notifications as read
+// @Description  Marks the given notifications as read, or all of them with "all": true. Requires authentication.
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Param        request body markReadRequest true "Notification IDs or all"
+// @Success      200 {object} map[string]int
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to mark notifications as read"
+// @Security     BearerAuth
+// @Router       /me/notifications/read [post]
+func (h *NotificationHandler) MarkNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req markReadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	// Пустой список без all -> скорее ошибка клиента, чем "отметить все"
+	if len(req.IDs) == 0 && !req.All {
+		http.Error(w, "Either ids or all is required", http.StatusBadRequest)
+		return
+	}
+	if req.All {
+		req.IDs = nil
+	}
+
+	marked, err := h.service.MarkRead(r.Context(), userID, req.IDs)
+	if err != nil {
+		writeError(w, err, "Failed to mark notifications as read")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"marked": marked})
+}
+
+// GetNotificationPreferences godoc
+// @Summary      Get my notification settings
+// @Description  Delivery settings (in-app and email) for every notification type. Requires authentication.
+// @Tags         notifications
+// @Produce      json
+// @Success      200 {array} ports.NotificationPreference
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to get notification settings"
+// @Security     BearerAuth
+// @Router       /me/notifications/preferences [get]
+func (h *NotificationHandler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	prefs, err := h.service.GetPreferences(r.Context(), userID)
+	if err != nil {
+		writeError(w, err, "Failed to get notification settings")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary      Update my notification settings
+// @Description  Changes delivery settings for the listed notification types; other types stay unchanged. Requires authentication.
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Param        preferences body []ports.NotificationPreference true "Settings per type"
+// @Success      200 {array} ports.NotificationPreference
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to update notification settings"
+// @Security     BearerAuth
+// @Router       /me/notifications/preferences [put]
+func (h *NotificationHandler) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req []ports.NotificationPreference
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := h.service.UpdatePreferences(r.Context(), userID, req)
+	if err != nil {
+		writeError(w, err, "Failed to update notification settings")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
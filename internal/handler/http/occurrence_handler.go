@@ -0,0 +1,133 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+// GetOccurrences godoc
+// @Summary      List occurrences of a recurring movie night
+// @Description  Occurrences of the series within the date range, with changes to single occurrences applied; cancelled ones have status cancelled. By default from today and one year ahead. A single event is returned as is if it falls into the range. Requires authentication.
+// @Tags         events
+// @Produce      json
+// @Param        id path int true "Event ID"
+// @Param        from query string false "First day, inclusive (2006-01-02)"
+// @Param        to query string false "Last day, inclusive (2006-01-02)"
+// @Success      200 {array} ports.Event
+// @Failure      400 {string} string "Invalid date range"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to get occurrences"
+// @Security     BearerAuth
+// @Router       /events/{id}/occurrences [get]
+func (h *EventHandler) GetOccurrences(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	from, err := queryDate(r, "from")
+	if err != nil {
+		http.Error(w, "Invalid from date", http.StatusBadRequest)
+		return
+	}
+	to, err := queryDate(r, "to")
+	if err != nil {
+		http.Error(w, "Invalid to date", http.StatusBadRequest)
+		return
+	}
+
+	occurrences, err := h.service.GetOccurrences(r.Context(), userID, id, from, to)
+	if err != nil {
+		writeError(w, err, "Failed to get occurrences")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(occurrences)
+}
+
+// UpdateOccurrence godoc
+// @Summary      Update an occurrence of a recurring movie night
+// @Description  scope=this (default) changes only this occurrence: title, description, starts_at, location and movie_id. scope=following ends the series before this occurrence and starts a new series from it with the changes applied (capacity, timezone and recurrence can be changed too); invitations and answers are copied, guests and polls stay with the old series. Returns the changed occurrence or the new series. Only the host can edit. Requires authentication.
+// @Tags         events
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Event ID"
+// @Param        occurrenceID path string true "Occurrence ID (original start in UTC, 20261023T150000Z)"
+// @Param        scope query string false "this or following" Enums(this, following)
+// @Param        event body updateEventRequest true "Fields to change"
+// @Success      200 {object} ports.Event
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "the occurrence has already started"
+// @Failure      500 {string} string "Failed to update occurrence"
+// @Security     BearerAuth
+// @Router       /events/{id}/occurrences/{occurrenceID} [patch]
+func (h *EventHandler) UpdateOccurrence(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req updateEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	scope := service.OccurrenceScope(r.URL.Query().Get("scope"))
+	event, err := h.service.UpdateOccurrence(r.Context(), userID, id, chi.URLParam(r, "occurrenceID"), scope, service.EventUpdate{
+		Title:       req.Title,
+		Description: req.Description,
+		StartsAt:    req.StartsAt,
+		Timezone:    req.Timezone,
+		Location:    req.Location,
+		Capacity:    req.Capacity,
+		MovieID:     req.MovieID,
+		Recurrence:  req.Recurrence,
+	})
+	if err != nil {
+		writeError(w, err, "Failed to update occurrence")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(event)
+}
+
+// CancelOccurrence godoc
+// @Summary      Cancel an occurrence of a recurring movie night
+// @Description  scope=this (default) cancels only this occurrence. scope=following ends the series before it; for the first occurrence that cancels the whole series. Only the host can cancel. Requires authentication.
+// @Tags         events
+// @Param        id path int true "Event ID"
+// @Param        occurrenceID path string true "Occurrence ID (original start in UTC, 20261023T150000Z)"
+// @Param        scope query string false "this or following" Enums(this, following)
+// @Success      204 "No Content"
+// @Failure      400 {string} string "Invalid event ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "the occurrence is already cancelled"
+// @Failure      500 {string} string "Failed to cancel occurrence"
+// @Security     BearerAuth
+// @Router       /events/{id}/occurrences/{occurrenceID} [delete]
+func (h *EventHandler) CancelOccurrence(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	scope := service.OccurrenceScope(r.URL.Query().Get("scope"))
+	if err := h.service.CancelOccurrence(r.Context(), userID, id, chi.URLParam(r, "occurrenceID"), scope); err != nil {
+		writeError(w, err, "Failed to cancel occurrence")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
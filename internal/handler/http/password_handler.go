@@ -0,0 +1,201 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+type PasswordHandler struct {
+	service *service.PasswordService
+}
+
+func NewPasswordHandler(s *service.PasswordService) *PasswordHandler {
+	return &PasswordHandler{service: s}
+}
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password" example:"old-secret"`
+	NewPassword     string `json:"new_password" example:"new-secret-123"`
+}
+
+type passwordResetRequest struct {
+	Email string `json:"email" example:"aigerim@example.com"`
+}
+
+type passwordResetConfirmRequest struct {
+	Token       string `json:"token" example:"q1w2e3..."`
+	NewPassword string `json:"new_password" example:"new-secret-123"`
+}
+
+// ChangePassword godoc
+// @Summary      Change my password
+// @Description  Sets a new password (8-72 characters). The current password is required. All previously issued tokens are revoked and a new token is returned. Requires authentication.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        passwords body changePasswordRequest true "Current and new password"
+// @Success      200 {object} map[string]string
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "current password is incorrect"
+// @Failure      500 {string} string "Failed to change password"
+// @Security     BearerAuth
+// @Router       /me/password [post]
+func (h *PasswordHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req changePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.service.ChangePassword(r.Context(), userID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPassword) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		writeError(w, err, "Failed to change password")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"token": token})
+}
+
+// RequestPasswordReset godoc
+// @Summary      Request a password reset
+// @Description  Sends a single-use reset token to the email if an account exists. The response is the same either way.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body passwordResetRequest true "Account email"
+// @Success      202 {object} map[string]string
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      500 {string} string "Failed to request password reset"
+// @Router       /auth/password-reset [post]
+func (h *PasswordHandler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var req passwordResetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.RequestReset(r.Context(), req.Email); err != nil {
+		writeError(w, err, "Failed to request password reset")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "If the account exists, a reset link has been sent",
+	})
+}
+
+// Страница из ссылки в письме: форма отправляется обычным POST на тот же адрес
+var passwordResetPage = template.Must(template.New("reset").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><meta name="referrer" content="no-referrer"><title>Reset your Movie Planner password</title></head>
+<body>
+{{if .Done}}<p>Your password has been changed. You can now log in with the new password.</p>
+{{else}}<h1>Reset your password</h1>
+{{if .Error}}<p>{{.Error}}</p>{{end}}
+<form method="post" action="/auth/password-reset/confirm">
+<input type="hidden" name="token" value="{{.Token}}">
+<label>New password (8-72 characters) <input type="password" name="new_password" minlength="8" maxlength="72" required></label>
+<button type="submit">Set new password</button>
+</form>
+{{end}}</body>
+</html>
+`))
+
+type passwordResetPageData struct {
+	Token string
+	Error string
+	Done  bool
+}
+
+// PasswordResetForm godoc
+// @Summary      Password reset page
+// @Description  HTML form opened from the link in the reset email. It submits the token and the new password to the same address as a regular form post.
+// @Tags         auth
+// @Produce      html
+// @Param        token query string true "Reset token from the email"
+// @Success      200 {string} string "HTML form"
+// @Failure      400 {string} string "token is required"
+// @Router       /auth/password-reset/confirm [get]
+func (h *PasswordHandler) PasswordResetForm(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		http.Error(w, "token is required", http.StatusBadRequest)
+		return
+	}
+	renderPasswordResetPage(w, http.StatusOK, passwordResetPageData{Token: token})
+}
+
+// ConfirmPasswordReset godoc
+// @Summary      Confirm a password reset
+// @Description  Sets a new password using the token from the reset email. The token works only once. All previously issued access tokens are revoked. Accepts JSON or, from the reset page, a form post (then the answer is an HTML page).
+// @Tags         auth
+// @Accept       json
+// @Param        request body passwordResetConfirmRequest true "Reset token and new password"
+// @Success      204 "No Content"
+// @Failure      400 {string} string "Invalid request body or token"
+// @Failure      500 {string} string "Failed to reset password"
+// @Router       /auth/password-reset/confirm [post]
+func (h *PasswordHandler) ConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		h.confirmPasswordResetForm(w, r)
+		return
+	}
+
+	var req passwordResetConfirmRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.ConfirmReset(r.Context(), req.Token, req.NewPassword); err != nil {
+		writeError(w, err, "Failed to reset password")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PasswordHandler) confirmPasswordResetForm(w http.ResponseWriter, r *http.Request) {
+	token, password := r.PostFormValue("token"), r.PostFormValue("new_password")
+
+	if err := h.service.ConfirmReset(r.Context(), token, password); err != nil {
+		// Ошибки ввода (слабый пароль, истекший токен) показываем на той же форме
+		if errors.Is(err, errs.ErrInvalidInput) {
+			renderPasswordResetPage(w, http.StatusBadRequest, passwordResetPageData{Token: token, Error: err.Error()})
+			return
+		}
+		writeError(w, err, "Failed to reset password")
+		return
+	}
+
+	renderPasswordResetPage(w, http.StatusOK, passwordResetPageData{Done: true})
+}
+
+func renderPasswordResetPage(w http.ResponseWriter, status int, data passwordResetPageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := passwordResetPage.Execute(w, data); err != nil {
+		log.Printf("Error rendering password reset page: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+type PlannerHandler struct {
+	service *service.PlannerService
+}
+
+func NewPlannerHandler(s *service.PlannerService) *PlannerHandler {
+	return &PlannerHandler{service: s}
+}
+
+type planTonightRequest struct {
+	ParticipantIDs []int     `json:"participant_ids" example:"1,2,3"`
+	WindowStart    time.Time `json:"window_start" example:"2026-10-30T19:00:00+05:00"`
+	WindowEnd      time.Time `json:"window_end" example:"2026-10-30T21:30:00+05:00"`
+	IncludeGenres  []string  `json:"include_genres" example:"Comedy,Drama"`
+	ExcludeGenres  []string  `json:"exclude_genres" example:"Horror"`
+	MinRating      float64   `json:"min_rating" example:"7"`
+	NoRewatches    bool      `json:"no_rewatches" example:"true"`
+	Limit          int       `json:"limit" example:"5"`
+}
+
+// PlanTonight godoc
+// @Summary      Tonight's pick for a group
+// @Description  Returns a ranked shortlist of movies that fit into the time window, with per-participant fit scores and an explanation. The caller must be one of the participants, and every other participant must follow the caller or share a group with them. Requires authentication.
+// @Tags         planner
+// @Accept       json
+// @Produce      json
+// @Param        plan body planTonightRequest true "Participants, time window and constraints"
+// @Success      200 {object} service.TonightPlan
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "Participant is not connected to the caller"
+// @Failure      500 {string} string "Failed to build a plan"
+// @Security     BearerAuth
+// @Router       /plan/tonight [post]
+func (h *PlannerHandler) PlanTonight(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req planTonightRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	plan, err := h.service.PlanTonight(r.Context(), userID, service.TonightConstraints{
+		ParticipantIDs: req.ParticipantIDs,
+		WindowStart:    req.WindowStart,
+		WindowEnd:      req.WindowEnd,
+		IncludeGenres:  req.IncludeGenres,
+		ExcludeGenres:  req.ExcludeGenres,
+		MinRating:      req.MinRating,
+		NoRewatches:    req.NoRewatches,
+		Limit:          req.Limit,
+	})
+	if err != nil {
+		writeError(w, err, "Failed to build a plan")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(plan)
+}
+
+type planMarathonRequest struct {
+	MovieIDs         []int      `json:"movie_ids" example:"12,7,31"`
+	Ordered          bool       `json:"ordered" example:"false"` // false -> по дате выхода
+	StartsAt         time.Time  `json:"starts_at" example:"2026-10-31T14:00:00+05:00"`
+	BreakMinutes     int        `json:"break_minutes" example:"15"`
+	MealEveryMinutes int        `json:"meal_every_minutes" example:"300"` // 0 -> без перерывов на еду
+	MealMinutes      int        `json:"meal_minutes" example:"45"`
+	EndsBy           *time.Time `json:"ends_by,omitempty" example:"2026-10-31T23:30:00+05:00"`
+	Optimize         string     `json:"optimize" example:"rating" enums:"rating,priority"`
+}
+
+// PlanMarathon godoc
+// @Summary      Marathon schedule
+// @Description  Builds a back-to-back schedule for several movies with short breaks between them and meal breaks at the configured interval. Without `ordered` the movies are watched by release date. If the movies don't fit before `ends_by`, the plan keeps the set with the highest total rating (or watchlist priority with `optimize=priority`) and lists the rest in `dropped`. Requires authentication.
+// @Tags         planner
+// @Accept       json
+// @Produce      json
+// @Param        plan body planMarathonRequest true "Movies, start time, breaks and optional end time"
+// @Success      200 {object} service.MarathonPlan
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "Movie not found"
+// @Failure      500 {string} string "Failed to build a schedule"
+// @Security     BearerAuth
+// @Router       /plan/marathon [post]
+func (h *PlannerHandler) PlanMarathon(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req planMarathonRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	plan, err := h.service.PlanMarathon(r.Context(), userID, service.MarathonInput{
+		MovieIDs:         req.MovieIDs,
+		Ordered:          req.Ordered,
+		StartsAt:         req.StartsAt,
+		BreakMinutes:     req.BreakMinutes,
+		MealEveryMinutes: req.MealEveryMinutes,
+		MealMinutes:      req.MealMinutes,
+		EndsBy:           req.EndsBy,
+		Optimize:         req.Optimize,
+	})
+	if err != nil {
+		writeError(w, err, "Failed to build a schedule")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(plan)
+}
@@ -0,0 +1,325 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+type PollHandler struct {
+	service *service.PollService
+}
+
+func NewPollHandler(s *service.PollService) *PollHandler {
+	return &PollHandler{service: s}
+}
+
+type createPollRequest struct {
+	Title    string             `json:"title" example:"What do we watch on Friday?"`
+	Method   ports.VotingMethod `json:"method" example:"irv" enums:"plurality,approval,irv,borda"`
+	MovieIDs []int              `json:"movie_ids" example:"12,7,31"`
+	OpensAt  time.Time          `json:"opens_at"`
+	ClosesAt time.Time          `json:"closes_at"`
+}
+
+type ballotRequest struct {
+	Choices []int `json:"choices" example:"12,7"`
+}
+
+// CreateEventPoll godoc
+// @Summary      Create a poll for a movie night
+// @Description  Creates a poll over candidate movies. Without opens_at the poll opens now; without closes_at it closes when the event starts. Only the host can create polls. Requires authentication.
+// @Tags         polls
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Event ID"
+// @Param        poll body createPollRequest true "Poll"
+// @Success      201 {object} ports.Poll
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "the event is cancelled"
+// @Failure      500 {string} string "Failed to create poll"
+// @Security     BearerAuth
+// @Router       /events/{id}/polls [post]
+func (h *PollHandler) CreateEventPoll(w http.ResponseWriter, r *http.Request) {
+	userID, eventID, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req createPollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	poll, err := h.service.CreateEventPoll(r.Context(), userID, eventID, service.PollInput{
+		Title:    req.Title,
+		Method:   req.Method,
+		MovieIDs: req.MovieIDs,
+		OpensAt:  req.OpensAt,
+		ClosesAt: req.ClosesAt,
+	})
+	if err != nil {
+		writeError(w, err, "Failed to create poll")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(poll)
+}
+
+// GetEventPolls godoc
+// @Summary      List polls of a movie night
+// @Description  Polls of the event with candidates and the caller's own ballot. Visible to the host and invited users. Requires authentication.
+// @Tags         polls
+// @Produce      json
+// @Param        id path int true "Event ID"
+// @Success      200 {array} ports.Poll
+// @Failure      400 {string} string "Invalid event ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to get polls"
+// @Security     BearerAuth
+// @Router       /events/{id}/polls [get]
+func (h *PollHandler) GetEventPolls(w http.ResponseWriter, r *http.Request) {
+	userID, eventID, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	polls, err := h.service.GetEventPolls(r.Context(), userID, eventID)
+	if err != nil {
+		writeError(w, err, "Failed to get polls")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(polls)
+}
+
+// CreateGroupPoll godoc
+// @Summary      Create a poll in a group
+// @Description  Creates a poll over candidate movies for the group. Any member can create one; closes_at is required. Without opens_at the poll opens now. Requires authentication.
+// @Tags         polls
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Group ID"
+// @Param        poll body createPollRequest true "Poll"
+// @Success      201 {object} ports.Poll
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to create poll"
+// @Security     BearerAuth
+// @Router       /groups/{id}/polls [post]
+func (h *PollHandler) CreateGroupPoll(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := parseGroupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req createPollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	poll, err := h.service.CreateGroupPoll(r.Context(), userID, groupID, service.PollInput{
+		Title:    req.Title,
+		Method:   req.Method,
+		MovieIDs: req.MovieIDs,
+		OpensAt:  req.OpensAt,
+		ClosesAt: req.ClosesAt,
+	})
+	if err != nil {
+		writeError(w, err, "Failed to create poll")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(poll)
+}
+
+// GetGroupPolls godoc
+// @Summary      List polls of a group
+// @Description  Polls of the group with candidates and the caller's own ballot. Visible to group members. Requires authentication.
+// @Tags         polls
+// @Produce      json
+// @Param        id path int true "Group ID"
+// @Success      200 {array} ports.Poll
+// @Failure      400 {string} string "Invalid group ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to get polls"
+// @Security     BearerAuth
+// @Router       /groups/{id}/polls [get]
+func (h *PollHandler) GetGroupPolls(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, ok := parseGroupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	polls, err := h.service.GetGroupPolls(r.Context(), userID, groupID)
+	if err != nil {
+		writeError(w, err, "Failed to get polls")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(polls)
+}
+
+// GetPoll godoc
+// @Summary      Get a poll
+// @Description  Returns the poll with candidates and the caller's own ballot. Requires authentication.
+// @Tags         polls
+// @Produce      json
+// @Param        id path int true "Poll ID"
+// @Success      200 {object} ports.Poll
+// @Failure      400 {string} string "Invalid poll ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to get poll"
+// @Security     BearerAuth
+// @Router       /polls/{id} [get]
+func (h *PollHandler) GetPoll(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parsePollRequest(w, r)
+	if !ok {
+		return
+	}
+
+	poll, err := h.service.GetPoll(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, err, "Failed to get poll")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(poll)
+}
+
+// CastBallot godoc
+// @Summary      Vote in a poll
+// @Description  Creates or replaces the caller's ballot while the poll is open. Plurality takes exactly one movie, approval any number of acceptable movies, irv and borda a ranking from most to least preferred (partial rankings are allowed). Requires authentication.
+// @Tags         polls
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Poll ID"
+// @Param        ballot body ballotRequest true "Chosen movies"
+// @Success      200 {object} ports.Poll
+// @Failure      400 {string} string "Invalid ballot"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "the poll is not open for voting"
+// @Failure      500 {string} string "Failed to save ballot"
+// @Security     BearerAuth
+// @Router       /polls/{id}/ballot [put]
+func (h *PollHandler) CastBallot(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parsePollRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req ballotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	poll, err := h.service.CastBallot(r.Context(), userID, id, req.Choices)
+	if err != nil {
+		writeError(w, err, "Failed to save ballot")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(poll)
+}
+
+// ClosePoll godoc
+// @Summary      Close a poll early
+// @Description  Stops voting now and returns the final results. Allowed for the poll author, the event host, and group owners and admins. Requires authentication.
+// @Tags         polls
+// @Produce      json
+// @Param        id path int true "Poll ID"
+// @Success      200 {object} service.PollResults
+// @Failure      400 {string} string "Invalid poll ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "the poll is already closed"
+// @Failure      500 {string} string "Failed to close poll"
+// @Security     BearerAuth
+// @Router       /polls/{id}/close [post]
+func (h *PollHandler) ClosePoll(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parsePollRequest(w, r)
+	if !ok {
+		return
+	}
+
+	results, err := h.service.ClosePoll(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, err, "Failed to close poll")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// GetPollResults godoc
+// @Summary      Get poll results
+// @Description  Deterministic tally of the current ballots; final once the poll is closed. For irv every round is listed with the eliminated movie. Ties: plurality and approval prefer the movie listed earlier in the poll; borda prefers more first places, then the earlier movie; in irv the movie eliminated on a tie is the one with fewer votes in the previous rounds (latest round first), then the one listed later in the poll. Requires authentication.
+// @Tags         polls
+// @Produce      json
+// @Param        id path int true "Poll ID"
+// @Success      200 {object} service.PollResults
+// @Failure      400 {string} string "Invalid poll ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to get poll results"
+// @Security     BearerAuth
+// @Router       /polls/{id}/results [get]
+func (h *PollHandler) GetPollResults(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parsePollRequest(w, r)
+	if !ok {
+		return
+	}
+
+	results, err := h.service.GetResults(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, err, "Failed to get poll results")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// parsePollRequest -> ID пользователя из контекста и ID опроса из пути.
+// При ошибке уже записывает ответ и возвращает ok == false
+func parsePollRequest(w http.ResponseWriter, r *http.Request) (userID, pollID int, ok bool) {
+	userID, ok = UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return 0, 0, false
+	}
+
+	pollID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid poll ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+
+	return userID, pollID, true
+}
@@ -0,0 +1,159 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+type PreferenceHandler struct {
+	service *service.PreferenceService
+}
+
+func NewPreferenceHandler(s *service.PreferenceService) *PreferenceHandler {
+	return &PreferenceHandler{service: s}
+}
+
+type hideMovieRequest struct {
+	MovieID int `json:"movie_id" example:"12"`
+}
+
+// GetMyPreferences godoc
+// @Summary      Get my viewing preferences
+// @Description  Returns the filters applied to movie lists, recommendations and the planner for the current user. Requires authentication.
+// @Tags         preferences
+// @Produce      json
+// @Success      200 {object} ports.ViewingPreferences
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to get preferences"
+// @Security     BearerAuth
+// @Router       /me/preferences [get]
+func (h *PreferenceHandler) GetMyPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	prefs, err := h.service.GetPreferences(r.Context(), userID)
+	if err != nil {
+		writeError(w, err, "Failed to get preferences")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// UpdateMyPreferences godoc
+// @Summary      Replace my viewing preferences
+// @Description  Replaces all viewing preferences. Empty values mean no restriction. Movies with unknown runtime or language are not filtered by those fields. Requires authentication.
+// @Tags         preferences
+// @Accept       json
+// @Produce      json
+// @Param        preferences body ports.ViewingPreferences true "Viewing preferences"
+// @Success      200 {object} ports.ViewingPreferences
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to update preferences"
+// @Security     BearerAuth
+// @Router       /me/preferences [put]
+func (h *PreferenceHandler) UpdateMyPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req ports.ViewingPreferences
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := h.service.UpdatePreferences(r.Context(), userID, &req)
+	if err != nil {
+		writeError(w, err, "Failed to update preferences")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// HideMovie godoc
+// @Summary      Never show a movie again
+// @Description  Adds the movie to the hidden list so it no longer appears in lists, recommendations and the planner. Requires authentication.
+// @Tags         preferences
+// @Accept       json
+// @Produce      json
+// @Param        request body hideMovieRequest true "Movie to hide"
+// @Success      200 {object} ports.ViewingPreferences
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to hide movie"
+// @Security     BearerAuth
+// @Router       /me/preferences/hidden-movies [post]
+func (h *PreferenceHandler) HideMovie(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req hideMovieRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := h.service.HideMovie(r.Context(), userID, req.MovieID)
+	if err != nil {
+		writeError(w, err, "Failed to hide movie")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// UnhideMovie godoc
+// @Summary      Show a hidden movie again
+// @Description  Removes the movie from the hidden list. Requires authentication.
+// @Tags         preferences
+// @Produce      json
+// @Param        movieID path int true "Movie ID"
+// @Success      200 {object} ports.ViewingPreferences
+// @Failure      400 {string} string "Invalid movie ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to unhide movie"
+// @Security     BearerAuth
+// @Router       /me/preferences/hidden-movies/{movieID} [delete]
+func (h *PreferenceHandler) UnhideMovie(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	movieID, err := strconv.Atoi(chi.URLParam(r, "movieID"))
+	if err != nil {
+		http.Error(w, "Invalid movie ID", http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := h.service.UnhideMovie(r.Context(), userID, movieID)
+	if err != nil {
+		writeError(w, err, "Failed to unhide movie")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
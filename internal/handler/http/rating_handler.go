@@ -0,0 +1,184 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+type RatingHandler struct {
+	service *service.RatingService
+}
+
+func NewRatingHandler(s *service.RatingService) *RatingHandler {
+	return &RatingHandler{service: s}
+}
+
+type ratingRequest struct {
+	Rating float64 `json:"rating" example:"8.5"`
+	Review string  `json:"review" example:"Great soundtrack, a bit too long."`
+}
+
+// RateMovie godoc
+// @Summary      Rate a movie
+// @Description  Adds the current user's rating (0.5-10, step 0.5) with an optional review. One rating per user and movie. Requires authentication.
+// @Tags         ratings
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Movie ID"
+// @Param        rating body ratingRequest true "Rating and optional review"
+// @Success      201 {object} ports.Rating
+// @Failure      400 {string} string "Invalid movie ID or request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "the resource already exists"
+// @Failure      500 {string} string "Failed to rate movie"
+// @Security     BearerAuth
+// @Router       /movies/{id}/ratings [post]
+func (h *RatingHandler) RateMovie(w http.ResponseWriter, r *http.Request) {
+	userID, movieID, req, ok := h.parseRatingRequest(w, r)
+	if !ok {
+		return
+	}
+
+	rating, err := h.service.RateMovie(r.Context(), userID, movieID, req.Rating, req.Review)
+	if err != nil {
+		writeError(w, err, "Failed to rate movie")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rating)
+}
+
+// UpdateRating godoc
+// @Summary      Update a movie rating
+// @Description  Changes the current user's rating and review for the movie. Requires authentication.
+// @Tags         ratings
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Movie ID"
+// @Param        rating body ratingRequest true "Rating and optional review"
+// @Success      200 {object} ports.Rating
+// @Failure      400 {string} string "Invalid movie ID or request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to update rating"
+// @Security     BearerAuth
+// @Router       /movies/{id}/ratings [put]
+func (h *RatingHandler) UpdateRating(w http.ResponseWriter, r *http.Request) {
+	userID, movieID, req, ok := h.parseRatingRequest(w, r)
+	if !ok {
+		return
+	}
+
+	rating, err := h.service.UpdateRating(r.Context(), userID, movieID, req.Rating, req.Review)
+	if err != nil {
+		writeError(w, err, "Failed to update rating")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rating)
+}
+
+// DeleteRating godoc
+// @Summary      Delete a movie rating
+// @Description  Removes the current user's rating and review for the movie. Requires authentication.
+// @Tags         ratings
+// @Param        id path int true "Movie ID"
+// @Success      204 "No Content"
+// @Failure      400 {string} string "Invalid movie ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to delete rating"
+// @Security     BearerAuth
+// @Router       /movies/{id}/ratings [delete]
+func (h *RatingHandler) DeleteRating(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	movieID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid movie ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteRating(r.Context(), userID, movieID); err != nil {
+		writeError(w, err, "Failed to delete rating")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetReviews godoc
+// @Summary      Get movie reviews
+// @Description  Returns user reviews for the movie, newest first. This endpoint is public.
+// @Tags         ratings
+// @Produce      json
+// @Param        id path int true "Movie ID"
+// @Param        page query int false "Page number, starting from 1"
+// @Param        page_size query int false "Page size (default 20, max 100)"
+// @Success      200 {object} service.ReviewPage
+// @Failure      400 {string} string "Invalid movie ID or pagination"
+// @Failure      500 {string} string "Failed to get reviews"
+// @Router       /movies/{id}/reviews [get]
+func (h *RatingHandler) GetReviews(w http.ResponseWriter, r *http.Request) {
+	movieID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid movie ID", http.StatusBadRequest)
+		return
+	}
+
+	page, err := queryInt(r, "page", 1)
+	if err != nil {
+		http.Error(w, "Invalid page", http.StatusBadRequest)
+		return
+	}
+	pageSize, err := queryInt(r, "page_size", 0)
+	if err != nil {
+		http.Error(w, "Invalid page_size", http.StatusBadRequest)
+		return
+	}
+
+	reviews, err := h.service.GetReviews(r.Context(), movieID, page, pageSize)
+	if err != nil {
+		writeError(w, err, "Failed to get reviews")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reviews)
+}
+
+// parseRatingRequest -> общая часть POST и PUT: пользователь, ID фильма и тело запроса
+func (h *RatingHandler) parseRatingRequest(w http.ResponseWriter, r *http.Request) (int, int, ratingRequest, bool) {
+	var req ratingRequest
+
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return 0, 0, req, false
+	}
+
+	movieID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid movie ID", http.StatusBadRequest)
+		return 0, 0, req, false
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return 0, 0, req, false
+	}
+
+	return userID, movieID, req, true
+}
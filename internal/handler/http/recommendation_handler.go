@@ -0,0 +1,103 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+const (
+	defaultRecommendationsLimit = 20
+	defaultSimilarMoviesLimit   = 10
+)
+
+type RecommendationHandler struct {
+	service *service.RecommendationService
+}
+
+func NewRecommendationHandler(s *service.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{service: s}
+}
+
+// GetMyRecommendations godoc
+// @Summary      Get personal recommendations
+// @Description  Returns "people who liked this also liked" recommendations based on the user's ratings. Users with too few ratings get popular movies instead. Requires authentication.
+// @Tags         recommendations
+// @Produce      json
+// @Param        limit query int false "Max number of movies (default 20, max 100)"
+// @Success      200 {array} service.RecommendedMovie
+// @Failure      400 {string} string "Invalid limit"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to get recommendations"
+// @Security     BearerAuth
+// @Router       /me/recommendations [get]
+func (h *RecommendationHandler) GetMyRecommendations(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit := defaultRecommendationsLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > 100 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	movies, err := h.service.GetRecommendationsForUser(r.Context(), userID, limit)
+	if err != nil {
+		log.Printf("Internal error: %v", err)
+		http.Error(w, "Failed to get recommendations", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(movies)
+}
+
+// GetSimilarMovies godoc
+// @Summary      Get similar movies
+// @Description  Returns movies liked by the same people who liked this movie, most similar first. Built from the rating similarity matrix, so movies with few ratings may have no similar movies yet. This endpoint is public; with a Bearer token movies excluded by the viewer's preferences are left out.
+// @Tags         recommendations
+// @Produce      json
+// @Param        id path int true "Movie ID"
+// @Param        limit query int false "Max number of movies (default 10, max 20)"
+// @Success      200 {array} service.SimilarMovie
+// @Failure      400 {string} string "Invalid movie ID or limit"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to get similar movies"
+// @Router       /movies/{id}/similar [get]
+func (h *RecommendationHandler) GetSimilarMovies(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid movie ID", http.StatusBadRequest)
+		return
+	}
+
+	// Соседей у фильма не больше, чем хранится в матрице похожести
+	limit, err := queryInt(r, "limit", defaultSimilarMoviesLimit)
+	if err != nil || limit <= 0 || limit > service.MaxSimilarMovies {
+		http.Error(w, "Invalid limit", http.StatusBadRequest)
+		return
+	}
+
+	// viewerID == 0, если запрос анонимный
+	viewerID, _ := UserIDFromContext(r.Context())
+
+	movies, err := h.service.GetSimilarMovies(r.Context(), id, viewerID, limit)
+	if err != nil {
+		writeError(w, err, "Failed to get similar movies")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(movies)
+}
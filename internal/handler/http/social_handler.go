@@ -0,0 +1,175 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+type SocialHandler struct {
+	service *service.SocialService
+}
+
+func NewSocialHandler(s *service.SocialService) *SocialHandler {
+	return &SocialHandler{service: s}
+}
+
+// FollowUser godoc
+// @Summary      Follow a user
+// @Description  Subscribes the current user to another user's activity. Requires authentication.
+// @Tags         social
+// @Param        id path int true "User ID"
+// @Success      204 "No Content"
+// @Failure      400 {string} string "Invalid user ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "the resource already exists"
+// @Failure      500 {string} string "Failed to follow user"
+// @Security     BearerAuth
+// @Router       /users/{id}/follow [post]
+func (h *SocialHandler) FollowUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	targetID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Follow(r.Context(), userID, targetID); err != nil {
+		writeError(w, err, "Failed to follow user")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnfollowUser godoc
+// @Summary      Unfollow a user
+// @Description  Removes the subscription to another user. Requires authentication.
+// @Tags         social
+// @Param        id path int true "User ID"
+// @Success      204 "No Content"
+// @Failure      400 {string} string "Invalid user ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to unfollow user"
+// @Security     BearerAuth
+// @Router       /users/{id}/follow [delete]
+func (h *SocialHandler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	targetID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Unfollow(r.Context(), userID, targetID); err != nil {
+		writeError(w, err, "Failed to unfollow user")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetFollowers godoc
+// @Summary      List followers
+// @Description  Returns the users who follow the given user, newest first
+// @Tags         social
+// @Produce      json
+// @Param        id path int true "User ID"
+// @Success      200 {array} ports.Follow
+// @Failure      400 {string} string "Invalid user ID"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to get followers"
+// @Router       /users/{id}/followers [get]
+func (h *SocialHandler) GetFollowers(w http.ResponseWriter, r *http.Request) {
+	targetID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	follows, err := h.service.GetFollowers(r.Context(), targetID)
+	if err != nil {
+		writeError(w, err, "Failed to get followers")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(follows)
+}
+
+// GetFollowing godoc
+// @Summary      List followed users
+// @Description  Returns the users the given user follows, newest first
+// @Tags         social
+// @Produce      json
+// @Param        id path int true "User ID"
+// @Success      200 {array} ports.Follow
+// @Failure      400 {string} string "Invalid user ID"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to get followed users"
+// @Router       /users/{id}/following [get]
+func (h *SocialHandler) GetFollowing(w http.ResponseWriter, r *http.Request) {
+	targetID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	follows, err := h.service.GetFollowing(r.Context(), targetID)
+	if err != nil {
+		writeError(w, err, "Failed to get followed users")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(follows)
+}
+
+// GetMyFeed godoc
+// @Summary      Get my activity feed
+// @Description  Ratings, reviews and diary entries of the users I follow, newest first. Pass next_cursor from the previous page as cursor to get the next one. Requires authentication.
+// @Tags         social
+// @Produce      json
+// @Param        cursor query string false "Cursor from the previous page"
+// @Param        limit query int false "Page size (default 20, max 100)"
+// @Success      200 {object} service.FeedPage
+// @Failure      400 {string} string "Invalid cursor or limit"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to get feed"
+// @Security     BearerAuth
+// @Router       /me/feed [get]
+func (h *SocialHandler) GetMyFeed(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit, err := queryInt(r, "limit", 0)
+	if err != nil {
+		http.Error(w, "Invalid limit", http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.service.GetFeed(r.Context(), userID, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		writeError(w, err, "Failed to get feed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
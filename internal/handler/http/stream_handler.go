@@ -0,0 +1,131 @@
+package http
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/ports"
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+const (
+	// streamHeartbeat -> комментарий раз в 25 секунд, чтобы прокси не закрывали тихое соединение
+	streamHeartbeat = 25 * time.Second
+	streamRetry     = 3 * time.Second // через сколько EventSource переподключается
+)
+
+type StreamHandler struct {
+	service *service.RoomService
+}
+
+func NewStreamHandler(s *service.RoomService) *StreamHandler {
+	return &StreamHandler{service: s}
+}
+
+// StreamEvent godoc
+// @Summary      Live updates of a movie night
+// @Description  Server-Sent Events stream for one event: `event.updated` and `event.cancelled` (the event, including a newly chosen movie or time), `rsvp` (who answered and the new counts), and `poll.created`, `vote` (who voted and the intermediate tally) and `poll.closed` for the event's polls. Every message has an id; after a reconnect the browser sends it in `Last-Event-ID` (or pass `last_event_id`) and missed messages are replayed. If they are no longer in the history, a `reset` message tells the client to reload the state. Browsers can't set headers on EventSource, so the token may be passed in `access_token`.
+// @Tags         events
+// @Produce      text/event-stream
+// @Param        id path int true "Event ID"
+// @Param        access_token query string false "JWT, instead of the Authorization header"
+// @Param        last_event_id query string false "Resume after this message id, instead of the Last-Event-ID header"
+// @Success      200 {string} string "Stream of server-sent events"
+// @Failure      400 {string} string "Invalid event ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to open the stream"
+// @Security     BearerAuth
+// @Router       /events/{id}/stream [get]
+func (h *StreamHandler) StreamEvent(w http.ResponseWriter, r *http.Request) {
+	userID, eventID, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	messages, err := h.service.SubscribeEvent(r.Context(), userID, eventID, lastEventID(r))
+	if err != nil {
+		writeError(w, err, "Failed to open the stream")
+		return
+	}
+	serveStream(w, r, messages)
+}
+
+// StreamPoll godoc
+// @Summary      Live updates of a poll
+// @Description  Server-Sent Events stream for one poll: `vote` (who voted and the intermediate tally) and `poll.closed` (the final tally). Resuming with `Last-Event-ID`, `reset` and `access_token` work as for event streams.
+// @Tags         polls
+// @Produce      text/event-stream
+// @Param        id path int true "Poll ID"
+// @Param        access_token query string false "JWT, instead of the Authorization header"
+// @Param        last_event_id query string false "Resume after this message id, instead of the Last-Event-ID header"
+// @Success      200 {string} string "Stream of server-sent events"
+// @Failure      400 {string} string "Invalid poll ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to open the stream"
+// @Security     BearerAuth
+// @Router       /polls/{id}/stream [get]
+func (h *StreamHandler) StreamPoll(w http.ResponseWriter, r *http.Request) {
+	userID, pollID, ok := parsePollRequest(w, r)
+	if !ok {
+		return
+	}
+
+	messages, err := h.service.SubscribePoll(r.Context(), userID, pollID, lastEventID(r))
+	if err != nil {
+		writeError(w, err, "Failed to open the stream")
+		return
+	}
+	serveStream(w, r, messages)
+}
+
+// serveStream -> пишет сообщения, пока клиент не отключится. Если канал закрылся
+// (клиент не успевал читать или пропала связь с Redis), просто завершаем ответ:
+// EventSource переподключится сам и догонит пропущенное по Last-Event-ID
+func serveStream(w http.ResponseWriter, r *http.Request, messages <-chan ports.RoomMessage) {
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx не должен буферизовать поток
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+	if err := rc.Flush(); err != nil {
+		log.Printf("Error flushing event stream: %v", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case m, ok := <-messages:
+			if !ok {
+				return
+			}
+			if m.ID != "" {
+				fmt.Fprintf(w, "id: %s\n", m.ID)
+			}
+			// JSON от encoding/json не содержит переводов строк, поэтому хватает одной строки data
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", m.Type, m.Data)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case <-r.Context().Done():
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func lastEventID(r *http.Request) string {
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		return id
+	}
+	return r.URL.Query().Get("last_event_id")
+}
@@ -0,0 +1,246 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+type slotRequest struct {
+	StartsAt time.Time `json:"starts_at" example:"2026-10-23T20:00:00+05:00"`
+	EndsAt   time.Time `json:"ends_at" example:"2026-10-23T23:00:00+05:00"`
+}
+
+type slotRangeRequest struct {
+	From            string `json:"from" example:"2026-10-20"`
+	To              string `json:"to" example:"2026-10-26"`
+	DayStart        string `json:"day_start" example:"19:00"`
+	DayEnd          string `json:"day_end" example:"23:30"`
+	DurationMinutes int    `json:"duration_minutes" example:"180"`
+	StepMinutes     int    `json:"step_minutes" example:"30"`
+}
+
+type proposeSlotsRequest struct {
+	Slots []slotRequest     `json:"slots"`
+	Range *slotRangeRequest `json:"range"`
+}
+
+type slotAnswer struct {
+	SlotID       int                `json:"slot_id" example:"1"`
+	Availability ports.Availability `json:"availability" example:"available" enums:"available,if_need_be,unavailable"`
+}
+
+type availabilityRequest struct {
+	Answers []slotAnswer `json:"answers"`
+}
+
+// GetTimeSlots godoc
+// @Summary      Rank proposed time slots
+// @Description  Proposed times for the event, best first: slots where every must-attend participant can come, then by how many participants can come (available + if_need_be), then by how many marked available, then earlier first. Participants are the host and all invitees who have not declined. Requires authentication.
+// @Tags         events
+// @Produce      json
+// @Param        id path int true "Event ID"
+// @Param        must_attend query string false "Comma-separated user IDs who must be able to attend"
+// @Success      200 {object} service.SlotRanking
+// @Failure      400 {string} string "Invalid must_attend"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to get time slots"
+// @Security     BearerAuth
+// @Router       /events/{id}/slots [get]
+func (h *EventHandler) GetTimeSlots(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	mustAttend, err := queryInts(r, "must_attend")
+	if err != nil {
+		http.Error(w, "Invalid must_attend", http.StatusBadRequest)
+		return
+	}
+
+	ranking, err := h.service.GetTimeSlots(r.Context(), userID, id, mustAttend)
+	if err != nil {
+		writeError(w, err, "Failed to get time slots")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ranking)
+}
+
+// ProposeTimeSlots godoc
+// @Summary      Propose time slots
+// @Description  The host proposes candidate times: explicit slots, a date range, or both. A range produces, for every day from..to in the event's timezone, slots of duration_minutes starting at day_start every step_minutes while they fit before day_end (without day_end, one slot per day). duration_minutes defaults to the movie's runtime. Already proposed slots are skipped. Requires authentication.
+// @Tags         events
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Event ID"
+// @Param        slots body proposeSlotsRequest true "Slots and/or range"
+// @Success      201 {object} service.SlotRanking
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "a cancelled event cannot be changed"
+// @Failure      500 {string} string "Failed to propose time slots"
+// @Security     BearerAuth
+// @Router       /events/{id}/slots [post]
+func (h *EventHandler) ProposeTimeSlots(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req proposeSlotsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	proposal := service.SlotProposal{Slots: make([]service.SlotInput, 0, len(req.Slots))}
+	for _, slot := range req.Slots {
+		proposal.Slots = append(proposal.Slots, service.SlotInput{StartsAt: slot.StartsAt, EndsAt: slot.EndsAt})
+	}
+	if req.Range != nil {
+		proposal.Range = &service.SlotRange{
+			From:     req.Range.From,
+			To:       req.Range.To,
+			DayStart: req.Range.DayStart,
+			DayEnd:   req.Range.DayEnd,
+			Duration: req.Range.DurationMinutes,
+			Step:     req.Range.StepMinutes,
+		}
+	}
+
+	ranking, err := h.service.ProposeTimeSlots(r.Context(), userID, id, proposal)
+	if err != nil {
+		writeError(w, err, "Failed to propose time slots")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(ranking)
+}
+
+// SetAvailability godoc
+// @Summary      Mark availability
+// @Description  The current user marks proposed slots as available, if_need_be or unavailable. Slots not mentioned keep their previous answer. Requires authentication.
+// @Tags         events
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Event ID"
+// @Param        answers body availabilityRequest true "Answers per slot"
+// @Success      200 {object} service.SlotRanking
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "the event is cancelled"
+// @Failure      500 {string} string "Failed to save availability"
+// @Security     BearerAuth
+// @Router       /events/{id}/slots/availability [put]
+func (h *EventHandler) SetAvailability(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req availabilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	answers := make(map[int]ports.Availability, len(req.Answers))
+	for _, a := range req.Answers {
+		answers[a.SlotID] = a.Availability
+	}
+
+	ranking, err := h.service.SetAvailability(r.Context(), userID, id, answers)
+	if err != nil {
+		writeError(w, err, "Failed to save availability")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ranking)
+}
+
+// DeleteTimeSlot godoc
+// @Summary      Remove a proposed time slot
+// @Description  Removes the slot together with everyone's answers for it. Only the host can do this. Requires authentication.
+// @Tags         events
+// @Param        id path int true "Event ID"
+// @Param        slotID path int true "Time slot ID"
+// @Success      204 "No Content"
+// @Failure      400 {string} string "Invalid event or slot ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to delete time slot"
+// @Security     BearerAuth
+// @Router       /events/{id}/slots/{slotID} [delete]
+func (h *EventHandler) DeleteTimeSlot(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	slotID, err := strconv.Atoi(chi.URLParam(r, "slotID"))
+	if err != nil {
+		http.Error(w, "Invalid slot ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteTimeSlot(r.Context(), userID, id, slotID); err != nil {
+		writeError(w, err, "Failed to delete time slot")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ChooseTimeSlot godoc
+// @Summary      Use a time slot as the event's start time
+// @Description  Moves the event's start to the chosen slot. Only the host can do this. Requires authentication.
+// @Tags         events
+// @Produce      json
+// @Param        id path int true "Event ID"
+// @Param        slotID path int true "Time slot ID"
+// @Success      200 {object} ports.Event
+// @Failure      400 {string} string "starts_at must be in the future"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "you are not allowed to perform this action"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "a cancelled event cannot be changed"
+// @Failure      500 {string} string "Failed to update event"
+// @Security     BearerAuth
+// @Router       /events/{id}/slots/{slotID}/choose [post]
+func (h *EventHandler) ChooseTimeSlot(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := parseEventRequest(w, r)
+	if !ok {
+		return
+	}
+
+	slotID, err := strconv.Atoi(chi.URLParam(r, "slotID"))
+	if err != nil {
+		http.Error(w, "Invalid slot ID", http.StatusBadRequest)
+		return
+	}
+
+	event, err := h.service.ChooseTimeSlot(r.Context(), userID, id, slotID)
+	if err != nil {
+		writeError(w, err, "Failed to update event")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(event)
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+type UserHandler struct {
+	userSvc *service.UserService
+}
+
+func NewUserHandler(userSvc *service.UserService) *UserHandler {
+	return &UserHandler{userSvc: userSvc}
+}
+
+type updateProfileRequest struct {
+	DisplayName *string `json:"display_name" example:"Aigerim"`
+	Timezone    *string `json:"timezone" example:"Asia/Almaty"`
+	Locale      *string `json:"locale" example:"ru-KZ"`
+	AvatarURL   *string `json:"avatar_url" example:"https://example.com/avatar.png"`
+}
+
+// GetMe godoc
+// @Summary      Get my profile
+// @Description  Returns the profile of the authenticated user. Requires authentication.
+// @Tags         users
+// @Produce      json
+// @Success      200 {object} ports.User
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to get profile"
+// @Security     BearerAuth
+// @Router       /me [get]
+func (h *UserHandler) GetMe(w http.ResponseWriter, r *http.Request) {
+	user, err := CurrentUser(r.Context(), h.userSvc)
+	if err != nil {
+		writeError(w, err, "Failed to get profile")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// UpdateMe godoc
+// @Summary      Update my profile
+// @Description  Changes display name, timezone, locale and/or avatar URL. Omitted fields stay unchanged. Requires authentication.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        profile body updateProfileRequest true "Fields to change"
+// @Success      200 {object} ports.User
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to update profile"
+// @Security     BearerAuth
+// @Router       /me [patch]
+func (h *UserHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req updateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.userSvc.UpdateProfile(r.Context(), userID, service.ProfileUpdate{
+		DisplayName: req.DisplayName,
+		Timezone:    req.Timezone,
+		Locale:      req.Locale,
+		AvatarURL:   req.AvatarURL,
+	})
+	if err != nil {
+		writeError(w, err, "Failed to update profile")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
@@ -0,0 +1,175 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/turysbekovg/movie-planner/internal/service"
+)
+
+type WatchlistHandler struct {
+	service *service.WatchlistService
+}
+
+func NewWatchlistHandler(s *service.WatchlistService) *WatchlistHandler {
+	return &WatchlistHandler{service: s}
+}
+
+type addToWatchlistRequest struct {
+	MovieID  int    `json:"movie_id" example:"1"`
+	Priority *int   `json:"priority" example:"3"`
+	Notes    string `json:"notes" example:"Watch with Aigerim"`
+}
+
+type updateWatchlistRequest struct {
+	Priority *int    `json:"priority" example:"5"`
+	Notes    *string `json:"notes" example:"Before the sequel comes out"`
+}
+
+// GetWatchlist godoc
+// @Summary      Get my watchlist
+// @Description  Returns the current user's watchlist. Requires authentication.
+// @Tags         watchlist
+// @Produce      json
+// @Param        sort query string false "Sort field" Enums(added, priority, rating)
+// @Param        order query string false "Sort order (default desc)" Enums(asc, desc)
+// @Success      200 {array} ports.WatchlistItem
+// @Failure      400 {string} string "Invalid sort or order"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      500 {string} string "Failed to get watchlist"
+// @Security     BearerAuth
+// @Router       /me/watchlist [get]
+func (h *WatchlistHandler) GetWatchlist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	items, err := h.service.GetWatchlist(r.Context(), userID, r.URL.Query().Get("sort"), r.URL.Query().Get("order"))
+	if err != nil {
+		writeError(w, err, "Failed to get watchlist")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
+
+// AddToWatchlist godoc
+// @Summary      Add a movie to my watchlist
+// @Description  Saves a movie to the current user's watchlist with an optional priority (1-5, default 3) and notes. Requires authentication.
+// @Tags         watchlist
+// @Accept       json
+// @Produce      json
+// @Param        item body addToWatchlistRequest true "Movie, priority and notes"
+// @Success      201 {object} ports.WatchlistItem
+// @Failure      400 {string} string "Invalid request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      409 {string} string "the resource already exists"
+// @Failure      500 {string} string "Failed to add to watchlist"
+// @Security     BearerAuth
+// @Router       /me/watchlist [post]
+func (h *WatchlistHandler) AddToWatchlist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req addToWatchlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.service.AddToWatchlist(r.Context(), userID, req.MovieID, req.Priority, req.Notes)
+	if err != nil {
+		writeError(w, err, "Failed to add to watchlist")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(item)
+}
+
+// UpdateWatchlistItem godoc
+// @Summary      Update a watchlist entry
+// @Description  Changes priority and/or notes of a movie in the current user's watchlist. Omitted fields stay unchanged. Requires authentication.
+// @Tags         watchlist
+// @Accept       json
+// @Produce      json
+// @Param        movieID path int true "Movie ID"
+// @Param        item body updateWatchlistRequest true "Fields to change"
+// @Success      200 {object} ports.WatchlistItem
+// @Failure      400 {string} string "Invalid movie ID or request body"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to update watchlist"
+// @Security     BearerAuth
+// @Router       /me/watchlist/{movieID} [patch]
+func (h *WatchlistHandler) UpdateWatchlistItem(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	movieID, err := strconv.Atoi(chi.URLParam(r, "movieID"))
+	if err != nil {
+		http.Error(w, "Invalid movie ID", http.StatusBadRequest)
+		return
+	}
+
+	var req updateWatchlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.service.UpdateWatchlistItem(r.Context(), userID, movieID, req.Priority, req.Notes)
+	if err != nil {
+		writeError(w, err, "Failed to update watchlist")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
+}
+
+// RemoveFromWatchlist godoc
+// @Summary      Remove a movie from my watchlist
+// @Description  Removes a movie from the current user's watchlist. Requires authentication.
+// @Tags         watchlist
+// @Param        movieID path int true "Movie ID"
+// @Success      204 "No Content"
+// @Failure      400 {string} string "Invalid movie ID"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      404 {string} string "the requested resource was not found"
+// @Failure      500 {string} string "Failed to remove from watchlist"
+// @Security     BearerAuth
+// @Router       /me/watchlist/{movieID} [delete]
+func (h *WatchlistHandler) RemoveFromWatchlist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	movieID, err := strconv.Atoi(chi.URLParam(r, "movieID"))
+	if err != nil {
+		http.Error(w, "Invalid movie ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.RemoveFromWatchlist(r.Context(), userID, movieID); err != nil {
+		writeError(w, err, "Failed to remove from watchlist")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,32 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// DeletedAccount -> что поменялось за пределами данных пользователя после удаления аккаунта
+type DeletedAccount struct {
+	// RatedMovieIDs -> фильмы, у которых пересчитан community_rating
+	RatedMovieIDs []int
+	// Promoted -> ID вечера -> пользователи, которые заняли освободившееся место из листа ожидания
+	Promoted map[int][]int
+}
+
+// AccountRepository -> отложенное удаление аккаунта
+type AccountRepository interface {
+	ScheduleAccountDeletion(ctx context.Context, userID int, at time.Time) error
+	CancelAccountDeletion(ctx context.Context, userID int) error
+	// GetAccountsDueForDeletion -> ID пользователей, у которых срок удаления уже наступил
+	GetAccountsDueForDeletion(ctx context.Context, now time.Time, limit int) ([]int, error)
+	// DeleteAccount -> удаляет пользователя и все его данные, пересчитывая общие агрегаты
+	DeleteAccount(ctx context.Context, userID int) (*DeletedAccount, error)
+}
+
+// TokenRevoker -> отзыв уже выданных JWT. Токены не хранятся на сервере,
+// поэтому отзываем разом все токены пользователя, выданные до момента at
+type TokenRevoker interface {
+	RevokeUserTokens(ctx context.Context, userID int, at time.Time) error
+	// TokensRevokedAt -> момент последнего отзыва, нулевое время -> токены не отзывались
+	TokensRevokedAt(ctx context.Context, userID int) (time.Time, error)
+}
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// CalendarFeed -> ссылка на подписку в календаре. Токен виден только в ответе на создание
+type CalendarFeed struct {
+	URL       string    `json:"url" example:"https://movie-planner.example/calendar/3q2-7wEAAAA.ics"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CalendarFeedRepository interface {
+	// SaveCalendarFeed -> создает или заменяет ссылку пользователя, старая перестает работать
+	SaveCalendarFeed(ctx context.Context, userID int, tokenHash string) (time.Time, error)
+	// DeleteCalendarFeed -> ErrNotFound, если подписки нет
+	DeleteCalendarFeed(ctx context.Context, userID int) error
+	// GetCalendarFeedUser -> владелец ссылки, ErrNotFound если токен не найден
+	GetCalendarFeedUser(ctx context.Context, tokenHash string) (int, error)
+}
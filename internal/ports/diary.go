@@ -0,0 +1,41 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// DiaryEntry -> запись в дневнике: какой фильм и когда посмотрели
+type DiaryEntry struct {
+	ID        int        `json:"id" example:"1"`
+	UserID    int        `json:"-"`
+	MovieID   int        `json:"movie_id" example:"1"`
+	WatchedOn CustomDate `json:"watched_on"`
+	Rating    *float64   `json:"rating,omitempty" example:"8.5"` // оценка на момент просмотра
+	Rewatch   bool       `json:"rewatch" example:"false"`
+	Location  string     `json:"location" example:"Office lounge, Friday movie night"`
+	Notes     string     `json:"notes" example:"Popcorn ran out halfway"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Movie     *Movie     `json:"movie,omitempty"`
+}
+
+// MovieWatchCount -> сколько раз пользователь смотрел фильм
+type MovieWatchCount struct {
+	MovieID        int        `json:"movie_id" example:"1"`
+	Title          string     `json:"title" example:"Inception"`
+	WatchCount     int        `json:"watch_count" example:"3"`
+	FirstWatchedOn CustomDate `json:"first_watched_on"`
+	LastWatchedOn  CustomDate `json:"last_watched_on"`
+}
+
+type DiaryRepository interface {
+	CreateDiaryEntry(ctx context.Context, entry *DiaryEntry) error
+	GetDiaryEntry(ctx context.Context, userID, id int) (*DiaryEntry, error)
+	UpdateDiaryEntry(ctx context.Context, entry *DiaryEntry) error
+	DeleteDiaryEntry(ctx context.Context, userID, id int) error
+	// GetDiaryEntries -> записи с from (включительно) по to (не включительно), по дате просмотра
+	GetDiaryEntries(ctx context.Context, userID int, from, to time.Time) ([]*DiaryEntry, error)
+	GetMovieWatchCounts(ctx context.Context, userID int) ([]*MovieWatchCount, error)
+	CountMovieWatches(ctx context.Context, userID, movieID int) (int, error)
+}
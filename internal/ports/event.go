@@ -0,0 +1,190 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// EventStatus -> состояние киновечера. Отмененный вечер не удаляется,
+// чтобы приглашенные увидели, что он отменен
+type EventStatus string
+
+const (
+	EventScheduled EventStatus = "scheduled"
+	EventCancelled EventStatus = "cancelled"
+)
+
+// RSVPStatus -> ответ приглашенного. going, maybe и declined выбирает сам пользователь,
+// waitlisted ставится вместо going, когда мест нет
+type RSVPStatus string
+
+const (
+	RSVPPending    RSVPStatus = "pending"
+	RSVPGoing      RSVPStatus = "going"
+	RSVPMaybe      RSVPStatus = "maybe"
+	RSVPDeclined   RSVPStatus = "declined"
+	RSVPWaitlisted RSVPStatus = "waitlisted"
+)
+
+// Event -> киновечер, который организует хост
+type Event struct {
+	ID           int         `json:"id" example:"1"`
+	Host         UserSummary `json:"host"`
+	Title        string      `json:"title" example:"Friday heist night"`
+	Description  string      `json:"description" example:"Bring snacks"`
+	StartsAt     time.Time   `json:"starts_at" example:"2026-10-23T20:00:00+05:00"`
+	Timezone     string      `json:"timezone" example:"Asia/Almaty"`
+	Location     string      `json:"location" example:"Aigerim's place, Abay ave 10"`
+	Capacity     int         `json:"capacity" example:"8"` // 0 -> без ограничения, хост тоже занимает место
+	MovieID      *int        `json:"movie_id,omitempty" example:"12"`
+	MovieTitle   string      `json:"movie_title,omitempty" example:"Heat"`
+	MovieRuntime int         `json:"movie_runtime,omitempty" example:"170"` // в минутах, 0 -> неизвестна
+	Status       EventStatus `json:"status" example:"scheduled"`
+	CancelledAt  *time.Time  `json:"cancelled_at,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+	Sequence     int         `json:"-"` // версия для iCalendar, растет при изменении и отмене
+	// GuestLinkVersion -> версия гостевых ссылок, ее увеличение отключает все выданные ссылки
+	GuestLinkVersion int `json:"-"`
+
+	// Recurrence -> RRULE серии (FREQ=WEEKLY;BYDAY=FR), пусто -> разовый вечер
+	Recurrence string `json:"recurrence,omitempty" example:"FREQ=WEEKLY;BYDAY=FR"`
+	// RecurrenceEnd -> начало последнего повторения, nil -> серия бесконечна
+	RecurrenceEnd *time.Time `json:"recurrence_end,omitempty"`
+	// OccurrenceID -> у одного повторения серии: его исходное начало по правилу (UTC),
+	// по нему повторение меняют и отменяют отдельно
+	OccurrenceID string `json:"occurrence_id,omitempty" example:"20261023T150000Z"`
+	// Overrides -> измененные и отмененные повторения серии
+	Overrides []*OccurrenceOverride `json:"-"`
+
+	GoingCount     int  `json:"going_count" example:"5"` // приглашенные и гости со статусом going, без хоста
+	MaybeCount     int  `json:"maybe_count" example:"1"`
+	DeclinedCount  int  `json:"declined_count" example:"2"`
+	WaitlistCount  int  `json:"waitlist_count" example:"1"`
+	SpotsAvailable *int `json:"spots_available,omitempty" example:"2"` // nil -> без ограничения
+
+	Invitations []*EventInvitation `json:"invitations,omitempty"`
+	Guests      []*EventGuest      `json:"guests,omitempty"`
+}
+
+// OccurrenceOverride -> изменения одного повторения серии, nil -> как у всей серии.
+// Приглашения и ответы общие для всей серии
+type OccurrenceOverride struct {
+	EventID          int
+	OriginalStartsAt time.Time  // начало по правилу, определяет повторение
+	CancelledAt      *time.Time // не nil -> повторение отменено
+	Title            *string
+	Description      *string
+	Location         *string
+	StartsAt         *time.Time
+	MovieSet         bool // true -> фильм заменен на MovieID (nil -> без фильма)
+	MovieID          *int
+	MovieTitle       string
+	MovieRuntime     int
+	Sequence         int
+	UpdatedAt        time.Time
+}
+
+// EventInvitation -> приглашенный на вечер пользователь и его ответ
+type EventInvitation struct {
+	User             UserSummary `json:"user"`
+	InvitedAt        time.Time   `json:"invited_at"`
+	RSVP             RSVPStatus  `json:"rsvp" example:"going"`
+	RespondedAt      *time.Time  `json:"responded_at,omitempty"`
+	WaitlistPosition int         `json:"waitlist_position,omitempty" example:"1"` // 1 -> следующий в очереди
+}
+
+// Availability -> может ли участник в предложенное время
+type Availability string
+
+const (
+	AvailabilityAvailable   Availability = "available"
+	AvailabilityIfNeedBe    Availability = "if_need_be" // может, но неудобно
+	AvailabilityUnavailable Availability = "unavailable"
+)
+
+// TimeSlot -> вариант времени для вечера
+type TimeSlot struct {
+	ID        int             `json:"id" example:"1"`
+	EventID   int             `json:"event_id" example:"3"`
+	StartsAt  time.Time       `json:"starts_at" example:"2026-10-23T20:00:00+05:00"`
+	EndsAt    time.Time       `json:"ends_at" example:"2026-10-23T23:00:00+05:00"`
+	CreatedAt time.Time       `json:"created_at"`
+	Responses []*SlotResponse `json:"responses"`
+}
+
+// SlotResponse -> отметка участника для варианта времени
+type SlotResponse struct {
+	UserID       int          `json:"user_id" example:"2"`
+	Availability Availability `json:"availability" example:"available"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// UserSlotAvailability -> отметка пользователя вместе с вариантом времени (для выгрузки данных)
+type UserSlotAvailability struct {
+	EventID      int          `json:"event_id" example:"3"`
+	SlotID       int          `json:"slot_id" example:"1"`
+	StartsAt     time.Time    `json:"starts_at"`
+	EndsAt       time.Time    `json:"ends_at"`
+	Availability Availability `json:"availability" example:"available"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// RSVPResult -> чем закончился ответ: итоговый статус (going мог превратиться в waitlisted)
+// и кто поднялся из листа ожидания на освободившиеся места
+type RSVPResult struct {
+	Status   RSVPStatus
+	Promoted []int
+}
+
+type EventRepository interface {
+	// CreateEvent -> создает вечер вместе с приглашениями одной транзакцией.
+	// ErrNotFound, если нет фильма или кого-то из приглашенных, тогда вечер не создается
+	CreateEvent(ctx context.Context, e *Event, inviteeIDs []int) error
+	GetEvent(ctx context.Context, id int) (*Event, error)
+	// UpdateEvent -> ErrConflict, если новая вместимость меньше числа идущих.
+	// Если мест стало больше, поднимает людей из листа ожидания и возвращает их ID
+	UpdateEvent(ctx context.Context, e *Event) ([]int, error)
+	CancelEvent(ctx context.Context, id int) error
+	// GetEventsForUser -> вечера, где пользователь хост или приглашен, со starts_at в [from, to),
+	// и серии, у которых в [from, to) могут быть повторения (их разворачивает сервис)
+	GetEventsForUser(ctx context.Context, userID int, from, to time.Time) ([]*Event, error)
+	// GetCalendarEvents -> вечера для подписки: пользователь хост или ответил going/maybe,
+	// starts_at >= from (у серий -> есть повторения после from). Отмененные тоже, чтобы календарь узнал об отмене
+	GetCalendarEvents(ctx context.Context, userID int, from time.Time) ([]*Event, error)
+
+	// GetOccurrenceOverrides -> изменения повторений нескольких серий сразу
+	GetOccurrenceOverrides(ctx context.Context, eventIDs []int) ([]*OccurrenceOverride, error)
+	// SaveOccurrenceOverride -> создает или заменяет изменение повторения, ErrNotFound если нет фильма
+	SaveOccurrenceOverride(ctx context.Context, o *OccurrenceOverride) error
+	// DeleteOccurrenceOverrides -> удаляет изменения повторений с исходным началом >= from
+	DeleteOccurrenceOverrides(ctx context.Context, eventID int, from time.Time) error
+	// SplitEventSeries -> "изменить это и следующие": в одной транзакции обрезает серию old
+	// (ее новое правило уже в old) и создает серию next с копией приглашений и ответов.
+	// Изменения повторений после from переходят к next, если moveOverrides, иначе удаляются.
+	// Возвращает тех, кто в новой серии поднялся из листа ожидания
+	SplitEventSeries(ctx context.Context, old, next *Event, from time.Time, moveOverrides bool) ([]int, error)
+
+	GetEventInvitations(ctx context.Context, eventID int) ([]*EventInvitation, error)
+	GetEventGuests(ctx context.Context, eventID int) ([]*EventGuest, error)
+	IsInvitedToEvent(ctx context.Context, eventID, userID int) (bool, error)
+	// AddEventInvitations -> возвращает только новых приглашенных, уже приглашенные пропускаются.
+	// ErrNotFound, если кого-то из пользователей нет
+	AddEventInvitations(ctx context.Context, eventID, invitedBy int, userIDs []int) ([]int, error)
+	// RemoveEventInvitation -> если у пользователя было место, его занимает первый из листа ожидания
+	RemoveEventInvitation(ctx context.Context, eventID, userID int) ([]int, error)
+	// SetEventRSVP -> меняет ответ под блокировкой вечера, чтобы не превысить вместимость
+	// при одновременных ответах. ErrNotFound, если пользователь не приглашен
+	SetEventRSVP(ctx context.Context, eventID, userID int, rsvp RSVPStatus) (*RSVPResult, error)
+
+	// AddEventTimeSlots -> добавляет варианты времени, уже существующие пропускаются
+	AddEventTimeSlots(ctx context.Context, eventID int, slots []*TimeSlot) error
+	// GetEventTimeSlots -> варианты по времени начала вместе с отметками участников
+	GetEventTimeSlots(ctx context.Context, eventID int) ([]*TimeSlot, error)
+	// DeleteEventTimeSlot -> ErrNotFound, если у вечера нет такого варианта
+	DeleteEventTimeSlot(ctx context.Context, eventID, slotID int) error
+	// SetSlotAvailability -> сохраняет отметки пользователя одной транзакцией.
+	// ErrNotFound, если какой-то вариант не относится к вечеру
+	SetSlotAvailability(ctx context.Context, eventID, userID int, answers map[int]Availability) error
+	GetSlotAvailabilityByUser(ctx context.Context, userID int) ([]*UserSlotAvailability, error)
+}
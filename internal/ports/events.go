@@ -0,0 +1,43 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// Типы доменных событий
+const (
+	EventRatingCreated     = "rating.created"
+	EventRatingUpdated     = "rating.updated"
+	EventRatingDeleted     = "rating.deleted"
+	EventDiaryEntryCreated = "diary_entry.created"
+	EventDiaryEntryUpdated = "diary_entry.updated"
+	EventDiaryEntryDeleted = "diary_entry.deleted"
+	EventListUpdated       = "list.updated" // изменился публичный список
+	EventListRemoved       = "list.removed" // список удален или перестал быть публичным
+
+	// Киновечера и опросы (RefID -> ID вечера или опроса)
+	EventMovieNightCreated   = "movie_night.created"
+	EventMovieNightUpdated   = "movie_night.updated" // хост поменял вечер, в том числе фильм или время
+	EventMovieNightCancelled = "movie_night.cancelled"
+	EventRSVPChanged         = "movie_night.rsvp_changed" // ответ приглашенного или перемещения в листе ожидания
+	EventPollCreated         = "poll.created"
+	EventBallotCast          = "poll.ballot_cast"
+	EventPollClosed          = "poll.closed"
+)
+
+// DomainEvent -> что-то произошло в предметной области. Сервисы публикуют события,
+// а побочные эффекты (лента активности и т.п.) живут в подписчиках
+type DomainEvent struct {
+	Type       string
+	ActorID    int // кто совершил действие
+	MovieID    int // 0, если событие не про фильм
+	RefID      int // ID затронутой сущности (для оценки -> ID фильма)
+	Payload    map[string]any
+	OccurredAt time.Time
+}
+
+// EventPublisher -> через него сервисы публикуют доменные события
+type EventPublisher interface {
+	Publish(ctx context.Context, event DomainEvent)
+}
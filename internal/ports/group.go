@@ -0,0 +1,80 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// GroupRole -> роль участника группы
+type GroupRole string
+
+const (
+	GroupRoleOwner  GroupRole = "owner"  // все права, включая роли и удаление группы
+	GroupRoleAdmin  GroupRole = "admin"  // ссылка-приглашение, настройки группы, удаление участников
+	GroupRoleMember GroupRole = "member" // общий список и опросы
+)
+
+// Group -> постоянная группа, которая смотрит кино вместе
+type Group struct {
+	ID          int       `json:"id" example:"1"`
+	Name        string    `json:"name" example:"Office lounge crew"`
+	Description string    `json:"description" example:"Fridays after 19:00"`
+	MemberCount int       `json:"member_count" example:"6"`
+	MyRole      GroupRole `json:"my_role,omitempty" example:"admin"`
+	InviteToken string    `json:"invite_token,omitempty" example:"3q2-7wEAAAA"` // только для owner и admin
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	Members []*GroupMember `json:"members,omitempty"`
+}
+
+// GroupMember -> участник группы
+type GroupMember struct {
+	User     UserSummary `json:"user"`
+	Role     GroupRole   `json:"role" example:"member"`
+	JoinedAt time.Time   `json:"joined_at"`
+}
+
+// GroupWatchlistItem -> фильм в общем списке группы
+type GroupWatchlistItem struct {
+	MovieID   int          `json:"movie_id" example:"12"`
+	Movie     *Movie       `json:"movie,omitempty"`
+	AddedBy   *UserSummary `json:"added_by,omitempty"` // nil -> автор удалил аккаунт
+	Note      string       `json:"note" example:"Someone said it's better than Heat"`
+	AddedAt   time.Time    `json:"added_at"`
+	Votes     int          `json:"votes" example:"4"`
+	VotedByMe bool         `json:"voted_by_me" example:"true"`
+	// SeenBy -> участники, у которых фильм есть в дневнике
+	SeenBy []int `json:"seen_by" example:"2"`
+}
+
+type GroupRepository interface {
+	// CreateGroup -> группа и ownerID в ней как owner, в одной транзакции
+	CreateGroup(ctx context.Context, g *Group, ownerID int) error
+	GetGroup(ctx context.Context, id int) (*Group, error)
+	GetGroupByInviteToken(ctx context.Context, token string) (*Group, error)
+	UpdateGroup(ctx context.Context, g *Group) error
+	DeleteGroup(ctx context.Context, id int) error
+	// GetUserGroups -> группы пользователя с его ролью
+	GetUserGroups(ctx context.Context, userID int) ([]*Group, error)
+
+	GetGroupMembers(ctx context.Context, groupID int) ([]*GroupMember, error)
+	// GetGroupRole -> ErrNotFound, если пользователь не участник
+	GetGroupRole(ctx context.Context, groupID, userID int) (GroupRole, error)
+	// AddGroupMember -> ErrConflict, если пользователь уже в группе
+	AddGroupMember(ctx context.Context, groupID, userID int, role GroupRole) error
+	SetGroupRole(ctx context.Context, groupID, userID int, role GroupRole) error
+	// TransferGroupOwnership -> новый owner, прежний становится admin
+	TransferGroupOwnership(ctx context.Context, groupID, fromID, toID int) error
+	RemoveGroupMember(ctx context.Context, groupID, userID int) error
+
+	// GetGroupWatchlist -> по числу голосов, затем по дате добавления
+	GetGroupWatchlist(ctx context.Context, groupID, viewerID int) ([]*GroupWatchlistItem, error)
+	GetGroupWatchlistItem(ctx context.Context, groupID, movieID, viewerID int) (*GroupWatchlistItem, error)
+	// AddGroupWatchlistItem -> ErrConflict если фильм уже в списке, ErrNotFound если фильма нет
+	AddGroupWatchlistItem(ctx context.Context, groupID, userID, movieID int, note string) error
+	RemoveGroupWatchlistItem(ctx context.Context, groupID, movieID int) error
+	// VoteGroupWatchlistItem -> повторный голос ничего не меняет
+	VoteGroupWatchlistItem(ctx context.Context, groupID, movieID, userID int) error
+	UnvoteGroupWatchlistItem(ctx context.Context, groupID, movieID, userID int) error
+}
@@ -0,0 +1,47 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// EventGuest -> гость без аккаунта, пришедший на вечер по ссылке
+type EventGuest struct {
+	ID               int        `json:"id" example:"4"`
+	EventID          int        `json:"-"`
+	DisplayName      string     `json:"display_name" example:"Dana (Aigerim's sister)"`
+	RSVP             RSVPStatus `json:"rsvp" example:"going"`
+	RespondedAt      *time.Time `json:"responded_at,omitempty"`
+	WaitlistPosition int        `json:"waitlist_position,omitempty" example:"1"` // место в общей очереди вечера
+	JoinedAt         time.Time  `json:"joined_at"`
+}
+
+// GuestLink -> подписанная ссылка-приглашение для гостей. Токен не хранится,
+// поэтому ссылку видно только в ответе на ее создание
+type GuestLink struct {
+	URL       string    `json:"url" example:"http://localhost:8080/guest/invites/eyJwIjp7..."`
+	Token     string    `json:"token" example:"eyJwIjp7..."`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type GuestRepository interface {
+	// CreateEventGuest -> ErrConflict, если вечер отменен
+	CreateEventGuest(ctx context.Context, g *EventGuest, tokenHash string) error
+	GetEventGuest(ctx context.Context, id int) (*EventGuest, error)
+	GetEventGuestByToken(ctx context.Context, tokenHash string) (*EventGuest, error)
+	// RemoveEventGuest -> если у гостя было место, его занимает первый из очереди
+	RemoveEventGuest(ctx context.Context, eventID, guestID int) ([]int, error)
+	// SetGuestRSVP -> как SetEventRSVP, но для гостя: та же блокировка вечера и общая очередь
+	SetGuestRSVP(ctx context.Context, eventID, guestID int, rsvp RSVPStatus) (*RSVPResult, error)
+	// RevokeGuestLinks -> увеличивает версию гостевых ссылок вечера
+	RevokeGuestLinks(ctx context.Context, eventID int) error
+
+	GetGuestBallot(ctx context.Context, pollID, guestID int) (*Ballot, error)
+	// SaveGuestBallot -> ErrConflict, если опрос сейчас не открыт
+	SaveGuestBallot(ctx context.Context, pollID, guestID int, choices []int) error
+
+	// ClaimEventGuest -> переносит ответ и бюллетени гостя на пользователя и удаляет гостя.
+	// Если пользователь уже ответил или голосовал сам, его ответ и бюллетени остаются.
+	// Возвращает тех, кто поднялся из очереди на освободившееся место
+	ClaimEventGuest(ctx context.Context, guestID, userID int) ([]int, error)
+}
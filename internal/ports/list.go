@@ -0,0 +1,62 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// ListVisibility -> кто видит список
+type ListVisibility string
+
+const (
+	ListPrivate  ListVisibility = "private"  // только владелец
+	ListUnlisted ListVisibility = "unlisted" // владелец и все, у кого есть ссылка с share_token
+	ListPublic   ListVisibility = "public"   // все, список виден в поиске и в популярных
+)
+
+// MovieList -> пользовательский список фильмов ("Лучшие фильмы про ограбления")
+type MovieList struct {
+	ID           int            `json:"id" example:"1"`
+	Owner        UserSummary    `json:"owner"`
+	Title        string         `json:"title" example:"Best heist films"`
+	Description  string         `json:"description" example:"Ocean's and friends"`
+	Visibility   ListVisibility `json:"visibility" example:"public"`
+	ShareToken   string         `json:"share_token,omitempty" example:"3q2-7wEAAAA"` // только для владельца
+	ClonedFromID *int           `json:"cloned_from_id,omitempty" example:"7"`
+	CloneCount   int            `json:"clone_count" example:"3"`
+	EntryCount   int            `json:"entry_count" example:"12"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	Entries      []*ListEntry   `json:"entries,omitempty"`
+}
+
+// ListEntry -> фильм на своей позиции в списке
+type ListEntry struct {
+	MovieID  int       `json:"movie_id" example:"1"`
+	Position int       `json:"position" example:"1"`
+	Note     string    `json:"note" example:"The one that started it all"`
+	AddedAt  time.Time `json:"added_at"`
+	Movie    *Movie    `json:"movie,omitempty"`
+}
+
+type ListRepository interface {
+	CreateList(ctx context.Context, list *MovieList) error
+	GetList(ctx context.Context, id int) (*MovieList, error)
+	GetListByShareToken(ctx context.Context, token string) (*MovieList, error)
+	UpdateList(ctx context.Context, list *MovieList) error
+	DeleteList(ctx context.Context, id int) error
+	GetListsByOwner(ctx context.Context, ownerID int, publicOnly bool) ([]*MovieList, error)
+	// GetPopularLists -> публичные списки, чаще всего клонированные
+	GetPopularLists(ctx context.Context, limit int) ([]*MovieList, error)
+
+	GetListEntries(ctx context.Context, listID int) ([]*ListEntry, error)
+	// AddListEntry -> добавляет фильм в конец списка, ErrConflict если он уже есть
+	AddListEntry(ctx context.Context, listID int, entry *ListEntry) error
+	UpdateListEntryNote(ctx context.Context, listID, movieID int, note string) error
+	// MoveListEntry -> ставит фильм на позицию position (1..N), остальные сдвигаются,
+	// сохраняя порядок между собой
+	MoveListEntry(ctx context.Context, listID, movieID, position int) error
+	RemoveListEntry(ctx context.Context, listID, movieID int) error
+	// CloneList -> копия списка со всеми фильмами и заметками для нового владельца
+	CloneList(ctx context.Context, sourceID int, clone *MovieList) error
+}
@@ -0,0 +1,16 @@
+package ports
+
+import "context"
+
+// MailMessage -> простое текстовое письмо
+type MailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer -> доставка писем. Реализации по умолчанию пишут в лог или в локальную папку,
+// поэтому все флоу с письмами можно проверить без настоящего SMTP
+type Mailer interface {
+	Send(ctx context.Context, msg MailMessage) error
+}
@@ -0,0 +1,102 @@
+package ports
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Типы уведомлений
+const (
+	NotificationNewFollower      = "new_follower"
+	NotificationFollowedReview   = "followed_review"
+	NotificationListShared       = "list_shared"
+	NotificationEventInvitation  = "event_invitation"
+	NotificationEventCancelled   = "event_cancelled"
+	NotificationWaitlistPromoted = "waitlist_promoted"
+	NotificationEventReminder    = "event_reminder"    // вечер скоро начнется
+	NotificationWatchlistRelease = "watchlist_release" // фильм из списка "хочу посмотреть" вышел
+)
+
+// NotificationTypes -> все типы, для которых можно настроить доставку
+var NotificationTypes = []string{
+	NotificationNewFollower,
+	NotificationFollowedReview,
+	NotificationListShared,
+	NotificationEventInvitation,
+	NotificationEventCancelled,
+	NotificationWaitlistPromoted,
+	NotificationEventReminder,
+	NotificationWatchlistRelease,
+}
+
+// Notification -> одно уведомление пользователю
+type Notification struct {
+	ID        int64           `json:"id" example:"17"`
+	UserID    int             `json:"-"`
+	Type      string          `json:"type" example:"new_follower"`
+	Message   string          `json:"message" example:"Aigerim started following you."`
+	Payload   json.RawMessage `json:"payload" swaggertype:"object"`
+	Read      bool            `json:"read" example:"false"`
+	ReadAt    *time.Time      `json:"read_at,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// NotificationPreference -> куда доставлять уведомления одного типа
+type NotificationPreference struct {
+	Type  string `json:"type" example:"event_invitation"`
+	InApp bool   `json:"in_app" example:"true"`
+	Email bool   `json:"email" example:"true"`
+}
+
+// DefaultNotificationPreference -> настройки, пока пользователь их не менял.
+// На почту по умолчанию приходит только то, что касается вечеров: приглашения, отмена, место из листа ожидания и напоминания
+func DefaultNotificationPreference(notificationType string) NotificationPreference {
+	return NotificationPreference{
+		Type:  notificationType,
+		InApp: true,
+		Email: notificationType == NotificationEventInvitation || notificationType == NotificationEventCancelled ||
+			notificationType == NotificationWaitlistPromoted || notificationType == NotificationEventReminder,
+	}
+}
+
+// Notifier -> через этот порт сервисы отправляют уведомления, не зная,
+// куда именно они будут доставлены
+type Notifier interface {
+	Notify(ctx context.Context, n *Notification) error
+}
+
+// NotificationEmail -> письмо об уведомлении в очереди на отправку
+type NotificationEmail struct {
+	ID       int64
+	UserID   int
+	To       string // адрес берется в момент отправки: пользователь мог его сменить
+	Type     string
+	Message  string
+	Attempts int
+}
+
+// NotificationEmailQueue -> очередь писем об уведомлениях (outbox). Письма отправляет фоновая задача,
+// поэтому запрос, создавший уведомление, не ждет почту
+type NotificationEmailQueue interface {
+	EnqueueNotificationEmail(ctx context.Context, n *Notification) error
+	// ClaimNotificationEmails -> берет до limit писем через FOR UPDATE SKIP LOCKED и помечает их sending
+	// с арендой до now+lease. Письма, чья аренда истекла (экземпляр упал во время отправки), забираются снова
+	ClaimNotificationEmails(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*NotificationEmail, error)
+	// MarkNotificationEmailSent и RetryNotificationEmail ничего не меняют, если письмо уже не забрано попыткой attempt
+	MarkNotificationEmailSent(ctx context.Context, id int64, attempt int) error
+	// RetryNotificationEmail -> письмо снова ждет до retryAt, после maxAttempts попыток помечается failed
+	RetryNotificationEmail(ctx context.Context, id int64, attempt int, lastError string, retryAt time.Time, maxAttempts int) error
+}
+
+type NotificationRepository interface {
+	// GetNotifications -> от новых к старым, с id < before (0 -> с начала)
+	GetNotifications(ctx context.Context, userID int, unreadOnly bool, before int64, limit int) ([]*Notification, error)
+	CountUnreadNotifications(ctx context.Context, userID int) (int, error)
+	// MarkNotificationsRead -> пустой ids -> отметить все. Возвращает число отмеченных
+	MarkNotificationsRead(ctx context.Context, userID int, ids []int64) (int, error)
+	// GetNotificationPreference -> значение по умолчанию, если пользователь его не менял
+	GetNotificationPreference(ctx context.Context, userID int, notificationType string) (NotificationPreference, error)
+	GetNotificationPreferences(ctx context.Context, userID int) ([]NotificationPreference, error)
+	SetNotificationPreference(ctx context.Context, userID int, pref NotificationPreference) error
+}
@@ -0,0 +1,74 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// VotingMethod -> как считаются голоса в опросе
+type VotingMethod string
+
+const (
+	VotingPlurality VotingMethod = "plurality" // один фильм в бюллетене
+	VotingApproval  VotingMethod = "approval"  // любое число подходящих фильмов
+	VotingIRV       VotingMethod = "irv"       // рейтинг фильмов, instant-runoff
+	VotingBorda     VotingMethod = "borda"     // рейтинг фильмов, очки по местам
+)
+
+// Poll -> опрос по выбору фильма для киновечера
+type Poll struct {
+	ID          int              `json:"id" example:"1"`
+	EventID     *int             `json:"event_id,omitempty" example:"3"`
+	GroupID     *int             `json:"group_id,omitempty" example:"2"` // опрос либо вечера, либо группы
+	CreatedBy   int              `json:"created_by" example:"1"`
+	Title       string           `json:"title" example:"What do we watch on Friday?"`
+	Method      VotingMethod     `json:"method" example:"irv"`
+	OpensAt     time.Time        `json:"opens_at"`
+	ClosesAt    time.Time        `json:"closes_at"`
+	CreatedAt   time.Time        `json:"created_at"`
+	BallotCount int              `json:"ballot_count" example:"6"`
+	Candidates  []*PollCandidate `json:"candidates"`
+	// MyBallot -> бюллетень текущего пользователя, если он уже голосовал
+	MyBallot []int `json:"my_ballot,omitempty" example:"12,7"`
+}
+
+// PollCandidate -> фильм-кандидат
+type PollCandidate struct {
+	MovieID  int    `json:"movie_id" example:"12"`
+	Title    string `json:"title" example:"Heat"`
+	Position int    `json:"position" example:"1"`
+}
+
+// Ballot -> бюллетень пользователя
+type Ballot struct {
+	PollID    int       `json:"poll_id" example:"1"`
+	UserID    int       `json:"-"`
+	Choices   []int     `json:"choices" example:"12,7"`
+	CastAt    time.Time `json:"cast_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type PollRepository interface {
+	// CreatePoll -> опрос вместе с кандидатами, ErrNotFound если какого-то фильма нет
+	CreatePoll(ctx context.Context, p *Poll) error
+	GetPoll(ctx context.Context, id int) (*Poll, error)
+	GetEventPolls(ctx context.Context, eventID int) ([]*Poll, error)
+	GetGroupPolls(ctx context.Context, groupID int) ([]*Poll, error)
+	// ClosePoll -> досрочно закрывает опрос (closes_at = сейчас), ErrConflict если он уже закрыт.
+	// О закрытии сообщает сам сервис, поэтому опрос сразу отмечается как объявленный
+	ClosePoll(ctx context.Context, id int) error
+	// ClaimClosedPolls -> до limit опросов, закрывшихся по closes_at, о которых еще не сообщали.
+	// Отмечает их через FOR UPDATE SKIP LOCKED, поэтому каждый опрос достанется одному экземпляру
+	ClaimClosedPolls(ctx context.Context, limit int) ([]int, error)
+	// GetOpenPollsByCreator -> ID еще не закрывшихся опросов, созданных пользователем
+	GetOpenPollsByCreator(ctx context.Context, userID int) ([]int, error)
+
+	// SaveBallot -> создает или заменяет бюллетень, ErrConflict если опрос сейчас не открыт
+	SaveBallot(ctx context.Context, pollID, userID int, choices []int) error
+	// GetBallot -> ErrNotFound, если пользователь не голосовал
+	GetBallot(ctx context.Context, pollID, userID int) (*Ballot, error)
+	// GetBallots -> все бюллетени опроса по user_id, чтобы подсчет не зависел от порядка строк.
+	// Бюллетени гостей идут с UserID = 0 (между собой -> по времени)
+	GetBallots(ctx context.Context, pollID int) ([]*Ballot, error)
+	GetBallotsByUser(ctx context.Context, userID int) ([]*Ballot, error)
+}
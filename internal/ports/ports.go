@@ -53,25 +53,64 @@ type Movie struct {
 	Rating          float64    `json:"rating" example:"8.8"`
 	PosterURL       string     `json:"poster_url" example:"https://image.tmdb.org/..."`
 	Recommendations []string   `json:"recommendations" example:"The Matrix,Shutter Island"`
+	Runtime         int        `json:"runtime" example:"148"` // в минутах
+	Genres          []string   `json:"genres" example:"Action,Sci-Fi"`
+	Language        string     `json:"language" example:"en"`          // ISO 639-1, язык оригинала
+	Certification   string     `json:"certification" example:"PG-13"`  // возрастной рейтинг
+	CommunityRating float64    `json:"community_rating" example:"8.4"` // средняя оценка пользователей
+	CommunityVotes  int        `json:"community_votes" example:"42"`
 }
 
 // Мы не добавляем json тег для password_hash, чтобы случайно не отдать его клиенту
 type User struct {
-	ID           int       `json:"id"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID            int       `json:"id" example:"1"`
+	Email         string    `json:"email" example:"aigerim@example.com"`
+	PasswordHash  string    `json:"-"`
+	EmailVerified bool      `json:"email_verified" example:"true"`
+	DisplayName   string    `json:"display_name" example:"Aigerim"`
+	Timezone      string    `json:"timezone" example:"Asia/Almaty"` // имя из базы IANA
+	Locale        string    `json:"locale" example:"ru-KZ"`
+	AvatarURL     string    `json:"avatar_url" example:"https://example.com/avatar.png"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	// DeletionScheduledAt -> пользователь запросил удаление аккаунта, данные будут удалены в этот момент
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
 }
 
 type MovieRepository interface {
 	CreateMovie(ctx context.Context, movie *Movie) (int, error)
 	GetMovieByID(ctx context.Context, id int) (*Movie, error)
 	GetAllMovies(ctx context.Context) ([]*Movie, error)
+	GetMoviesByIDs(ctx context.Context, ids []int) ([]*Movie, error)
 	UpdateMovie(ctx context.Context, id int, movie *Movie) error
 	DeleteMovie(ctx context.Context, id int) error
 }
 
+// MovieCache -> позволяет сбросить закэшированный фильм, когда он меняется не через MovieRepository
+type MovieCache interface {
+	InvalidateMovie(ctx context.Context, id int)
+}
+
+// WatchHistoryRepository -> какие фильмы пользователь уже смотрел
+type WatchHistoryRepository interface {
+	GetWatchedMovieIDs(ctx context.Context, userID int) ([]int, error)
+}
+
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *User) (int, error)
 	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	GetUserByID(ctx context.Context, id int) (*User, error)
+	// UpdateUser -> обновляет профиль (display name, timezone, locale, avatar)
+	UpdateUser(ctx context.Context, user *User) error
+	UpdatePassword(ctx context.Context, userID int, passwordHash string) error
+	// SetEmailVerified -> подтверждает email, только если у пользователя все еще этот адрес
+	SetEmailVerified(ctx context.Context, userID int, email string) error
+}
+
+// PasswordResetRepository -> одноразовые токены сброса пароля (в базе только хэши)
+type PasswordResetRepository interface {
+	CreatePasswordResetToken(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error
+	// ResetPasswordWithToken -> атомарно гасит токен и меняет пароль.
+	// Если токен не найден, уже использован или истек -> errs.ErrNotFound
+	ResetPasswordWithToken(ctx context.Context, tokenHash, passwordHash string) (int, error)
 }
@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// ViewingPreferences -> что пользователь не хочет видеть в списках, рекомендациях и планировщике.
+// Пустые значения -> без ограничения
+type ViewingPreferences struct {
+	DislikedGenres       []string  `json:"disliked_genres" example:"Horror,Musical"`
+	MaxRuntime           int       `json:"max_runtime_minutes" example:"150"`
+	MinRating            float64   `json:"min_rating" example:"6.5"`
+	PreferredLanguages   []string  `json:"preferred_languages" example:"en,fr"`
+	HiddenCertifications []string  `json:"hidden_certifications" example:"NC-17"`
+	HiddenMovieIDs       []int     `json:"hidden_movie_ids" example:"12,40"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+type PreferenceRepository interface {
+	// GetViewingPreferences -> пустые предпочтения, если пользователь их не задавал
+	GetViewingPreferences(ctx context.Context, userID int) (*ViewingPreferences, error)
+	SaveViewingPreferences(ctx context.Context, userID int, p *ViewingPreferences) error
+}
@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// Rating -> оценка фильма конкретным пользователем
+type Rating struct {
+	UserID    int       `json:"user_id" example:"1"`
+	MovieID   int       `json:"movie_id" example:"1"`
+	Value     float64   `json:"rating" example:"8.5"`
+	Review    string    `json:"review,omitempty" example:"Great soundtrack, a bit too long."`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type RatingRepository interface {
+	// CreateRating, UpdateRating и DeleteRating пересчитывают community_rating
+	// фильма в той же транзакции
+	CreateRating(ctx context.Context, rating *Rating) error
+	UpdateRating(ctx context.Context, rating *Rating) error
+	DeleteRating(ctx context.Context, userID, movieID int) error
+	GetRating(ctx context.Context, userID, movieID int) (*Rating, error)
+	GetReviews(ctx context.Context, movieID, limit, offset int) ([]*Rating, int, error)
+	GetAllRatings(ctx context.Context) ([]*Rating, error)
+	GetRatingsByUser(ctx context.Context, userID int) ([]*Rating, error)
+}
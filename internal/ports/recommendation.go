@@ -0,0 +1,19 @@
+package ports
+
+import "context"
+
+// MovieNeighbour -> похожий фильм из матрицы совместных оценок (item-item CF)
+type MovieNeighbour struct {
+	MovieID     int     `json:"movie_id"`
+	NeighbourID int     `json:"neighbour_id"`
+	Rank        int     `json:"rank"`
+	Score       float64 `json:"score"`
+	CoRatings   int     `json:"co_ratings"`
+}
+
+type RecommendationRepository interface {
+	// ReplaceMovieNeighbours -> полностью заменяет сохраненную матрицу соседей
+	ReplaceMovieNeighbours(ctx context.Context, neighbours map[int][]MovieNeighbour) error
+	GetMovieNeighbours(ctx context.Context, movieIDs []int) ([]*MovieNeighbour, error)
+	GetPopularMovies(ctx context.Context, limit int) ([]*Movie, error)
+}
@@ -0,0 +1,52 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// Виды напоминаний
+const (
+	ReminderEventStarting    = "event_starting"    // вечер скоро начнется
+	ReminderWatchlistRelease = "watchlist_release" // фильм из списка "хочу посмотреть" выходит сегодня
+)
+
+// Reminder -> отложенное уведомление одному пользователю
+type Reminder struct {
+	ID      int64
+	UserID  int
+	Kind    string
+	EventID *int // у напоминаний о вечере
+	MovieID *int // у напоминаний о выходе фильма
+	Title   string
+	// TargetAt -> начало вечера (повторения серии) или начало дня выхода фильма у пользователя
+	TargetAt time.Time
+	DueAt    time.Time
+	// ExpiresAt -> после этого момента напоминание бессмысленно и не отправляется
+	ExpiresAt time.Time
+	Attempts  int
+}
+
+type ReminderRepository interface {
+	// ReplaceEventReminders -> одной транзакцией отменяет ожидающие и забранные на отправку напоминания вечера
+	// и планирует новые. Уже отправленные или отправляемые не повторяются: напоминание с тем же пользователем
+	// и TargetAt пропускается
+	ReplaceEventReminders(ctx context.Context, eventID int, reminders []*Reminder) error
+	// ScheduleReleaseReminders -> планирует напоминания о фильмах из списков "хочу посмотреть",
+	// которые выходят в ближайшие days дней, и отменяет ожидающие, если фильм убрали из списка
+	// или дата выхода поменялась. dueHour -> час отправки по времени пользователя
+	ScheduleReleaseReminders(ctx context.Context, days, dueHour int) error
+	// ClaimDueReminders -> короткой транзакцией берет до limit наступивших напоминаний через FOR UPDATE SKIP LOCKED
+	// и помечает их sending с арендой до now+lease, поэтому несколько экземпляров не отправят одно напоминание дважды.
+	// Напоминания, чья аренда истекла (экземпляр упал во время отправки), забираются снова.
+	// Просроченные отменяются без отправки
+	ClaimDueReminders(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*Reminder, error)
+	// IsReminderClaimed -> напоминание все еще забрано попыткой attempt (Reminder.Attempts после ClaimDueReminders):
+	// его не отменили и не забрал заново другой экземпляр
+	IsReminderClaimed(ctx context.Context, id int64, attempt int) (bool, error)
+	// MarkReminderSent и RetryReminder ничего не меняют, если напоминание уже не забрано попыткой attempt
+	MarkReminderSent(ctx context.Context, id int64, attempt int) error
+	// RetryReminder -> после неудачной отправки напоминание снова ждет до retryAt,
+	// а после maxAttempts попыток помечается failed
+	RetryReminder(ctx context.Context, id int64, attempt int, lastError string, retryAt time.Time, maxAttempts int) error
+}
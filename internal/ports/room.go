@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Типы сообщений в комнатах
+const (
+	RoomEventUpdated   = "event.updated"   // Data -> Event
+	RoomEventCancelled = "event.cancelled" // Data -> Event
+	RoomRSVP           = "rsvp"            // Data -> ответ участника и новые счетчики вечера
+	RoomPollCreated    = "poll.created"    // Data -> Poll
+	RoomVote           = "vote"            // Data -> кто проголосовал и промежуточный подсчет
+	RoomPollClosed     = "poll.closed"     // Data -> итоговый подсчет
+	// RoomReset -> история не покрывает Last-Event-ID клиента, состояние нужно перечитать через API
+	RoomReset = "reset"
+)
+
+// RoomMessage -> обновление для клиентов, открывших поток вечера или опроса
+type RoomMessage struct {
+	ID   string // растет в пределах комнаты, клиент возвращает его в Last-Event-ID
+	Type string
+	Data json.RawMessage
+}
+
+// RoomBroker -> рассылка обновлений по комнатам между всеми инстансами API
+type RoomBroker interface {
+	// Publish -> сообщение получат подписчики комнаты на всех инстансах
+	Publish(ctx context.Context, room, msgType string, data any) error
+	// Subscribe -> сначала сообщения после lastID (пустой -> без истории), затем новые.
+	// Канал закрывается, когда ctx отменен или клиент не успевает читать
+	Subscribe(ctx context.Context, room, lastID string) (<-chan RoomMessage, error)
+}
@@ -0,0 +1,56 @@
+package ports
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Типы записей в ленте активности
+const (
+	ActivityRated    = "rated"
+	ActivityReviewed = "reviewed"
+	ActivityWatched  = "watched"
+	ActivityList     = "list_updated"
+)
+
+// UserSummary -> публичная информация о пользователе (без email)
+type UserSummary struct {
+	ID          int    `json:"id" example:"2"`
+	DisplayName string `json:"display_name" example:"Aigerim"`
+	AvatarURL   string `json:"avatar_url" example:"https://example.com/avatar.png"`
+}
+
+// Follow -> пользователь в списке подписчиков или подписок
+type Follow struct {
+	User       UserSummary `json:"user"`
+	FollowedAt time.Time   `json:"followed_at"`
+}
+
+// Activity -> одна запись в ленте
+type Activity struct {
+	ID         int64           `json:"id" example:"1042"`
+	Actor      UserSummary     `json:"actor"`
+	Type       string          `json:"type" example:"reviewed"`
+	MovieID    *int            `json:"movie_id,omitempty" example:"1"`
+	MovieTitle string          `json:"movie_title,omitempty" example:"Inception"`
+	RefID      int             `json:"-"`
+	Payload    json.RawMessage `json:"payload" swaggertype:"object"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type SocialRepository interface {
+	// Follow -> ErrConflict, если подписка уже есть
+	Follow(ctx context.Context, followerID, followeeID int) error
+	Unfollow(ctx context.Context, followerID, followeeID int) error
+	GetFollowers(ctx context.Context, userID int) ([]*Follow, error)
+	GetFollowing(ctx context.Context, userID int) ([]*Follow, error)
+}
+
+type ActivityRepository interface {
+	CreateActivity(ctx context.Context, activity *Activity) error
+	// DeleteActivities -> удаляет записи актора данных типов, ссылающиеся на refID
+	DeleteActivities(ctx context.Context, actorID int, types []string, refID int) error
+	// GetFeed -> активность тех, на кого подписан userID, с id < before (0 -> с начала)
+	GetFeed(ctx context.Context, userID int, before int64, limit int) ([]*Activity, error)
+}
@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// WatchlistItem -> фильм в личном списке "хочу посмотреть"
+type WatchlistItem struct {
+	UserID   int       `json:"-"`
+	MovieID  int       `json:"movie_id" example:"1"`
+	Priority int       `json:"priority" example:"3"` // 1 (низкий) .. 5 (высокий)
+	Notes    string    `json:"notes" example:"Watch with Aigerim"`
+	AddedAt  time.Time `json:"added_at"`
+	Movie    *Movie    `json:"movie,omitempty"`
+}
+
+// WatchlistSort -> поле, по которому сортируется список
+type WatchlistSort string
+
+const (
+	WatchlistSortAdded    WatchlistSort = "added"
+	WatchlistSortPriority WatchlistSort = "priority"
+	WatchlistSortRating   WatchlistSort = "rating"
+)
+
+type WatchlistRepository interface {
+	AddToWatchlist(ctx context.Context, item *WatchlistItem) error
+	UpdateWatchlistItem(ctx context.Context, item *WatchlistItem) error
+	RemoveFromWatchlist(ctx context.Context, userID, movieID int) error
+	GetWatchlistItem(ctx context.Context, userID, movieID int) (*WatchlistItem, error)
+	GetWatchlist(ctx context.Context, userID int, sort WatchlistSort, desc bool) ([]*WatchlistItem, error)
+	// FilterWatchlisted -> возвращает те из movieIDs, которые есть в списке пользователя
+	FilterWatchlisted(ctx context.Context, userID int, movieIDs []int) ([]int, error)
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+// accountDeletionBatch -> сколько аккаунтов удаляем за один проход фоновой задачи
+const accountDeletionBatch = 100
+
+// AccountExportProfile -> профиль в выгрузке. В отличие от ответа /me, тут нет служебных полей
+type AccountExportProfile struct {
+	ID            int       `json:"id"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"email_verified"`
+	DisplayName   string    `json:"display_name"`
+	Timezone      string    `json:"timezone"`
+	Locale        string    `json:"locale"`
+	AvatarURL     string    `json:"avatar_url"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// AccountService -> выгрузка персональных данных и удаление аккаунта
+type AccountService struct {
+	users       ports.UserRepository
+	accounts    ports.AccountRepository
+	ratings     ports.RatingRepository
+	watchlist   ports.WatchlistRepository
+	diary       ports.DiaryRepository
+	social      ports.SocialRepository
+	lists       ports.ListRepository
+	preferences ports.PreferenceRepository
+	events      ports.EventRepository
+	polls       ports.PollRepository
+	groups      ports.GroupRepository
+	cache       ports.MovieCache
+	notifier    ports.Notifier
+	auth        *AuthSvc
+	eventSvc    *EventService
+	pollSvc     *PollService
+	gracePeriod time.Duration
+}
+
+func NewAccountService(users ports.UserRepository, accounts ports.AccountRepository, ratings ports.RatingRepository, watchlist ports.WatchlistRepository, diary ports.DiaryRepository, social ports.SocialRepository, lists ports.ListRepository, preferences ports.PreferenceRepository, events ports.EventRepository, polls ports.PollRepository, groups ports.GroupRepository, cache ports.MovieCache, notifier ports.Notifier, auth *AuthSvc, eventSvc *EventService, pollSvc *PollService, gracePeriod time.Duration) *AccountService {
+	return &AccountService{
+		users:       users,
+		accounts:    accounts,
+		ratings:     ratings,
+		watchlist:   watchlist,
+		diary:       diary,
+		social:      social,
+		lists:       lists,
+		preferences: preferences,
+		events:      events,
+		polls:       polls,
+		groups:      groups,
+		cache:       cache,
+		notifier:    notifier,
+		auth:        auth,
+		eventSvc:    eventSvc,
+		pollSvc:     pollSvc,
+		gracePeriod: gracePeriod,
+	}
+}
+
+// ExportData -> пишет в w ZIP-архив с JSON-файлами всех данных пользователя.
+// Сначала собираем все данные, чтобы ошибка БД не оставила в w половину архива
+func (s *AccountService) ExportData(ctx context.Context, userID int, w io.Writer) error {
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	ratings, err := s.ratings.GetRatingsByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	watchlist, err := s.watchlist.GetWatchlist(ctx, userID, ports.WatchlistSortAdded, false)
+	if err != nil {
+		return err
+	}
+	diary, err := s.diary.GetDiaryEntries(ctx, userID, time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return err
+	}
+	following, err := s.social.GetFollowing(ctx, userID)
+	if err != nil {
+		return err
+	}
+	followers, err := s.social.GetFollowers(ctx, userID)
+	if err != nil {
+		return err
+	}
+	lists, err := s.lists.GetListsByOwner(ctx, userID, false)
+	if err != nil {
+		return err
+	}
+	for _, l := range lists {
+		if l.Entries, err = s.lists.GetListEntries(ctx, l.ID); err != nil {
+			return err
+		}
+	}
+	preferences, err := s.preferences.GetViewingPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+	// Вечера, где пользователь хост или приглашен, за все время
+	events, err := s.events.GetEventsForUser(ctx, userID, time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return err
+	}
+	availability, err := s.events.GetSlotAvailabilityByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	ballots, err := s.polls.GetBallotsByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	groups, err := s.groups.GetUserGroups(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		hideInviteToken(g)
+	}
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", AccountExportProfile{
+			ID:            user.ID,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerified,
+			DisplayName:   user.DisplayName,
+			Timezone:      user.Timezone,
+			Locale:        user.Locale,
+			AvatarURL:     user.AvatarURL,
+			CreatedAt:     user.CreatedAt,
+			UpdatedAt:     user.UpdatedAt,
+		}},
+		{"ratings.json", ratings},
+		{"watchlist.json", watchlist},
+		{"diary.json", diary},
+		{"following.json", following},
+		{"followers.json", followers},
+		{"lists.json", lists},
+		{"preferences.json", preferences},
+		{"events.json", events},
+		{"availability.json", availability},
+		{"ballots.json", ballots},
+		{"groups.json", groups},
+	}
+
+	zw := zip.NewWriter(w)
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return fmt.Errorf("failed to add %s to export: %w", f.name, err)
+		}
+		enc := json.NewEncoder(fw)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+	}
+
+	return zw.Close()
+}
+
+// RequestDeletion -> аккаунт будет удален через gracePeriod, до этого можно передумать
+func (s *AccountService) RequestDeletion(ctx context.Context, userID int) (time.Time, error) {
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// Повторный запрос не должен отодвигать уже назначенную дату
+	if user.DeletionScheduledAt != nil {
+		return *user.DeletionScheduledAt, nil
+	}
+
+	at := time.Now().Add(s.gracePeriod).Truncate(time.Second)
+	if err := s.accounts.ScheduleAccountDeletion(ctx, userID, at); err != nil {
+		return time.Time{}, err
+	}
+
+	return at, nil
+}
+
+// CancelDeletion -> ErrNotFound, если удаление не было запрошено
+func (s *AccountService) CancelDeletion(ctx context.Context, userID int) error {
+	return s.accounts.CancelAccountDeletion(ctx, userID)
+}
+
+// PurgeDueAccounts -> удаляет аккаунты, у которых истек срок ожидания, и возвращает их число
+func (s *AccountService) PurgeDueAccounts(ctx context.Context) (int, error) {
+	ids, err := s.accounts.GetAccountsDueForDeletion(ctx, time.Now(), accountDeletionBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, id := range ids {
+		// Сначала отзываем токены: если удаление упадет, попробуем снова
+		// на следующем проходе, а вот токены удаленного пользователя не должны работать ни секунды
+		if err := s.auth.RevokeUserTokens(ctx, id); err != nil {
+			log.Printf("Failed to revoke tokens of user %d: %v", id, err)
+			continue
+		}
+		if err := s.closeOwnedActivity(ctx, id); err != nil {
+			log.Printf("Failed to cancel events and polls of user %d: %v", id, err)
+			continue
+		}
+		deleted, err := s.accounts.DeleteAccount(ctx, id)
+		if err != nil {
+			log.Printf("Failed to delete account %d: %v", id, err)
+			continue
+		}
+		// Оценки пользователя ушли из community_rating -> закэшированные фильмы устарели
+		for _, movieID := range deleted.RatedMovieIDs {
+			s.cache.InvalidateMovie(ctx, movieID)
+		}
+		// Места удаленного пользователя достались листу ожидания
+		for eventID, userIDs := range deleted.Promoted {
+			e, err := s.events.GetEvent(ctx, eventID)
+			if err != nil {
+				log.Printf("Failed to get event %d to notify promoted users: %v", eventID, err)
+				continue
+			}
+			notifyPromoted(ctx, s.notifier, e, userIDs)
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// closeOwnedActivity -> вечера и опросы пользователя удалятся вместе с ним каскадом,
+// поэтому до удаления отменяем и закрываем их обычным путем: приглашенные получают
+// уведомление об отмене, а напоминания, комнаты и календари узнают об этом из событий
+func (s *AccountService) closeOwnedActivity(ctx context.Context, userID int) error {
+	events, err := s.events.GetEventsForUser(ctx, userID, time.Now(), time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		if e.Host.ID != userID || e.Status == ports.EventCancelled {
+			continue
+		}
+		if err := s.eventSvc.CancelEvent(ctx, userID, e.ID); err != nil && !errors.Is(err, errs.ErrConflict) {
+			return err
+		}
+	}
+
+	pollIDs, err := s.polls.GetOpenPollsByCreator(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, id := range pollIDs {
+		// Опрос мог стать недоступен (пользователь вышел из группы) или уже закрыться:
+		// такой все равно удалится вместе с аккаунтом
+		if _, err := s.pollSvc.ClosePoll(ctx, userID, id); err != nil &&
+			!errors.Is(err, errs.ErrNotFound) && !errors.Is(err, errs.ErrConflict) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RunDeletionJob -> фоновая задача: удаление аккаунтов сразу при старте и затем каждые interval
+func (s *AccountService) RunDeletionJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := s.PurgeDueAccounts(ctx); err != nil {
+			log.Printf("Account deletion job failed: %v", err)
+		} else if n > 0 {
+			log.Printf("Account deletion job removed %d accounts", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+// actionLog -> общий журнал вызовов, чтобы проверить порядок отмены и удаления
+type actionLog []string
+
+func (l *actionLog) add(format string, args ...any) {
+	*l = append(*l, fmt.Sprintf(format, args...))
+}
+
+type stubAccounts struct {
+	ports.AccountRepository
+	due []int
+	log *actionLog
+}
+
+func (s *stubAccounts) GetAccountsDueForDeletion(_ context.Context, _ time.Time, _ int) ([]int, error) {
+	return s.due, nil
+}
+
+func (s *stubAccounts) DeleteAccount(_ context.Context, userID int) (*ports.DeletedAccount, error) {
+	s.log.add("delete user %d", userID)
+	return &ports.DeletedAccount{}, nil
+}
+
+// stubHostedEvents -> вечера с приглашениями
+type stubHostedEvents struct {
+	stubEvents
+	invitations map[int][]*ports.EventInvitation
+	log         *actionLog
+}
+
+func (s *stubHostedEvents) IsInvitedToEvent(_ context.Context, eventID, userID int) (bool, error) {
+	for _, inv := range s.invitations[eventID] {
+		if inv.User.ID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *stubHostedEvents) GetEventsForUser(ctx context.Context, userID int, from, to time.Time) ([]*ports.Event, error) {
+	result := make([]*ports.Event, 0)
+	for id := 1; id <= len(s.events); id++ {
+		e := s.events[id]
+		invited, _ := s.IsInvitedToEvent(ctx, id, userID)
+		if (e.Host.ID == userID || invited) && !e.StartsAt.Before(from) && e.StartsAt.Before(to) {
+			copied := *e
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (s *stubHostedEvents) CancelEvent(_ context.Context, id int) error {
+	if s.events[id].Status == ports.EventCancelled {
+		return errs.ErrConflict
+	}
+	s.events[id].Status = ports.EventCancelled
+	s.log.add("cancel event %d", id)
+	return nil
+}
+
+func (s *stubHostedEvents) GetEventInvitations(_ context.Context, eventID int) ([]*ports.EventInvitation, error) {
+	return s.invitations[eventID], nil
+}
+
+type stubPolls struct {
+	ports.PollRepository
+	polls map[int]*ports.Poll
+	log   *actionLog
+}
+
+func (s *stubPolls) GetOpenPollsByCreator(_ context.Context, userID int) ([]int, error) {
+	ids := make([]int, 0)
+	for id := 1; id <= len(s.polls); id++ {
+		if p := s.polls[id]; p.CreatedBy == userID && p.ClosesAt.After(time.Now()) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (s *stubPolls) GetPoll(_ context.Context, id int) (*ports.Poll, error) {
+	p, ok := s.polls[id]
+	if !ok {
+		return nil, errs.ErrNotFound
+	}
+	copied := *p
+	return &copied, nil
+}
+
+func (s *stubPolls) ClosePoll(_ context.Context, id int) error {
+	s.polls[id].ClosesAt = time.Now()
+	s.log.add("close poll %d", id)
+	return nil
+}
+
+func (s *stubPolls) GetBallots(_ context.Context, _ int) ([]*ports.Ballot, error) {
+	return nil, nil
+}
+
+type recordingPublisher struct {
+	published []string
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, e ports.DomainEvent) {
+	p.published = append(p.published, fmt.Sprintf("%s %d", e.Type, e.RefID))
+}
+
+type recordingNotifier struct {
+	sent []string
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, notification *ports.Notification) error {
+	n.sent = append(n.sent, fmt.Sprintf("%s -> %d", notification.Type, notification.UserID))
+	return nil
+}
+
+func TestPurgeDueAccountsCancelsHostedEventsAndPolls(t *testing.T) {
+	const userID = 1
+	var log actionLog
+	tomorrow := time.Now().Add(24 * time.Hour)
+
+	events := &stubHostedEvents{
+		stubEvents: stubEvents{events: map[int]*ports.Event{
+			// Вечер пользователя: 2 идет, 3 отказался
+			1: {ID: 1, Host: ports.UserSummary{ID: userID}, Title: "Heat", StartsAt: tomorrow, Status: ports.EventScheduled},
+			// Уже отмененный вечер пользователя
+			2: {ID: 2, Host: ports.UserSummary{ID: userID}, Title: "Alien", StartsAt: tomorrow, Status: ports.EventCancelled},
+			// Чужой вечер, куда пользователь приглашен и где создал опрос
+			3: {ID: 3, Host: ports.UserSummary{ID: 4}, Title: "Ronin", StartsAt: tomorrow, Status: ports.EventScheduled},
+		}},
+		invitations: map[int][]*ports.EventInvitation{
+			1: {
+				{User: ports.UserSummary{ID: 2}, RSVP: ports.RSVPGoing},
+				{User: ports.UserSummary{ID: 3}, RSVP: ports.RSVPDeclined},
+			},
+			2: {{User: ports.UserSummary{ID: 2}, RSVP: ports.RSVPGoing}},
+			3: {{User: ports.UserSummary{ID: userID}, RSVP: ports.RSVPGoing}},
+		},
+		log: &log,
+	}
+	eventID := 3
+	polls := &stubPolls{
+		polls: map[int]*ports.Poll{
+			1: {ID: 1, EventID: &eventID, CreatedBy: userID, Method: ports.VotingPlurality, ClosesAt: tomorrow},
+			2: {ID: 2, EventID: &eventID, CreatedBy: 4, Method: ports.VotingPlurality, ClosesAt: tomorrow},
+		},
+		log: &log,
+	}
+	publisher := &recordingPublisher{}
+	notifier := &recordingNotifier{}
+	eventSvc := NewEventService(events, nil, notifier, publisher)
+	pollSvc := NewPollService(polls, events, nil, publisher)
+	auth := NewAuthSvc(testSecret, time.Hour, &stubRevoker{revokedAt: map[int]time.Time{}})
+	s := NewAccountService(nil, &stubAccounts{due: []int{userID}, log: &log}, nil, nil, nil, nil, nil, nil,
+		events, polls, nil, nil, notifier, auth, eventSvc, pollSvc, 30*24*time.Hour)
+
+	n, err := s.PurgeDueAccounts(context.Background())
+	if err != nil {
+		t.Fatalf("PurgeDueAccounts() error: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("purged = %d, want 1", n)
+	}
+
+	// Отмена и закрытие идут до удаления: после него вечера и опросы исчезнут каскадом
+	if want := (actionLog{"cancel event 1", "close poll 1", "delete user 1"}); !reflect.DeepEqual(log, want) {
+		t.Errorf("actions = %v, want %v", log, want)
+	}
+	if want := []string{ports.EventMovieNightCancelled + " 1", ports.EventPollClosed + " 1"}; !reflect.DeepEqual(publisher.published, want) {
+		t.Errorf("published = %v, want %v", publisher.published, want)
+	}
+	if want := []string{ports.NotificationEventCancelled + " -> 2"}; !reflect.DeepEqual(notifier.sent, want) {
+		t.Errorf("notifications = %v, want %v", notifier.sent, want)
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+// ActivityRecorder -> подписчик, который превращает доменные события в записи ленты
+type ActivityRecorder struct {
+	repo ports.ActivityRepository
+}
+
+func NewActivityRecorder(repo ports.ActivityRepository) *ActivityRecorder {
+	return &ActivityRecorder{repo: repo}
+}
+
+// Register -> подписывает recorder на нужные события
+func (r *ActivityRecorder) Register(bus *EventBus) {
+	bus.Subscribe("activity", r.handle,
+		ports.EventRatingCreated,
+		ports.EventRatingUpdated,
+		ports.EventRatingDeleted,
+		ports.EventDiaryEntryCreated,
+		ports.EventDiaryEntryUpdated,
+		ports.EventDiaryEntryDeleted,
+		ports.EventListUpdated,
+		ports.EventListRemoved,
+	)
+}
+
+func (r *ActivityRecorder) handle(ctx context.Context, e ports.DomainEvent) error {
+	switch e.Type {
+	case ports.EventRatingCreated, ports.EventRatingUpdated:
+		// У пользователя одна оценка на фильм, поэтому в ленте держим только последнюю
+		if err := r.repo.DeleteActivities(ctx, e.ActorID, []string{ports.ActivityRated, ports.ActivityReviewed}, e.RefID); err != nil {
+			return err
+		}
+		activityType := ports.ActivityRated
+		if review, _ := e.Payload["review"].(string); review != "" {
+			activityType = ports.ActivityReviewed
+		}
+		return r.record(ctx, e, activityType)
+
+	case ports.EventRatingDeleted:
+		return r.repo.DeleteActivities(ctx, e.ActorID, []string{ports.ActivityRated, ports.ActivityReviewed}, e.RefID)
+
+	case ports.EventDiaryEntryCreated:
+		return r.record(ctx, e, ports.ActivityWatched)
+
+	case ports.EventDiaryEntryUpdated:
+		// Запись в ленте заменяем, как и при изменении оценки
+		if err := r.repo.DeleteActivities(ctx, e.ActorID, []string{ports.ActivityWatched}, e.RefID); err != nil {
+			return err
+		}
+		return r.record(ctx, e, ports.ActivityWatched)
+
+	case ports.EventDiaryEntryDeleted:
+		return r.repo.DeleteActivities(ctx, e.ActorID, []string{ports.ActivityWatched}, e.RefID)
+
+	case ports.EventListUpdated:
+		// Для списка достаточно одной, последней, записи в ленте
+		if err := r.repo.DeleteActivities(ctx, e.ActorID, []string{ports.ActivityList}, e.RefID); err != nil {
+			return err
+		}
+		return r.record(ctx, e, ports.ActivityList)
+
+	case ports.EventListRemoved:
+		return r.repo.DeleteActivities(ctx, e.ActorID, []string{ports.ActivityList}, e.RefID)
+	}
+
+	return nil
+}
+
+func (r *ActivityRecorder) record(ctx context.Context, e ports.DomainEvent, activityType string) error {
+	payload, err := json.Marshal(e.Payload)
+	if err != nil {
+		return err
+	}
+
+	activity := &ports.Activity{
+		Actor:   ports.UserSummary{ID: e.ActorID},
+		Type:    activityType,
+		RefID:   e.RefID,
+		Payload: payload,
+	}
+	if e.MovieID != 0 {
+		movieID := e.MovieID
+		activity.MovieID = &movieID
+	}
+
+	return r.repo.CreateActivity(ctx, activity)
+}
@@ -1,30 +1,46 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"math"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/turysbekovg/movie-planner/internal/ports"
 )
 
+// ErrTokenRevoked -> токен валиден, но все токены пользователя были отозваны
+var ErrTokenRevoked = errors.New("token has been revoked")
+
 // AuthSvc отвечает за создание и проверку JWT
 type AuthSvc struct {
 	secretKey []byte // просто пока что так оставил
 	ttl       time.Duration
+	revoker   ports.TokenRevoker
 }
 
-func NewAuthSvc(secretKey string, ttl time.Duration) *AuthSvc {
+func NewAuthSvc(secretKey string, ttl time.Duration, revoker ports.TokenRevoker) *AuthSvc {
 	return &AuthSvc{
 		secretKey: []byte(secretKey),
 		ttl:       ttl,
+		revoker:   revoker,
 	}
 }
 
+// TokenTTL -> сколько живет выданный токен
+func (s *AuthSvc) TokenTTL() time.Duration {
+	return s.ttl
+}
+
 func (s *AuthSvc) GenerateToken(userID int) (string, error) {
+	now := time.Now()
 	claims := jwt.MapClaims{
-		"sub": userID,                       // Subject (кому выдан токен)
-		"exp": time.Now().Add(s.ttl).Unix(), // Expires at (когда истекает)
-		"iat": time.Now().Unix(),            // Issued at (когда выдан)
+		"sub": userID,                // Subject (кому выдан токен)
+		"exp": now.Add(s.ttl).Unix(), // Expires at (когда истекает)
+		"iat": unixSeconds(now),      // Issued at (когда выдан), с миллисекундами для сравнения с отзывом
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -39,6 +55,12 @@ func (s *AuthSvc) GenerateToken(userID int) (string, error) {
 }
 
 func (s *AuthSvc) ValidateToken(tokenString string) (int, error) {
+	userID, _, err := s.parseToken(tokenString)
+	return userID, err
+}
+
+// parseToken -> ID пользователя и момент выдачи из проверенного токена
+func (s *AuthSvc) parseToken(tokenString string) (int, time.Time, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
@@ -47,15 +69,49 @@ func (s *AuthSvc) ValidateToken(tokenString string) (int, error) {
 	})
 
 	if err != nil {
-		return 0, fmt.Errorf("invalid token: %w", err)
+		return 0, time.Time{}, fmt.Errorf("invalid token: %w", err)
 	}
 
 	// Если токен валиден, извлекаем из него claims
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		if userIDFloat, ok := claims["sub"].(float64); ok {
-			return int(userIDFloat), nil
+		userIDFloat, ok := claims["sub"].(float64)
+		if !ok {
+			return 0, time.Time{}, fmt.Errorf("invalid token claims")
 		}
+		// Без iat нельзя сравнить с моментом отзыва -> считаем токен выданным в начале эпохи
+		issuedAt, _ := claims["iat"].(float64)
+		return int(userIDFloat), time.UnixMilli(int64(math.Round(issuedAt * 1000))), nil
+	}
+
+	return 0, time.Time{}, fmt.Errorf("invalid token claims")
+}
+
+// Authenticate -> ValidateToken плюс проверка, что токены пользователя не отозваны
+func (s *AuthSvc) Authenticate(ctx context.Context, tokenString string) (int, error) {
+	userID, issuedAt, err := s.parseToken(tokenString)
+	if err != nil {
+		return 0, err
+	}
+
+	revokedAt, err := s.revoker.TokensRevokedAt(ctx, userID)
+	if err != nil {
+		log.Printf("Error checking token revocation for user %d: %v", userID, err)
+		return 0, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	if issuedAt.Before(revokedAt) {
+		return 0, ErrTokenRevoked
 	}
 
-	return 0, fmt.Errorf("invalid token claims")
+	return userID, nil
+}
+
+// RevokeUserTokens -> все ранее выданные пользователю токены перестают работать,
+// а выданные после этого вызова продолжают
+func (s *AuthSvc) RevokeUserTokens(ctx context.Context, userID int) error {
+	return s.revoker.RevokeUserTokens(ctx, userID, time.Now())
+}
+
+// unixSeconds -> NumericDate из JWT с точностью до миллисекунд
+func unixSeconds(t time.Time) float64 {
+	return float64(t.UnixMilli()) / 1000
 }
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	// calendarFeedLookback -> вечер остается в подписке еще сутки после начала,
+	// чтобы не пропасть из календаря прямо во время просмотра
+	calendarFeedLookback = 24 * time.Hour
+	calendarFeedRefresh  = time.Hour
+	calendarFeedName     = "Movie nights"
+)
+
+// CalendarService -> выгрузка вечеров в iCalendar: один вечер файлом и подписка по секретной ссылке
+type CalendarService struct {
+	events  ports.EventRepository
+	feeds   ports.CalendarFeedRepository
+	baseURL string
+}
+
+func NewCalendarService(events ports.EventRepository, feeds ports.CalendarFeedRepository, baseURL string) *CalendarService {
+	return &CalendarService{
+		events:  events,
+		feeds:   feeds,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// EventICS -> .ics одного вечера. Доступен тем же, кому виден вечер
+func (s *CalendarService) EventICS(ctx context.Context, userID, id int) ([]byte, error) {
+	e, err := visibleEvent(ctx, s.events, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := attachOverrides(ctx, s.events, []*ports.Event{e}); err != nil {
+		return nil, err
+	}
+
+	return renderICS(icalCalendar{baseURL: s.baseURL, now: time.Now()}, []*ports.Event{e}), nil
+}
+
+// CreateFeed -> новая ссылка на подписку. Предыдущая перестает работать.
+// Токен не хранится, поэтому ссылку видно только в этом ответе
+func (s *CalendarService) CreateFeed(ctx context.Context, userID int) (*ports.CalendarFeed, error) {
+	token, hash, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	createdAt, err := s.feeds.SaveCalendarFeed(ctx, userID, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ports.CalendarFeed{
+		URL:       fmt.Sprintf("%s/calendar/%s.ics", s.baseURL, url.PathEscape(token)),
+		CreatedAt: createdAt,
+	}, nil
+}
+
+// DeleteFeed -> отключает подписку, ErrNotFound если ее не было
+func (s *CalendarService) DeleteFeed(ctx context.Context, userID int) error {
+	return s.feeds.DeleteCalendarFeed(ctx, userID)
+}
+
+// FeedICS -> подписка: предстоящие вечера, где пользователь хост или ответил going/maybe.
+// Неизвестный токен -> ErrNotFound
+func (s *CalendarService) FeedICS(ctx context.Context, token string) ([]byte, error) {
+	userID, err := s.feeds.GetCalendarFeedUser(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	events, err := s.events.GetCalendarEvents(ctx, userID, now.Add(-calendarFeedLookback))
+	if err != nil {
+		return nil, err
+	}
+	// Серия уходит в подписку целиком, с правилом: повторения календарь развернет сам
+	if err := attachOverrides(ctx, s.events, events); err != nil {
+		return nil, err
+	}
+
+	cal := icalCalendar{
+		name:    calendarFeedName,
+		refresh: calendarFeedRefresh,
+		baseURL: s.baseURL,
+		now:     now,
+	}
+	return renderICS(cal, events), nil
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	maxDiaryLocationLength = 200
+	maxDiaryNotesLength    = 5000
+)
+
+// DiaryInput -> данные записи из запроса. Rewatch == nil -> определяем сами по истории
+type DiaryInput struct {
+	MovieID   int
+	WatchedOn time.Time
+	Rating    *float64
+	Rewatch   *bool
+	Location  string
+	Notes     string
+}
+
+// DiaryDay -> один день календаря с записями
+type DiaryDay struct {
+	Date    ports.CustomDate    `json:"date"`
+	Entries []*ports.DiaryEntry `json:"entries"`
+}
+
+// DiaryMonth -> календарь за месяц, в нем только дни с просмотрами
+type DiaryMonth struct {
+	Year      int         `json:"year" example:"2026"`
+	Month     int         `json:"month" example:"10"`
+	Total     int         `json:"total" example:"7"`
+	Rewatches int         `json:"rewatches" example:"2"`
+	Days      []*DiaryDay `json:"days"`
+}
+
+// DiaryMonthSummary -> месяц внутри годового обзора
+type DiaryMonthSummary struct {
+	Month   int                 `json:"month" example:"10"`
+	Count   int                 `json:"count" example:"7"`
+	Entries []*ports.DiaryEntry `json:"entries"`
+}
+
+// DiaryYear -> обзор за год по месяцам (все 12 месяцев, даже пустые)
+type DiaryYear struct {
+	Year         int                  `json:"year" example:"2026"`
+	Total        int                  `json:"total" example:"84"`
+	Rewatches    int                  `json:"rewatches" example:"12"`
+	UniqueMovies int                  `json:"unique_movies" example:"70"`
+	Months       []*DiaryMonthSummary `json:"months"`
+}
+
+// DiaryService -> дневник просмотров пользователя
+type DiaryService struct {
+	repo   ports.DiaryRepository
+	events ports.EventPublisher
+}
+
+func NewDiaryService(repo ports.DiaryRepository, events ports.EventPublisher) *DiaryService {
+	return &DiaryService{
+		repo:   repo,
+		events: events,
+	}
+}
+
+func (s *DiaryService) CreateEntry(ctx context.Context, userID int, in DiaryInput) (*ports.DiaryEntry, error) {
+	entry, err := newDiaryEntry(userID, in)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.Rewatch == nil {
+		// Если фильм уже есть в дневнике -> это повторный просмотр
+		count, err := s.repo.CountMovieWatches(ctx, userID, in.MovieID)
+		if err != nil {
+			return nil, err
+		}
+		entry.Rewatch = count > 0
+	}
+
+	if err := s.repo.CreateDiaryEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	s.publish(ctx, ports.EventDiaryEntryCreated, entry)
+
+	return s.repo.GetDiaryEntry(ctx, userID, entry.ID)
+}
+
+func (s *DiaryService) GetEntry(ctx context.Context, userID, id int) (*ports.DiaryEntry, error) {
+	return s.repo.GetDiaryEntry(ctx, userID, id)
+}
+
+// UpdateEntry -> полная замена записи. Если rewatch не передан, оставляем старое значение
+func (s *DiaryService) UpdateEntry(ctx context.Context, userID, id int, in DiaryInput) (*ports.DiaryEntry, error) {
+	existing, err := s.repo.GetDiaryEntry(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := newDiaryEntry(userID, in)
+	if err != nil {
+		return nil, err
+	}
+	entry.ID = id
+	if in.Rewatch == nil {
+		entry.Rewatch = existing.Rewatch
+	}
+
+	if err := s.repo.UpdateDiaryEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	s.publish(ctx, ports.EventDiaryEntryUpdated, entry)
+
+	return s.repo.GetDiaryEntry(ctx, userID, id)
+}
+
+// publish -> событие о записи с тем, что видно в ленте: дата, повторный просмотр и оценка
+func (s *DiaryService) publish(ctx context.Context, eventType string, entry *ports.DiaryEntry) {
+	payload := map[string]any{
+		"watched_on": entry.WatchedOn.Format("2006-01-02"),
+		"rewatch":    entry.Rewatch,
+	}
+	if entry.Rating != nil {
+		payload["rating"] = *entry.Rating
+	}
+	s.events.Publish(ctx, ports.DomainEvent{
+		Type:    eventType,
+		ActorID: entry.UserID,
+		MovieID: entry.MovieID,
+		RefID:   entry.ID,
+		Payload: payload,
+	})
+}
+
+func (s *DiaryService) DeleteEntry(ctx context.Context, userID, id int) error {
+	if err := s.repo.DeleteDiaryEntry(ctx, userID, id); err != nil {
+		return err
+	}
+
+	s.events.Publish(ctx, ports.DomainEvent{Type: ports.EventDiaryEntryDeleted, ActorID: userID, RefID: id})
+	return nil
+}
+
+// GetEntries -> записи за период [from, to]. Нулевые даты -> без ограничения с этой стороны
+func (s *DiaryService) GetEntries(ctx context.Context, userID int, from, to time.Time) ([]*ports.DiaryEntry, error) {
+	if from.IsZero() {
+		from = time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if to.IsZero() {
+		to = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: to must not be before from", errs.ErrInvalidInput)
+	}
+
+	// Репозиторий принимает полуинтервал, поэтому сдвигаем конец на день
+	return s.repo.GetDiaryEntries(ctx, userID, from, to.AddDate(0, 0, 1))
+}
+
+func (s *DiaryService) GetMonth(ctx context.Context, userID, year, month int) (*DiaryMonth, error) {
+	if month < 1 || month > 12 {
+		return nil, fmt.Errorf("%w: month must be between 1 and 12", errs.ErrInvalidInput)
+	}
+
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	entries, err := s.repo.GetDiaryEntries(ctx, userID, from, from.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DiaryMonth{Year: year, Month: month, Total: len(entries), Days: make([]*DiaryDay, 0)}
+
+	// Записи уже отсортированы по дате, поэтому дни собираются подряд
+	var current *DiaryDay
+	for _, e := range entries {
+		if e.Rewatch {
+			result.Rewatches++
+		}
+		if current == nil || !sameDay(current.Date.Time, e.WatchedOn.Time) {
+			current = &DiaryDay{Date: e.WatchedOn, Entries: make([]*ports.DiaryEntry, 0)}
+			result.Days = append(result.Days, current)
+		}
+		current.Entries = append(current.Entries, e)
+	}
+
+	return result, nil
+}
+
+func (s *DiaryService) GetYear(ctx context.Context, userID, year int) (*DiaryYear, error) {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	entries, err := s.repo.GetDiaryEntries(ctx, userID, from, from.AddDate(1, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DiaryYear{Year: year, Total: len(entries), Months: make([]*DiaryMonthSummary, 12)}
+	for i := range result.Months {
+		result.Months[i] = &DiaryMonthSummary{Month: i + 1, Entries: make([]*ports.DiaryEntry, 0)}
+	}
+
+	unique := make(map[int]bool)
+	for _, e := range entries {
+		m := result.Months[e.WatchedOn.Month()-1]
+		m.Count++
+		m.Entries = append(m.Entries, e)
+		if e.Rewatch {
+			result.Rewatches++
+		}
+		unique[e.MovieID] = true
+	}
+	result.UniqueMovies = len(unique)
+
+	return result, nil
+}
+
+func (s *DiaryService) GetWatchCounts(ctx context.Context, userID int) ([]*ports.MovieWatchCount, error) {
+	return s.repo.GetMovieWatchCounts(ctx, userID)
+}
+
+func newDiaryEntry(userID int, in DiaryInput) (*ports.DiaryEntry, error) {
+	if in.MovieID <= 0 {
+		return nil, fmt.Errorf("%w: movie_id is required", errs.ErrInvalidInput)
+	}
+	if in.WatchedOn.IsZero() {
+		return nil, fmt.Errorf("%w: watched_on is required", errs.ErrInvalidInput)
+	}
+	// День запаса на часовые пояса: "сегодня" у пользователя может быть "завтра" по UTC
+	if in.WatchedOn.After(time.Now().AddDate(0, 0, 1)) {
+		return nil, fmt.Errorf("%w: watched_on cannot be in the future", errs.ErrInvalidInput)
+	}
+	if in.Rating != nil {
+		if _, err := newRating(userID, in.MovieID, *in.Rating, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	location := strings.TrimSpace(in.Location)
+	if utf8.RuneCountInString(location) > maxDiaryLocationLength {
+		return nil, fmt.Errorf("%w: location must be at most %d characters", errs.ErrInvalidInput, maxDiaryLocationLength)
+	}
+	notes := strings.TrimSpace(in.Notes)
+	if utf8.RuneCountInString(notes) > maxDiaryNotesLength {
+		return nil, fmt.Errorf("%w: notes must be at most %d characters", errs.ErrInvalidInput, maxDiaryNotesLength)
+	}
+
+	entry := &ports.DiaryEntry{
+		UserID:    userID,
+		MovieID:   in.MovieID,
+		WatchedOn: ports.CustomDate{Time: in.WatchedOn},
+		Rating:    in.Rating,
+		Location:  location,
+		Notes:     notes,
+	}
+	if in.Rewatch != nil {
+		entry.Rewatch = *in.Rewatch
+	}
+
+	return entry, nil
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
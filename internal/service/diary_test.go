@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+// stubDiary -> записи дневника в памяти
+type stubDiary struct {
+	ports.DiaryRepository
+	entries map[int]*ports.DiaryEntry
+}
+
+func (s *stubDiary) CreateDiaryEntry(_ context.Context, entry *ports.DiaryEntry) error {
+	entry.ID = len(s.entries) + 1
+	copied := *entry
+	s.entries[entry.ID] = &copied
+	return nil
+}
+
+func (s *stubDiary) GetDiaryEntry(_ context.Context, userID, id int) (*ports.DiaryEntry, error) {
+	e, ok := s.entries[id]
+	if !ok || e.UserID != userID {
+		return nil, errs.ErrNotFound
+	}
+	copied := *e
+	return &copied, nil
+}
+
+func (s *stubDiary) UpdateDiaryEntry(_ context.Context, entry *ports.DiaryEntry) error {
+	copied := *entry
+	s.entries[entry.ID] = &copied
+	return nil
+}
+
+func (s *stubDiary) DeleteDiaryEntry(_ context.Context, _, id int) error {
+	delete(s.entries, id)
+	return nil
+}
+
+func (s *stubDiary) CountMovieWatches(_ context.Context, _, _ int) (int, error) {
+	return 0, nil
+}
+
+func TestDiaryEntryEventsReachTheFeed(t *testing.T) {
+	ctx := context.Background()
+	publisher := &recordingPublisher{}
+	s := NewDiaryService(&stubDiary{entries: map[int]*ports.DiaryEntry{}}, publisher)
+	watchedOn := time.Now().AddDate(0, 0, -1)
+
+	entry, err := s.CreateEntry(ctx, 3, DiaryInput{MovieID: 12, WatchedOn: watchedOn})
+	if err != nil {
+		t.Fatalf("CreateEntry() error: %v", err)
+	}
+	rating, rewatch := 8.5, true
+	if _, err := s.UpdateEntry(ctx, 3, entry.ID, DiaryInput{MovieID: 12, WatchedOn: watchedOn, Rating: &rating, Rewatch: &rewatch}); err != nil {
+		t.Fatalf("UpdateEntry() error: %v", err)
+	}
+	if err := s.DeleteEntry(ctx, 3, entry.ID); err != nil {
+		t.Fatalf("DeleteEntry() error: %v", err)
+	}
+
+	want := []string{
+		ports.EventDiaryEntryCreated + " 1",
+		ports.EventDiaryEntryUpdated + " 1",
+		ports.EventDiaryEntryDeleted + " 1",
+	}
+	if !reflect.DeepEqual(publisher.published, want) {
+		t.Errorf("published = %v, want %v", publisher.published, want)
+	}
+}
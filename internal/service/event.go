@@ -0,0 +1,480 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	maxEventTitleLength       = 200
+	maxEventDescriptionLength = 2000
+	maxEventLocationLength    = 300
+	maxEventCapacity          = 1000
+	maxInvitesPerRequest      = 50
+)
+
+// EventInput -> данные нового киновечера. Пустой Timezone -> часовой пояс хоста,
+// Recurrence -> RRULE для серии вечеров (пусто -> разовый вечер)
+type EventInput struct {
+	Title       string
+	Description string
+	StartsAt    time.Time
+	Timezone    string
+	Location    string
+	Capacity    int
+	MovieID     *int
+	Recurrence  string
+	InviteeIDs  []int
+}
+
+// EventUpdate -> частичное обновление: nil означает "не менять", MovieID == 0 -> убрать фильм,
+// Recurrence == "" -> серия становится разовым вечером
+type EventUpdate struct {
+	Title       *string
+	Description *string
+	StartsAt    *time.Time
+	Timezone    *string
+	Location    *string
+	Capacity    *int
+	MovieID     *int
+	Recurrence  *string
+}
+
+// EventService -> киновечера: хост создает и меняет вечер, приглашенные его видят
+type EventService struct {
+	repo      ports.EventRepository
+	users     ports.UserRepository
+	notifier  ports.Notifier
+	publisher ports.EventPublisher
+}
+
+func NewEventService(repo ports.EventRepository, users ports.UserRepository, notifier ports.Notifier, publisher ports.EventPublisher) *EventService {
+	return &EventService{
+		repo:      repo,
+		users:     users,
+		notifier:  notifier,
+		publisher: publisher,
+	}
+}
+
+func (s *EventService) CreateEvent(ctx context.Context, hostID int, in EventInput) (*ports.Event, error) {
+	host, err := s.users.GetUserByID(ctx, hostID)
+	if err != nil {
+		return nil, err
+	}
+	if in.Timezone == "" {
+		in.Timezone = host.Timezone
+	}
+
+	e := &ports.Event{
+		Host:        ports.UserSummary{ID: hostID},
+		Title:       strings.TrimSpace(in.Title),
+		Description: strings.TrimSpace(in.Description),
+		StartsAt:    in.StartsAt,
+		Timezone:    in.Timezone,
+		Location:    strings.TrimSpace(in.Location),
+		Capacity:    in.Capacity,
+		MovieID:     in.MovieID,
+		Recurrence:  in.Recurrence,
+	}
+	if err := validateEvent(e); err != nil {
+		return nil, err
+	}
+	if !e.StartsAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: starts_at must be in the future", errs.ErrInvalidInput)
+	}
+	if err := applyRecurrence(e); err != nil {
+		return nil, err
+	}
+	invitees, err := validateInvitees(hostID, in.InviteeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateEvent(ctx, e, invitees); err != nil {
+		return nil, err
+	}
+	s.notifyInvited(ctx, host, e, invitees)
+	s.publisher.Publish(ctx, ports.DomainEvent{
+		Type:    ports.EventMovieNightCreated,
+		ActorID: hostID,
+		RefID:   e.ID,
+	})
+
+	return s.GetEvent(ctx, hostID, e.ID)
+}
+
+// GetEvent -> вечер видят только хост и приглашенные, для остальных его нет
+func (s *EventService) GetEvent(ctx context.Context, userID, id int) (*ports.Event, error) {
+	e, err := s.visibleEvent(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.Invitations, err = s.repo.GetEventInvitations(ctx, id); err != nil {
+		return nil, err
+	}
+	if e.Guests, err = s.repo.GetEventGuests(ctx, id); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// GetMyEvents -> вечера, где пользователь хост или приглашен. Нулевой from -> с сегодняшнего дня,
+// нулевой to -> без ограничения (повторения серий -> на год вперед). Серии разворачиваются в повторения
+func (s *EventService) GetMyEvents(ctx context.Context, userID int, from, to time.Time) ([]*ports.Event, error) {
+	if from.IsZero() {
+		now := time.Now().UTC()
+		from = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	seriesTo := to
+	if to.IsZero() {
+		to = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+		seriesTo = from.AddDate(recurrenceHorizon, 0, 0)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: to must not be before from", errs.ErrInvalidInput)
+	}
+
+	// Как и в дневнике, to включительно -> сдвигаем конец на день
+	events, err := s.repo.GetEventsForUser(ctx, userID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	if err := attachOverrides(ctx, s.repo, events); err != nil {
+		return nil, err
+	}
+
+	result := make([]*ports.Event, 0, len(events))
+	for _, e := range events {
+		if e.Recurrence == "" {
+			localizeEvent(e)
+			result = append(result, e)
+			continue
+		}
+		result = append(result, expandEvent(e, from, seriesTo.AddDate(0, 0, 1))...)
+	}
+	sortEvents(result)
+	return result, nil
+}
+
+func (s *EventService) UpdateEvent(ctx context.Context, userID, id int, upd EventUpdate) (*ports.Event, error) {
+	e, err := s.hostedEvent(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if e.Status == ports.EventCancelled {
+		return nil, fmt.Errorf("%w: a cancelled event cannot be changed", errs.ErrConflict)
+	}
+
+	before := *e
+	if err := applyEventUpdate(e, upd); err != nil {
+		return nil, err
+	}
+	if err := validateEvent(e); err != nil {
+		return nil, err
+	}
+	if upd.Recurrence != nil {
+		e.Recurrence = *upd.Recurrence
+	} else if rule := eventRule(&before); rule != nil && !e.StartsAt.Equal(before.StartsAt) {
+		// Серию перенесли на другой день недели без нового правила -> BYDAY сдвигается вместе с ней
+		e.Recurrence = rule.shiftDays(before.StartsAt.In(eventLocation(&before)), e.StartsAt.In(eventLocation(e))).String()
+	}
+	if err := applyRecurrence(e); err != nil {
+		return nil, err
+	}
+
+	promoted, err := s.repo.UpdateEvent(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+	// Повторение определяется началом по правилу -> после переноса серии старые изменения повторений
+	// ни к чему не относятся. Если поменялось только правило, лишние изменения просто не применяются
+	if !e.StartsAt.Equal(before.StartsAt) || e.Timezone != before.Timezone {
+		if err := s.repo.DeleteOccurrenceOverrides(ctx, id, time.Time{}); err != nil {
+			return nil, err
+		}
+	}
+	s.notifyPromoted(ctx, e, promoted)
+	s.publisher.Publish(ctx, ports.DomainEvent{
+		Type:    ports.EventMovieNightUpdated,
+		ActorID: userID,
+		RefID:   id,
+		Payload: map[string]any{"promoted": promoted},
+	})
+
+	return s.GetEvent(ctx, userID, id)
+}
+
+// CancelEvent -> вечер остается в базе со статусом cancelled, повторная отмена -> 409.
+// Приглашенные, кроме отказавшихся, получают уведомление
+func (s *EventService) CancelEvent(ctx context.Context, userID, id int) error {
+	e, err := s.hostedEvent(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if e.Status == ports.EventCancelled {
+		return fmt.Errorf("%w: the event is already cancelled", errs.ErrConflict)
+	}
+
+	if err := s.repo.CancelEvent(ctx, id); err != nil {
+		return err
+	}
+	s.notifyCancelled(ctx, e)
+	s.publisher.Publish(ctx, ports.DomainEvent{
+		Type:    ports.EventMovieNightCancelled,
+		ActorID: userID,
+		RefID:   id,
+	})
+	return nil
+}
+
+// InviteUsers -> приглашает пользователей и отправляет им уведомления.
+// Уже приглашенные пропускаются и повторно не уведомляются
+func (s *EventService) InviteUsers(ctx context.Context, userID, id int, userIDs []int) (*ports.Event, error) {
+	e, err := s.hostedEvent(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if e.Status == ports.EventCancelled {
+		return nil, fmt.Errorf("%w: a cancelled event cannot be changed", errs.ErrConflict)
+	}
+	invitees, err := validateInvitees(userID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(invitees) == 0 {
+		return nil, fmt.Errorf("%w: user_ids is required", errs.ErrInvalidInput)
+	}
+
+	host, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.invite(ctx, host, e, invitees); err != nil {
+		return nil, err
+	}
+
+	return s.GetEvent(ctx, userID, id)
+}
+
+func (s *EventService) RemoveInvitation(ctx context.Context, userID, id, inviteeID int) error {
+	e, err := s.hostedEvent(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	promoted, err := s.repo.RemoveEventInvitation(ctx, id, inviteeID)
+	if err != nil {
+		return err
+	}
+	s.notifyPromoted(ctx, e, promoted)
+	s.publisher.Publish(ctx, ports.DomainEvent{
+		Type:    ports.EventRSVPChanged,
+		ActorID: userID,
+		RefID:   id,
+		Payload: map[string]any{"user_id": inviteeID, "removed": true, "promoted": promoted},
+	})
+	return nil
+}
+
+// RSVP -> ответ приглашенного. Если мест нет, going превращается в waitlisted,
+// а при отказе от места его получает первый из очереди
+func (s *EventService) RSVP(ctx context.Context, userID, id int, status ports.RSVPStatus) (*ports.Event, error) {
+	switch status {
+	case ports.RSVPGoing, ports.RSVPMaybe, ports.RSVPDeclined:
+	default:
+		return nil, fmt.Errorf("%w: status must be going, maybe or declined", errs.ErrInvalidInput)
+	}
+
+	e, err := s.visibleEvent(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if e.Host.ID == userID {
+		return nil, fmt.Errorf("%w: the host always attends their own event", errs.ErrInvalidInput)
+	}
+	// Ответ на серию -> сразу на все повторения, пока они еще будут
+	if _, ok := nextStart(e, time.Now()); !ok {
+		return nil, fmt.Errorf("%w: the event has already started", errs.ErrConflict)
+	}
+
+	result, err := s.repo.SetEventRSVP(ctx, id, userID, status)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyPromoted(ctx, e, result.Promoted)
+	s.publisher.Publish(ctx, ports.DomainEvent{
+		Type:    ports.EventRSVPChanged,
+		ActorID: userID,
+		RefID:   id,
+		Payload: map[string]any{"user_id": userID, "rsvp": result.Status, "promoted": result.Promoted},
+	})
+
+	return s.GetEvent(ctx, userID, id)
+}
+
+func (s *EventService) notifyPromoted(ctx context.Context, e *ports.Event, userIDs []int) {
+	notifyPromoted(ctx, s.notifier, e, userIDs)
+}
+
+// notifyPromoted -> сообщаем поднятым из листа ожидания, что для них нашлось место
+func notifyPromoted(ctx context.Context, notifier ports.Notifier, e *ports.Event, userIDs []int) {
+	localizeEvent(e)
+	message := fmt.Sprintf("A spot opened up: you are now going to %q on %s.", e.Title, e.StartsAt.Format("Mon, 02 Jan 15:04 MST"))
+	for _, id := range userIDs {
+		notify(ctx, notifier, id, ports.NotificationWaitlistPromoted, message, map[string]any{
+			"event_id":  e.ID,
+			"title":     e.Title,
+			"starts_at": e.StartsAt,
+			"timezone":  e.Timezone,
+		})
+	}
+}
+
+// notifyCancelled -> уведомления об отмене всем приглашенным, кто не отказался
+func (s *EventService) notifyCancelled(ctx context.Context, e *ports.Event) {
+	invitations, err := s.repo.GetEventInvitations(ctx, e.ID)
+	if err != nil {
+		log.Printf("Failed to get invitations of event %d to notify about cancellation: %v", e.ID, err)
+		return
+	}
+
+	message := fmt.Sprintf("%q on %s has been cancelled.", e.Title, e.StartsAt.Format("Mon, 02 Jan 15:04 MST"))
+	for _, inv := range invitations {
+		if inv.RSVP == ports.RSVPDeclined {
+			continue
+		}
+		notify(ctx, s.notifier, inv.User.ID, ports.NotificationEventCancelled, message, map[string]any{
+			"event_id":  e.ID,
+			"title":     e.Title,
+			"starts_at": e.StartsAt,
+			"timezone":  e.Timezone,
+			"host_id":   e.Host.ID,
+		})
+	}
+}
+
+func (s *EventService) invite(ctx context.Context, host *ports.User, e *ports.Event, userIDs []int) error {
+	added, err := s.repo.AddEventInvitations(ctx, e.ID, host.ID, userIDs)
+	if err != nil {
+		return err
+	}
+	s.notifyInvited(ctx, host, e, added)
+	return nil
+}
+
+// notifyInvited -> уведомления о приглашении только что добавленным
+func (s *EventService) notifyInvited(ctx context.Context, host *ports.User, e *ports.Event, added []int) {
+	localizeEvent(e)
+	message := fmt.Sprintf("%s invited you to %q on %s.", displayName(host), e.Title, e.StartsAt.Format("Mon, 02 Jan 15:04 MST"))
+	for _, id := range added {
+		notify(ctx, s.notifier, id, ports.NotificationEventInvitation, message, map[string]any{
+			"event_id":  e.ID,
+			"title":     e.Title,
+			"starts_at": e.StartsAt,
+			"timezone":  e.Timezone,
+			"host_id":   host.ID,
+		})
+	}
+}
+
+func (s *EventService) visibleEvent(ctx context.Context, userID, id int) (*ports.Event, error) {
+	return visibleEvent(ctx, s.repo, userID, id)
+}
+
+// visibleEvent -> вечер, если пользователь хост или приглашен, иначе ErrNotFound.
+// Общая проверка для всего, что привязано к вечеру (опросы и т.д.)
+func visibleEvent(ctx context.Context, repo ports.EventRepository, userID, id int) (*ports.Event, error) {
+	e, err := repo.GetEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if e.Host.ID != userID {
+		invited, err := repo.IsInvitedToEvent(ctx, id, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !invited {
+			return nil, errs.ErrNotFound
+		}
+	}
+
+	localizeEvent(e)
+	return e, nil
+}
+
+// hostedEvent -> менять вечер может только хост. Приглашенный получает 403,
+// остальные -> 404, чтобы не раскрывать чужие вечера
+func (s *EventService) hostedEvent(ctx context.Context, userID, id int) (*ports.Event, error) {
+	e, err := s.visibleEvent(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if e.Host.ID != userID {
+		return nil, errs.ErrForbidden
+	}
+	return e, nil
+}
+
+func validateEvent(e *ports.Event) error {
+	if e.Title == "" {
+		return fmt.Errorf("%w: title is required", errs.ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(e.Title) > maxEventTitleLength {
+		return fmt.Errorf("%w: title must be at most %d characters", errs.ErrInvalidInput, maxEventTitleLength)
+	}
+	if utf8.RuneCountInString(e.Description) > maxEventDescriptionLength {
+		return fmt.Errorf("%w: description must be at most %d characters", errs.ErrInvalidInput, maxEventDescriptionLength)
+	}
+	if utf8.RuneCountInString(e.Location) > maxEventLocationLength {
+		return fmt.Errorf("%w: location must be at most %d characters", errs.ErrInvalidInput, maxEventLocationLength)
+	}
+	if e.StartsAt.IsZero() {
+		return fmt.Errorf("%w: starts_at is required", errs.ErrInvalidInput)
+	}
+	if e.Capacity < 0 || e.Capacity > maxEventCapacity {
+		return fmt.Errorf("%w: capacity must be between 0 (unlimited) and %d", errs.ErrInvalidInput, maxEventCapacity)
+	}
+	if e.MovieID != nil && *e.MovieID <= 0 {
+		return fmt.Errorf("%w: movie_id must be positive", errs.ErrInvalidInput)
+	}
+	// Как и в профиле: пустую строку и "Local" LoadLocation принимает, но это не IANA-имя
+	if e.Timezone == "" || e.Timezone == "Local" {
+		return fmt.Errorf("%w: timezone must be an IANA name like Asia/Almaty", errs.ErrInvalidInput)
+	}
+	if _, err := time.LoadLocation(e.Timezone); err != nil {
+		return fmt.Errorf("%w: unknown timezone %q", errs.ErrInvalidInput, e.Timezone)
+	}
+	return nil
+}
+
+func validateInvitees(hostID int, userIDs []int) ([]int, error) {
+	ids := uniqueInts(userIDs)
+	if len(ids) > maxInvitesPerRequest {
+		return nil, fmt.Errorf("%w: at most %d users can be invited at once", errs.ErrInvalidInput, maxInvitesPerRequest)
+	}
+	for _, id := range ids {
+		if id <= 0 {
+			return nil, fmt.Errorf("%w: user ids must be positive", errs.ErrInvalidInput)
+		}
+		if id == hostID {
+			return nil, fmt.Errorf("%w: the host cannot invite themselves", errs.ErrInvalidInput)
+		}
+	}
+	return ids, nil
+}
+
+// localizeEvent -> время начала в часовом поясе вечера, чтобы в JSON был его offset
+func localizeEvent(e *ports.Event) {
+	if loc, err := time.LoadLocation(e.Timezone); err == nil {
+		e.StartsAt = e.StartsAt.In(loc)
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+// EventHandler -> подписчик доменных событий
+type EventHandler func(ctx context.Context, event ports.DomainEvent) error
+
+// EventBus -> простая внутрипроцессная шина доменных событий.
+// Подписчики вызываются синхронно и по порядку подписки. Ошибка подписчика
+// только логируется: действие пользователя уже выполнено и откатывать его не нужно
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]namedHandler
+}
+
+type namedHandler struct {
+	name string
+	fn   EventHandler
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{handlers: make(map[string][]namedHandler)}
+}
+
+// Subscribe -> name нужен только для логов
+func (b *EventBus) Subscribe(name string, fn EventHandler, eventTypes ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, t := range eventTypes {
+		b.handlers[t] = append(b.handlers[t], namedHandler{name: name, fn: fn})
+	}
+}
+
+func (b *EventBus) Publish(ctx context.Context, event ports.DomainEvent) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	b.mu.RLock()
+	handlers := b.handlers[event.Type]
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		if err := h.fn(ctx, event); err != nil {
+			logEventError(h.name, event, err)
+		}
+	}
+}
+
+func logEventError(handler string, e ports.DomainEvent, err error) {
+	log.Printf("Event handler %q failed on %s (actor %d, ref %d): %v", handler, e.Type, e.ActorID, e.RefID, err)
+}
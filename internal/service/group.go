@@ -0,0 +1,370 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	maxGroupNameLength        = 100
+	maxGroupDescriptionLength = 2000
+	maxGroupNoteLength        = 1000
+)
+
+// groupRoleRank -> чем больше, тем больше прав
+var groupRoleRank = map[ports.GroupRole]int{
+	ports.GroupRoleMember: 1,
+	ports.GroupRoleAdmin:  2,
+	ports.GroupRoleOwner:  3,
+}
+
+// GroupInput -> данные новой группы
+type GroupInput struct {
+	Name        string
+	Description string
+}
+
+// GroupUpdate -> частичное обновление группы: nil означает "не менять"
+type GroupUpdate struct {
+	Name        *string
+	Description *string
+}
+
+// GroupService -> группы, их участники и общий список фильмов.
+// Для тех, кто не в группе, группа выглядит как несуществующая
+type GroupService struct {
+	repo ports.GroupRepository
+}
+
+func NewGroupService(repo ports.GroupRepository) *GroupService {
+	return &GroupService{repo: repo}
+}
+
+// CreateGroup -> создатель становится owner, ссылка-приглашение сразу включена
+func (s *GroupService) CreateGroup(ctx context.Context, userID int, in GroupInput) (*ports.Group, error) {
+	g := &ports.Group{
+		Name:        strings.TrimSpace(in.Name),
+		Description: strings.TrimSpace(in.Description),
+	}
+	if err := validateGroup(g); err != nil {
+		return nil, err
+	}
+	token, _, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	g.InviteToken = token
+
+	if err := s.repo.CreateGroup(ctx, g, userID); err != nil {
+		return nil, err
+	}
+
+	return s.GetGroup(ctx, userID, g.ID)
+}
+
+// GetGroup -> группа с участниками и ролью текущего пользователя
+func (s *GroupService) GetGroup(ctx context.Context, userID, id int) (*ports.Group, error) {
+	role, err := s.memberRole(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	g, err := s.repo.GetGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if g.Members, err = s.repo.GetGroupMembers(ctx, id); err != nil {
+		return nil, err
+	}
+	g.MyRole = role
+	hideInviteToken(g)
+	return g, nil
+}
+
+func (s *GroupService) GetMyGroups(ctx context.Context, userID int) ([]*ports.Group, error) {
+	groups, err := s.repo.GetUserGroups(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		hideInviteToken(g)
+	}
+	return groups, nil
+}
+
+// UpdateGroup -> название и описание меняют owner и admin
+func (s *GroupService) UpdateGroup(ctx context.Context, userID, id int, upd GroupUpdate) (*ports.Group, error) {
+	if _, err := s.requireRole(ctx, id, userID, ports.GroupRoleAdmin); err != nil {
+		return nil, err
+	}
+	g, err := s.repo.GetGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if upd.Name != nil {
+		g.Name = strings.TrimSpace(*upd.Name)
+	}
+	if upd.Description != nil {
+		g.Description = strings.TrimSpace(*upd.Description)
+	}
+	if err := validateGroup(g); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateGroup(ctx, g); err != nil {
+		return nil, err
+	}
+
+	return s.GetGroup(ctx, userID, id)
+}
+
+// DeleteGroup -> удалить группу может только owner
+func (s *GroupService) DeleteGroup(ctx context.Context, userID, id int) error {
+	if _, err := s.requireRole(ctx, id, userID, ports.GroupRoleOwner); err != nil {
+		return err
+	}
+	return s.repo.DeleteGroup(ctx, id)
+}
+
+// RotateInviteLink -> новая ссылка-приглашение, старая перестает работать
+func (s *GroupService) RotateInviteLink(ctx context.Context, userID, id int) (*ports.Group, error) {
+	token, _, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	return s.setInviteToken(ctx, userID, id, token)
+}
+
+// DisableInviteLink -> вступить по ссылке больше нельзя, пока ее не создадут заново
+func (s *GroupService) DisableInviteLink(ctx context.Context, userID, id int) (*ports.Group, error) {
+	return s.setInviteToken(ctx, userID, id, "")
+}
+
+func (s *GroupService) setInviteToken(ctx context.Context, userID, id int, token string) (*ports.Group, error) {
+	if _, err := s.requireRole(ctx, id, userID, ports.GroupRoleAdmin); err != nil {
+		return nil, err
+	}
+	g, err := s.repo.GetGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	g.InviteToken = token
+	if err := s.repo.UpdateGroup(ctx, g); err != nil {
+		return nil, err
+	}
+
+	return s.GetGroup(ctx, userID, id)
+}
+
+// JoinGroup -> вступление по ссылке-приглашению с ролью member
+func (s *GroupService) JoinGroup(ctx context.Context, userID int, token string) (*ports.Group, error) {
+	if token == "" {
+		return nil, fmt.Errorf("%w: token is required", errs.ErrInvalidInput)
+	}
+	g, err := s.repo.GetGroupByInviteToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.AddGroupMember(ctx, g.ID, userID, ports.GroupRoleMember); err != nil {
+		return nil, err
+	}
+
+	return s.GetGroup(ctx, userID, g.ID)
+}
+
+// SetMemberRole -> роли раздает только owner. Роль owner означает передачу владения:
+// прежний owner становится admin
+func (s *GroupService) SetMemberRole(ctx context.Context, userID, id, memberID int, role ports.GroupRole) (*ports.Group, error) {
+	if _, ok := groupRoleRank[role]; !ok {
+		return nil, fmt.Errorf("%w: role must be owner, admin or member", errs.ErrInvalidInput)
+	}
+	if _, err := s.requireRole(ctx, id, userID, ports.GroupRoleOwner); err != nil {
+		return nil, err
+	}
+	if memberID == userID {
+		return nil, fmt.Errorf("%w: transfer ownership to another member instead", errs.ErrInvalidInput)
+	}
+	if _, err := s.repo.GetGroupRole(ctx, id, memberID); err != nil {
+		return nil, err
+	}
+
+	if role == ports.GroupRoleOwner {
+		if err := s.repo.TransferGroupOwnership(ctx, id, userID, memberID); err != nil {
+			return nil, err
+		}
+	} else if err := s.repo.SetGroupRole(ctx, id, memberID, role); err != nil {
+		return nil, err
+	}
+
+	return s.GetGroup(ctx, userID, id)
+}
+
+// RemoveMember -> owner удаляет кого угодно, admin -> только обычных участников.
+// Удалить себя -> то же, что выйти из группы
+func (s *GroupService) RemoveMember(ctx context.Context, userID, id, memberID int) error {
+	if memberID == userID {
+		return s.LeaveGroup(ctx, userID, id)
+	}
+
+	role, err := s.requireRole(ctx, id, userID, ports.GroupRoleAdmin)
+	if err != nil {
+		return err
+	}
+	memberRole, err := s.repo.GetGroupRole(ctx, id, memberID)
+	if err != nil {
+		return err
+	}
+	if groupRoleRank[memberRole] >= groupRoleRank[role] {
+		return errs.ErrForbidden
+	}
+
+	return s.repo.RemoveGroupMember(ctx, id, memberID)
+}
+
+// LeaveGroup -> owner не может уйти, пока в группе есть кто-то еще: сначала нужно
+// передать владение. Если owner остался один, группа удаляется
+func (s *GroupService) LeaveGroup(ctx context.Context, userID, id int) error {
+	role, err := s.memberRole(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if role != ports.GroupRoleOwner {
+		return s.repo.RemoveGroupMember(ctx, id, userID)
+	}
+
+	g, err := s.repo.GetGroup(ctx, id)
+	if err != nil {
+		return err
+	}
+	if g.MemberCount > 1 {
+		return fmt.Errorf("%w: transfer ownership before leaving the group", errs.ErrConflict)
+	}
+	return s.repo.DeleteGroup(ctx, id)
+}
+
+// GetWatchlist -> общий список по голосам. unseenOnly -> только фильмы,
+// которых нет в дневнике ни у одного участника
+func (s *GroupService) GetWatchlist(ctx context.Context, userID, id int, unseenOnly bool) ([]*ports.GroupWatchlistItem, error) {
+	if _, err := s.memberRole(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.GetGroupWatchlist(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !unseenOnly {
+		return items, nil
+	}
+
+	unseen := make([]*ports.GroupWatchlistItem, 0, len(items))
+	for _, it := range items {
+		if len(it.SeenBy) == 0 {
+			unseen = append(unseen, it)
+		}
+	}
+	return unseen, nil
+}
+
+// AddToWatchlist -> добавить фильм может любой участник, его голос засчитывается сразу
+func (s *GroupService) AddToWatchlist(ctx context.Context, userID, id, movieID int, note string) (*ports.GroupWatchlistItem, error) {
+	if _, err := s.memberRole(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	if movieID <= 0 {
+		return nil, fmt.Errorf("%w: movie_id is required", errs.ErrInvalidInput)
+	}
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxGroupNoteLength {
+		return nil, fmt.Errorf("%w: note must be at most %d characters", errs.ErrInvalidInput, maxGroupNoteLength)
+	}
+
+	if err := s.repo.AddGroupWatchlistItem(ctx, id, userID, movieID, note); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetGroupWatchlistItem(ctx, id, movieID, userID)
+}
+
+// RemoveFromWatchlist -> убрать фильм может тот, кто его добавил, или owner/admin
+func (s *GroupService) RemoveFromWatchlist(ctx context.Context, userID, id, movieID int) error {
+	role, err := s.memberRole(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	item, err := s.repo.GetGroupWatchlistItem(ctx, id, movieID, userID)
+	if err != nil {
+		return err
+	}
+	addedByMe := item.AddedBy != nil && item.AddedBy.ID == userID
+	if !addedByMe && groupRoleRank[role] < groupRoleRank[ports.GroupRoleAdmin] {
+		return errs.ErrForbidden
+	}
+
+	return s.repo.RemoveGroupWatchlistItem(ctx, id, movieID)
+}
+
+func (s *GroupService) Upvote(ctx context.Context, userID, id, movieID int) (*ports.GroupWatchlistItem, error) {
+	if _, err := s.memberRole(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.VoteGroupWatchlistItem(ctx, id, movieID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetGroupWatchlistItem(ctx, id, movieID, userID)
+}
+
+func (s *GroupService) RemoveUpvote(ctx context.Context, userID, id, movieID int) (*ports.GroupWatchlistItem, error) {
+	if _, err := s.memberRole(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetGroupWatchlistItem(ctx, id, movieID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UnvoteGroupWatchlistItem(ctx, id, movieID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetGroupWatchlistItem(ctx, id, movieID, userID)
+}
+
+// memberRole -> ErrNotFound и для несуществующей группы, и для чужой
+func (s *GroupService) memberRole(ctx context.Context, groupID, userID int) (ports.GroupRole, error) {
+	return s.repo.GetGroupRole(ctx, groupID, userID)
+}
+
+// requireRole -> роль пользователя, если она не ниже min, иначе ErrForbidden
+func (s *GroupService) requireRole(ctx context.Context, groupID, userID int, min ports.GroupRole) (ports.GroupRole, error) {
+	role, err := s.memberRole(ctx, groupID, userID)
+	if err != nil {
+		return "", err
+	}
+	if groupRoleRank[role] < groupRoleRank[min] {
+		return "", errs.ErrForbidden
+	}
+	return role, nil
+}
+
+func validateGroup(g *ports.Group) error {
+	if g.Name == "" {
+		return fmt.Errorf("%w: name is required", errs.ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(g.Name) > maxGroupNameLength {
+		return fmt.Errorf("%w: name must be at most %d characters", errs.ErrInvalidInput, maxGroupNameLength)
+	}
+	if utf8.RuneCountInString(g.Description) > maxGroupDescriptionLength {
+		return fmt.Errorf("%w: description must be at most %d characters", errs.ErrInvalidInput, maxGroupDescriptionLength)
+	}
+	return nil
+}
+
+// hideInviteToken -> ссылку-приглашение видят только owner и admin
+func hideInviteToken(g *ports.Group) {
+	if groupRoleRank[g.MyRole] < groupRoleRank[ports.GroupRoleAdmin] {
+		g.InviteToken = ""
+	}
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	maxGuestNameLength = 60
+	// guestSessionGrace -> после начала вечера гость еще неделю видит итоги и может перенести
+	// свою активность в аккаунт, потом его токен перестает работать
+	guestSessionGrace = 7 * 24 * time.Hour
+)
+
+// guestLinkPayload -> то, что зашито в подписанную ссылку. Version сверяется с версией
+// ссылок вечера, поэтому хост может отключить все выданные ссылки разом
+type guestLinkPayload struct {
+	EventID int `json:"eid"`
+	Version int `json:"v"`
+}
+
+// GuestView -> что видит гость: себя и вечер без списка приглашенных
+type GuestView struct {
+	Guest *ports.EventGuest `json:"guest"`
+	Event *ports.Event      `json:"event"`
+}
+
+// GuestSession -> ответ на вход по ссылке. Токен гостя не хранится, поэтому виден только здесь
+type GuestSession struct {
+	Token string `json:"guest_token" example:"Qm9yZWQ..."`
+	GuestView
+}
+
+// GuestService -> гости без аккаунта. Хост выдает подписанную ссылку на один вечер,
+// по ней гость называет себя и получает токен, с которым может ответить на приглашение
+// и голосовать в опросах этого вечера. Больше токен гостя ни к чему доступа не дает
+type GuestService struct {
+	guests    ports.GuestRepository
+	events    ports.EventRepository
+	polls     ports.PollRepository
+	notifier  ports.Notifier
+	publisher ports.EventPublisher
+	signer    *signer
+	baseURL   string
+}
+
+func NewGuestService(guests ports.GuestRepository, events ports.EventRepository, polls ports.PollRepository, notifier ports.Notifier, publisher ports.EventPublisher, secretKey, baseURL string) *GuestService {
+	return &GuestService{
+		guests:    guests,
+		events:    events,
+		polls:     polls,
+		notifier:  notifier,
+		publisher: publisher,
+		signer:    newSigner(secretKey, "guest-invite"),
+		baseURL:   strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// CreateLink -> ссылку выдает только хост. Нулевой expiresAt -> ссылка работает до начала вечера
+// (у серии -> до ближайшего повторения), позже начала вечера (последнего повторения) она работать не может
+func (s *GuestService) CreateLink(ctx context.Context, userID, eventID int, expiresAt time.Time) (*ports.GuestLink, error) {
+	e, err := s.hostedEvent(ctx, userID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if e.Status == ports.EventCancelled {
+		return nil, fmt.Errorf("%w: the event is cancelled", errs.ErrConflict)
+	}
+	next, ok := nextStart(e, time.Now())
+	if !ok {
+		return nil, fmt.Errorf("%w: the event has already started", errs.ErrConflict)
+	}
+
+	if expiresAt.IsZero() {
+		expiresAt = next
+	}
+	last, bounded := lastStart(e)
+	if !expiresAt.After(time.Now()) || bounded && expiresAt.After(last) {
+		return nil, fmt.Errorf("%w: expires_at must be in the future and not after the start of the event", errs.ErrInvalidInput)
+	}
+
+	token, err := s.signer.Sign(guestLinkPayload{EventID: e.ID, Version: e.GuestLinkVersion}, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ports.GuestLink{
+		URL:       fmt.Sprintf("%s/guest/invites/%s", s.baseURL, url.PathEscape(token)),
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// RevokeLinks -> все выданные ссылки перестают работать. Уже пришедшие гости остаются
+func (s *GuestService) RevokeLinks(ctx context.Context, userID, eventID int) error {
+	if _, err := s.hostedEvent(ctx, userID, eventID); err != nil {
+		return err
+	}
+	return s.guests.RevokeGuestLinks(ctx, eventID)
+}
+
+// RemoveGuest -> хост убирает гостя, его место получает первый из очереди
+func (s *GuestService) RemoveGuest(ctx context.Context, userID, eventID, guestID int) error {
+	e, err := s.hostedEvent(ctx, userID, eventID)
+	if err != nil {
+		return err
+	}
+
+	promoted, err := s.guests.RemoveEventGuest(ctx, eventID, guestID)
+	if err != nil {
+		return err
+	}
+	notifyPromoted(ctx, s.notifier, e, promoted)
+	s.publisher.Publish(ctx, ports.DomainEvent{
+		Type:    ports.EventRSVPChanged,
+		ActorID: userID,
+		RefID:   eventID,
+		Payload: map[string]any{"guest_id": guestID, "removed": true, "promoted": promoted},
+	})
+	return nil
+}
+
+// PreviewInvite -> вечер по ссылке, чтобы гость видел, куда его зовут, до того как назовется
+func (s *GuestService) PreviewInvite(ctx context.Context, inviteToken string) (*ports.Event, error) {
+	return s.invitedEvent(ctx, inviteToken)
+}
+
+// Join -> гость называет себя и получает токен
+func (s *GuestService) Join(ctx context.Context, inviteToken, displayName string) (*GuestSession, error) {
+	name := strings.TrimSpace(displayName)
+	if name == "" {
+		return nil, fmt.Errorf("%w: display_name is required", errs.ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(name) > maxGuestNameLength {
+		return nil, fmt.Errorf("%w: display_name must be at most %d characters", errs.ErrInvalidInput, maxGuestNameLength)
+	}
+
+	e, err := s.invitedEvent(ctx, inviteToken)
+	if err != nil {
+		return nil, err
+	}
+
+	token, hash, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	g := &ports.EventGuest{EventID: e.ID, DisplayName: name}
+	if err := s.guests.CreateEventGuest(ctx, g, hash); err != nil {
+		return nil, err
+	}
+
+	return &GuestSession{Token: token, GuestView: GuestView{Guest: g, Event: e}}, nil
+}
+
+// Authenticate -> гость по токену. Неизвестный токен или вечер, прошедший больше недели назад,
+// -> ErrUnauthorized
+func (s *GuestService) Authenticate(ctx context.Context, guestToken string) (*ports.EventGuest, error) {
+	g, err := s.guests.GetEventGuestByToken(ctx, hashToken(guestToken))
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			return nil, errs.ErrUnauthorized
+		}
+		return nil, err
+	}
+
+	e, err := s.events.GetEvent(ctx, g.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if last, ok := lastStart(e); ok && time.Now().After(last.Add(guestSessionGrace)) {
+		return nil, errs.ErrUnauthorized
+	}
+	return g, nil
+}
+
+func (s *GuestService) GetView(ctx context.Context, g *ports.EventGuest) (*GuestView, error) {
+	e, err := s.events.GetEvent(ctx, g.EventID)
+	if err != nil {
+		return nil, err
+	}
+	localizeEvent(e)
+	return &GuestView{Guest: g, Event: e}, nil
+}
+
+// RSVP -> те же правила, что у приглашенных: места и очередь у гостей с ними общие
+func (s *GuestService) RSVP(ctx context.Context, g *ports.EventGuest, status ports.RSVPStatus) (*GuestView, error) {
+	switch status {
+	case ports.RSVPGoing, ports.RSVPMaybe, ports.RSVPDeclined:
+	default:
+		return nil, fmt.Errorf("%w: status must be going, maybe or declined", errs.ErrInvalidInput)
+	}
+
+	e, err := s.events.GetEvent(ctx, g.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := nextStart(e, time.Now()); !ok {
+		return nil, fmt.Errorf("%w: the event has already started", errs.ErrConflict)
+	}
+
+	result, err := s.guests.SetGuestRSVP(ctx, g.EventID, g.ID, status)
+	if err != nil {
+		return nil, err
+	}
+	notifyPromoted(ctx, s.notifier, e, result.Promoted)
+	s.publisher.Publish(ctx, ports.DomainEvent{
+		Type:    ports.EventRSVPChanged,
+		RefID:   g.EventID,
+		Payload: map[string]any{"guest_id": g.ID, "rsvp": result.Status, "promoted": result.Promoted},
+	})
+
+	// Перечитываем гостя: у него новый ответ и, возможно, место в очереди
+	if g, err = s.guests.GetEventGuest(ctx, g.ID); err != nil {
+		return nil, err
+	}
+	return s.GetView(ctx, g)
+}
+
+// GetPolls -> опросы вечера гостя с его бюллетенями
+func (s *GuestService) GetPolls(ctx context.Context, g *ports.EventGuest) ([]*ports.Poll, error) {
+	polls, err := s.polls.GetEventPolls(ctx, g.EventID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range polls {
+		if err := s.withGuestBallot(ctx, p, g); err != nil {
+			return nil, err
+		}
+	}
+	return polls, nil
+}
+
+func (s *GuestService) CastBallot(ctx context.Context, g *ports.EventGuest, pollID int, choices []int) (*ports.Poll, error) {
+	p, err := s.guestPoll(ctx, g, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateBallot(p, choices); err != nil {
+		return nil, err
+	}
+
+	if err := s.guests.SaveGuestBallot(ctx, pollID, g.ID, choices); err != nil {
+		return nil, err
+	}
+	s.publisher.Publish(ctx, ports.DomainEvent{
+		Type:    ports.EventBallotCast,
+		RefID:   pollID,
+		Payload: map[string]any{"guest_id": g.ID},
+	})
+
+	if p, err = s.polls.GetPoll(ctx, pollID); err != nil {
+		return nil, err
+	}
+	if err := s.withGuestBallot(ctx, p, g); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *GuestService) GetResults(ctx context.Context, g *ports.EventGuest, pollID int) (*PollResults, error) {
+	p, err := s.guestPoll(ctx, g, pollID)
+	if err != nil {
+		return nil, err
+	}
+	return pollResults(ctx, s.polls, p)
+}
+
+// Claim -> переносит ответ и бюллетени гостя на пользователя (например, сразу после регистрации).
+// Пользователь становится приглашенным, гость удаляется
+func (s *GuestService) Claim(ctx context.Context, userID int, guestToken string) (*ports.Event, error) {
+	g, err := s.guests.GetEventGuestByToken(ctx, hashToken(guestToken))
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			return nil, fmt.Errorf("%w: guest token is invalid", errs.ErrInvalidInput)
+		}
+		return nil, err
+	}
+
+	promoted, err := s.guests.ClaimEventGuest(ctx, g.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := visibleEvent(ctx, s.events, userID, g.EventID)
+	if err != nil {
+		return nil, err
+	}
+	notifyPromoted(ctx, s.notifier, e, promoted)
+	s.publisher.Publish(ctx, ports.DomainEvent{
+		Type:    ports.EventRSVPChanged,
+		ActorID: userID,
+		RefID:   g.EventID,
+		Payload: map[string]any{"user_id": userID, "guest_id": g.ID, "removed": true, "promoted": promoted},
+	})
+
+	return e, nil
+}
+
+// invitedEvent -> вечер по ссылке, если подпись, срок и версия ссылки в порядке
+func (s *GuestService) invitedEvent(ctx context.Context, inviteToken string) (*ports.Event, error) {
+	invalid := fmt.Errorf("%w: invite link is invalid or expired", errs.ErrInvalidInput)
+
+	var payload guestLinkPayload
+	if err := s.signer.Verify(inviteToken, time.Now(), &payload); err != nil {
+		return nil, invalid
+	}
+
+	e, err := s.events.GetEvent(ctx, payload.EventID)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+	if e.GuestLinkVersion != payload.Version {
+		return nil, invalid
+	}
+	if e.Status == ports.EventCancelled {
+		return nil, fmt.Errorf("%w: the event is cancelled", errs.ErrConflict)
+	}
+
+	localizeEvent(e)
+	return e, nil
+}
+
+// guestPoll -> гостю видны только опросы его вечера
+func (s *GuestService) guestPoll(ctx context.Context, g *ports.EventGuest, pollID int) (*ports.Poll, error) {
+	p, err := s.polls.GetPoll(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if p.EventID == nil || *p.EventID != g.EventID {
+		return nil, errs.ErrNotFound
+	}
+	return p, nil
+}
+
+func (s *GuestService) withGuestBallot(ctx context.Context, p *ports.Poll, g *ports.EventGuest) error {
+	b, err := s.guests.GetGuestBallot(ctx, p.ID, g.ID)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	p.MyBallot = b.Choices
+	return nil
+}
+
+func (s *GuestService) hostedEvent(ctx context.Context, userID, eventID int) (*ports.Event, error) {
+	e, err := visibleEvent(ctx, s.events, userID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if e.Host.ID != userID {
+		return nil, errs.ErrForbidden
+	}
+	return e, nil
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+// stubEvents -> вечера в памяти. Остальные методы репозитория в этих тестах не вызываются
+type stubEvents struct {
+	ports.EventRepository
+	events map[int]*ports.Event
+}
+
+func (s *stubEvents) GetEvent(_ context.Context, id int) (*ports.Event, error) {
+	e, ok := s.events[id]
+	if !ok {
+		return nil, errs.ErrNotFound
+	}
+	copied := *e
+	return &copied, nil
+}
+
+func TestGuestInviteLinks(t *testing.T) {
+	const hostID = 1
+	startsAt := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	newEvent := func(id, version int, status ports.EventStatus) *ports.Event {
+		return &ports.Event{
+			ID:               id,
+			Host:             ports.UserSummary{ID: hostID},
+			Title:            "Movie night",
+			StartsAt:         startsAt,
+			Timezone:         "UTC",
+			Status:           status,
+			GuestLinkVersion: version,
+		}
+	}
+	events := &stubEvents{events: map[int]*ports.Event{
+		7: newEvent(7, 1, ports.EventScheduled),
+		8: newEvent(8, 1, ports.EventScheduled),
+		9: newEvent(9, 1, ports.EventScheduled),
+	}}
+	s := &GuestService{events: events, signer: newSigner(testSecret, "guest-invite"), baseURL: "https://movies.example"}
+
+	link, err := s.CreateLink(context.Background(), hostID, 7, time.Time{})
+	if err != nil {
+		t.Fatalf("CreateLink() error: %v", err)
+	}
+	if !link.ExpiresAt.Equal(startsAt) {
+		t.Errorf("link expires at %v, want the start of the event %v", link.ExpiresAt, startsAt)
+	}
+	if !strings.HasPrefix(link.URL, "https://movies.example/guest/invites/") {
+		t.Errorf("link URL = %q", link.URL)
+	}
+
+	sign := func(s *signer, payload guestLinkPayload, expiresAt time.Time) string {
+		token, err := s.Sign(payload, expiresAt)
+		if err != nil {
+			t.Fatalf("Sign() error: %v", err)
+		}
+		return token
+	}
+	revoked, err := s.CreateLink(context.Background(), hostID, 9, time.Time{})
+	if err != nil {
+		t.Fatalf("CreateLink() error: %v", err)
+	}
+	// Хост отключил выданные ссылки -> версия вечера выросла
+	events.events[9].GuestLinkVersion++
+
+	tests := []struct {
+		name    string
+		token   string
+		eventID int   // ожидаемый вечер, если ошибки нет
+		wantErr error // nil -> ссылка работает
+	}{
+		{
+			name:    "valid link",
+			token:   link.Token,
+			eventID: 7,
+		},
+		{
+			name:    "signed for a different purpose",
+			token:   sign(newSigner(testSecret, "verify-email"), guestLinkPayload{EventID: 7, Version: 1}, startsAt),
+			wantErr: errs.ErrInvalidInput,
+		},
+		{
+			name:    "tampered payload",
+			token:   tamper(t, link.Token, func(body string) string { return strings.Replace(body, `"eid":7`, `"eid":8`, 1) }),
+			wantErr: errs.ErrInvalidInput,
+		},
+		{
+			name:    "expired link",
+			token:   sign(s.signer, guestLinkPayload{EventID: 7, Version: 1}, time.Now().Add(-time.Minute)),
+			wantErr: errs.ErrInvalidInput,
+		},
+		{
+			name:    "revoked link",
+			token:   revoked.Token,
+			wantErr: errs.ErrInvalidInput,
+		},
+		{
+			name:    "deleted event",
+			token:   sign(s.signer, guestLinkPayload{EventID: 42, Version: 1}, startsAt),
+			wantErr: errs.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := s.PreviewInvite(context.Background(), tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("PreviewInvite() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PreviewInvite() error: %v", err)
+			}
+			if e.ID != tt.eventID {
+				t.Errorf("PreviewInvite() event = %d, want %d", e.ID, tt.eventID)
+			}
+		})
+	}
+}
+
+func TestGuestInviteLinkExpiry(t *testing.T) {
+	const hostID = 1
+	startsAt := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	events := &stubEvents{events: map[int]*ports.Event{
+		7: {ID: 7, Host: ports.UserSummary{ID: hostID}, StartsAt: startsAt, Timezone: "UTC", Status: ports.EventScheduled},
+	}}
+	s := &GuestService{events: events, signer: newSigner(testSecret, "guest-invite")}
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		wantErr   error
+	}{
+		{name: "before the start", expiresAt: startsAt.Add(-time.Hour)},
+		{name: "at the start", expiresAt: startsAt},
+		{name: "after the start", expiresAt: startsAt.Add(time.Minute), wantErr: errs.ErrInvalidInput},
+		{name: "in the past", expiresAt: time.Now().Add(-time.Minute), wantErr: errs.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, err := s.CreateLink(context.Background(), hostID, 7, tt.expiresAt)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("CreateLink() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateLink() error: %v", err)
+			}
+			if !link.ExpiresAt.Equal(tt.expiresAt) {
+				t.Errorf("link expires at %v, want %v", link.ExpiresAt, tt.expiresAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	icalProdID = "-//movie-planner//Movie nights//EN"
+	// defaultEventDuration -> длительность вечера, если фильм не выбран или его длина неизвестна
+	defaultEventDuration = 2 * time.Hour
+	// icalMaxLine -> RFC 5545 3.1: строки длиннее 75 октетов переносятся
+	icalMaxLine = 75
+	// icalSeriesHorizon -> VTIMEZONE бесконечной серии покрывает столько лет вперед
+	icalSeriesHorizon = 2
+)
+
+const (
+	icalLocalFormat = "20060102T150405"
+	icalUTCFormat   = "20060102T150405Z"
+)
+
+// icalCalendar -> параметры VCALENDAR. name и refresh нужны только подписке
+type icalCalendar struct {
+	name    string
+	refresh time.Duration
+	baseURL string
+	now     time.Time
+}
+
+// renderICS -> VCALENDAR по RFC 5545: VTIMEZONE для каждого часового пояса вечеров и по VEVENT на вечер.
+// Время вечера пишется в его часовом поясе (DTSTART;TZID=...), окончание -> начало + длина фильма.
+// Серия -> VEVENT с RRULE, отмененные повторения -> EXDATE, измененные -> отдельные VEVENT
+// с тем же UID и RECURRENCE-ID. У серий должны быть загружены Overrides
+func renderICS(cal icalCalendar, events []*ports.Event) []byte {
+	var w icalWriter
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:" + icalProdID)
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	if cal.name != "" {
+		w.line("X-WR-CALNAME:" + icalText(cal.name))
+	}
+	if cal.refresh > 0 {
+		w.line("REFRESH-INTERVAL;VALUE=DURATION:" + icalDuration(cal.refresh))
+		w.line("X-PUBLISHED-TTL:" + icalDuration(cal.refresh))
+	}
+
+	for _, tz := range eventTimezones(events, cal.now) {
+		writeVTimezone(&w, tz.loc, tz.from, tz.to)
+	}
+
+	stamp := cal.now.UTC().Format(icalUTCFormat)
+	for _, e := range events {
+		writeVEvent(&w, cal, e, stamp)
+
+		rule := eventRule(e)
+		if rule == nil {
+			continue
+		}
+		dtstart := e.StartsAt.In(eventLocation(e))
+		for _, o := range e.Overrides {
+			original := o.OriginalStartsAt.In(dtstart.Location())
+			if o.CancelledAt == nil && rule.includes(dtstart, original) {
+				writeVEvent(&w, cal, occurrenceEvent(e, original, o), stamp)
+			}
+		}
+	}
+
+	w.line("END:VCALENDAR")
+	return []byte(w.b.String())
+}
+
+// writeVEvent -> VEVENT вечера, серии (с RRULE и EXDATE) или измененного повторения (с RECURRENCE-ID)
+func writeVEvent(w *icalWriter, cal icalCalendar, e *ports.Event, stamp string) {
+	loc := eventLocation(e)
+	start := e.StartsAt.In(loc)
+	end := start.Add(eventDuration(e))
+
+	w.line("BEGIN:VEVENT")
+	w.line(fmt.Sprintf("UID:event-%d@movie-planner", e.ID))
+	w.line("DTSTAMP:" + stamp)
+	if e.OccurrenceID != "" {
+		original, _ := time.Parse(icalUTCFormat, e.OccurrenceID)
+		w.line(fmt.Sprintf("RECURRENCE-ID;TZID=%s:%s", loc.String(), original.In(loc).Format(icalLocalFormat)))
+	}
+	w.line(fmt.Sprintf("DTSTART;TZID=%s:%s", loc.String(), start.Format(icalLocalFormat)))
+	w.line(fmt.Sprintf("DTEND;TZID=%s:%s", loc.String(), end.Format(icalLocalFormat)))
+	if e.OccurrenceID == "" && e.Recurrence != "" {
+		w.line("RRULE:" + e.Recurrence)
+		for _, o := range e.Overrides {
+			if o.CancelledAt != nil {
+				w.line(fmt.Sprintf("EXDATE;TZID=%s:%s", loc.String(), o.OriginalStartsAt.In(loc).Format(icalLocalFormat)))
+			}
+		}
+	}
+	w.line(fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+	w.line("CREATED:" + e.CreatedAt.UTC().Format(icalUTCFormat))
+	w.line("LAST-MODIFIED:" + e.UpdatedAt.UTC().Format(icalUTCFormat))
+	w.line("SUMMARY:" + icalText(eventSummary(e)))
+	if desc := eventICSDescription(e); desc != "" {
+		w.line("DESCRIPTION:" + icalText(desc))
+	}
+	if e.Location != "" {
+		w.line("LOCATION:" + icalText(e.Location))
+	}
+	if cal.baseURL != "" {
+		w.line(fmt.Sprintf("URL:%s/events/%d", cal.baseURL, e.ID))
+	}
+	if e.Status == ports.EventCancelled {
+		w.line("STATUS:CANCELLED")
+	} else {
+		w.line("STATUS:CONFIRMED")
+	}
+	w.line("END:VEVENT")
+}
+
+func eventSummary(e *ports.Event) string {
+	if e.MovieTitle == "" {
+		return e.Title
+	}
+	return e.Title + ": " + e.MovieTitle
+}
+
+func eventICSDescription(e *ports.Event) string {
+	var parts []string
+	if e.MovieTitle != "" {
+		movie := "Movie: " + e.MovieTitle
+		if e.MovieRuntime > 0 {
+			movie += fmt.Sprintf(" (%d min)", e.MovieRuntime)
+		}
+		parts = append(parts, movie)
+	}
+	if e.Description != "" {
+		parts = append(parts, e.Description)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func eventDuration(e *ports.Event) time.Duration {
+	if e.MovieRuntime > 0 {
+		return time.Duration(e.MovieRuntime) * time.Minute
+	}
+	return defaultEventDuration
+}
+
+// eventLocation -> часовой пояс вечера. Он проверяется при сохранении, так что UTC -> только на всякий случай
+func eventLocation(e *ports.Event) *time.Location {
+	loc, err := time.LoadLocation(e.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+type icalZone struct {
+	loc      *time.Location
+	from, to time.Time
+}
+
+// eventTimezones -> часовые пояса вечеров (по имени) и период, который должен покрыть их VTIMEZONE.
+// Серия -> до последнего повторения, бесконечная -> на icalSeriesHorizon лет вперед
+func eventTimezones(events []*ports.Event, now time.Time) []icalZone {
+	zones := make(map[string]*icalZone)
+	for _, e := range events {
+		loc := eventLocation(e)
+		start := e.StartsAt
+		end := start.Add(eventDuration(e))
+		if e.Recurrence != "" {
+			last, ok := lastStart(e)
+			if !ok {
+				last = now.AddDate(icalSeriesHorizon, 0, 0)
+			}
+			end = last.Add(eventDuration(e))
+			for _, o := range e.Overrides {
+				if o.StartsAt != nil && o.StartsAt.Before(start) {
+					start = *o.StartsAt
+				}
+				if o.StartsAt != nil && o.StartsAt.Add(eventDuration(e)).After(end) {
+					end = o.StartsAt.Add(eventDuration(e))
+				}
+			}
+		}
+		z, ok := zones[loc.String()]
+		if !ok {
+			zones[loc.String()] = &icalZone{loc: loc, from: start, to: end}
+			continue
+		}
+		if start.Before(z.from) {
+			z.from = start
+		}
+		if end.After(z.to) {
+			z.to = end
+		}
+	}
+
+	names := make([]string, 0, len(zones))
+	for name := range zones {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]icalZone, 0, len(names))
+	for _, name := range names {
+		result = append(result, *zones[name])
+	}
+	return result
+}
+
+// writeVTimezone -> VTIMEZONE из базы часовых поясов Go. Правил (RRULE) Go не отдает,
+// поэтому пишем сами переходы: начальный период и каждую смену смещения
+// с начала года первого вечера до конца года последнего
+func writeVTimezone(w *icalWriter, loc *time.Location, from, to time.Time) {
+	start := time.Date(from.In(loc).Year(), 1, 1, 0, 0, 0, 0, loc)
+	end := time.Date(to.In(loc).Year()+1, 1, 1, 0, 0, 0, 0, loc)
+
+	w.line("BEGIN:VTIMEZONE")
+	w.line("TZID:" + loc.String())
+
+	_, offset := start.Zone()
+	writeObservance(w, start, offset)
+	for _, t := range zoneTransitions(loc, start, end) {
+		writeObservance(w, t, offset)
+		_, offset = t.Zone()
+	}
+
+	w.line("END:VTIMEZONE")
+}
+
+// writeObservance -> STANDARD или DAYLIGHT, который начинается в момент t.
+// DTSTART по RFC 5545 -> местное время по смещению до перехода (prevOffset)
+func writeObservance(w *icalWriter, t time.Time, prevOffset int) {
+	name, offset := t.Zone()
+	kind := "STANDARD"
+	if t.IsDST() {
+		kind = "DAYLIGHT"
+	}
+
+	w.line("BEGIN:" + kind)
+	w.line("DTSTART:" + t.In(time.FixedZone("", prevOffset)).Format(icalLocalFormat))
+	w.line("TZOFFSETFROM:" + icalOffset(prevOffset))
+	w.line("TZOFFSETTO:" + icalOffset(offset))
+	w.line("TZNAME:" + icalText(name))
+	w.line("END:" + kind)
+}
+
+// zoneTransitions -> моменты смены смещения в [from, to). Идем по дням, а найденную
+// смену уточняем бинарным поиском до секунды
+func zoneTransitions(loc *time.Location, from, to time.Time) []time.Time {
+	var transitions []time.Time
+	_, prev := from.In(loc).Zone()
+	for day := from; day.Before(to); day = day.Add(24 * time.Hour) {
+		next := day.Add(24 * time.Hour)
+		if _, offset := next.In(loc).Zone(); offset == prev {
+			continue
+		}
+
+		lo, hi := day, next
+		for hi.Sub(lo) > time.Second {
+			mid := lo.Add(hi.Sub(lo) / 2)
+			if _, offset := mid.In(loc).Zone(); offset == prev {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		t := hi.In(loc)
+		transitions = append(transitions, t)
+		_, prev = t.Zone()
+	}
+	return transitions
+}
+
+// icalOffset -> +0500, -0330
+func icalOffset(seconds int) string {
+	sign := '+'
+	if seconds < 0 {
+		sign = '-'
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+// icalDuration -> PT1H30M
+func icalDuration(d time.Duration) string {
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	s := "PT"
+	if h > 0 {
+		s += fmt.Sprintf("%dH", h)
+	}
+	if m > 0 || h == 0 {
+		s += fmt.Sprintf("%dM", m)
+	}
+	return s
+}
+
+// icalText -> экранирование TEXT по RFC 5545 3.3.11
+func icalText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// icalWriter -> строки с CRLF и переносом длинных строк (продолжение начинается с пробела).
+// Режем только по границе символа, чтобы не сломать UTF-8
+type icalWriter struct {
+	b strings.Builder
+}
+
+func (w *icalWriter) line(s string) {
+	limit := icalMaxLine
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.b.WriteString(s[:cut])
+		w.b.WriteString("\r\n ")
+		s = s[cut:]
+		// Пробел в начале продолжения тоже занимает октет
+		limit = icalMaxLine - 1
+	}
+	w.b.WriteString(s)
+	w.b.WriteString("\r\n")
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+func TestRenderICSSeriesGolden(t *testing.T) {
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	created := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	at := func(s string) time.Time { return localTimes(t, berlin, s)[0] }
+	ptr := func(v time.Time) *time.Time { return &v }
+	title := "Late heist night"
+	movieID := 12
+
+	// Серия по пятницам: 30 октября отменено, 6 ноября перенесено на час позже с другим названием
+	series := &ports.Event{
+		ID:            7,
+		Title:         "Friday heist night",
+		Description:   "Bring snacks",
+		StartsAt:      at("2026-10-23 20:00"),
+		Timezone:      "Europe/Berlin",
+		Location:      "Aigerim's place, Abay ave 10",
+		MovieID:       &movieID,
+		MovieTitle:    "Heat",
+		MovieRuntime:  170,
+		Status:        ports.EventScheduled,
+		CreatedAt:     created,
+		UpdatedAt:     created,
+		Sequence:      1,
+		Recurrence:    "FREQ=WEEKLY;BYDAY=FR;COUNT=4",
+		RecurrenceEnd: ptr(at("2026-11-13 20:00")),
+		Overrides: []*ports.OccurrenceOverride{
+			{EventID: 7, OriginalStartsAt: at("2026-10-30 20:00"), CancelledAt: ptr(created.Add(time.Hour)), UpdatedAt: created.Add(time.Hour)},
+			{EventID: 7, OriginalStartsAt: at("2026-11-06 20:00"), Title: &title, StartsAt: ptr(at("2026-11-06 21:00")), Sequence: 1, UpdatedAt: created.Add(2 * time.Hour)},
+		},
+	}
+
+	cal := icalCalendar{
+		name:    "Movie nights",
+		refresh: time.Hour,
+		baseURL: "https://planner.example.com",
+		now:     time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+	}
+	got := renderICS(cal, []*ports.Event{series})
+
+	golden := filepath.Join("testdata", "series.ics")
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("renderICS mismatch (run with -update to rewrite)\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestICalWriterFolding(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		lines int
+	}{
+		{"short line", "SUMMARY:Heat", 1},
+		{"exactly 75 octets", "DESCRIPTION:" + strings.Repeat("a", 63), 1},
+		{"76 octets", "DESCRIPTION:" + strings.Repeat("a", 64), 2},
+		// Продолжение вмещает 74 октета текста: 75 + 74 + 74 = 223
+		{"three lines", "DESCRIPTION:" + strings.Repeat("a", 211), 3},
+		{"four lines", "DESCRIPTION:" + strings.Repeat("a", 212), 4},
+		// Кириллица по 2 октета, эмодзи по 4 -> граница строки попадает внутрь символа
+		{"cyrillic", "SUMMARY:" + strings.Repeat("Кино", 30), 4},
+		{"emoji", "SUMMARY:" + strings.Repeat("🎬", 40), 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w icalWriter
+			w.line(tt.line)
+			out := w.b.String()
+
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("output does not end with CRLF: %q", out)
+			}
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			if len(lines) != tt.lines {
+				t.Errorf("lines = %d, want %d", len(lines), tt.lines)
+			}
+			for i, l := range lines {
+				if len(l) > icalMaxLine {
+					t.Errorf("line %d is %d octets long", i, len(l))
+				}
+				if i > 0 && !strings.HasPrefix(l, " ") {
+					t.Errorf("continuation line %d does not start with a space: %q", i, l)
+				}
+				if !utf8.ValidString(l) {
+					t.Errorf("line %d splits a character: %q", i, l)
+				}
+			}
+
+			// RFC 5545 3.1: разворачивание -> убрать CRLF и один пробел за ним
+			if unfolded := strings.ReplaceAll(strings.TrimSuffix(out, "\r\n"), "\r\n ", ""); unfolded != tt.line {
+				t.Errorf("unfolded = %q, want %q", unfolded, tt.line)
+			}
+		})
+	}
+}
+
+func TestRenderICSFoldsLongText(t *testing.T) {
+	created := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	description := strings.Repeat("Приносите попкорн, пледы и хорошее настроение; ", 6)
+	e := &ports.Event{
+		ID:          3,
+		Title:       "Вечер фильмов Майкла Манна",
+		Description: description,
+		StartsAt:    time.Date(2026, 10, 23, 15, 0, 0, 0, time.UTC),
+		Timezone:    "Asia/Almaty",
+		Status:      ports.EventScheduled,
+		CreatedAt:   created,
+		UpdatedAt:   created,
+	}
+
+	out := string(renderICS(icalCalendar{now: created}, []*ports.Event{e}))
+	for _, l := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(l) > icalMaxLine || !utf8.ValidString(l) {
+			t.Errorf("bad line %q (%d octets)", l, len(l))
+		}
+	}
+	if unfolded := strings.ReplaceAll(out, "\r\n ", ""); !strings.Contains(unfolded, "\r\nDESCRIPTION:"+icalText(description)+"\r\n") {
+		t.Errorf("description is lost after unfolding:\n%s", unfolded)
+	}
+}
+
+func TestICalText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Heat", "Heat"},
+		{`a\b`, `a\\b`},
+		{"Abay ave 10, flat 5; ring twice", `Abay ave 10\, flat 5\; ring twice`},
+		{"line\r\nnext\nlast\rend", `line\nnext\nlast\nend`},
+	}
+	for _, tt := range tests {
+		if got := icalText(tt.in); got != tt.want {
+			t.Errorf("icalText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
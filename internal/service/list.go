@@ -0,0 +1,384 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	maxListTitleLength       = 200
+	maxListDescriptionLength = 2000
+	maxListNoteLength        = 1000
+	defaultPopularLists      = 20
+	maxPopularLists          = 100
+)
+
+// ListInput -> данные нового списка
+type ListInput struct {
+	Title       string
+	Description string
+	Visibility  ports.ListVisibility
+}
+
+// ListUpdate -> частичное обновление списка: nil означает "не менять"
+type ListUpdate struct {
+	Title       *string
+	Description *string
+	Visibility  *ports.ListVisibility
+}
+
+// ListEntryUpdate -> изменение заметки и/или позиции фильма в списке
+type ListEntryUpdate struct {
+	Note     *string
+	Position *int
+}
+
+// ListService -> пользовательские списки фильмов
+type ListService struct {
+	repo     ports.ListRepository
+	users    ports.UserRepository
+	events   ports.EventPublisher
+	notifier ports.Notifier
+}
+
+func NewListService(repo ports.ListRepository, users ports.UserRepository, events ports.EventPublisher, notifier ports.Notifier) *ListService {
+	return &ListService{
+		repo:     repo,
+		users:    users,
+		events:   events,
+		notifier: notifier,
+	}
+}
+
+func (s *ListService) CreateList(ctx context.Context, ownerID int, in ListInput) (*ports.MovieList, error) {
+	if in.Visibility == "" {
+		in.Visibility = ports.ListPrivate
+	}
+	list := &ports.MovieList{
+		Owner:       ports.UserSummary{ID: ownerID},
+		Title:       strings.TrimSpace(in.Title),
+		Description: strings.TrimSpace(in.Description),
+		Visibility:  in.Visibility,
+	}
+	if err := validateList(list); err != nil {
+		return nil, err
+	}
+	if err := setShareToken(list); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateList(ctx, list); err != nil {
+		return nil, err
+	}
+
+	s.publishListUpdated(ctx, list, "created", 0)
+	return s.repo.GetList(ctx, list.ID)
+}
+
+// GetList -> список с фильмами. Чужой приватный или unlisted-список выглядит как несуществующий
+func (s *ListService) GetList(ctx context.Context, id, viewerID int) (*ports.MovieList, error) {
+	list, err := s.repo.GetList(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if list.Owner.ID != viewerID && list.Visibility != ports.ListPublic {
+		return nil, errs.ErrNotFound
+	}
+
+	return s.withEntries(ctx, list, viewerID)
+}
+
+// GetSharedList -> доступ по ссылке. Работает только пока список unlisted или public
+func (s *ListService) GetSharedList(ctx context.Context, token string, viewerID int) (*ports.MovieList, error) {
+	list, err := s.repo.GetListByShareToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if list.Owner.ID != viewerID && list.Visibility == ports.ListPrivate {
+		return nil, errs.ErrNotFound
+	}
+
+	return s.withEntries(ctx, list, viewerID)
+}
+
+func (s *ListService) UpdateList(ctx context.Context, userID, id int, upd ListUpdate) (*ports.MovieList, error) {
+	list, err := s.ownedList(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	wasPublic := list.Visibility == ports.ListPublic
+
+	if upd.Title != nil {
+		list.Title = strings.TrimSpace(*upd.Title)
+	}
+	if upd.Description != nil {
+		list.Description = strings.TrimSpace(*upd.Description)
+	}
+	if upd.Visibility != nil {
+		list.Visibility = *upd.Visibility
+	}
+	if err := validateList(list); err != nil {
+		return nil, err
+	}
+	if err := setShareToken(list); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateList(ctx, list); err != nil {
+		return nil, err
+	}
+
+	if wasPublic && list.Visibility != ports.ListPublic {
+		s.events.Publish(ctx, ports.DomainEvent{Type: ports.EventListRemoved, ActorID: userID, RefID: id})
+	} else {
+		s.publishListUpdated(ctx, list, "updated", 0)
+	}
+
+	return s.withEntries(ctx, list, userID)
+}
+
+func (s *ListService) DeleteList(ctx context.Context, userID, id int) error {
+	if _, err := s.ownedList(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteList(ctx, id); err != nil {
+		return err
+	}
+
+	s.events.Publish(ctx, ports.DomainEvent{Type: ports.EventListRemoved, ActorID: userID, RefID: id})
+	return nil
+}
+
+// GetUserLists -> владелец видит все свои списки, остальные -> только публичные
+func (s *ListService) GetUserLists(ctx context.Context, ownerID, viewerID int) ([]*ports.MovieList, error) {
+	lists, err := s.repo.GetListsByOwner(ctx, ownerID, ownerID != viewerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range lists {
+		hideShareToken(l, viewerID)
+	}
+	return lists, nil
+}
+
+func (s *ListService) GetPopularLists(ctx context.Context, limit int) ([]*ports.MovieList, error) {
+	if limit <= 0 {
+		limit = defaultPopularLists
+	}
+	if limit > maxPopularLists {
+		limit = maxPopularLists
+	}
+
+	lists, err := s.repo.GetPopularLists(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range lists {
+		l.ShareToken = ""
+	}
+	return lists, nil
+}
+
+func (s *ListService) AddEntry(ctx context.Context, userID, listID, movieID int, note string) (*ports.MovieList, error) {
+	list, err := s.ownedList(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+	if movieID <= 0 {
+		return nil, fmt.Errorf("%w: movie_id is required", errs.ErrInvalidInput)
+	}
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxListNoteLength {
+		return nil, fmt.Errorf("%w: note must be at most %d characters", errs.ErrInvalidInput, maxListNoteLength)
+	}
+
+	if err := s.repo.AddListEntry(ctx, listID, &ports.ListEntry{MovieID: movieID, Note: note}); err != nil {
+		return nil, err
+	}
+
+	s.publishListUpdated(ctx, list, "movie_added", movieID)
+	return s.GetList(ctx, listID, userID)
+}
+
+func (s *ListService) UpdateEntry(ctx context.Context, userID, listID, movieID int, upd ListEntryUpdate) (*ports.MovieList, error) {
+	list, err := s.ownedList(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	if upd.Note != nil {
+		note := strings.TrimSpace(*upd.Note)
+		if utf8.RuneCountInString(note) > maxListNoteLength {
+			return nil, fmt.Errorf("%w: note must be at most %d characters", errs.ErrInvalidInput, maxListNoteLength)
+		}
+		if err := s.repo.UpdateListEntryNote(ctx, listID, movieID, note); err != nil {
+			return nil, err
+		}
+	}
+	if upd.Position != nil {
+		if *upd.Position < 1 {
+			return nil, fmt.Errorf("%w: position must be at least 1", errs.ErrInvalidInput)
+		}
+		if err := s.repo.MoveListEntry(ctx, listID, movieID, *upd.Position); err != nil {
+			return nil, err
+		}
+	}
+
+	s.publishListUpdated(ctx, list, "reordered", 0)
+	return s.GetList(ctx, listID, userID)
+}
+
+func (s *ListService) RemoveEntry(ctx context.Context, userID, listID, movieID int) error {
+	list, err := s.ownedList(ctx, userID, listID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.RemoveListEntry(ctx, listID, movieID); err != nil {
+		return err
+	}
+
+	s.publishListUpdated(ctx, list, "movie_removed", movieID)
+	return nil
+}
+
+// ShareList -> отправляет другому пользователю уведомление со ссылкой на список.
+// Приватным списком поделиться нельзя: получатель все равно не смог бы его открыть
+func (s *ListService) ShareList(ctx context.Context, userID, listID, recipientID int) error {
+	list, err := s.ownedList(ctx, userID, listID)
+	if err != nil {
+		return err
+	}
+	if list.Visibility == ports.ListPrivate {
+		return fmt.Errorf("%w: make the list unlisted or public before sharing it", errs.ErrInvalidInput)
+	}
+	if recipientID == userID {
+		return fmt.Errorf("%w: you cannot share a list with yourself", errs.ErrInvalidInput)
+	}
+
+	owner, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.users.GetUserByID(ctx, recipientID); err != nil {
+		return err
+	}
+
+	notify(ctx, s.notifier, recipientID, ports.NotificationListShared,
+		fmt.Sprintf("%s shared the list %q with you.", displayName(owner), list.Title),
+		map[string]any{"list_id": list.ID, "title": list.Title, "share_token": list.ShareToken, "user_id": userID})
+	return nil
+}
+
+// CloneList -> приватная копия чужого (или своего) списка. Чтобы склонировать
+// unlisted-список, нужен его share_token
+func (s *ListService) CloneList(ctx context.Context, userID, sourceID int, shareToken string) (*ports.MovieList, error) {
+	source, err := s.repo.GetList(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	canView := source.Owner.ID == userID || source.Visibility == ports.ListPublic ||
+		(source.Visibility == ports.ListUnlisted && shareToken != "" && shareToken == source.ShareToken)
+	if !canView {
+		return nil, errs.ErrNotFound
+	}
+
+	clone := &ports.MovieList{
+		Owner:       ports.UserSummary{ID: userID},
+		Title:       source.Title,
+		Description: source.Description,
+		Visibility:  ports.ListPrivate,
+	}
+	if err := s.repo.CloneList(ctx, sourceID, clone); err != nil {
+		return nil, err
+	}
+
+	return s.GetList(ctx, clone.ID, userID)
+}
+
+func (s *ListService) ownedList(ctx context.Context, userID, id int) (*ports.MovieList, error) {
+	list, err := s.repo.GetList(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if list.Owner.ID != userID {
+		// Чужой непубличный список не раскрываем даже через 403
+		if list.Visibility != ports.ListPublic {
+			return nil, errs.ErrNotFound
+		}
+		return nil, errs.ErrForbidden
+	}
+	return list, nil
+}
+
+func (s *ListService) withEntries(ctx context.Context, list *ports.MovieList, viewerID int) (*ports.MovieList, error) {
+	entries, err := s.repo.GetListEntries(ctx, list.ID)
+	if err != nil {
+		return nil, err
+	}
+	list.Entries = entries
+	list.EntryCount = len(entries)
+	hideShareToken(list, viewerID)
+	return list, nil
+}
+
+// publishListUpdated -> в ленту попадают только публичные списки
+func (s *ListService) publishListUpdated(ctx context.Context, list *ports.MovieList, action string, movieID int) {
+	if list.Visibility != ports.ListPublic {
+		return
+	}
+	payload := map[string]any{"list_id": list.ID, "title": list.Title, "action": action}
+	s.events.Publish(ctx, ports.DomainEvent{
+		Type:    ports.EventListUpdated,
+		ActorID: list.Owner.ID,
+		MovieID: movieID,
+		RefID:   list.ID,
+		Payload: payload,
+	})
+}
+
+func validateList(list *ports.MovieList) error {
+	if list.Title == "" {
+		return fmt.Errorf("%w: title is required", errs.ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(list.Title) > maxListTitleLength {
+		return fmt.Errorf("%w: title must be at most %d characters", errs.ErrInvalidInput, maxListTitleLength)
+	}
+	if utf8.RuneCountInString(list.Description) > maxListDescriptionLength {
+		return fmt.Errorf("%w: description must be at most %d characters", errs.ErrInvalidInput, maxListDescriptionLength)
+	}
+	switch list.Visibility {
+	case ports.ListPrivate, ports.ListUnlisted, ports.ListPublic:
+	default:
+		return fmt.Errorf("%w: visibility must be private, unlisted or public", errs.ErrInvalidInput)
+	}
+	return nil
+}
+
+// setShareToken -> у unlisted и public списков есть ссылка для шаринга. При переводе
+// в private ссылка сбрасывается, и старые ссылки перестают работать
+func setShareToken(list *ports.MovieList) error {
+	if list.Visibility == ports.ListPrivate {
+		list.ShareToken = ""
+		return nil
+	}
+	if list.ShareToken != "" {
+		return nil
+	}
+	token, _, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+	list.ShareToken = token
+	return nil
+}
+
+func hideShareToken(list *ports.MovieList, viewerID int) {
+	if list.Owner.ID != viewerID {
+		list.ShareToken = ""
+	}
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	// maxMarathonMovies -> при нехватке времени перебираем все подмножества фильмов, 2^12 -> еще быстро
+	minMarathonMovies   = 2
+	maxMarathonMovies   = 12
+	maxMarathonBreak    = 240
+	defaultMealMinutes  = 45
+	minMealEveryMinutes = 60
+)
+
+// Что оптимизируем, когда все фильмы не помещаются
+const (
+	MarathonOptimizeRating   = "rating"   // сумма рейтингов фильмов
+	MarathonOptimizePriority = "priority" // сумма приоритетов в списке "хочу посмотреть"
+)
+
+// Виды элементов расписания
+const (
+	MarathonItemMovie = "movie"
+	MarathonItemBreak = "break"
+	MarathonItemMeal  = "meal"
+)
+
+// MarathonInput -> входные данные для расписания марафона
+type MarathonInput struct {
+	MovieIDs []int
+	// Ordered -> смотреть в заданном порядке. Иначе -> по дате выхода (для франшиз это порядок частей)
+	Ordered      bool
+	StartsAt     time.Time
+	BreakMinutes int // перерыв между фильмами
+	// MealEveryMinutes -> как часто нужен перерыв на еду, 0 -> без еды
+	MealEveryMinutes int
+	MealMinutes      int        // длина перерыва на еду, 0 -> 45 минут
+	EndsBy           *time.Time // жесткое время окончания, nil -> без ограничения
+	Optimize         string     // rating (по умолчанию) или priority
+}
+
+// MarathonItem -> фильм или перерыв в расписании
+type MarathonItem struct {
+	Kind     string    `json:"kind" example:"movie" enums:"movie,break,meal"`
+	MovieID  int       `json:"movie_id,omitempty" example:"12"`
+	Title    string    `json:"title,omitempty" example:"Heat"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Minutes  int       `json:"minutes" example:"170"`
+}
+
+// MarathonDrop -> фильм, который не поместился до ends_by
+type MarathonDrop struct {
+	MovieID int     `json:"movie_id" example:"7"`
+	Title   string  `json:"title" example:"Ronin"`
+	Runtime int     `json:"runtime" example:"122"`
+	Value   float64 `json:"value" example:"7.3"` // рейтинг или приоритет, смотря что оптимизировали
+}
+
+// MarathonPlan -> готовое расписание
+type MarathonPlan struct {
+	StartsAt     time.Time       `json:"starts_at"`
+	EndsAt       time.Time       `json:"ends_at"`
+	TotalMinutes int             `json:"total_minutes" example:"415"`
+	Optimize     string          `json:"optimize" example:"rating"`
+	TotalValue   float64         `json:"total_value" example:"23.1"`
+	Items        []*MarathonItem `json:"items"`
+	// Dropped -> что предлагаем не смотреть, чтобы успеть к ends_by. Пусто -> поместилось все
+	Dropped []*MarathonDrop `json:"dropped"`
+}
+
+// PlanMarathon -> расписание марафона. Если все фильмы не успеть до EndsBy, выбираем набор
+// с наибольшей суммой рейтингов (или приоритетов в списке пользователя), сохраняя порядок
+func (s *PlannerService) PlanMarathon(ctx context.Context, userID int, in MarathonInput) (*MarathonPlan, error) {
+	if err := validateMarathon(&in); err != nil {
+		return nil, err
+	}
+
+	all, err := s.movies.GetAllMovies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*ports.Movie, len(all))
+	for _, m := range all {
+		byID[m.ID] = m
+	}
+
+	movies := make([]*ports.Movie, 0, len(in.MovieIDs))
+	for _, id := range in.MovieIDs {
+		m, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: movie %d", errs.ErrNotFound, id)
+		}
+		if m.Runtime <= 0 {
+			return nil, fmt.Errorf("%w: the runtime of %q is unknown", errs.ErrInvalidInput, m.Title)
+		}
+		movies = append(movies, m)
+	}
+	if !in.Ordered {
+		sort.SliceStable(movies, func(i, j int) bool {
+			return movies[i].ReleaseDate.Before(movies[j].ReleaseDate.Time)
+		})
+	}
+
+	values := make([]float64, len(movies))
+	for i, m := range movies {
+		if in.Optimize == MarathonOptimizePriority {
+			item, err := s.watchlist.GetWatchlistItem(ctx, userID, m.ID)
+			if err != nil && !errors.Is(err, errs.ErrNotFound) {
+				return nil, err
+			}
+			// Фильма нет в списке -> приоритет 0, его предложим выкинуть первым
+			if item != nil {
+				values[i] = float64(item.Priority)
+			}
+		} else {
+			values[i] = m.Rating
+		}
+	}
+
+	plan := buildMarathon(movies, values, in)
+	if plan == nil {
+		return nil, fmt.Errorf("%w: none of the movies fit before ends_by", errs.ErrInvalidInput)
+	}
+	return plan, nil
+}
+
+func validateMarathon(in *MarathonInput) error {
+	ids := uniqueInts(in.MovieIDs)
+	if len(ids) != len(in.MovieIDs) {
+		return fmt.Errorf("%w: movie_ids must not repeat", errs.ErrInvalidInput)
+	}
+	if len(ids) < minMarathonMovies || len(ids) > maxMarathonMovies {
+		return fmt.Errorf("%w: a marathon needs between %d and %d movies", errs.ErrInvalidInput, minMarathonMovies, maxMarathonMovies)
+	}
+	if in.StartsAt.IsZero() {
+		return fmt.Errorf("%w: starts_at is required", errs.ErrInvalidInput)
+	}
+	if in.EndsBy != nil && !in.EndsBy.After(in.StartsAt) {
+		return fmt.Errorf("%w: ends_by must be after starts_at", errs.ErrInvalidInput)
+	}
+	if in.BreakMinutes < 0 || in.BreakMinutes > maxMarathonBreak || in.MealMinutes < 0 || in.MealMinutes > maxMarathonBreak {
+		return fmt.Errorf("%w: breaks must be between 0 and %d minutes", errs.ErrInvalidInput, maxMarathonBreak)
+	}
+	if in.MealEveryMinutes != 0 && in.MealEveryMinutes < minMealEveryMinutes {
+		return fmt.Errorf("%w: meal_every_minutes must be 0 or at least %d", errs.ErrInvalidInput, minMealEveryMinutes)
+	}
+	if in.MealEveryMinutes > 0 && in.MealMinutes == 0 {
+		in.MealMinutes = defaultMealMinutes
+	}
+	switch in.Optimize {
+	case "":
+		in.Optimize = MarathonOptimizeRating
+	case MarathonOptimizeRating, MarathonOptimizePriority:
+	default:
+		return fmt.Errorf("%w: optimize must be rating or priority", errs.ErrInvalidInput)
+	}
+	return nil
+}
+
+// buildMarathon -> расписание для movies (уже в порядке просмотра). Без EndsBy берем все фильмы.
+// С EndsBy перебираем все подмножества с сохранением порядка и берем то, что успевает и имеет
+// наибольшую сумму values; при равенстве -> больше фильмов, затем раньше конец, затем
+// подмножество, которое раньше по порядку. nil -> не помещается ни один фильм
+func buildMarathon(movies []*ports.Movie, values []float64, in MarathonInput) *MarathonPlan {
+	n := len(movies)
+	full := uint(1)<<n - 1
+
+	bestMask, found := uint(0), false
+	var bestItems []*MarathonItem
+	var bestValue float64
+	var bestEnd time.Time
+
+	for mask := full; mask > 0; mask-- {
+		subset := make([]*ports.Movie, 0, bits.OnesCount(mask))
+		var value float64
+		for i := range movies {
+			if mask&(1<<i) != 0 {
+				subset = append(subset, movies[i])
+				value += values[i]
+			}
+		}
+		items, end := scheduleMarathon(subset, in)
+		if in.EndsBy != nil && end.After(*in.EndsBy) {
+			continue
+		}
+		if mask == full {
+			bestMask, found, bestItems, bestValue, bestEnd = mask, true, items, value, end
+			break
+		}
+
+		better := !found || value > bestValue+1e-9
+		if !better && value > bestValue-1e-9 {
+			cnt, bestCnt := bits.OnesCount(mask), bits.OnesCount(bestMask)
+			better = cnt > bestCnt || (cnt == bestCnt && end.Before(bestEnd)) ||
+				(cnt == bestCnt && end.Equal(bestEnd) && earlierSubset(mask, bestMask))
+		}
+		if better {
+			bestMask, found, bestItems, bestValue, bestEnd = mask, true, items, value, end
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	dropped := make([]*MarathonDrop, 0)
+	for i, m := range movies {
+		if bestMask&(1<<i) == 0 {
+			dropped = append(dropped, &MarathonDrop{MovieID: m.ID, Title: m.Title, Runtime: m.Runtime, Value: round2(values[i])})
+		}
+	}
+
+	return &MarathonPlan{
+		StartsAt:     in.StartsAt,
+		EndsAt:       bestEnd,
+		TotalMinutes: int(bestEnd.Sub(in.StartsAt).Minutes()),
+		Optimize:     in.Optimize,
+		TotalValue:   round2(bestValue),
+		Items:        bestItems,
+		Dropped:      dropped,
+	}
+}
+
+// earlierSubset -> первый фильм, который есть только в одном из наборов, входит в a.
+// Маски перебираются по убыванию, а старшие биты -> поздние фильмы, поэтому без этого
+// при полном равенстве выигрывал бы набор с более поздними фильмами
+func earlierSubset(a, b uint) bool {
+	diff := a ^ b
+	return a&(diff&-diff) != 0
+}
+
+// scheduleMarathon -> фильмы подряд с перерывами. Перерыв на еду ставится вместо обычного,
+// если иначе следующий фильм закончится позже, чем через MealEveryMinutes после прошлой еды
+// (считаем, что перед началом марафона все поели)
+func scheduleMarathon(movies []*ports.Movie, in MarathonInput) ([]*MarathonItem, time.Time) {
+	items := make([]*MarathonItem, 0, 2*len(movies))
+	t := in.StartsAt
+	sinceMeal := 0
+
+	add := func(kind string, minutes int, m *ports.Movie) {
+		item := &MarathonItem{Kind: kind, StartsAt: t, EndsAt: t.Add(time.Duration(minutes) * time.Minute), Minutes: minutes}
+		if m != nil {
+			item.MovieID, item.Title = m.ID, m.Title
+		}
+		items = append(items, item)
+		t = item.EndsAt
+	}
+
+	for i, m := range movies {
+		if i > 0 {
+			if in.MealEveryMinutes > 0 && sinceMeal+in.BreakMinutes+m.Runtime > in.MealEveryMinutes {
+				add(MarathonItemMeal, in.MealMinutes, nil)
+				sinceMeal = 0
+			} else if in.BreakMinutes > 0 {
+				add(MarathonItemBreak, in.BreakMinutes, nil)
+				sinceMeal += in.BreakMinutes
+			}
+		}
+		add(MarathonItemMovie, m.Runtime, m)
+		sinceMeal += m.Runtime
+	}
+	return items, t
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+var marathonStart = time.Date(2026, 10, 24, 20, 0, 0, 0, time.UTC)
+
+// clock -> время марафона в виде "21:30" или "+1 01:40" для следующего дня
+func clock(t time.Time) string {
+	if days := int(t.Sub(marathonStart.Truncate(24*time.Hour)) / (24 * time.Hour)); days > 0 {
+		return "+1 " + t.Format("15:04")
+	}
+	return t.Format("15:04")
+}
+
+// itemsText -> расписание в виде "movie A 20:00-22:00"
+func itemsText(items []*MarathonItem) []string {
+	result := make([]string, len(items))
+	for i, it := range items {
+		what := it.Kind
+		if it.Kind == MarathonItemMovie {
+			what += " " + it.Title
+		}
+		result[i] = what + " " + clock(it.StartsAt) + "-" + clock(it.EndsAt)
+	}
+	return result
+}
+
+func testMovies(runtimes ...int) []*ports.Movie {
+	movies := make([]*ports.Movie, len(runtimes))
+	for i, r := range runtimes {
+		movies[i] = &ports.Movie{ID: i + 1, Title: string(rune('A' + i)), Runtime: r}
+	}
+	return movies
+}
+
+func TestScheduleMarathon(t *testing.T) {
+	tests := []struct {
+		name    string
+		runtime []int
+		in      MarathonInput
+		want    []string
+		end     string
+	}{
+		{
+			name:    "breaks between movies only",
+			runtime: []int{120, 100, 90},
+			in:      MarathonInput{StartsAt: marathonStart, BreakMinutes: 15},
+			want: []string{
+				"movie A 20:00-22:00", "break 22:00-22:15", "movie B 22:15-23:55",
+				"break 23:55-+1 00:10", "movie C +1 00:10-+1 01:40",
+			},
+			end: "+1 01:40",
+		},
+		{
+			name:    "meal replaces the break before the movie that would run too long",
+			runtime: []int{120, 100, 90},
+			// После B прошло 235 минут, с C было бы 340 > 240 -> перед C еда
+			in: MarathonInput{StartsAt: marathonStart, BreakMinutes: 15, MealEveryMinutes: 240, MealMinutes: 45},
+			want: []string{
+				"movie A 20:00-22:00", "break 22:00-22:15", "movie B 22:15-23:55",
+				"meal 23:55-+1 00:40", "movie C +1 00:40-+1 02:10",
+			},
+			end: "+1 02:10",
+		},
+		{
+			name:    "meal resets the counter",
+			runtime: []int{150, 150, 60},
+			in:      MarathonInput{StartsAt: marathonStart, MealEveryMinutes: 210, MealMinutes: 30},
+			want: []string{
+				"movie A 20:00-22:30", "meal 22:30-23:00", "movie B 23:00-+1 01:30", "movie C +1 01:30-+1 02:30",
+			},
+			end: "+1 02:30",
+		},
+		{
+			name:    "no breaks",
+			runtime: []int{90, 90},
+			in:      MarathonInput{StartsAt: marathonStart},
+			want:    []string{"movie A 20:00-21:30", "movie B 21:30-23:00"},
+			end:     "23:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, end := scheduleMarathon(testMovies(tt.runtime...), tt.in)
+			if got := itemsText(items); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("items = %q, want %q", got, tt.want)
+			}
+			if clock(end) != tt.end {
+				t.Errorf("end = %s, want %s", clock(end), tt.end)
+			}
+			for _, it := range items {
+				if it.EndsAt.Sub(it.StartsAt) != time.Duration(it.Minutes)*time.Minute {
+					t.Errorf("%s lasts %v, minutes = %d", it.Kind, it.EndsAt.Sub(it.StartsAt), it.Minutes)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildMarathon(t *testing.T) {
+	endsBy := func(minutes int) *time.Time {
+		t := marathonStart.Add(time.Duration(minutes) * time.Minute)
+		return &t
+	}
+
+	tests := []struct {
+		name    string
+		runtime []int
+		values  []float64
+		in      MarathonInput
+		movies  []string // что смотрим, по порядку
+		dropped []int
+		value   float64
+	}{
+		{
+			name:    "everything fits without ends_by",
+			runtime: []int{120, 100, 90},
+			values:  []float64{8, 6, 7.5},
+			in:      MarathonInput{StartsAt: marathonStart, BreakMinutes: 15},
+			movies:  []string{"A", "B", "C"},
+			dropped: []int{},
+			value:   21.5,
+		},
+		{
+			name:    "highest total value that fits, order kept",
+			runtime: []int{120, 100, 90},
+			values:  []float64{8, 6, 7.5},
+			// A+B = 235, A+C = 225, B+C = 205 минут
+			in:      MarathonInput{StartsAt: marathonStart, BreakMinutes: 15, EndsBy: endsBy(225)},
+			movies:  []string{"A", "C"},
+			dropped: []int{2},
+			value:   15.5,
+		},
+		{
+			name:    "equal value -> more movies",
+			runtime: []int{100, 100, 200},
+			values:  []float64{6, 6, 12},
+			in:      MarathonInput{StartsAt: marathonStart, BreakMinutes: 15, EndsBy: endsBy(215)},
+			movies:  []string{"A", "B"},
+			dropped: []int{3},
+			value:   12,
+		},
+		{
+			name:    "equal value and count -> earlier end",
+			runtime: []int{120, 90},
+			values:  []float64{5, 5},
+			in:      MarathonInput{StartsAt: marathonStart, EndsBy: endsBy(130)},
+			movies:  []string{"B"},
+			dropped: []int{1},
+			value:   5,
+		},
+		{
+			name:    "full tie -> earlier movies",
+			runtime: []int{100, 100},
+			values:  []float64{5, 5},
+			in:      MarathonInput{StartsAt: marathonStart, EndsBy: endsBy(100)},
+			movies:  []string{"A"},
+			dropped: []int{2},
+			value:   5,
+		},
+		{
+			name:    "ends_by exactly at the end of everything",
+			runtime: []int{90, 90},
+			values:  []float64{1, 1},
+			in:      MarathonInput{StartsAt: marathonStart, BreakMinutes: 20, EndsBy: endsBy(200)},
+			movies:  []string{"A", "B"},
+			dropped: []int{},
+			value:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := buildMarathon(testMovies(tt.runtime...), tt.values, tt.in)
+			if plan == nil {
+				t.Fatal("plan = nil")
+			}
+
+			movies := make([]string, 0)
+			for _, it := range plan.Items {
+				if it.Kind == MarathonItemMovie {
+					movies = append(movies, it.Title)
+				}
+			}
+			if !reflect.DeepEqual(movies, tt.movies) {
+				t.Errorf("movies = %v, want %v", movies, tt.movies)
+			}
+			dropped := make([]int, 0)
+			for _, d := range plan.Dropped {
+				dropped = append(dropped, d.MovieID)
+			}
+			if !reflect.DeepEqual(dropped, tt.dropped) {
+				t.Errorf("dropped = %v, want %v", dropped, tt.dropped)
+			}
+			if plan.TotalValue != tt.value {
+				t.Errorf("total value = %v, want %v", plan.TotalValue, tt.value)
+			}
+			if want := int(plan.EndsAt.Sub(marathonStart).Minutes()); plan.TotalMinutes != want {
+				t.Errorf("total minutes = %d, want %d", plan.TotalMinutes, want)
+			}
+			if tt.in.EndsBy != nil && plan.EndsAt.After(*tt.in.EndsBy) {
+				t.Errorf("plan ends at %v after ends_by %v", plan.EndsAt, *tt.in.EndsBy)
+			}
+		})
+	}
+}
+
+func TestBuildMarathonNothingFits(t *testing.T) {
+	endsBy := marathonStart.Add(90 * time.Minute)
+	plan := buildMarathon(testMovies(120, 100), []float64{8, 7}, MarathonInput{StartsAt: marathonStart, EndsBy: &endsBy})
+	if plan != nil {
+		t.Errorf("plan = %+v, want nil", plan)
+	}
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	defaultNotificationLimit = 20
+	maxNotificationLimit     = 100
+
+	// notificationEmailBatch и notificationEmailLease -> пачка должна успеть уйти до конца аренды,
+	// иначе ее заберет другой экземпляр
+	notificationEmailBatch       = 20
+	notificationEmailLease       = 5 * time.Minute
+	maxNotificationEmailAttempts = 5
+	notificationEmailRetryAfter  = 5 * time.Minute
+)
+
+// NotificationPage -> страница уведомлений и общее число непрочитанных
+type NotificationPage struct {
+	Items       []*ports.Notification `json:"items"`
+	UnreadCount int                   `json:"unread_count" example:"3"`
+	NextCursor  string                `json:"next_cursor,omitempty" example:"12"`
+}
+
+// NotificationService -> чтение уведомлений, настройки их доставки и отправка писем из очереди
+type NotificationService struct {
+	repo   ports.NotificationRepository
+	emails ports.NotificationEmailQueue
+	mailer ports.Mailer
+}
+
+func NewNotificationService(repo ports.NotificationRepository, emails ports.NotificationEmailQueue, mailer ports.Mailer) *NotificationService {
+	return &NotificationService{
+		repo:   repo,
+		emails: emails,
+		mailer: mailer,
+	}
+}
+
+func (s *NotificationService) GetNotifications(ctx context.Context, userID int, unreadOnly bool, cursor string, limit int) (*NotificationPage, error) {
+	var before int64
+	if cursor != "" {
+		v, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || v <= 0 {
+			return nil, fmt.Errorf("%w: invalid cursor", errs.ErrInvalidInput)
+		}
+		before = v
+	}
+	if limit <= 0 {
+		limit = defaultNotificationLimit
+	}
+	if limit > maxNotificationLimit {
+		limit = maxNotificationLimit
+	}
+
+	items, err := s.repo.GetNotifications(ctx, userID, unreadOnly, before, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.repo.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &NotificationPage{Items: items, UnreadCount: unread}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.NextCursor = strconv.FormatInt(page.Items[limit-1].ID, 10)
+	}
+
+	return page, nil
+}
+
+// MarkRead -> пустой ids -> отметить все уведомления пользователя
+func (s *NotificationService) MarkRead(ctx context.Context, userID int, ids []int64) (int, error) {
+	return s.repo.MarkNotificationsRead(ctx, userID, ids)
+}
+
+func (s *NotificationService) GetPreferences(ctx context.Context, userID int) ([]ports.NotificationPreference, error) {
+	return s.repo.GetNotificationPreferences(ctx, userID)
+}
+
+// UpdatePreferences -> меняет настройки только для переданных типов
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID int, prefs []ports.NotificationPreference) ([]ports.NotificationPreference, error) {
+	for _, p := range prefs {
+		if !isNotificationType(p.Type) {
+			return nil, fmt.Errorf("%w: unknown notification type %q", errs.ErrInvalidInput, p.Type)
+		}
+	}
+	for _, p := range prefs {
+		if err := s.repo.SetNotificationPreference(ctx, userID, p); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.GetNotificationPreferences(ctx, userID)
+}
+
+// SendQueuedEmails -> забирает письма из очереди пачками и отправляет их.
+// Каждое отмечается отдельно сразу после отправки. Возвращает число отправленных
+func (s *NotificationService) SendQueuedEmails(ctx context.Context) (int, error) {
+	sent := 0
+	for ctx.Err() == nil {
+		batch, err := s.emails.ClaimNotificationEmails(ctx, time.Now(), notificationEmailBatch, notificationEmailLease)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, e := range batch {
+			err := s.mailer.Send(ctx, ports.MailMessage{
+				To:      e.To,
+				Subject: "Movie Planner: " + e.Message,
+				Body:    e.Message + "\n\nYou can change which notifications you receive by email in your notification settings.",
+			})
+			if err != nil {
+				log.Printf("Failed to email %s notification %d (attempt %d): %v", e.Type, e.ID, e.Attempts, err)
+				retryAt := time.Now().Add(notificationEmailRetryAfter)
+				if err := s.emails.RetryNotificationEmail(ctx, e.ID, e.Attempts, err.Error(), retryAt, maxNotificationEmailAttempts); err != nil {
+					return sent, err
+				}
+				continue
+			}
+			if err := s.emails.MarkNotificationEmailSent(ctx, e.ID, e.Attempts); err != nil {
+				return sent, err
+			}
+			sent++
+		}
+
+		if len(batch) < notificationEmailBatch {
+			break
+		}
+	}
+
+	return sent, nil
+}
+
+// RunEmailJob -> фоновая задача: каждые interval отправляет письма из очереди. Экземпляров может быть несколько
+func (s *NotificationService) RunEmailJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := s.SendQueuedEmails(ctx); err != nil {
+			log.Printf("Notification email job failed: %v", err)
+		} else if n > 0 {
+			log.Printf("Notification email job sent %d emails", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func isNotificationType(t string) bool {
+	for _, known := range ports.NotificationTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// notify -> общий помощник для сервисов. Ошибку уведомления только логируем:
+// основное действие пользователя уже выполнено
+func notify(ctx context.Context, notifier ports.Notifier, userID int, notificationType, message string, payload map[string]any) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Failed to marshal %s notification payload: %v", notificationType, err)
+		return
+	}
+
+	n := &ports.Notification{UserID: userID, Type: notificationType, Message: message, Payload: raw}
+	if err := notifier.Notify(ctx, n); err != nil {
+		log.Printf("Failed to notify user %d (%s): %v", userID, notificationType, err)
+	}
+}
+
+// displayName -> имя для текста уведомления
+func displayName(u *ports.User) string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	return fmt.Sprintf("User %d", u.ID)
+}
+
+// NotificationDispatcher -> подписчик доменных событий, который рассылает уведомления
+type NotificationDispatcher struct {
+	notifier ports.Notifier
+	social   ports.SocialRepository
+	users    ports.UserRepository
+}
+
+func NewNotificationDispatcher(notifier ports.Notifier, social ports.SocialRepository, users ports.UserRepository) *NotificationDispatcher {
+	return &NotificationDispatcher{
+		notifier: notifier,
+		social:   social,
+		users:    users,
+	}
+}
+
+func (d *NotificationDispatcher) Register(bus *EventBus) {
+	bus.Subscribe("notifications", d.handle, ports.EventRatingCreated, ports.EventRatingUpdated)
+}
+
+func (d *NotificationDispatcher) handle(ctx context.Context, e ports.DomainEvent) error {
+	// Подписчиков уведомляем только об отзывах, простые оценки видны в ленте
+	review, _ := e.Payload["review"].(string)
+	if review == "" {
+		return nil
+	}
+
+	actor, err := d.users.GetUserByID(ctx, e.ActorID)
+	if err != nil {
+		return err
+	}
+	followers, err := d.social.GetFollowers(ctx, e.ActorID)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]any{"user_id": e.ActorID, "movie_id": e.MovieID, "rating": e.Payload["rating"]}
+	message := fmt.Sprintf("%s reviewed a movie.", displayName(actor))
+	for _, f := range followers {
+		notify(ctx, d.notifier, f.User.ID, ports.NotificationFollowedReview, message, payload)
+	}
+
+	return nil
+}
@@ -0,0 +1,502 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+// OccurrenceScope -> что меняется при правке повторения: только оно или оно и все следующие
+type OccurrenceScope string
+
+const (
+	OccurrenceThis      OccurrenceScope = "this"
+	OccurrenceFollowing OccurrenceScope = "following"
+)
+
+// GetOccurrences -> повторения одной серии с началом в [from, to] по дням. Нулевой from -> с сегодняшнего дня,
+// нулевой to -> на год вперед. Разовый вечер -> он сам, если попадает в промежуток
+func (s *EventService) GetOccurrences(ctx context.Context, userID, id int, from, to time.Time) ([]*ports.Event, error) {
+	e, err := s.visibleEvent(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if from.IsZero() {
+		now := time.Now().UTC()
+		from = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	if to.IsZero() {
+		to = from.AddDate(recurrenceHorizon, 0, 0)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: to must not be before from", errs.ErrInvalidInput)
+	}
+
+	if err := attachOverrides(ctx, s.repo, []*ports.Event{e}); err != nil {
+		return nil, err
+	}
+	return expandEvent(e, from, to.AddDate(0, 0, 1)), nil
+}
+
+// UpdateOccurrence -> правка повторения серии. this -> меняется только это повторение
+// (время, название, описание, место, фильм). following -> серия обрезается перед ним,
+// а с него начинается новая серия с правками и копией приглашений; возвращается новая серия
+func (s *EventService) UpdateOccurrence(ctx context.Context, userID, id int, occurrenceID string, scope OccurrenceScope, upd EventUpdate) (*ports.Event, error) {
+	e, err := s.hostedEvent(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if e.Status == ports.EventCancelled {
+		return nil, fmt.Errorf("%w: a cancelled event cannot be changed", errs.ErrConflict)
+	}
+	rule, original, o, err := s.occurrence(ctx, e, occurrenceID)
+	if err != nil {
+		return nil, err
+	}
+	if !occurrenceEvent(e, original, o).StartsAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: the occurrence has already started", errs.ErrConflict)
+	}
+
+	switch scope {
+	case OccurrenceThis, "":
+		return s.updateOne(ctx, userID, e, original, o, upd)
+	case OccurrenceFollowing:
+		return s.updateFollowing(ctx, userID, e, rule, original, upd)
+	default:
+		return nil, fmt.Errorf("%w: scope must be this or following", errs.ErrInvalidInput)
+	}
+}
+
+// CancelOccurrence -> this -> отменяется одно повторение, following -> серия заканчивается перед ним.
+// Если это первое повторение, following отменяет всю серию
+func (s *EventService) CancelOccurrence(ctx context.Context, userID, id int, occurrenceID string, scope OccurrenceScope) error {
+	e, err := s.hostedEvent(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if e.Status == ports.EventCancelled {
+		return fmt.Errorf("%w: the event is already cancelled", errs.ErrConflict)
+	}
+	rule, original, o, err := s.occurrence(ctx, e, occurrenceID)
+	if err != nil {
+		return err
+	}
+	if !occurrenceEvent(e, original, o).StartsAt.After(time.Now()) {
+		return fmt.Errorf("%w: the occurrence has already started", errs.ErrConflict)
+	}
+
+	switch scope {
+	case OccurrenceThis, "":
+		if o == nil {
+			o = &ports.OccurrenceOverride{EventID: id, OriginalStartsAt: original}
+		}
+		if o.CancelledAt != nil {
+			return fmt.Errorf("%w: the occurrence is already cancelled", errs.ErrConflict)
+		}
+		now := time.Now()
+		o.CancelledAt = &now
+		if err := s.repo.SaveOccurrenceOverride(ctx, o); err != nil {
+			return err
+		}
+	case OccurrenceFollowing:
+		dtstart := e.StartsAt.In(eventLocation(e))
+		if original.Equal(dtstart) {
+			return s.CancelEvent(ctx, userID, id)
+		}
+		truncateSeries(e, rule, original)
+		if _, err := s.repo.UpdateEvent(ctx, e); err != nil {
+			return err
+		}
+		if err := s.repo.DeleteOccurrenceOverrides(ctx, id, original); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: scope must be this or following", errs.ErrInvalidInput)
+	}
+
+	s.publisher.Publish(ctx, ports.DomainEvent{
+		Type:    ports.EventMovieNightUpdated,
+		ActorID: userID,
+		RefID:   id,
+		Payload: map[string]any{"occurrence_id": occurrenceID, "scope": string(scope), "cancelled": true},
+	})
+	return nil
+}
+
+func (s *EventService) updateOne(ctx context.Context, userID int, e *ports.Event, original time.Time, o *ports.OccurrenceOverride, upd EventUpdate) (*ports.Event, error) {
+	if upd.Capacity != nil || upd.Timezone != nil || upd.Recurrence != nil {
+		return nil, fmt.Errorf("%w: capacity, timezone and recurrence can only be changed for the whole series", errs.ErrInvalidInput)
+	}
+	if o == nil {
+		o = &ports.OccurrenceOverride{EventID: e.ID, OriginalStartsAt: original}
+	}
+	if o.CancelledAt != nil {
+		return nil, fmt.Errorf("%w: the occurrence is cancelled", errs.ErrConflict)
+	}
+
+	if upd.Title != nil {
+		title := strings.TrimSpace(*upd.Title)
+		o.Title = &title
+	}
+	if upd.Description != nil {
+		description := strings.TrimSpace(*upd.Description)
+		o.Description = &description
+	}
+	if upd.Location != nil {
+		location := strings.TrimSpace(*upd.Location)
+		o.Location = &location
+	}
+	if upd.StartsAt != nil {
+		if !upd.StartsAt.After(time.Now()) {
+			return nil, fmt.Errorf("%w: starts_at must be in the future", errs.ErrInvalidInput)
+		}
+		o.StartsAt = upd.StartsAt
+	}
+	if upd.MovieID != nil {
+		o.MovieSet = true
+		o.MovieID = upd.MovieID
+		if *upd.MovieID == 0 {
+			o.MovieID = nil
+		}
+	}
+	if err := validateEvent(occurrenceEvent(e, original, o)); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SaveOccurrenceOverride(ctx, o); err != nil {
+		return nil, err
+	}
+	occurrenceID := occurrenceKey(original)
+	s.publisher.Publish(ctx, ports.DomainEvent{
+		Type:    ports.EventMovieNightUpdated,
+		ActorID: userID,
+		RefID:   e.ID,
+		Payload: map[string]any{"occurrence_id": occurrenceID, "scope": string(OccurrenceThis)},
+	})
+
+	// Перечитываем, чтобы получить название и длину нового фильма
+	_, original, o, err := s.occurrence(ctx, e, occurrenceID)
+	if err != nil {
+		return nil, err
+	}
+	return occurrenceEvent(e, original, o), nil
+}
+
+func (s *EventService) updateFollowing(ctx context.Context, userID int, e *ports.Event, rule *recurrence, original time.Time, upd EventUpdate) (*ports.Event, error) {
+	dtstart := e.StartsAt.In(eventLocation(e))
+	if original.Equal(dtstart) {
+		return s.UpdateEvent(ctx, userID, e.ID, upd)
+	}
+
+	next := &ports.Event{
+		Host:        e.Host,
+		Title:       e.Title,
+		Description: e.Description,
+		StartsAt:    original,
+		Timezone:    e.Timezone,
+		Location:    e.Location,
+		Capacity:    e.Capacity,
+		MovieID:     e.MovieID,
+	}
+	if err := applyEventUpdate(next, upd); err != nil {
+		return nil, err
+	}
+	if err := validateEvent(next); err != nil {
+		return nil, err
+	}
+	if upd.Recurrence != nil {
+		next.Recurrence = *upd.Recurrence
+	} else {
+		// Правило то же, но только на оставшиеся повторения
+		rest := *rule
+		if rule.count > 0 {
+			rest.count = rule.count - rule.countBefore(dtstart, original)
+		}
+		next.Recurrence = rest.shiftDays(original, next.StartsAt.In(eventLocation(next))).String()
+	}
+	if err := applyRecurrence(next); err != nil {
+		return nil, err
+	}
+
+	old := *e
+	truncateSeries(&old, rule, original)
+	// Повторения новой серии совпадают со старыми, только если не менялись время, пояс и правило
+	moveOverrides := upd.StartsAt == nil && upd.Timezone == nil && upd.Recurrence == nil
+	promoted, err := s.repo.SplitEventSeries(ctx, &old, next, original, moveOverrides)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyPromoted(ctx, next, promoted)
+	s.publisher.Publish(ctx, ports.DomainEvent{
+		Type:    ports.EventMovieNightUpdated,
+		ActorID: userID,
+		RefID:   e.ID,
+		Payload: map[string]any{"occurrence_id": occurrenceKey(original), "scope": string(OccurrenceFollowing), "new_event_id": next.ID},
+	})
+
+	return s.GetEvent(ctx, userID, next.ID)
+}
+
+// occurrence -> правило серии, исходное начало повторения (в часовом поясе вечера) и его изменения (nil -> нет).
+// ErrNotFound, если у серии нет такого повторения
+func (s *EventService) occurrence(ctx context.Context, e *ports.Event, occurrenceID string) (*recurrence, time.Time, *ports.OccurrenceOverride, error) {
+	rule := eventRule(e)
+	if rule == nil {
+		return nil, time.Time{}, nil, fmt.Errorf("%w: the event is not recurring", errs.ErrConflict)
+	}
+	original, err := time.Parse(icalUTCFormat, occurrenceID)
+	if err != nil {
+		return nil, time.Time{}, nil, fmt.Errorf("%w: occurrence id must look like 20261023T150000Z", errs.ErrInvalidInput)
+	}
+	loc := eventLocation(e)
+	original = original.In(loc)
+	if !rule.includes(e.StartsAt.In(loc), original) {
+		return nil, time.Time{}, nil, errs.ErrNotFound
+	}
+
+	overrides, err := s.repo.GetOccurrenceOverrides(ctx, []int{e.ID})
+	if err != nil {
+		return nil, time.Time{}, nil, err
+	}
+	for _, o := range overrides {
+		if o.OriginalStartsAt.Equal(original) {
+			return rule, original, o, nil
+		}
+	}
+	return rule, original, nil, nil
+}
+
+// applyEventUpdate -> переносит в вечер поля, которые нужно поменять
+func applyEventUpdate(e *ports.Event, upd EventUpdate) error {
+	if upd.Title != nil {
+		e.Title = strings.TrimSpace(*upd.Title)
+	}
+	if upd.Description != nil {
+		e.Description = strings.TrimSpace(*upd.Description)
+	}
+	if upd.StartsAt != nil {
+		if !upd.StartsAt.After(time.Now()) {
+			return fmt.Errorf("%w: starts_at must be in the future", errs.ErrInvalidInput)
+		}
+		e.StartsAt = *upd.StartsAt
+	}
+	if upd.Timezone != nil {
+		e.Timezone = *upd.Timezone
+	}
+	if upd.Location != nil {
+		e.Location = strings.TrimSpace(*upd.Location)
+	}
+	if upd.Capacity != nil {
+		e.Capacity = *upd.Capacity
+	}
+	if upd.MovieID != nil {
+		if *upd.MovieID == 0 {
+			e.MovieID = nil
+		} else {
+			e.MovieID = upd.MovieID
+		}
+	}
+	return nil
+}
+
+// applyRecurrence -> проверяет правило серии, приводит его к каноническому виду
+// и считает начало последнего повторения. Часовой пояс вечера уже проверен validateEvent
+func applyRecurrence(e *ports.Event) error {
+	e.Recurrence = strings.TrimSpace(e.Recurrence)
+	e.RecurrenceEnd = nil
+	if e.Recurrence == "" {
+		return nil
+	}
+
+	loc := eventLocation(e)
+	rule, err := parseRecurrence(e.Recurrence, loc)
+	if err != nil {
+		return err
+	}
+	start := e.StartsAt.In(loc)
+	if err := rule.validate(start); err != nil {
+		return err
+	}
+	e.Recurrence = rule.String()
+	if last, ok := rule.last(start); ok {
+		e.RecurrenceEnd = &last
+	}
+	return nil
+}
+
+// truncateSeries -> серия заканчивается перед повторением original
+func truncateSeries(e *ports.Event, rule *recurrence, original time.Time) {
+	dtstart := e.StartsAt.In(eventLocation(e))
+	cut := rule.truncate(dtstart, original)
+	e.Recurrence = cut.String()
+	last, _ := cut.last(dtstart)
+	e.RecurrenceEnd = &last
+}
+
+// eventRule -> правило серии, nil -> разовый вечер. Правило проверяется при сохранении
+func eventRule(e *ports.Event) *recurrence {
+	if e.Recurrence == "" {
+		return nil
+	}
+	rule, err := parseRecurrence(e.Recurrence, eventLocation(e))
+	if err != nil {
+		log.Printf("Invalid recurrence %q of event %d: %v", e.Recurrence, e.ID, err)
+		return nil
+	}
+	return rule
+}
+
+// nextStart -> ближайшее начало вечера после now: у серии -> следующее повторение по правилу.
+// false -> вечер уже начался или повторений больше не будет
+func nextStart(e *ports.Event, now time.Time) (time.Time, bool) {
+	rule := eventRule(e)
+	if rule == nil {
+		return e.StartsAt, e.StartsAt.After(now)
+	}
+	next := rule.between(e.StartsAt.In(eventLocation(e)), now.Add(time.Nanosecond), time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC), 1)
+	if len(next) == 0 {
+		return time.Time{}, false
+	}
+	return next[0], true
+}
+
+// lastStart -> начало вечера или последнего повторения серии. false -> серия бесконечна
+func lastStart(e *ports.Event) (time.Time, bool) {
+	if e.Recurrence == "" {
+		return e.StartsAt, true
+	}
+	if e.RecurrenceEnd != nil {
+		return *e.RecurrenceEnd, true
+	}
+	return time.Time{}, false
+}
+
+// attachOverrides -> загружает изменения повторений для серий из списка
+func attachOverrides(ctx context.Context, repo ports.EventRepository, events []*ports.Event) error {
+	series := make(map[int]*ports.Event)
+	ids := make([]int, 0)
+	for _, e := range events {
+		if e.Recurrence != "" {
+			e.Overrides = nil
+			series[e.ID] = e
+			ids = append(ids, e.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	overrides, err := repo.GetOccurrenceOverrides(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, o := range overrides {
+		if e, ok := series[o.EventID]; ok {
+			e.Overrides = append(e.Overrides, o)
+		}
+	}
+	return nil
+}
+
+// expandEvent -> повторения серии с началом в [from, to) с учетом изменений (отмененные тоже,
+// со статусом cancelled). Повторение, перенесенное за пределы промежутка, в него не попадает,
+// перенесенное в промежуток -> попадает. Разовый вечер -> он сам, если начинается в [from, to)
+func expandEvent(e *ports.Event, from, to time.Time) []*ports.Event {
+	rule := eventRule(e)
+	if rule == nil {
+		if e.StartsAt.Before(from) || !e.StartsAt.Before(to) {
+			return []*ports.Event{}
+		}
+		localizeEvent(e)
+		return []*ports.Event{e}
+	}
+
+	dtstart := e.StartsAt.In(eventLocation(e))
+	inRange := func(t time.Time) bool {
+		return !t.Before(from) && t.Before(to)
+	}
+	overrides := make(map[string]*ports.OccurrenceOverride, len(e.Overrides))
+	for _, o := range e.Overrides {
+		overrides[occurrenceKey(o.OriginalStartsAt)] = o
+	}
+
+	result := make([]*ports.Event, 0)
+	for _, original := range rule.between(dtstart, from, to, maxExpandedOccurrences) {
+		o := overrides[occurrenceKey(original)]
+		if o != nil && o.StartsAt != nil && !inRange(*o.StartsAt) {
+			continue
+		}
+		result = append(result, occurrenceEvent(e, original, o))
+	}
+	for _, o := range e.Overrides {
+		if o.StartsAt == nil || !inRange(*o.StartsAt) || inRange(o.OriginalStartsAt) {
+			continue
+		}
+		original := o.OriginalStartsAt.In(dtstart.Location())
+		if rule.includes(dtstart, original) {
+			result = append(result, occurrenceEvent(e, original, o))
+		}
+	}
+
+	sortEvents(result)
+	return result
+}
+
+// occurrenceEvent -> одно повторение серии в виде вечера: поля серии с изменениями повторения
+func occurrenceEvent(e *ports.Event, original time.Time, o *ports.OccurrenceOverride) *ports.Event {
+	occ := *e
+	occ.Invitations, occ.Guests, occ.Overrides = nil, nil, nil
+	occ.OccurrenceID = occurrenceKey(original)
+	occ.StartsAt = original
+
+	if o != nil {
+		if o.Title != nil {
+			occ.Title = *o.Title
+		}
+		if o.Description != nil {
+			occ.Description = *o.Description
+		}
+		if o.Location != nil {
+			occ.Location = *o.Location
+		}
+		if o.StartsAt != nil {
+			occ.StartsAt = *o.StartsAt
+		}
+		if o.MovieSet {
+			occ.MovieID, occ.MovieTitle, occ.MovieRuntime = o.MovieID, o.MovieTitle, o.MovieRuntime
+		}
+		if o.CancelledAt != nil && occ.Status != ports.EventCancelled {
+			occ.Status = ports.EventCancelled
+			occ.CancelledAt = o.CancelledAt
+		}
+		// Версия повторения растет и вместе с серией, и при его собственных изменениях
+		occ.Sequence = e.Sequence + o.Sequence
+		if o.UpdatedAt.After(occ.UpdatedAt) {
+			occ.UpdatedAt = o.UpdatedAt
+		}
+	}
+
+	localizeEvent(&occ)
+	return &occ
+}
+
+// occurrenceKey -> ID повторения: исходное начало в UTC в формате iCalendar
+func occurrenceKey(original time.Time) string {
+	return original.UTC().Format(icalUTCFormat)
+}
+
+func sortEvents(events []*ports.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].StartsAt.Equal(events[j].StartsAt) {
+			return events[i].StartsAt.Before(events[j].StartsAt)
+		}
+		return events[i].ID < events[j].ID
+	})
+}
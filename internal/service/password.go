@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	minPasswordLength = 8
+	// bcrypt учитывает только первые 72 байта пароля
+	maxPasswordLength = 72
+)
+
+// ErrInvalidPassword -> текущий пароль не совпал
+var ErrInvalidPassword = errors.New("current password is incorrect")
+
+// PasswordService -> смена пароля и восстановление доступа через одноразовые токены
+type PasswordService struct {
+	users    ports.UserRepository
+	resets   ports.PasswordResetRepository
+	mailer   ports.Mailer
+	auth     *AuthSvc
+	baseURL  string
+	tokenTTL time.Duration
+}
+
+func NewPasswordService(users ports.UserRepository, resets ports.PasswordResetRepository, mailer ports.Mailer, auth *AuthSvc, baseURL string, tokenTTL time.Duration) *PasswordService {
+	return &PasswordService{
+		users:    users,
+		resets:   resets,
+		mailer:   mailer,
+		auth:     auth,
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		tokenTTL: tokenTTL,
+	}
+}
+
+// ChangePassword -> меняет пароль и отзывает все выданные токены.
+// Возвращает новый токен, чтобы сессия, из которой сменили пароль, не прервалась
+func (s *PasswordService) ChangePassword(ctx context.Context, userID int, currentPassword, newPassword string) (string, error) {
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword)); err != nil {
+		return "", ErrInvalidPassword
+	}
+
+	hash, err := hashPassword(newPassword)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.users.UpdatePassword(ctx, userID, hash); err != nil {
+		return "", err
+	}
+	if err := s.auth.RevokeUserTokens(ctx, userID); err != nil {
+		return "", fmt.Errorf("password changed but failed to revoke old tokens: %w", err)
+	}
+
+	return s.auth.GenerateToken(userID)
+}
+
+// RequestReset -> выдает токен и отправляет письмо. Если email не найден, молча
+// ничего не делаем: ответ не должен выдавать, зарегистрирован ли адрес
+func (s *PasswordService) RequestReset(ctx context.Context, email string) error {
+	user, err := s.users.GetUserByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			log.Printf("Password reset requested for unknown email")
+			return nil
+		}
+		return err
+	}
+
+	token, hash, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	if err := s.resets.CreatePasswordResetToken(ctx, user.ID, hash, time.Now().Add(s.tokenTTL)); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/auth/password-reset/confirm?token=%s", s.baseURL, url.QueryEscape(token))
+	body := fmt.Sprintf("Someone asked to reset the password for your Movie Planner account.\n\n"+
+		"Use this token to set a new password (valid for %s, works once):\n\n%s\n\n%s\n\n"+
+		"If it was not you, just ignore this email.", s.tokenTTL, token, link)
+
+	return s.mailer.Send(ctx, ports.MailMessage{
+		To:      user.Email,
+		Subject: "Reset your Movie Planner password",
+		Body:    body,
+	})
+}
+
+// ConfirmReset -> меняет пароль по токену из письма. Токен сгорает после первого использования,
+// а все выданные ранее JWT отзываются: сброс должен выкинуть того, кто завладел аккаунтом
+func (s *PasswordService) ConfirmReset(ctx context.Context, token, newPassword string) error {
+	if token == "" {
+		return fmt.Errorf("%w: token is required", errs.ErrInvalidInput)
+	}
+
+	hash, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	userID, err := s.resets.ResetPasswordWithToken(ctx, hashToken(token), hash)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			return fmt.Errorf("%w: reset token is invalid or expired", errs.ErrInvalidInput)
+		}
+		return err
+	}
+
+	if err := s.auth.RevokeUserTokens(ctx, userID); err != nil {
+		return fmt.Errorf("password reset but failed to revoke old tokens: %w", err)
+	}
+
+	return nil
+}
+
+func hashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return "", fmt.Errorf("%w: password must be between %d and %d characters", errs.ErrInvalidInput, minPasswordLength, maxPasswordLength)
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hashed), nil
+}
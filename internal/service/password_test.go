@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// stubUsers -> пользователи в памяти. Остальные методы репозитория в этих тестах не вызываются
+type stubUsers struct {
+	ports.UserRepository
+	users map[string]*ports.User
+}
+
+func (s *stubUsers) GetUserByEmail(_ context.Context, email string) (*ports.User, error) {
+	u, ok := s.users[email]
+	if !ok {
+		return nil, errs.ErrNotFound
+	}
+	return u, nil
+}
+
+type resetToken struct {
+	userID    int
+	expiresAt time.Time
+	used      bool
+}
+
+// stubResets -> как password_reset_tokens: по хэшу, с одноразовым использованием и сроком.
+// Часы сдвигаются через skew, чтобы проверить истечение
+type stubResets struct {
+	users  *stubUsers
+	tokens map[string]*resetToken
+	skew   time.Duration
+}
+
+func (s *stubResets) CreatePasswordResetToken(_ context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	s.tokens[tokenHash] = &resetToken{userID: userID, expiresAt: expiresAt}
+	return nil
+}
+
+func (s *stubResets) ResetPasswordWithToken(_ context.Context, tokenHash, passwordHash string) (int, error) {
+	t, ok := s.tokens[tokenHash]
+	if !ok || t.used || !t.expiresAt.After(time.Now().Add(s.skew)) {
+		return 0, errs.ErrNotFound
+	}
+	t.used = true
+	for _, u := range s.users.users {
+		if u.ID == t.userID {
+			u.PasswordHash = passwordHash
+		}
+	}
+	return t.userID, nil
+}
+
+type stubMailer struct {
+	sent []ports.MailMessage
+}
+
+func (m *stubMailer) Send(_ context.Context, msg ports.MailMessage) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+type stubRevoker struct {
+	revokedAt map[int]time.Time
+}
+
+func (r *stubRevoker) RevokeUserTokens(_ context.Context, userID int, at time.Time) error {
+	r.revokedAt[userID] = at
+	return nil
+}
+
+func (r *stubRevoker) TokensRevokedAt(_ context.Context, userID int) (time.Time, error) {
+	return r.revokedAt[userID], nil
+}
+
+// resetTokenFromMail -> токен из ссылки в последнем письме
+func resetTokenFromMail(t *testing.T, m *stubMailer) string {
+	t.Helper()
+	if len(m.sent) == 0 {
+		t.Fatal("no reset email was sent")
+	}
+	body := m.sent[len(m.sent)-1].Body
+	_, rest, ok := strings.Cut(body, "?token=")
+	if !ok {
+		t.Fatalf("reset email has no link: %q", body)
+	}
+	raw, _, _ := strings.Cut(rest, "\n")
+	token, err := url.QueryUnescape(raw)
+	if err != nil {
+		t.Fatalf("failed to unescape token: %v", err)
+	}
+	return token
+}
+
+func TestPasswordResetTokenLifecycle(t *testing.T) {
+	const (
+		email = "aigerim@example.com"
+		ttl   = time.Hour
+	)
+	ctx := context.Background()
+	users := &stubUsers{users: map[string]*ports.User{email: {ID: 3, Email: email}}}
+	resets := &stubResets{users: users, tokens: map[string]*resetToken{}}
+	mailer := &stubMailer{}
+	revoker := &stubRevoker{revokedAt: map[int]time.Time{}}
+	s := NewPasswordService(users, resets, mailer, NewAuthSvc(testSecret, time.Hour, revoker), "https://movies.example", ttl)
+
+	if err := s.RequestReset(ctx, email); err != nil {
+		t.Fatalf("RequestReset() error: %v", err)
+	}
+	token := resetTokenFromMail(t, mailer)
+
+	// В базе только хэш: по нему нельзя восстановить токен из письма
+	if _, ok := resets.tokens[token]; ok {
+		t.Fatal("reset token is stored in plain text")
+	}
+	if _, ok := resets.tokens[hashToken(token)]; !ok || len(resets.tokens) != 1 {
+		t.Fatalf("stored tokens = %v, want only the SHA-256 of the emailed token", resets.tokens)
+	}
+
+	oldSession, err := s.auth.GenerateToken(3)
+	if err != nil {
+		t.Fatalf("GenerateToken() error: %v", err)
+	}
+
+	if err := s.ConfirmReset(ctx, token, "new-secret-123"); err != nil {
+		t.Fatalf("ConfirmReset() error: %v", err)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(users.users[email].PasswordHash), []byte("new-secret-123")); err != nil {
+		t.Errorf("password was not changed: %v", err)
+	}
+	if _, err := s.auth.Authenticate(ctx, oldSession); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("session issued before the reset: error = %v, want ErrTokenRevoked", err)
+	}
+
+	// Второй раз тот же токен не работает
+	if err := s.ConfirmReset(ctx, token, "another-secret-123"); !errors.Is(err, errs.ErrInvalidInput) {
+		t.Errorf("second ConfirmReset() error = %v, want ErrInvalidInput", err)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(users.users[email].PasswordHash), []byte("new-secret-123")); err != nil {
+		t.Errorf("password changed by a used token: %v", err)
+	}
+
+	// Истекший токен не работает
+	if err := s.RequestReset(ctx, email); err != nil {
+		t.Fatalf("RequestReset() error: %v", err)
+	}
+	expired := resetTokenFromMail(t, mailer)
+	resets.skew = ttl + time.Second
+	if err := s.ConfirmReset(ctx, expired, "another-secret-123"); !errors.Is(err, errs.ErrInvalidInput) {
+		t.Errorf("ConfirmReset() with an expired token error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestPasswordResetUnknownEmail(t *testing.T) {
+	users := &stubUsers{users: map[string]*ports.User{}}
+	resets := &stubResets{users: users, tokens: map[string]*resetToken{}}
+	mailer := &stubMailer{}
+	s := NewPasswordService(users, resets, mailer, nil, "https://movies.example", time.Hour)
+
+	// Ответ тот же, что для известного адреса, но ни токена, ни письма
+	if err := s.RequestReset(context.Background(), "nobody@example.com"); err != nil {
+		t.Fatalf("RequestReset() error: %v", err)
+	}
+	if len(resets.tokens) != 0 || len(mailer.sent) != 0 {
+		t.Errorf("tokens = %v, emails = %v, want none", resets.tokens, mailer.sent)
+	}
+}
@@ -0,0 +1,384 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	defaultPlanLimit = 5
+	maxPlanLimit     = 20
+	maxParticipants  = 20
+	// groupMinWeight -> вес "самого недовольного" участника в общем скоре (стратегия least misery)
+	groupMinWeight = 0.3
+)
+
+// Откуда взялась оценка участника для фильма
+const (
+	FitSourceRated     = "rated"     // участник сам оценил фильм
+	FitSourcePredicted = "predicted" // предсказание collaborative filtering
+	FitSourceGenre     = "genre"     // средняя оценка участника по тем же жанрам
+	FitSourceEditorial = "editorial" // о вкусах участника ничего не известно
+)
+
+// TonightConstraints -> входные данные для подбора фильма на вечер
+type TonightConstraints struct {
+	ParticipantIDs []int
+	WindowStart    time.Time
+	WindowEnd      time.Time
+	IncludeGenres  []string
+	ExcludeGenres  []string
+	MinRating      float64
+	NoRewatches    bool
+	Limit          int
+}
+
+// ParticipantFit -> насколько фильм подходит одному участнику (0..1)
+type ParticipantFit struct {
+	UserID int     `json:"user_id" example:"1"`
+	Fit    float64 `json:"fit" example:"0.82"`
+	Source string  `json:"source" example:"predicted"`
+}
+
+// PlanCandidate -> один фильм из шорт-листа
+type PlanCandidate struct {
+	ports.Movie
+	Score       float64          `json:"score" example:"0.78"`
+	EndsAt      time.Time        `json:"ends_at"`
+	Fits        []ParticipantFit `json:"fits"`
+	Explanation string           `json:"explanation" example:"Runs 148 min and ends at 21:28, within your 150 min window. Average fit 82%, lowest 70% (user 3)."`
+	Reasons     []Reason         `json:"reasons"`
+}
+
+// TonightPlan -> ответ планировщика
+type TonightPlan struct {
+	WindowMinutes int              `json:"window_minutes" example:"150"`
+	Shortlist     []*PlanCandidate `json:"shortlist"`
+}
+
+// PlannerService -> подбирает фильм на вечер для группы людей и составляет расписание марафонов
+type PlannerService struct {
+	movies    *MovieService
+	ratings   ports.RatingRepository
+	history   ports.WatchHistoryRepository
+	recs      ports.RecommendationRepository
+	watchlist ports.WatchlistRepository
+	social    ports.SocialRepository
+	groups    ports.GroupRepository
+}
+
+func NewPlannerService(movies *MovieService, ratings ports.RatingRepository, history ports.WatchHistoryRepository, recs ports.RecommendationRepository, watchlist ports.WatchlistRepository, social ports.SocialRepository, groups ports.GroupRepository) *PlannerService {
+	return &PlannerService{
+		movies:    movies,
+		ratings:   ratings,
+		history:   history,
+		recs:      recs,
+		watchlist: watchlist,
+		social:    social,
+		groups:    groups,
+	}
+}
+
+// participantProfile -> все, что мы знаем о вкусах одного участника
+type participantProfile struct {
+	userID      int
+	rated       map[int]float64
+	predicted   map[int]float64
+	genreRating map[string]float64
+	watched     map[int]bool
+	preferences *ports.ViewingPreferences
+}
+
+// PlanTonight -> шорт-лист для userID и его компании. Разбивка по участникам раскрывает
+// их оценки, историю и скрытые предпочтения, поэтому звать можно только своих:
+// подписчиков userID и тех, с кем он состоит в одной группе
+func (s *PlannerService) PlanTonight(ctx context.Context, userID int, c TonightConstraints) (*TonightPlan, error) {
+	participants := uniqueInts(c.ParticipantIDs)
+	if len(participants) == 0 {
+		return nil, fmt.Errorf("%w: at least one participant is required", errs.ErrInvalidInput)
+	}
+	if len(participants) > maxParticipants {
+		return nil, fmt.Errorf("%w: at most %d participants are allowed", errs.ErrInvalidInput, maxParticipants)
+	}
+	if err := s.checkParticipants(ctx, userID, participants); err != nil {
+		return nil, err
+	}
+	if !c.WindowEnd.After(c.WindowStart) {
+		return nil, fmt.Errorf("%w: window_end must be after window_start", errs.ErrInvalidInput)
+	}
+	if c.Limit <= 0 {
+		c.Limit = defaultPlanLimit
+	}
+	if c.Limit > maxPlanLimit {
+		c.Limit = maxPlanLimit
+	}
+
+	windowMinutes := int(c.WindowEnd.Sub(c.WindowStart).Minutes())
+
+	movies, err := s.movies.GetAllMovies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]*participantProfile, 0, len(participants))
+	for _, userID := range participants {
+		p, err := s.buildProfile(ctx, userID, movies)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, p)
+	}
+
+	candidates := make([]*PlanCandidate, 0)
+	for _, m := range movies {
+		if !fitsConstraints(m, c, windowMinutes, profiles) {
+			continue
+		}
+		candidates = append(candidates, scoreCandidate(m, c, windowMinutes, profiles))
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].ID < candidates[j].ID
+	})
+	if len(candidates) > c.Limit {
+		candidates = candidates[:c.Limit]
+	}
+
+	return &TonightPlan{WindowMinutes: windowMinutes, Shortlist: candidates}, nil
+}
+
+// checkParticipants -> userID должен быть среди участников, а остальные связаны с ним
+func (s *PlannerService) checkParticipants(ctx context.Context, userID int, participants []int) error {
+	if !slices.Contains(participants, userID) {
+		return fmt.Errorf("%w: you must be one of the participants", errs.ErrForbidden)
+	}
+
+	connected := map[int]bool{userID: true}
+
+	followers, err := s.social.GetFollowers(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, f := range followers {
+		connected[f.User.ID] = true
+	}
+
+	groups, err := s.groups.GetUserGroups(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		members, err := s.groups.GetGroupMembers(ctx, g.ID)
+		if err != nil {
+			return err
+		}
+		for _, m := range members {
+			connected[m.User.ID] = true
+		}
+	}
+
+	for _, id := range participants {
+		if !connected[id] {
+			return fmt.Errorf("%w: user %d is neither your follower nor in a group with you", errs.ErrForbidden, id)
+		}
+	}
+	return nil
+}
+
+func (s *PlannerService) buildProfile(ctx context.Context, userID int, movies []*ports.Movie) (*participantProfile, error) {
+	ratings, err := s.ratings.GetRatingsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	watchedIDs, err := s.history.GetWatchedMovieIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs, err := s.movies.ViewingPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &participantProfile{
+		userID:      userID,
+		preferences: prefs,
+		rated:       make(map[int]float64, len(ratings)),
+		predicted:   make(map[int]float64),
+		genreRating: make(map[string]float64),
+		watched:     make(map[int]bool, len(watchedIDs)),
+	}
+	for _, id := range watchedIDs {
+		p.watched[id] = true
+	}
+
+	movieIDs := make([]int, 0, len(ratings))
+	for _, r := range ratings {
+		p.rated[r.MovieID] = r.Value
+		movieIDs = append(movieIDs, r.MovieID)
+	}
+
+	if len(movieIDs) > 0 {
+		neighbours, err := s.recs.GetMovieNeighbours(ctx, movieIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range PredictRatings(ratings, neighbours) {
+			p.predicted[pr.MovieID] = pr.Score
+		}
+	}
+
+	// Средняя оценка участника по каждому жанру
+	genres := make(map[int][]string, len(movies))
+	for _, m := range movies {
+		genres[m.ID] = m.Genres
+	}
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, r := range ratings {
+		for _, g := range genres[r.MovieID] {
+			key := strings.ToLower(g)
+			sums[key] += r.Value
+			counts[key]++
+		}
+	}
+	for g, sum := range sums {
+		p.genreRating[g] = sum / float64(counts[g])
+	}
+
+	return p, nil
+}
+
+func fitsConstraints(m *ports.Movie, c TonightConstraints, windowMinutes int, profiles []*participantProfile) bool {
+	// Фильм без длительности нельзя гарантированно уложить в окно
+	if m.Runtime <= 0 || m.Runtime > windowMinutes {
+		return false
+	}
+	if c.MinRating > 0 && m.Rating < c.MinRating {
+		return false
+	}
+	if len(c.IncludeGenres) > 0 && !hasAnyGenre(m.Genres, c.IncludeGenres) {
+		return false
+	}
+	if hasAnyGenre(m.Genres, c.ExcludeGenres) {
+		return false
+	}
+	for _, p := range profiles {
+		if c.NoRewatches && p.watched[m.ID] {
+			return false
+		}
+		// Фильм, который кто-то из участников не хочет видеть, не предлагаем всей группе
+		if !allowedByPreferences(m, p.preferences) {
+			return false
+		}
+	}
+	return true
+}
+
+func scoreCandidate(m *ports.Movie, c TonightConstraints, windowMinutes int, profiles []*participantProfile) *PlanCandidate {
+	fits := make([]ParticipantFit, 0, len(profiles))
+	var sum float64
+	worst := ParticipantFit{Fit: math.Inf(1)}
+
+	for _, p := range profiles {
+		fit := participantFit(m, p)
+		fits = append(fits, fit)
+		sum += fit.Fit
+		if fit.Fit < worst.Fit {
+			worst = fit
+		}
+	}
+
+	mean := sum / float64(len(fits))
+	score := (1-groupMinWeight)*mean + groupMinWeight*worst.Fit
+	endsAt := c.WindowStart.Add(time.Duration(m.Runtime) * time.Minute)
+
+	reasons := []Reason{fitsTimeWindowReason(m.Runtime, windowMinutes, endsAt)}
+	for _, g := range m.Genres {
+		if hasAnyGenre([]string{g}, c.IncludeGenres) {
+			reasons = append(reasons, matchesGenreReason(g))
+		}
+	}
+	reasons = append(reasons, groupFitReason(mean, worst))
+	if m.Rating >= 7.5 {
+		reasons = append(reasons, ratingReason(m.Rating))
+	}
+
+	// Текстовое объяснение собираем из тех же причин, чтобы они не расходились
+	messages := make([]string, 0, len(reasons))
+	for _, reason := range reasons {
+		messages = append(messages, reason.Message)
+	}
+
+	return &PlanCandidate{
+		Movie:       *m,
+		Score:       round2(score),
+		EndsAt:      endsAt,
+		Fits:        fits,
+		Explanation: strings.Join(messages, " "),
+		Reasons:     reasons,
+	}
+}
+
+// participantFit -> берем самый надежный из доступных сигналов
+func participantFit(m *ports.Movie, p *participantProfile) ParticipantFit {
+	if v, ok := p.rated[m.ID]; ok {
+		return ParticipantFit{UserID: p.userID, Fit: round2(v / 10), Source: FitSourceRated}
+	}
+	if v, ok := p.predicted[m.ID]; ok {
+		return ParticipantFit{UserID: p.userID, Fit: round2(v / 10), Source: FitSourcePredicted}
+	}
+
+	var sum float64
+	var n int
+	for _, g := range m.Genres {
+		if v, ok := p.genreRating[strings.ToLower(g)]; ok {
+			sum += v
+			n++
+		}
+	}
+	if n > 0 {
+		return ParticipantFit{UserID: p.userID, Fit: round2(sum / float64(n) / 10), Source: FitSourceGenre}
+	}
+
+	return ParticipantFit{UserID: p.userID, Fit: round2(m.Rating / 10), Source: FitSourceEditorial}
+}
+
+func hasAnyGenre(movieGenres, wanted []string) bool {
+	for _, g := range movieGenres {
+		for _, w := range wanted {
+			if strings.EqualFold(strings.TrimSpace(g), strings.TrimSpace(w)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func uniqueInts(values []int) []int {
+	seen := make(map[int]bool, len(values))
+	result := make([]int, 0, len(values))
+	for _, v := range values {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+	return result
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+// stubSocial -> подписчики по id пользователя
+type stubSocial struct {
+	ports.SocialRepository
+	followers map[int][]int
+}
+
+func (s *stubSocial) GetFollowers(_ context.Context, userID int) ([]*ports.Follow, error) {
+	result := make([]*ports.Follow, 0, len(s.followers[userID]))
+	for _, id := range s.followers[userID] {
+		result = append(result, &ports.Follow{User: ports.UserSummary{ID: id}})
+	}
+	return result, nil
+}
+
+// stubGroups -> участники по id группы
+type stubGroups struct {
+	ports.GroupRepository
+	members map[int][]int
+}
+
+func (s *stubGroups) GetUserGroups(_ context.Context, userID int) ([]*ports.Group, error) {
+	result := make([]*ports.Group, 0)
+	for groupID, members := range s.members {
+		for _, id := range members {
+			if id == userID {
+				result = append(result, &ports.Group{ID: groupID})
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *stubGroups) GetGroupMembers(_ context.Context, groupID int) ([]*ports.GroupMember, error) {
+	result := make([]*ports.GroupMember, 0, len(s.members[groupID]))
+	for _, id := range s.members[groupID] {
+		result = append(result, &ports.GroupMember{User: ports.UserSummary{ID: id}})
+	}
+	return result, nil
+}
+
+func TestPlanTonightParticipants(t *testing.T) {
+	// 1 -> вызывающий, 2 подписан на него, 3 с ним в группе,
+	// 4 -> тот, на кого подписан сам вызывающий, 5 -> незнакомец
+	s := NewPlannerService(nil, nil, nil, nil, nil,
+		&stubSocial{followers: map[int][]int{1: {2}, 4: {1}}},
+		&stubGroups{members: map[int][]int{10: {1, 3}, 11: {4, 5}}},
+	)
+	start := time.Date(2026, 10, 30, 19, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		participants []int
+		wantErr      error
+	}{
+		{name: "only the caller", participants: []int{1}},
+		{name: "follower and group member", participants: []int{1, 2, 3}},
+		{name: "stranger", participants: []int{1, 5}, wantErr: errs.ErrForbidden},
+		{name: "someone the caller follows", participants: []int{1, 4}, wantErr: errs.ErrForbidden},
+		{name: "caller is not a participant", participants: []int{2, 3}, wantErr: errs.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				if err := s.checkParticipants(context.Background(), 1, tt.participants); err != nil {
+					t.Errorf("checkParticipants(%v) error: %v", tt.participants, err)
+				}
+				return
+			}
+			// Отказ приходит до того, как планировщик читает чьи-либо данные
+			_, err := s.PlanTonight(context.Background(), 1, TonightConstraints{
+				ParticipantIDs: tt.participants,
+				WindowStart:    start,
+				WindowEnd:      start.Add(150 * time.Minute),
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PlanTonight(%v) error = %v, want %v", tt.participants, err, tt.wantErr)
+			}
+		})
+	}
+}
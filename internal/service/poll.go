@@ -0,0 +1,410 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	minPollCandidates  = 2
+	maxPollCandidates  = 20
+	maxPollTitleLength = 200
+	// closedPollBatchSize -> сколько закрывшихся опросов объявляем за один запрос к базе
+	closedPollBatchSize = 100
+)
+
+// PollInput -> данные нового опроса. Нулевой OpensAt -> открыт сразу,
+// нулевой ClosesAt -> закрывается к началу вечера (у опроса группы он обязателен)
+type PollInput struct {
+	Title    string
+	Method   ports.VotingMethod
+	MovieIDs []int
+	OpensAt  time.Time
+	ClosesAt time.Time
+}
+
+// PollResults -> подсчет голосов. Пока опрос открыт, результат промежуточный
+type PollResults struct {
+	PollID int  `json:"poll_id" example:"1"`
+	Closed bool `json:"closed" example:"true"`
+	*TallyResult
+}
+
+// PollService -> опросы по выбору фильма. Голосовать могут все, кто видит вечер
+// или состоит в группе, к которой относится опрос
+type PollService struct {
+	repo      ports.PollRepository
+	events    ports.EventRepository
+	groups    ports.GroupRepository
+	publisher ports.EventPublisher
+}
+
+func NewPollService(repo ports.PollRepository, events ports.EventRepository, groups ports.GroupRepository, publisher ports.EventPublisher) *PollService {
+	return &PollService{
+		repo:      repo,
+		events:    events,
+		groups:    groups,
+		publisher: publisher,
+	}
+}
+
+// CreateEventPoll -> создать опрос для вечера может только хост
+func (s *PollService) CreateEventPoll(ctx context.Context, userID, eventID int, in PollInput) (*ports.Poll, error) {
+	e, err := visibleEvent(ctx, s.events, userID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if e.Host.ID != userID {
+		return nil, errs.ErrForbidden
+	}
+	if e.Status == ports.EventCancelled {
+		return nil, fmt.Errorf("%w: the event is cancelled", errs.ErrConflict)
+	}
+
+	// По умолчанию опрос закрывается к началу вечера (у серии -> ближайшего повторения)
+	if in.ClosesAt.IsZero() {
+		in.ClosesAt = e.StartsAt
+		if next, ok := nextStart(e, time.Now()); ok {
+			in.ClosesAt = next
+		}
+	}
+	p, err := newPoll(userID, in)
+	if err != nil {
+		return nil, err
+	}
+	p.EventID = &eventID
+
+	if err := s.repo.CreatePoll(ctx, p); err != nil {
+		return nil, err
+	}
+	s.publish(ctx, ports.EventPollCreated, userID, p.ID)
+
+	return s.GetPoll(ctx, userID, p.ID)
+}
+
+func (s *PollService) GetEventPolls(ctx context.Context, userID, eventID int) ([]*ports.Poll, error) {
+	if _, err := visibleEvent(ctx, s.events, userID, eventID); err != nil {
+		return nil, err
+	}
+
+	polls, err := s.repo.GetEventPolls(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range polls {
+		if err := s.withMyBallot(ctx, p, userID); err != nil {
+			return nil, err
+		}
+	}
+	return polls, nil
+}
+
+// CreateGroupPoll -> опрос в группе может создать любой участник. Вечера у группы нет,
+// поэтому closes_at обязателен
+func (s *PollService) CreateGroupPoll(ctx context.Context, userID, groupID int, in PollInput) (*ports.Poll, error) {
+	if _, err := s.groups.GetGroupRole(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	if in.ClosesAt.IsZero() {
+		return nil, fmt.Errorf("%w: closes_at is required", errs.ErrInvalidInput)
+	}
+
+	p, err := newPoll(userID, in)
+	if err != nil {
+		return nil, err
+	}
+	p.GroupID = &groupID
+
+	if err := s.repo.CreatePoll(ctx, p); err != nil {
+		return nil, err
+	}
+	s.publish(ctx, ports.EventPollCreated, userID, p.ID)
+
+	return s.GetPoll(ctx, userID, p.ID)
+}
+
+func (s *PollService) GetGroupPolls(ctx context.Context, userID, groupID int) ([]*ports.Poll, error) {
+	if _, err := s.groups.GetGroupRole(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+
+	polls, err := s.repo.GetGroupPolls(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range polls {
+		if err := s.withMyBallot(ctx, p, userID); err != nil {
+			return nil, err
+		}
+	}
+	return polls, nil
+}
+
+func (s *PollService) GetPoll(ctx context.Context, userID, id int) (*ports.Poll, error) {
+	p, err := s.accessiblePoll(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.withMyBallot(ctx, p, userID); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// CastBallot -> создает или заменяет бюллетень пользователя, пока опрос открыт
+func (s *PollService) CastBallot(ctx context.Context, userID, id int, choices []int) (*ports.Poll, error) {
+	p, err := s.accessiblePoll(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateBallot(p, choices); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SaveBallot(ctx, id, userID, choices); err != nil {
+		return nil, err
+	}
+	s.publish(ctx, ports.EventBallotCast, userID, id)
+
+	return s.GetPoll(ctx, userID, id)
+}
+
+// ClosePoll -> досрочно закрыть опрос может его автор, хост вечера или owner/admin группы
+func (s *PollService) ClosePoll(ctx context.Context, userID, id int) (*PollResults, error) {
+	p, err := s.accessiblePoll(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.CreatedBy != userID {
+		if err := s.canManage(ctx, userID, p); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.ClosePoll(ctx, id); err != nil {
+		return nil, err
+	}
+	s.publish(ctx, ports.EventPollClosed, userID, id)
+
+	return s.GetResults(ctx, userID, id)
+}
+
+// AnnounceClosedPolls -> публикует poll.closed для опросов, закрывшихся сами по closes_at.
+// Возвращает число объявленных
+func (s *PollService) AnnounceClosedPolls(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		ids, err := s.repo.ClaimClosedPolls(ctx, closedPollBatchSize)
+		if err != nil {
+			return total, err
+		}
+		for _, id := range ids {
+			s.publish(ctx, ports.EventPollClosed, 0, id)
+		}
+		total += len(ids)
+		if len(ids) < closedPollBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// RunCloseJob -> фоновая задача: каждые interval объявляет закрывшиеся опросы
+func (s *PollService) RunCloseJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := s.AnnounceClosedPolls(ctx); err != nil {
+			log.Printf("Poll close job failed: %v", err)
+		} else if n > 0 {
+			log.Printf("Poll close job announced %d polls", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetResults -> подсчет по текущим бюллетеням, правила подсчета и равенства описаны у Tally
+func (s *PollService) GetResults(ctx context.Context, userID, id int) (*PollResults, error) {
+	p, err := s.accessiblePoll(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return pollResults(ctx, s.repo, p)
+}
+
+// pollResults -> общий подсчет для API и для обновлений в реальном времени
+func pollResults(ctx context.Context, repo ports.PollRepository, p *ports.Poll) (*PollResults, error) {
+	ballots, err := repo.GetBallots(ctx, p.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]int, 0, len(p.Candidates))
+	for _, c := range p.Candidates {
+		candidates = append(candidates, c.MovieID)
+	}
+	choices := make([][]int, 0, len(ballots))
+	for _, b := range ballots {
+		choices = append(choices, b.Choices)
+	}
+
+	return &PollResults{
+		PollID:      p.ID,
+		Closed:      !p.ClosesAt.After(time.Now()),
+		TallyResult: Tally(p.Method, candidates, choices),
+	}, nil
+}
+
+func (s *PollService) accessiblePoll(ctx context.Context, userID, id int) (*ports.Poll, error) {
+	return accessiblePoll(ctx, s.repo, s.events, s.groups, userID, id)
+}
+
+// accessiblePoll -> опрос виден тем же, кому виден его вечер, или участникам его группы
+func accessiblePoll(ctx context.Context, repo ports.PollRepository, events ports.EventRepository, groups ports.GroupRepository, userID, id int) (*ports.Poll, error) {
+	p, err := repo.GetPoll(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case p.EventID != nil:
+		if _, err := visibleEvent(ctx, events, userID, *p.EventID); err != nil {
+			return nil, err
+		}
+	case p.GroupID != nil:
+		if _, err := groups.GetGroupRole(ctx, *p.GroupID, userID); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errs.ErrNotFound
+	}
+	return p, nil
+}
+
+// canManage -> хост вечера или owner/admin группы опроса
+func (s *PollService) canManage(ctx context.Context, userID int, p *ports.Poll) error {
+	if p.EventID != nil {
+		e, err := s.events.GetEvent(ctx, *p.EventID)
+		if err != nil {
+			return err
+		}
+		if e.Host.ID != userID {
+			return errs.ErrForbidden
+		}
+		return nil
+	}
+
+	role, err := s.groups.GetGroupRole(ctx, *p.GroupID, userID)
+	if err != nil {
+		return err
+	}
+	if groupRoleRank[role] < groupRoleRank[ports.GroupRoleAdmin] {
+		return errs.ErrForbidden
+	}
+	return nil
+}
+
+func (s *PollService) publish(ctx context.Context, eventType string, actorID, pollID int) {
+	s.publisher.Publish(ctx, ports.DomainEvent{
+		Type:    eventType,
+		ActorID: actorID,
+		RefID:   pollID,
+	})
+}
+
+func (s *PollService) withMyBallot(ctx context.Context, p *ports.Poll, userID int) error {
+	b, err := s.repo.GetBallot(ctx, p.ID, userID)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	p.MyBallot = b.Choices
+	return nil
+}
+
+func newPoll(userID int, in PollInput) (*ports.Poll, error) {
+	title := strings.TrimSpace(in.Title)
+	if title == "" {
+		return nil, fmt.Errorf("%w: title is required", errs.ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(title) > maxPollTitleLength {
+		return nil, fmt.Errorf("%w: title must be at most %d characters", errs.ErrInvalidInput, maxPollTitleLength)
+	}
+
+	switch in.Method {
+	case ports.VotingPlurality, ports.VotingApproval, ports.VotingIRV, ports.VotingBorda:
+	default:
+		return nil, fmt.Errorf("%w: method must be plurality, approval, irv or borda", errs.ErrInvalidInput)
+	}
+
+	ids := uniqueInts(in.MovieIDs)
+	if len(ids) != len(in.MovieIDs) {
+		return nil, fmt.Errorf("%w: movie_ids must not repeat", errs.ErrInvalidInput)
+	}
+	if len(ids) < minPollCandidates || len(ids) > maxPollCandidates {
+		return nil, fmt.Errorf("%w: a poll needs between %d and %d movies", errs.ErrInvalidInput, minPollCandidates, maxPollCandidates)
+	}
+
+	now := time.Now()
+	opensAt := in.OpensAt
+	if opensAt.IsZero() {
+		opensAt = now
+	}
+	if !in.ClosesAt.After(opensAt) || !in.ClosesAt.After(now) {
+		return nil, fmt.Errorf("%w: closes_at must be in the future and after opens_at", errs.ErrInvalidInput)
+	}
+
+	p := &ports.Poll{
+		CreatedBy:  userID,
+		Title:      title,
+		Method:     in.Method,
+		OpensAt:    opensAt,
+		ClosesAt:   in.ClosesAt,
+		Candidates: make([]*ports.PollCandidate, 0, len(ids)),
+	}
+	for _, id := range ids {
+		p.Candidates = append(p.Candidates, &ports.PollCandidate{MovieID: id})
+	}
+	return p, nil
+}
+
+// validateBallot -> только кандидаты опроса, без повторов. В plurality ровно один фильм,
+// в остальных методах -> от одного до всех (частичный рейтинг разрешен)
+func validateBallot(p *ports.Poll, choices []int) error {
+	if len(choices) == 0 {
+		return fmt.Errorf("%w: choices must not be empty", errs.ErrInvalidInput)
+	}
+	if p.Method == ports.VotingPlurality && len(choices) != 1 {
+		return fmt.Errorf("%w: a plurality ballot has exactly one choice", errs.ErrInvalidInput)
+	}
+
+	allowed := make(map[int]bool, len(p.Candidates))
+	for _, c := range p.Candidates {
+		allowed[c.MovieID] = true
+	}
+	seen := make(map[int]bool, len(choices))
+	for _, id := range choices {
+		if !allowed[id] {
+			return fmt.Errorf("%w: movie %d is not a candidate in this poll", errs.ErrInvalidInput, id)
+		}
+		if seen[id] {
+			return fmt.Errorf("%w: movie %d is chosen more than once", errs.ErrInvalidInput, id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
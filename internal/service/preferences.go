@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	maxPreferenceValues = 50
+	maxHiddenMovies     = 1000
+)
+
+// PreferenceService -> предпочтения просмотра, которыми фильтруются списки, рекомендации и планировщик
+type PreferenceService struct {
+	repo   ports.PreferenceRepository
+	movies ports.MovieRepository
+}
+
+func NewPreferenceService(repo ports.PreferenceRepository, movies ports.MovieRepository) *PreferenceService {
+	return &PreferenceService{
+		repo:   repo,
+		movies: movies,
+	}
+}
+
+func (s *PreferenceService) GetPreferences(ctx context.Context, userID int) (*ports.ViewingPreferences, error) {
+	return s.repo.GetViewingPreferences(ctx, userID)
+}
+
+// UpdatePreferences -> полная замена предпочтений
+func (s *PreferenceService) UpdatePreferences(ctx context.Context, userID int, p *ports.ViewingPreferences) (*ports.ViewingPreferences, error) {
+	if err := normalizePreferences(p); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveViewingPreferences(ctx, userID, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// HideMovie -> "больше не показывать" фильм. Повторное скрытие ничего не меняет
+func (s *PreferenceService) HideMovie(ctx context.Context, userID, movieID int) (*ports.ViewingPreferences, error) {
+	if _, err := s.movies.GetMovieByID(ctx, movieID); err != nil {
+		return nil, err
+	}
+
+	p, err := s.repo.GetViewingPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	p.HiddenMovieIDs = append(p.HiddenMovieIDs, movieID)
+
+	return s.UpdatePreferences(ctx, userID, p)
+}
+
+func (s *PreferenceService) UnhideMovie(ctx context.Context, userID, movieID int) (*ports.ViewingPreferences, error) {
+	p, err := s.repo.GetViewingPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make([]int, 0, len(p.HiddenMovieIDs))
+	for _, id := range p.HiddenMovieIDs {
+		if id != movieID {
+			kept = append(kept, id)
+		}
+	}
+	if len(kept) == len(p.HiddenMovieIDs) {
+		return nil, errs.ErrNotFound
+	}
+	p.HiddenMovieIDs = kept
+
+	return s.UpdatePreferences(ctx, userID, p)
+}
+
+// normalizePreferences -> проверяет значения и приводит списки к одному виду:
+// без лишних пробелов и без дубликатов (регистр не учитывается)
+func normalizePreferences(p *ports.ViewingPreferences) error {
+	if p.MaxRuntime < 0 {
+		return fmt.Errorf("%w: max_runtime_minutes must not be negative", errs.ErrInvalidInput)
+	}
+	if p.MinRating < 0 || p.MinRating > 10 {
+		return fmt.Errorf("%w: min_rating must be between 0 and 10", errs.ErrInvalidInput)
+	}
+
+	var err error
+	if p.DislikedGenres, err = normalizeValues("disliked_genres", p.DislikedGenres); err != nil {
+		return err
+	}
+	if p.PreferredLanguages, err = normalizeValues("preferred_languages", p.PreferredLanguages); err != nil {
+		return err
+	}
+	if p.HiddenCertifications, err = normalizeValues("hidden_certifications", p.HiddenCertifications); err != nil {
+		return err
+	}
+
+	ids := uniqueInts(p.HiddenMovieIDs)
+	for _, id := range ids {
+		if id <= 0 {
+			return fmt.Errorf("%w: hidden_movie_ids must be positive", errs.ErrInvalidInput)
+		}
+	}
+	if len(ids) > maxHiddenMovies {
+		return fmt.Errorf("%w: at most %d hidden movies are allowed", errs.ErrInvalidInput, maxHiddenMovies)
+	}
+	sort.Ints(ids)
+	p.HiddenMovieIDs = ids
+
+	return nil
+}
+
+func normalizeValues(field string, values []string) ([]string, error) {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		key := strings.ToLower(v)
+		if v == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, v)
+	}
+	if len(result) > maxPreferenceValues {
+		return nil, fmt.Errorf("%w: %s must have at most %d values", errs.ErrInvalidInput, field, maxPreferenceValues)
+	}
+	return result, nil
+}
+
+// allowedByPreferences -> проходит ли фильм фильтр пользователя.
+// Если у фильма не указана длительность или язык, по этому условию его не отсекаем:
+// неизвестное значение не значит "неподходящее"
+func allowedByPreferences(m *ports.Movie, p *ports.ViewingPreferences) bool {
+	if p == nil {
+		return true
+	}
+	for _, id := range p.HiddenMovieIDs {
+		if id == m.ID {
+			return false
+		}
+	}
+	if hasAnyGenre(m.Genres, p.DislikedGenres) {
+		return false
+	}
+	if p.MaxRuntime > 0 && m.Runtime > p.MaxRuntime {
+		return false
+	}
+	if p.MinRating > 0 && m.Rating < p.MinRating {
+		return false
+	}
+	if len(p.PreferredLanguages) > 0 && m.Language != "" && !containsFold(p.PreferredLanguages, m.Language) {
+		return false
+	}
+	if m.Certification != "" && containsFold(p.HiddenCertifications, m.Certification) {
+		return false
+	}
+	return true
+}
+
+// filterByPreferences -> оставляет только разрешенные фильмы, порядок сохраняется
+func filterByPreferences(movies []*ports.Movie, p *ports.ViewingPreferences) []*ports.Movie {
+	result := make([]*ports.Movie, 0, len(movies))
+	for _, m := range movies {
+		if allowedByPreferences(m, p) {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+func containsFold(values []string, v string) bool {
+	v = strings.TrimSpace(v)
+	for _, x := range values {
+		if strings.EqualFold(x, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	minRatingValue  = 0.5
+	maxRatingValue  = 10.0
+	maxReviewLength = 5000
+
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// ReviewPage -> одна страница отзывов о фильме
+type ReviewPage struct {
+	Items    []*ports.Rating `json:"items"`
+	Page     int             `json:"page" example:"1"`
+	PageSize int             `json:"page_size" example:"20"`
+	Total    int             `json:"total" example:"42"`
+}
+
+// RatingService -> пользовательские оценки и отзывы
+type RatingService struct {
+	repo   ports.RatingRepository
+	cache  ports.MovieCache
+	events ports.EventPublisher
+}
+
+func NewRatingService(repo ports.RatingRepository, cache ports.MovieCache, events ports.EventPublisher) *RatingService {
+	return &RatingService{
+		repo:   repo,
+		cache:  cache,
+		events: events,
+	}
+}
+
+func (s *RatingService) RateMovie(ctx context.Context, userID, movieID int, value float64, review string) (*ports.Rating, error) {
+	rating, err := newRating(userID, movieID, value, review)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateRating(ctx, rating); err != nil {
+		return nil, err
+	}
+
+	// community_rating поменялся -> закэшированный фильм устарел
+	s.cache.InvalidateMovie(ctx, movieID)
+	s.events.Publish(ctx, ratingEvent(ports.EventRatingCreated, rating))
+	return rating, nil
+}
+
+func (s *RatingService) UpdateRating(ctx context.Context, userID, movieID int, value float64, review string) (*ports.Rating, error) {
+	rating, err := newRating(userID, movieID, value, review)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateRating(ctx, rating); err != nil {
+		return nil, err
+	}
+
+	s.cache.InvalidateMovie(ctx, movieID)
+	s.events.Publish(ctx, ratingEvent(ports.EventRatingUpdated, rating))
+	return rating, nil
+}
+
+func (s *RatingService) DeleteRating(ctx context.Context, userID, movieID int) error {
+	if err := s.repo.DeleteRating(ctx, userID, movieID); err != nil {
+		return err
+	}
+
+	s.cache.InvalidateMovie(ctx, movieID)
+	s.events.Publish(ctx, ports.DomainEvent{Type: ports.EventRatingDeleted, ActorID: userID, MovieID: movieID, RefID: movieID})
+	return nil
+}
+
+func (s *RatingService) GetReviews(ctx context.Context, movieID, page, pageSize int) (*ReviewPage, error) {
+	page, pageSize = normalizePage(page, pageSize)
+
+	reviews, total, err := s.repo.GetReviews(ctx, movieID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReviewPage{
+		Items:    reviews,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}, nil
+}
+
+func ratingEvent(eventType string, r *ports.Rating) ports.DomainEvent {
+	payload := map[string]any{"rating": r.Value}
+	if r.Review != "" {
+		payload["review"] = r.Review
+	}
+	return ports.DomainEvent{Type: eventType, ActorID: r.UserID, MovieID: r.MovieID, RefID: r.MovieID, Payload: payload}
+}
+
+func newRating(userID, movieID int, value float64, review string) (*ports.Rating, error) {
+	if value < minRatingValue || value > maxRatingValue {
+		return nil, fmt.Errorf("%w: rating must be between %.1f and %.0f", errs.ErrInvalidInput, minRatingValue, maxRatingValue)
+	}
+	// Шкала с шагом 0.5: 0.5, 1, 1.5 ... 10
+	if math.Mod(value*2, 1) != 0 {
+		return nil, fmt.Errorf("%w: rating must be a multiple of 0.5", errs.ErrInvalidInput)
+	}
+
+	review = strings.TrimSpace(review)
+	if utf8.RuneCountInString(review) > maxReviewLength {
+		return nil, fmt.Errorf("%w: review must be at most %d characters", errs.ErrInvalidInput, maxReviewLength)
+	}
+
+	return &ports.Rating{
+		UserID:  userID,
+		MovieID: movieID,
+		Value:   value,
+		Review:  review,
+	}, nil
+}
+
+// normalizePage -> страницы считаются с 1, размер страницы ограничен maxPageSize
+func normalizePage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return page, pageSize
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+// Коды причин, по которым фильм попал в подборку или получил совет.
+// UI опирается на код, а message -> готовый текст для показа как есть.
+const (
+	ReasonHighRating      = "HIGH_RATING"
+	ReasonAverageRating   = "AVERAGE_RATING"
+	ReasonLowRating       = "LOW_RATING"
+	ReasonPredictedRating = "PREDICTED_RATING"
+	ReasonSimilarToRated  = "SIMILAR_TO_RATED"
+	ReasonSimilarAudience = "SIMILAR_AUDIENCE"
+	ReasonPopular         = "POPULAR"
+	ReasonFitsTimeWindow  = "FITS_TIME_WINDOW"
+	ReasonMatchesGenre    = "MATCHES_GENRE"
+	ReasonGroupFit        = "GROUP_FIT"
+)
+
+// Reason -> одна причина с машиночитаемым кодом и данными, на которых она основана
+type Reason struct {
+	Code    string         `json:"code" example:"HIGH_RATING"`
+	Message string         `json:"message" example:"Highly rated: 8.8 out of 10."`
+	Data    map[string]any `json:"data,omitempty"`
+}
+
+func ratingReason(rating float64) Reason {
+	data := map[string]any{"rating": rating}
+	switch {
+	case rating >= 7.5:
+		return Reason{Code: ReasonHighRating, Message: fmt.Sprintf("Highly rated: %.1f out of 10.", rating), Data: data}
+	case rating >= 5.0:
+		return Reason{Code: ReasonAverageRating, Message: fmt.Sprintf("Average rating: %.1f out of 10.", rating), Data: data}
+	default:
+		return Reason{Code: ReasonLowRating, Message: fmt.Sprintf("Low rating: %.1f out of 10.", rating), Data: data}
+	}
+}
+
+func predictedRatingReason(score float64) Reason {
+	return Reason{
+		Code:    ReasonPredictedRating,
+		Message: fmt.Sprintf("We expect you to rate it around %.1f.", score),
+		Data:    map[string]any{"predicted_rating": round2(score)},
+	}
+}
+
+func similarToRatedReason(movie *ports.Movie, yourRating, similarity float64) Reason {
+	return Reason{
+		Code:    ReasonSimilarToRated,
+		Message: fmt.Sprintf("People who liked %q (you rated it %.1f) also liked this.", movie.Title, yourRating),
+		Data: map[string]any{
+			"movie_id":    movie.ID,
+			"your_rating": yourRating,
+			"similarity":  round2(similarity),
+		},
+	}
+}
+
+func similarAudienceReason(movie *ports.Movie, similarity float64, coRatings int) Reason {
+	return Reason{
+		Code:    ReasonSimilarAudience,
+		Message: fmt.Sprintf("People who liked %q also liked this (%d rated both).", movie.Title, coRatings),
+		Data: map[string]any{
+			"movie_id":   movie.ID,
+			"similarity": round2(similarity),
+			"co_ratings": coRatings,
+		},
+	}
+}
+
+func popularReason() Reason {
+	return Reason{Code: ReasonPopular, Message: "Popular with other users."}
+}
+
+func fitsTimeWindowReason(runtime, windowMinutes int, endsAt time.Time) Reason {
+	return Reason{
+		Code:    ReasonFitsTimeWindow,
+		Message: fmt.Sprintf("Runs %d min and ends at %s, within your %d min window.", runtime, endsAt.Format("15:04"), windowMinutes),
+		Data: map[string]any{
+			"runtime_minutes": runtime,
+			"window_minutes":  windowMinutes,
+			"ends_at":         endsAt,
+		},
+	}
+}
+
+func matchesGenreReason(genre string) Reason {
+	return Reason{
+		Code:    ReasonMatchesGenre,
+		Message: fmt.Sprintf("Matches the requested genre %q.", genre),
+		Data:    map[string]any{"genre": genre},
+	}
+}
+
+func groupFitReason(mean float64, worst ParticipantFit) Reason {
+	return Reason{
+		Code:    ReasonGroupFit,
+		Message: fmt.Sprintf("Average fit %.0f%%, lowest %.0f%% (user %d).", mean*100, worst.Fit*100, worst.UserID),
+		Data: map[string]any{
+			"average_fit": round2(mean),
+			"lowest_fit":  worst.Fit,
+			"user_id":     worst.UserID,
+		},
+	}
+}
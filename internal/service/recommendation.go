@@ -0,0 +1,419 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	// neighboursPerMovie -> сколько похожих фильмов (top-K) храним для каждого фильма
+	neighboursPerMovie = 20
+	// minCoRatings -> минимальное число пользователей, оценивших оба фильма
+	minCoRatings = 2
+	// similarityShrinkage -> штраф для пар с маленьким числом совместных оценок
+	similarityShrinkage = 10.0
+	// minRatingsForCF -> с меньшим числом оценок пользователь считается cold-start
+	minRatingsForCF = 3
+)
+
+// MaxSimilarMovies -> больше похожих фильмов, чем соседей в матрице, не бывает
+const MaxSimilarMovies = neighboursPerMovie
+
+const (
+	RecommendationSourceCF      = "collaborative"
+	RecommendationSourcePopular = "popular"
+)
+
+// RecommendedMovie -> фильм из персональной подборки пользователя
+type RecommendedMovie struct {
+	ports.Movie
+	Score   float64  `json:"score" example:"8.1"`
+	Source  string   `json:"source" example:"collaborative"`
+	Reasons []Reason `json:"reasons"`
+}
+
+// SimilarMovie -> фильм, который нравится тем же людям, что и исходный
+type SimilarMovie struct {
+	ports.Movie
+	Similarity float64  `json:"similarity" example:"0.42"`
+	Reasons    []Reason `json:"reasons"`
+}
+
+// RecommendationService -> item-item collaborative filtering по оценкам пользователей
+type RecommendationService struct {
+	ratings     ports.RatingRepository
+	repo        ports.RecommendationRepository
+	movies      ports.MovieRepository
+	preferences ports.PreferenceRepository
+}
+
+func NewRecommendationService(ratings ports.RatingRepository, repo ports.RecommendationRepository, movies ports.MovieRepository, preferences ports.PreferenceRepository) *RecommendationService {
+	return &RecommendationService{
+		ratings:     ratings,
+		repo:        repo,
+		movies:      movies,
+		preferences: preferences,
+	}
+}
+
+// RebuildSimilarities -> пересчитывает матрицу похожести по всем оценкам и сохраняет top-K соседей
+func (s *RecommendationService) RebuildSimilarities(ctx context.Context) error {
+	ratings, err := s.ratings.GetAllRatings(ctx)
+	if err != nil {
+		return err
+	}
+
+	neighbours := BuildMovieNeighbours(ratings, neighboursPerMovie)
+	return s.repo.ReplaceMovieNeighbours(ctx, neighbours)
+}
+
+// RunSimilarityJob -> фоновая задача: пересчет сразу при старте и затем каждые interval
+func (s *RecommendationService) RunSimilarityJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		if err := s.RebuildSimilarities(ctx); err != nil {
+			log.Printf("Similarity job failed: %v", err)
+		} else {
+			log.Printf("Similarity job finished in %s", time.Since(start))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type moviePair struct {
+	a, b int // всегда a < b
+}
+
+type pairStats struct {
+	dot, sqA, sqB float64
+	count         int
+}
+
+// BuildMovieNeighbours -> считает adjusted cosine similarity между фильмами.
+// Оценки центрируются по среднему пользователя, а похожесть штрафуется
+// за малое число совместных оценок. Результат детерминирован:
+// соседи отсортированы по score (по убыванию), при равенстве -> по ID.
+func BuildMovieNeighbours(ratings []*ports.Rating, k int) map[int][]ports.MovieNeighbour {
+	byUser := make(map[int][]*ports.Rating)
+	userIDs := make([]int, 0)
+	for _, r := range ratings {
+		if _, ok := byUser[r.UserID]; !ok {
+			userIDs = append(userIDs, r.UserID)
+		}
+		byUser[r.UserID] = append(byUser[r.UserID], r)
+	}
+	// Обходим пользователей в фиксированном порядке, чтобы суммы
+	// с плавающей точкой не зависели от порядка обхода map
+	sort.Ints(userIDs)
+
+	stats := make(map[moviePair]*pairStats)
+	for _, userID := range userIDs {
+		userRatings := byUser[userID]
+		mean := meanRating(userRatings)
+
+		for i := 0; i < len(userRatings); i++ {
+			for j := i + 1; j < len(userRatings); j++ {
+				ri, rj := userRatings[i], userRatings[j]
+				di, dj := ri.Value-mean, rj.Value-mean
+
+				// Чтобы ключ пары не зависел от порядка оценок
+				if ri.MovieID > rj.MovieID {
+					ri, rj = rj, ri
+					di, dj = dj, di
+				}
+
+				key := moviePair{a: ri.MovieID, b: rj.MovieID}
+				st, ok := stats[key]
+				if !ok {
+					st = &pairStats{}
+					stats[key] = st
+				}
+				st.dot += di * dj
+				st.sqA += di * di
+				st.sqB += dj * dj
+				st.count++
+			}
+		}
+	}
+
+	candidates := make(map[int][]ports.MovieNeighbour)
+	for key, st := range stats {
+		if st.count < minCoRatings || st.sqA == 0 || st.sqB == 0 {
+			continue
+		}
+
+		sim := st.dot / math.Sqrt(st.sqA*st.sqB)
+		sim *= float64(st.count) / (float64(st.count) + similarityShrinkage)
+		if sim <= 0 {
+			continue
+		}
+
+		candidates[key.a] = append(candidates[key.a], ports.MovieNeighbour{MovieID: key.a, NeighbourID: key.b, Score: sim, CoRatings: st.count})
+		candidates[key.b] = append(candidates[key.b], ports.MovieNeighbour{MovieID: key.b, NeighbourID: key.a, Score: sim, CoRatings: st.count})
+	}
+
+	result := make(map[int][]ports.MovieNeighbour, len(candidates))
+	for movieID, list := range candidates {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Score != list[j].Score {
+				return list[i].Score > list[j].Score
+			}
+			return list[i].NeighbourID < list[j].NeighbourID
+		})
+		if len(list) > k {
+			list = list[:k]
+		}
+		for i := range list {
+			list[i].Rank = i + 1
+		}
+		result[movieID] = list
+	}
+
+	return result
+}
+
+// GetRecommendationsForUser -> CF-подборка, добитая популярными фильмами,
+// если у пользователя мало оценок или соседей не хватает.
+// Фильмы, которые не проходят предпочтения пользователя, пропускаем
+func (s *RecommendationService) GetRecommendationsForUser(ctx context.Context, userID, limit int) ([]*RecommendedMovie, error) {
+	userRatings, err := s.ratings.GetRatingsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	prefs, err := s.preferences.GetViewingPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rated := make(map[int]bool, len(userRatings))
+	for _, r := range userRatings {
+		rated[r.MovieID] = true
+	}
+
+	result := make([]*RecommendedMovie, 0, limit)
+	seen := make(map[int]bool)
+
+	if len(userRatings) >= minRatingsForCF {
+		movieIDs := make([]int, 0, len(userRatings))
+		for _, r := range userRatings {
+			movieIDs = append(movieIDs, r.MovieID)
+		}
+
+		neighbours, err := s.repo.GetMovieNeighbours(ctx, movieIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		// С запасом: часть предсказаний может отсечь фильтр предпочтений
+		predictions := PredictRatings(userRatings, neighbours)
+		if len(predictions) > 2*limit {
+			predictions = predictions[:2*limit]
+		}
+
+		if len(predictions) > 0 {
+			// Заодно достаем фильмы, которыми объясняем рекомендации
+			ids := make([]int, 0, len(predictions)*2)
+			for _, p := range predictions {
+				ids = append(ids, p.MovieID)
+				if p.BecauseOf != 0 {
+					ids = append(ids, p.BecauseOf)
+				}
+			}
+			movies, err := s.movies.GetMoviesByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := make(map[int]*ports.Movie, len(movies))
+			for _, m := range movies {
+				byID[m.ID] = m
+			}
+
+			for _, p := range predictions {
+				if len(result) >= limit {
+					break
+				}
+				m, ok := byID[p.MovieID]
+				if !ok || !allowedByPreferences(m, prefs) {
+					continue
+				}
+				reasons := []Reason{predictedRatingReason(p.Score)}
+				if because, ok := byID[p.BecauseOf]; ok {
+					reasons = append(reasons, similarToRatedReason(because, p.BecauseOfRating, p.BecauseOfSimilarity))
+				}
+				result = append(result, &RecommendedMovie{Movie: *m, Score: p.Score, Source: RecommendationSourceCF, Reasons: reasons})
+				seen[m.ID] = true
+			}
+		}
+	}
+
+	if len(result) < limit {
+		// Берем с запасом, потому что часть популярных фильмов пользователь уже оценил
+		// или скрыл своими предпочтениями
+		popular, err := s.repo.GetPopularMovies(ctx, 2*limit+len(rated)+len(seen)+len(prefs.HiddenMovieIDs))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range popular {
+			if len(result) >= limit {
+				break
+			}
+			if rated[m.ID] || seen[m.ID] || !allowedByPreferences(m, prefs) {
+				continue
+			}
+			reasons := []Reason{popularReason(), ratingReason(m.Rating)}
+			result = append(result, &RecommendedMovie{Movie: *m, Score: m.Rating, Source: RecommendationSourcePopular, Reasons: reasons})
+			seen[m.ID] = true
+		}
+	}
+
+	return result, nil
+}
+
+// GetSimilarMovies -> соседи фильма из матрицы похожести, от самого похожего.
+// viewerID == 0 -> анонимный запрос, иначе скрываем фильмы, которые не проходят предпочтения зрителя
+func (s *RecommendationService) GetSimilarMovies(ctx context.Context, movieID, viewerID, limit int) ([]*SimilarMovie, error) {
+	movie, err := s.movies.GetMovieByID(ctx, movieID)
+	if err != nil {
+		return nil, err
+	}
+
+	var prefs *ports.ViewingPreferences
+	if viewerID != 0 {
+		prefs, err = s.preferences.GetViewingPreferences(ctx, viewerID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	neighbours, err := s.repo.GetMovieNeighbours(ctx, []int{movieID})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*SimilarMovie, 0, limit)
+	if len(neighbours) == 0 {
+		return result, nil
+	}
+
+	ids := make([]int, 0, len(neighbours))
+	for _, n := range neighbours {
+		ids = append(ids, n.NeighbourID)
+	}
+	movies, err := s.movies.GetMoviesByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*ports.Movie, len(movies))
+	for _, m := range movies {
+		byID[m.ID] = m
+	}
+
+	// Соседи уже отсортированы по рангу
+	for _, n := range neighbours {
+		if len(result) >= limit {
+			break
+		}
+		m, ok := byID[n.NeighbourID]
+		if !ok || !allowedByPreferences(m, prefs) {
+			continue
+		}
+		result = append(result, &SimilarMovie{
+			Movie:      *m,
+			Similarity: round2(n.Score),
+			Reasons:    []Reason{similarAudienceReason(movie, n.Score, n.CoRatings)},
+		})
+	}
+
+	return result, nil
+}
+
+// PredictedRating -> предсказанная оценка фильма, который пользователь еще не оценивал
+type PredictedRating struct {
+	MovieID int
+	Score   float64
+	// BecauseOf -> оцененный фильм, который сильнее всего "потянул" этот фильм вверх
+	BecauseOf           int
+	BecauseOfRating     float64
+	BecauseOfSimilarity float64
+}
+
+// PredictRatings -> взвешенная сумма отклонений пользователя от своего среднего
+// по соседям оцененных фильмов. Уже оцененные фильмы не возвращаются.
+func PredictRatings(userRatings []*ports.Rating, neighbours []*ports.MovieNeighbour) []PredictedRating {
+	mean := meanRating(userRatings)
+
+	deviation := make(map[int]float64, len(userRatings))
+	values := make(map[int]float64, len(userRatings))
+	for _, r := range userRatings {
+		deviation[r.MovieID] = r.Value - mean
+		values[r.MovieID] = r.Value
+	}
+
+	num := make(map[int]float64)
+	den := make(map[int]float64)
+	best := make(map[int]*ports.MovieNeighbour)
+	for _, n := range neighbours {
+		dev, ok := deviation[n.MovieID]
+		if !ok {
+			continue
+		}
+		if _, alreadyRated := deviation[n.NeighbourID]; alreadyRated {
+			continue
+		}
+		num[n.NeighbourID] += n.Score * dev
+		den[n.NeighbourID] += math.Abs(n.Score)
+
+		// Объясняем рекомендацию самым сильным положительным вкладом
+		contribution := n.Score * dev
+		if b, ok := best[n.NeighbourID]; contribution > 0 && (!ok || contribution > b.Score*deviation[b.MovieID]) {
+			best[n.NeighbourID] = n
+		}
+	}
+
+	predictions := make([]PredictedRating, 0, len(num))
+	for movieID, d := range den {
+		if d == 0 {
+			continue
+		}
+		score := math.Min(10, math.Max(0.5, mean+num[movieID]/d))
+		p := PredictedRating{MovieID: movieID, Score: score}
+		if b, ok := best[movieID]; ok {
+			p.BecauseOf = b.MovieID
+			p.BecauseOfRating = values[b.MovieID]
+			p.BecauseOfSimilarity = b.Score
+		}
+		predictions = append(predictions, p)
+	}
+
+	sort.Slice(predictions, func(i, j int) bool {
+		if predictions[i].Score != predictions[j].Score {
+			return predictions[i].Score > predictions[j].Score
+		}
+		return predictions[i].MovieID < predictions[j].MovieID
+	})
+
+	return predictions
+}
+
+func meanRating(ratings []*ports.Rating) float64 {
+	if len(ratings) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, r := range ratings {
+		sum += r.Value
+	}
+	return sum / float64(len(ratings))
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const epsilon = 1e-9
+
+// Синтетические оценки. Среднее у пользователей 1-3 равно 6, поэтому отклонения считаются в уме:
+//
+//	user 1: m1=8 (+2), m2=8 (+2), m3=2 (-4)
+//	user 2: m1=4 (-2), m2=4 (-2), m3=10 (+4)
+//	user 3: m1=9 (+3), m2=7 (+1), m3=2 (-4)
+//	user 4: m1=5 -> одна оценка, пар не дает
+//	user 5: m1=5 (-2), m4=9 (+2) -> пара (1, 4) оценена одним пользователем, меньше minCoRatings
+var ratingsFixture = []*ports.Rating{
+	{UserID: 1, MovieID: 1, Value: 8}, {UserID: 1, MovieID: 2, Value: 8}, {UserID: 1, MovieID: 3, Value: 2},
+	{UserID: 2, MovieID: 1, Value: 4}, {UserID: 2, MovieID: 2, Value: 4}, {UserID: 2, MovieID: 3, Value: 10},
+	{UserID: 3, MovieID: 1, Value: 9}, {UserID: 3, MovieID: 2, Value: 7}, {UserID: 3, MovieID: 3, Value: 2},
+	{UserID: 4, MovieID: 1, Value: 5},
+	{UserID: 5, MovieID: 1, Value: 5}, {UserID: 5, MovieID: 4, Value: 9},
+}
+
+func TestBuildMovieNeighbours(t *testing.T) {
+	got := BuildMovieNeighbours(ratingsFixture, neighboursPerMovie)
+
+	// Пара (1, 2): dot = 4+4+3 = 11, |m1|^2 = 4+4+9 = 17, |m2|^2 = 4+4+1 = 9,
+	// cos = 11/sqrt(153), совместных оценок 3 -> штраф 3/(3+10).
+	// Пары (1, 3) и (2, 3) отрицательные, (1, 4) отсекается по minCoRatings
+	cosine := 11 / math.Sqrt(153)
+	want := cosine * 3 / 13
+
+	if len(got) != 2 {
+		t.Fatalf("movies with neighbours = %d, want 2: %v", len(got), got)
+	}
+	for movieID, neighbourID := range map[int]int{1: 2, 2: 1} {
+		list := got[movieID]
+		if len(list) != 1 {
+			t.Fatalf("movie %d neighbours = %v, want one", movieID, list)
+		}
+		n := list[0]
+		if n.MovieID != movieID || n.NeighbourID != neighbourID || n.Rank != 1 || n.CoRatings != 3 {
+			t.Errorf("movie %d neighbour = %+v", movieID, n)
+		}
+		if math.Abs(n.Score-want) > epsilon {
+			t.Errorf("movie %d score = %f, want %f", movieID, n.Score, want)
+		}
+	}
+
+	// Результат не зависит от порядка оценок
+	reversed := make([]*ports.Rating, len(ratingsFixture))
+	for i, r := range ratingsFixture {
+		reversed[len(ratingsFixture)-1-i] = r
+	}
+	again := BuildMovieNeighbours(reversed, neighboursPerMovie)
+	if again[1][0].Score != got[1][0].Score {
+		t.Errorf("score depends on input order: %v vs %v", again[1][0].Score, got[1][0].Score)
+	}
+}
+
+func TestBuildMovieNeighboursShrinkage(t *testing.T) {
+	// Полная корреляция, но при разном числе совместных оценок штраф разный: n/(n+10)
+	pairs := func(users int) []*ports.Rating {
+		ratings := make([]*ports.Rating, 0, users*3)
+		for u := 1; u <= users; u++ {
+			hi, lo := 9.0, 3.0
+			if u%2 == 0 {
+				hi, lo = lo, hi
+			}
+			ratings = append(ratings, &ports.Rating{UserID: u, MovieID: 1, Value: hi}, &ports.Rating{UserID: u, MovieID: 2, Value: hi})
+			// Третий фильм нужен, чтобы отклонения от среднего не были нулевыми
+			ratings = append(ratings, &ports.Rating{UserID: u, MovieID: 3, Value: lo})
+		}
+		return ratings
+	}
+
+	for _, users := range []int{2, 10, 30} {
+		got := BuildMovieNeighbours(pairs(users), neighboursPerMovie)
+		var score float64
+		for _, n := range got[1] {
+			if n.NeighbourID == 2 {
+				score = n.Score
+			}
+		}
+		want := float64(users) / (float64(users) + similarityShrinkage)
+		if math.Abs(score-want) > epsilon {
+			t.Errorf("users = %d: score = %f, want %f", users, score, want)
+		}
+	}
+}
+
+func TestPredictRatings(t *testing.T) {
+	// Среднее пользователя 6: m1 -> +3, m3 -> -3
+	userRatings := []*ports.Rating{{UserID: 9, MovieID: 1, Value: 9}, {UserID: 9, MovieID: 3, Value: 3}}
+	neighbours := []*ports.MovieNeighbour{
+		{MovieID: 1, NeighbourID: 2, Score: 0.5},
+		{MovieID: 3, NeighbourID: 2, Score: 0.25},
+		{MovieID: 1, NeighbourID: 4, Score: 0.2},
+		{MovieID: 3, NeighbourID: 6, Score: 0.5},
+		{MovieID: 3, NeighbourID: 1, Score: 0.9}, // уже оценен -> пропускается
+		{MovieID: 7, NeighbourID: 8, Score: 0.9}, // сосед неоцененного фильма -> не влияет
+	}
+
+	// m4: 6 + 0.2*3/0.2 = 9
+	// m2: 6 + (0.5*3 + 0.25*(-3)) / (0.5+0.25) = 7, объяснение -> m1 (вклад 1.5 > -0.75)
+	// m6: 6 + 0.5*(-3)/0.5 = 3, положительного вклада нет -> без объяснения
+	want := []PredictedRating{
+		{MovieID: 4, Score: 9, BecauseOf: 1, BecauseOfRating: 9, BecauseOfSimilarity: 0.2},
+		{MovieID: 2, Score: 7, BecauseOf: 1, BecauseOfRating: 9, BecauseOfSimilarity: 0.5},
+		{MovieID: 6, Score: 3},
+	}
+
+	got := PredictRatings(userRatings, neighbours)
+	if len(got) != len(want) {
+		t.Fatalf("predictions = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.MovieID != w.MovieID || g.BecauseOf != w.BecauseOf || math.Abs(g.Score-w.Score) > epsilon ||
+			g.BecauseOfRating != w.BecauseOfRating || g.BecauseOfSimilarity != w.BecauseOfSimilarity {
+			t.Errorf("prediction %d = %+v, want %+v", i, g, w)
+		}
+	}
+}
+
+func TestPredictRatingsFromFixture(t *testing.T) {
+	neighbours := BuildMovieNeighbours(ratingsFixture, neighboursPerMovie)
+	list := make([]*ports.MovieNeighbour, 0)
+	for _, movieID := range []int{1, 2} {
+		for i := range neighbours[movieID] {
+			list = append(list, &neighbours[movieID][i])
+		}
+	}
+
+	// Пользователь 4 оценил только m1 -> отклонение 0, предсказание для m2 равно его среднему
+	got := PredictRatings([]*ports.Rating{{UserID: 4, MovieID: 1, Value: 5}}, list)
+	if len(got) != 1 || got[0].MovieID != 2 || math.Abs(got[0].Score-5) > epsilon || got[0].BecauseOf != 0 {
+		t.Errorf("predictions = %+v, want m2 with score 5 and no explanation", got)
+	}
+}
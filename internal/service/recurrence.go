@@ -0,0 +1,418 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+)
+
+const (
+	maxRecurrenceInterval = 99
+	maxRecurrenceCount    = 500
+	// maxRecurrenceSpan -> UNTIL не дальше 10 лет от начала серии
+	maxRecurrenceSpan = 10
+	// maxRecurrenceSteps -> защита от бесконечного обхода правила (ежедневно это ~270 лет)
+	maxRecurrenceSteps = 100000
+	// maxExpandedOccurrences -> не больше стольких повторений одной серии за запрос
+	maxExpandedOccurrences = 1000
+	// recurrenceHorizon -> без конца промежутка повторения показываются на год вперед
+	recurrenceHorizon = 1
+)
+
+const (
+	freqDaily   = "DAILY"
+	freqWeekly  = "WEEKLY"
+	freqMonthly = "MONTHLY"
+)
+
+var icalWeekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// weekdayNum -> элемент BYDAY: день недели и номер в месяце (1FR -> первая пятница,
+// -1SU -> последнее воскресенье, 0 -> каждый такой день)
+type weekdayNum struct {
+	n   int
+	day time.Weekday
+}
+
+// recurrence -> поддерживаемая часть RRULE из RFC 5545: FREQ (DAILY, WEEKLY, MONTHLY),
+// INTERVAL, BYDAY, COUNT и UNTIL. Неделя начинается с понедельника (WKST=MO по умолчанию)
+type recurrence struct {
+	freq     string
+	interval int
+	byDay    []weekdayNum
+	count    int
+	until    time.Time // в UTC, нулевое -> без ограничения
+}
+
+// parseRecurrence -> разбирает RRULE (с префиксом "RRULE:" или без). UNTIL без времени или без Z
+// считается в часовом поясе вечера loc: дата -> до конца этого дня
+func parseRecurrence(s string, loc *time.Location) (*recurrence, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "RRULE:")
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: recurrence: "+format, append([]any{errs.ErrInvalidInput}, args...)...)
+	}
+
+	r := &recurrence{interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, invalid("%q is not a NAME=VALUE pair", part)
+		}
+		if seen[key] {
+			return nil, invalid("%s is given twice", key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			switch value {
+			case freqDaily, freqWeekly, freqMonthly:
+				r.freq = value
+			default:
+				return nil, invalid("FREQ must be DAILY, WEEKLY or MONTHLY")
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > maxRecurrenceInterval {
+				return nil, invalid("INTERVAL must be between 1 and %d", maxRecurrenceInterval)
+			}
+			r.interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > maxRecurrenceCount {
+				return nil, invalid("COUNT must be between 1 and %d", maxRecurrenceCount)
+			}
+			r.count = n
+		case "UNTIL":
+			until, err := parseUntil(value, loc)
+			if err != nil {
+				return nil, invalid("UNTIL must look like 20261231T235959Z or 20261231")
+			}
+			r.until = until
+		case "BYDAY":
+			for _, item := range strings.Split(value, ",") {
+				wd, err := parseWeekdayNum(item)
+				if err != nil {
+					return nil, invalid("unknown BYDAY value %q", item)
+				}
+				if !slices.Contains(r.byDay, wd) {
+					r.byDay = append(r.byDay, wd)
+				}
+			}
+		default:
+			return nil, invalid("%s is not supported", key)
+		}
+	}
+
+	if r.freq == "" {
+		return nil, invalid("FREQ is required")
+	}
+	if r.count > 0 && !r.until.IsZero() {
+		return nil, invalid("COUNT and UNTIL cannot be used together")
+	}
+	for _, wd := range r.byDay {
+		// RFC 5545: номер в BYDAY допустим только для MONTHLY и YEARLY
+		if wd.n != 0 && r.freq != freqMonthly {
+			return nil, invalid("numbered BYDAY like 2FR is only allowed with FREQ=MONTHLY")
+		}
+	}
+	sort.Slice(r.byDay, func(i, j int) bool {
+		if r.byDay[i].n != r.byDay[j].n {
+			return r.byDay[i].n < r.byDay[j].n
+		}
+		return mondayIndex(r.byDay[i].day) < mondayIndex(r.byDay[j].day)
+	})
+	return r, nil
+}
+
+func parseUntil(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(icalUTCFormat, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(icalLocalFormat, value, loc); err == nil {
+		return t.UTC(), nil
+	}
+	d, err := time.ParseInLocation("20060102", value, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(d.Year(), d.Month(), d.Day(), 23, 59, 59, 0, loc).UTC(), nil
+}
+
+func parseWeekdayNum(s string) (weekdayNum, error) {
+	if len(s) < 2 {
+		return weekdayNum{}, errors.New("too short")
+	}
+	day, ok := icalWeekdays[s[len(s)-2:]]
+	if !ok {
+		return weekdayNum{}, errors.New("unknown weekday")
+	}
+	wd := weekdayNum{day: day}
+	if prefix := s[:len(s)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return weekdayNum{}, errors.New("bad ordinal")
+		}
+		wd.n = n
+	}
+	return wd, nil
+}
+
+// String -> правило в каноническом виде, так оно хранится и уходит в iCalendar
+func (r *recurrence) String() string {
+	parts := []string{"FREQ=" + r.freq}
+	if r.interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.interval))
+	}
+	if len(r.byDay) > 0 {
+		days := make([]string, len(r.byDay))
+		for i, wd := range r.byDay {
+			days[i] = wd.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.count))
+	}
+	if !r.until.IsZero() {
+		parts = append(parts, "UNTIL="+r.until.UTC().Format(icalUTCFormat))
+	}
+	return strings.Join(parts, ";")
+}
+
+func (wd weekdayNum) String() string {
+	name := strings.ToUpper(wd.day.String()[:2])
+	if wd.n == 0 {
+		return name
+	}
+	return strconv.Itoa(wd.n) + name
+}
+
+// validate -> RFC 5545 оставляет неопределенным набор повторений, если DTSTART не совпадает
+// с правилом, поэтому такое не принимаем
+func (r *recurrence) validate(dtstart time.Time) error {
+	if !r.until.IsZero() {
+		if r.until.Before(dtstart) {
+			return fmt.Errorf("%w: recurrence: UNTIL must not be before starts_at", errs.ErrInvalidInput)
+		}
+		if r.until.After(dtstart.AddDate(maxRecurrenceSpan, 0, 0)) {
+			return fmt.Errorf("%w: recurrence: UNTIL must be within %d years of starts_at", errs.ErrInvalidInput, maxRecurrenceSpan)
+		}
+	}
+	if !r.matches(dtstart) {
+		return fmt.Errorf("%w: starts_at must be one of the occurrences of the recurrence rule", errs.ErrInvalidInput)
+	}
+	return nil
+}
+
+// matches -> подходит ли день dtstart под BYDAY (без учета INTERVAL)
+func (r *recurrence) matches(t time.Time) bool {
+	if len(r.byDay) == 0 {
+		return true
+	}
+	if r.freq == freqMonthly {
+		y, m, d := t.Date()
+		return slices.Contains(r.monthDays(y, m), d)
+	}
+	return r.hasWeekday(t.Weekday())
+}
+
+func (r *recurrence) hasWeekday(day time.Weekday) bool {
+	for _, wd := range r.byDay {
+		if wd.day == day {
+			return true
+		}
+	}
+	return false
+}
+
+// monthDays -> дни месяца по BYDAY, по возрастанию
+func (r *recurrence) monthDays(year int, month time.Month) []int {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Weekday()
+
+	var days []int
+	for _, wd := range r.byDay {
+		// Первый такой день недели в месяце
+		d := 1 + (int(wd.day)-int(first)+7)%7
+		switch {
+		case wd.n == 0:
+			for ; d <= last; d += 7 {
+				days = append(days, d)
+			}
+		case wd.n > 0:
+			if d += (wd.n - 1) * 7; d <= last {
+				days = append(days, d)
+			}
+		default:
+			for d+7 <= last {
+				d += 7
+			}
+			if d += (wd.n + 1) * 7; d >= 1 {
+				days = append(days, d)
+			}
+		}
+	}
+	slices.Sort(days)
+	return slices.Compact(days)
+}
+
+// walk -> повторения серии по порядку, начиная с dtstart, с учетом COUNT и UNTIL.
+// Время повторения -> то же местное время, что у dtstart, в его часовом поясе
+// (при переходе на летнее время смещение меняется, а час остается)
+func (r *recurrence) walk(dtstart time.Time, yield func(time.Time) bool) {
+	loc := dtstart.Location()
+	hour, minute, sec := dtstart.Clock()
+	y, m, d := dtstart.Date()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hour, minute, sec, 0, loc)
+	}
+
+	n, steps := 0, 0
+	emit := func(t time.Time) bool {
+		if t.Before(dtstart) {
+			return true
+		}
+		if r.count > 0 && n >= r.count || !r.until.IsZero() && t.After(r.until) {
+			return false
+		}
+		n++
+		return yield(t)
+	}
+
+	// Период -> день, неделя (с понедельника) или месяц, шаг -> INTERVAL периодов
+	weekStart := d - mondayIndex(dtstart.Weekday())
+	for period := 0; steps < maxRecurrenceSteps; period += r.interval {
+		switch r.freq {
+		case freqDaily:
+			t := at(y, m, d+period)
+			steps++
+			if (len(r.byDay) == 0 || r.hasWeekday(t.Weekday())) && !emit(t) {
+				return
+			}
+		case freqWeekly:
+			days := []int{mondayIndex(dtstart.Weekday())}
+			if len(r.byDay) > 0 {
+				days = days[:0]
+				for _, wd := range r.byDay {
+					days = append(days, mondayIndex(wd.day))
+				}
+				slices.Sort(days)
+			}
+			for _, offset := range days {
+				steps++
+				if !emit(at(y, m, weekStart+period*7+offset)) {
+					return
+				}
+			}
+		case freqMonthly:
+			month := time.Date(y, m+time.Month(period), 1, 0, 0, 0, 0, time.UTC)
+			days := []int{d}
+			if len(r.byDay) > 0 {
+				days = r.monthDays(month.Year(), month.Month())
+			}
+			steps++
+			for _, day := range days {
+				// Без BYDAY месяцы, где нет такого числа (31-е), пропускаются
+				if time.Date(month.Year(), month.Month(), day, 0, 0, 0, 0, time.UTC).Month() != month.Month() {
+					continue
+				}
+				if !emit(at(month.Year(), month.Month(), day)) {
+					return
+				}
+			}
+		default:
+			return
+		}
+	}
+}
+
+// between -> повторения с началом в [from, to), не больше limit
+func (r *recurrence) between(dtstart, from, to time.Time, limit int) []time.Time {
+	var result []time.Time
+	r.walk(dtstart, func(t time.Time) bool {
+		if !t.Before(to) {
+			return false
+		}
+		if !t.Before(from) {
+			result = append(result, t)
+		}
+		return len(result) < limit
+	})
+	return result
+}
+
+// countBefore -> сколько повторений начинается раньше t
+func (r *recurrence) countBefore(dtstart, t time.Time) int {
+	n := 0
+	r.walk(dtstart, func(occ time.Time) bool {
+		if !occ.Before(t) {
+			return false
+		}
+		n++
+		return true
+	})
+	return n
+}
+
+// includes -> является ли t повторением серии
+func (r *recurrence) includes(dtstart, t time.Time) bool {
+	found := r.between(dtstart, t, t.Add(time.Second), 1)
+	return len(found) == 1 && found[0].Equal(t)
+}
+
+// last -> начало последнего повторения. false -> серия бесконечна
+func (r *recurrence) last(dtstart time.Time) (time.Time, bool) {
+	if r.count == 0 && r.until.IsZero() {
+		return time.Time{}, false
+	}
+	last := dtstart
+	r.walk(dtstart, func(t time.Time) bool {
+		last = t
+		return true
+	})
+	return last, true
+}
+
+// truncate -> та же серия, но только с повторениями раньше t ("изменить это и следующие")
+func (r *recurrence) truncate(dtstart, t time.Time) *recurrence {
+	cut := *r
+	if r.count > 0 {
+		cut.count = r.countBefore(dtstart, t)
+	} else {
+		cut.until = t.Add(-time.Second).UTC()
+	}
+	return &cut
+}
+
+// shiftDays -> BYDAY, сдвинутые на столько же дней недели, на сколько сдвинулось начало серии
+// (была пятница, стала суббота -> BYDAY=FR становится BYDAY=SA)
+func (r *recurrence) shiftDays(from, to time.Time) *recurrence {
+	delta := (int(to.Weekday()) - int(from.Weekday()) + 7) % 7
+	shifted := *r
+	shifted.byDay = make([]weekdayNum, len(r.byDay))
+	for i, wd := range r.byDay {
+		shifted.byDay[i] = weekdayNum{n: wd.n, day: time.Weekday((int(wd.day) + delta) % 7)}
+	}
+	return &shifted
+}
+
+func mondayIndex(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
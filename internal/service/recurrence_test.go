@@ -0,0 +1,268 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load location %s: %v", name, err)
+	}
+	return loc
+}
+
+func mustParseRecurrence(t *testing.T, rule string, loc *time.Location) *recurrence {
+	t.Helper()
+	r, err := parseRecurrence(rule, loc)
+	if err != nil {
+		t.Fatalf("parse %q: %v", rule, err)
+	}
+	return r
+}
+
+// localTimes -> "2026-10-23 20:00" в часовом поясе loc
+func localTimes(t *testing.T, loc *time.Location, values ...string) []time.Time {
+	t.Helper()
+	result := make([]time.Time, len(values))
+	for i, v := range values {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", v, loc)
+		if err != nil {
+			t.Fatalf("parse %q: %v", v, err)
+		}
+		result[i] = parsed
+	}
+	return result
+}
+
+func equalTimes(a, b []time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRecurrenceWalk(t *testing.T) {
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart string
+		limit   int // 0 -> до конца серии
+		want    []string
+	}{
+		{
+			name:    "weekly keeps local time across DST end",
+			rule:    "FREQ=WEEKLY;BYDAY=FR;COUNT=3",
+			dtstart: "2026-10-23 20:00",
+			want:    []string{"2026-10-23 20:00", "2026-10-30 20:00", "2026-11-06 20:00"},
+		},
+		{
+			name:    "weekly every other week on two days",
+			rule:    "FREQ=WEEKLY;INTERVAL=2;BYDAY=TH,TU",
+			dtstart: "2026-10-06 19:30",
+			limit:   4,
+			want:    []string{"2026-10-06 19:30", "2026-10-08 19:30", "2026-10-20 19:30", "2026-10-22 19:30"},
+		},
+		{
+			name:    "weekly skips days of the first week before dtstart",
+			rule:    "FREQ=WEEKLY;BYDAY=MO,FR",
+			dtstart: "2026-10-09 20:00",
+			limit:   3,
+			want:    []string{"2026-10-09 20:00", "2026-10-12 20:00", "2026-10-16 20:00"},
+		},
+		{
+			name:    "weekly without BYDAY repeats the dtstart weekday",
+			rule:    "FREQ=WEEKLY;COUNT=2",
+			dtstart: "2026-10-23 20:00",
+			want:    []string{"2026-10-23 20:00", "2026-10-30 20:00"},
+		},
+		{
+			name:    "monthly on the 31st skips short months",
+			rule:    "FREQ=MONTHLY;COUNT=3",
+			dtstart: "2026-01-31 18:00",
+			want:    []string{"2026-01-31 18:00", "2026-03-31 18:00", "2026-05-31 18:00"},
+		},
+		{
+			name:    "monthly last sunday",
+			rule:    "FREQ=MONTHLY;BYDAY=-1SU;COUNT=3",
+			dtstart: "2026-10-25 17:00",
+			want:    []string{"2026-10-25 17:00", "2026-11-29 17:00", "2026-12-27 17:00"},
+		},
+		{
+			name:    "monthly first friday crosses the year",
+			rule:    "FREQ=MONTHLY;BYDAY=1FR;COUNT=3",
+			dtstart: "2026-11-06 20:00",
+			want:    []string{"2026-11-06 20:00", "2026-12-04 20:00", "2027-01-01 20:00"},
+		},
+		{
+			name:    "daily with interval and date UNTIL includes the last day",
+			rule:    "FREQ=DAILY;INTERVAL=3;UNTIL=20261010",
+			dtstart: "2026-10-01 21:00",
+			want:    []string{"2026-10-01 21:00", "2026-10-04 21:00", "2026-10-07 21:00", "2026-10-10 21:00"},
+		},
+		{
+			name:    "daily on weekends",
+			rule:    "FREQ=DAILY;BYDAY=SA,SU;COUNT=3",
+			dtstart: "2026-10-03 12:00",
+			want:    []string{"2026-10-03 12:00", "2026-10-04 12:00", "2026-10-10 12:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := mustParseRecurrence(t, tt.rule, berlin)
+			dtstart := localTimes(t, berlin, tt.dtstart)[0]
+
+			var got []time.Time
+			r.walk(dtstart, func(occ time.Time) bool {
+				got = append(got, occ)
+				return tt.limit == 0 || len(got) < tt.limit
+			})
+
+			want := localTimes(t, berlin, tt.want...)
+			if !equalTimes(got, want) {
+				t.Errorf("walk = %v, want %v", got, want)
+			}
+			for _, occ := range got {
+				if occ.Location() != berlin {
+					t.Errorf("occurrence %v is not in the series time zone", occ)
+				}
+			}
+		})
+	}
+}
+
+func TestRecurrenceBetween(t *testing.T) {
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	r := mustParseRecurrence(t, "FREQ=WEEKLY;BYDAY=FR", berlin)
+	dtstart := localTimes(t, berlin, "2026-10-23 20:00")[0]
+
+	tests := []struct {
+		name     string
+		from, to string
+		limit    int
+		want     []string
+	}{
+		{
+			name:  "whole month",
+			from:  "2026-11-01 00:00",
+			to:    "2026-12-01 00:00",
+			limit: 10,
+			want:  []string{"2026-11-06 20:00", "2026-11-13 20:00", "2026-11-20 20:00", "2026-11-27 20:00"},
+		},
+		{
+			name:  "limit",
+			from:  "2026-11-01 00:00",
+			to:    "2026-12-01 00:00",
+			limit: 2,
+			want:  []string{"2026-11-06 20:00", "2026-11-13 20:00"},
+		},
+		{
+			name:  "from is inclusive, to is exclusive",
+			from:  "2026-11-06 20:00",
+			to:    "2026-11-20 20:00",
+			limit: 10,
+			want:  []string{"2026-11-06 20:00", "2026-11-13 20:00"},
+		},
+		{
+			name:  "range before the series",
+			from:  "2026-09-01 00:00",
+			to:    "2026-10-23 20:00",
+			limit: 10,
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := localTimes(t, berlin, tt.from)[0]
+			to := localTimes(t, berlin, tt.to)[0]
+			got := r.between(dtstart, from, to, tt.limit)
+			want := localTimes(t, berlin, tt.want...)
+			if !equalTimes(got, want) {
+				t.Errorf("between = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestRecurrenceTruncate(t *testing.T) {
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	dtstart := localTimes(t, berlin, "2026-10-23 20:00")[0]
+	cut := localTimes(t, berlin, "2026-11-06 20:00")[0]
+
+	tests := []struct {
+		name string
+		rule string
+		want string
+	}{
+		{
+			name: "count is reduced to the occurrences before the cut",
+			rule: "FREQ=WEEKLY;BYDAY=FR;COUNT=5",
+			want: "FREQ=WEEKLY;BYDAY=FR;COUNT=2",
+		},
+		{
+			name: "endless series gets UNTIL a second before the cut",
+			rule: "FREQ=WEEKLY;BYDAY=FR",
+			want: "FREQ=WEEKLY;BYDAY=FR;UNTIL=20261106T185959Z",
+		},
+		{
+			name: "UNTIL is moved back",
+			rule: "FREQ=WEEKLY;BYDAY=FR;UNTIL=20261231",
+			want: "FREQ=WEEKLY;BYDAY=FR;UNTIL=20261106T185959Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := mustParseRecurrence(t, tt.rule, berlin)
+			truncated := r.truncate(dtstart, cut)
+			if got := truncated.String(); got != tt.want {
+				t.Errorf("truncate = %s, want %s", got, tt.want)
+			}
+			if got := r.String(); got == truncated.String() {
+				t.Errorf("truncate changed the original rule: %s", got)
+			}
+
+			last, ok := truncated.last(dtstart)
+			want := localTimes(t, berlin, "2026-10-30 20:00")[0]
+			if !ok || !last.Equal(want) {
+				t.Errorf("last = %v, %v, want %v", last, ok, want)
+			}
+		})
+	}
+}
+
+func TestRecurrenceShiftDays(t *testing.T) {
+	friday := time.Date(2026, 10, 23, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		rule string
+		to   time.Time
+		want string
+	}{
+		{"next day", "FREQ=WEEKLY;BYDAY=FR", friday.AddDate(0, 0, 1), "FREQ=WEEKLY;BYDAY=SA"},
+		{"across the week boundary", "FREQ=WEEKLY;BYDAY=MO,FR", friday.AddDate(0, 0, 2), "FREQ=WEEKLY;BYDAY=WE,SU"},
+		{"back a day keeps the ordinal", "FREQ=MONTHLY;BYDAY=1FR", friday.AddDate(0, 0, -1), "FREQ=MONTHLY;BYDAY=1TH"},
+		{"same weekday", "FREQ=WEEKLY;BYDAY=FR", friday.AddDate(0, 0, 7), "FREQ=WEEKLY;BYDAY=FR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := mustParseRecurrence(t, tt.rule, time.UTC)
+			if got := r.shiftDays(friday, tt.to).String(); got != tt.want {
+				t.Errorf("shiftDays = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
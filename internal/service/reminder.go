@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	eventReminderLead = 2 * time.Hour
+	// reminderOccurrences -> у серии планируем два ближайших повторения: пока ждем начала первого,
+	// напоминание о втором уже стоит в очереди. После отправки очередь пополняется
+	reminderOccurrences = 2
+
+	releaseReminderDays = 2 // на сколько дней вперед планируем напоминания о выходе фильмов
+	releaseReminderHour = 9 // во сколько по времени пользователя напоминаем
+	releaseScanInterval = time.Hour
+
+	// reminderBatchSize и reminderLease -> пачка должна успеть уйти до конца аренды
+	// даже при медленной почте, иначе ее заберет другой экземпляр
+	reminderBatchSize   = 20
+	reminderLease       = 10 * time.Minute
+	maxReminderAttempts = 5
+	reminderRetryAfter  = 5 * time.Minute
+)
+
+// ReminderService -> отложенные напоминания: о начале вечера и о выходе фильмов из списка "хочу посмотреть".
+// Напоминания хранятся в базе, поэтому переживают перезапуск, а рассылает их фоновая задача
+type ReminderService struct {
+	repo     ports.ReminderRepository
+	events   ports.EventRepository
+	notifier ports.Notifier
+}
+
+func NewReminderService(repo ports.ReminderRepository, events ports.EventRepository, notifier ports.Notifier) *ReminderService {
+	return &ReminderService{
+		repo:     repo,
+		events:   events,
+		notifier: notifier,
+	}
+}
+
+// Register -> при любом изменении вечера или ответов напоминания вечера пересобираются заново
+func (s *ReminderService) Register(bus *EventBus) {
+	bus.Subscribe("reminders", s.handle,
+		ports.EventMovieNightCreated,
+		ports.EventMovieNightUpdated,
+		ports.EventMovieNightCancelled,
+		ports.EventRSVPChanged,
+	)
+}
+
+func (s *ReminderService) handle(ctx context.Context, e ports.DomainEvent) error {
+	if err := s.SyncEventReminders(ctx, e.RefID); err != nil {
+		return err
+	}
+	// "Изменить это и следующие" создает новую серию
+	if next, ok := e.Payload["new_event_id"].(int); ok {
+		return s.SyncEventReminders(ctx, next)
+	}
+	return nil
+}
+
+// SyncEventReminders -> отменяет ожидающие напоминания вечера и планирует их заново
+// для хоста и тех, кто идет. Отмененный вечер остается без напоминаний.
+// Гостям без аккаунта напоминания не отправляются
+func (s *ReminderService) SyncEventReminders(ctx context.Context, eventID int) error {
+	e, err := s.events.GetEvent(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	reminders := make([]*ports.Reminder, 0)
+	if e.Status != ports.EventCancelled {
+		if err := attachOverrides(ctx, s.events, []*ports.Event{e}); err != nil {
+			return err
+		}
+		invitations, err := s.events.GetEventInvitations(ctx, eventID)
+		if err != nil {
+			return err
+		}
+
+		recipients := []int{e.Host.ID}
+		for _, inv := range invitations {
+			if inv.RSVP == ports.RSVPGoing {
+				recipients = append(recipients, inv.User.ID)
+			}
+		}
+
+		for _, occ := range upcomingOccurrences(e, time.Now(), reminderOccurrences) {
+			for _, userID := range recipients {
+				reminders = append(reminders, &ports.Reminder{
+					UserID:    userID,
+					Kind:      ports.ReminderEventStarting,
+					Title:     occ.Title,
+					TargetAt:  occ.StartsAt,
+					DueAt:     occ.StartsAt.Add(-eventReminderLead),
+					ExpiresAt: occ.StartsAt,
+				})
+			}
+		}
+	}
+
+	return s.repo.ReplaceEventReminders(ctx, eventID, reminders)
+}
+
+// upcomingOccurrences -> до n ближайших неотмененных повторений, которые начнутся после now
+func upcomingOccurrences(e *ports.Event, now time.Time, n int) []*ports.Event {
+	result := make([]*ports.Event, 0, n)
+	for _, occ := range expandEvent(e, now, now.AddDate(recurrenceHorizon, 0, 0)) {
+		if occ.Status == ports.EventCancelled {
+			continue
+		}
+		result = append(result, occ)
+		if len(result) == n {
+			break
+		}
+	}
+	return result
+}
+
+// SendDueReminders -> забирает наступившие напоминания пачками и отправляет их.
+// Каждое отмечается отдельно сразу после отправки, поэтому сбой посреди пачки
+// повторит только те, что еще не отмечены. Возвращает число отправленных
+func (s *ReminderService) SendDueReminders(ctx context.Context) (int, error) {
+	sent := 0
+	delivered := make(map[int]bool)
+	for ctx.Err() == nil {
+		batch, err := s.repo.ClaimDueReminders(ctx, time.Now(), reminderBatchSize, reminderLease)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, r := range batch {
+			// Пока пачка отправлялась, вечер могли перенести или отменить
+			claimed, err := s.repo.IsReminderClaimed(ctx, r.ID, r.Attempts)
+			if err != nil {
+				return sent, err
+			}
+			if !claimed {
+				continue
+			}
+
+			if err := s.deliver(ctx, r, time.Now()); err != nil {
+				log.Printf("Failed to deliver reminder %d (attempt %d): %v", r.ID, r.Attempts, err)
+				if err := s.repo.RetryReminder(ctx, r.ID, r.Attempts, err.Error(), time.Now().Add(reminderRetryAfter), maxReminderAttempts); err != nil {
+					return sent, err
+				}
+				continue
+			}
+			if err := s.repo.MarkReminderSent(ctx, r.ID, r.Attempts); err != nil {
+				return sent, err
+			}
+			sent++
+			if r.EventID != nil {
+				delivered[*r.EventID] = true
+			}
+		}
+
+		if len(batch) < reminderBatchSize {
+			break
+		}
+	}
+
+	// Пополняем очередь серий следующим повторением
+	for eventID := range delivered {
+		if err := s.SyncEventReminders(ctx, eventID); err != nil {
+			log.Printf("Failed to reschedule reminders of event %d: %v", eventID, err)
+		}
+	}
+
+	return sent, nil
+}
+
+// deliver -> в отличие от notify возвращает ошибку, чтобы напоминание отправилось повторно
+func (s *ReminderService) deliver(ctx context.Context, r *ports.Reminder, now time.Time) error {
+	var (
+		notificationType, message string
+		payload                   map[string]any
+	)
+	switch r.Kind {
+	case ports.ReminderEventStarting:
+		notificationType = ports.NotificationEventReminder
+		message = fmt.Sprintf("Movie night %q starts in %s.", r.Title, untilText(r.TargetAt.Sub(now)))
+		payload = map[string]any{"event_id": r.EventID, "starts_at": r.TargetAt}
+	case ports.ReminderWatchlistRelease:
+		notificationType = ports.NotificationWatchlistRelease
+		message = fmt.Sprintf("%q from your watchlist is released today.", r.Title)
+		payload = map[string]any{"movie_id": r.MovieID}
+	default:
+		return fmt.Errorf("unknown reminder kind %q", r.Kind)
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return s.notifier.Notify(ctx, &ports.Notification{UserID: r.UserID, Type: notificationType, Message: message, Payload: raw})
+}
+
+// untilText -> "2 hours", "45 minutes" для текста напоминания
+func untilText(d time.Duration) string {
+	d = d.Round(time.Minute)
+	switch {
+	case d >= 90*time.Minute:
+		return fmt.Sprintf("%d hours", int(d.Round(time.Hour)/time.Hour))
+	case d >= time.Hour:
+		return "1 hour"
+	case d > time.Minute:
+		return fmt.Sprintf("%d minutes", int(d/time.Minute))
+	default:
+		return "a minute"
+	}
+}
+
+// RunReminderJob -> фоновая задача: каждые interval отправляет наступившие напоминания,
+// раз в час планирует напоминания о выходе фильмов. Экземпляров может быть несколько
+func (s *ReminderService) RunReminderJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastScan time.Time
+	for {
+		if time.Since(lastScan) >= releaseScanInterval {
+			if err := s.repo.ScheduleReleaseReminders(ctx, releaseReminderDays, releaseReminderHour); err != nil {
+				log.Printf("Release reminder scan failed: %v", err)
+			} else {
+				lastScan = time.Now()
+			}
+		}
+
+		if n, err := s.SendDueReminders(ctx); err != nil {
+			log.Printf("Reminder job failed: %v", err)
+		} else if n > 0 {
+			log.Printf("Reminder job sent %d reminders", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
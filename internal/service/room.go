@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+// RSVPUpdate -> сообщение rsvp: кто ответил (пользователь или гость, или кого убрали из приглашенных),
+// кто поднялся из листа ожидания и вечер с новыми счетчиками
+type RSVPUpdate struct {
+	UserID   int              `json:"user_id,omitempty" example:"2"`
+	GuestID  int              `json:"guest_id,omitempty" example:"4"`
+	RSVP     ports.RSVPStatus `json:"rsvp,omitempty" example:"going"`
+	Removed  bool             `json:"removed,omitempty" example:"false"`
+	Promoted []int            `json:"promoted" example:"5"`
+	Event    *ports.Event     `json:"event"`
+}
+
+// VoteUpdate -> сообщение vote: кто проголосовал (но не за что) и промежуточный подсчет
+type VoteUpdate struct {
+	PollID  int          `json:"poll_id" example:"1"`
+	UserID  int          `json:"user_id,omitempty" example:"2"`
+	GuestID int          `json:"guest_id,omitempty" example:"4"`
+	Results *PollResults `json:"results"`
+}
+
+// RoomService -> обновления киновечеров и опросов в реальном времени. Подписан на доменные
+// события и пересылает их в комнаты, а клиенты читают комнаты потоком SSE.
+// Сообщения опроса вечера попадают и в комнату опроса, и в комнату вечера
+type RoomService struct {
+	broker ports.RoomBroker
+	events ports.EventRepository
+	polls  ports.PollRepository
+	groups ports.GroupRepository
+}
+
+func NewRoomService(broker ports.RoomBroker, events ports.EventRepository, polls ports.PollRepository, groups ports.GroupRepository) *RoomService {
+	return &RoomService{
+		broker: broker,
+		events: events,
+		polls:  polls,
+		groups: groups,
+	}
+}
+
+// Register -> подписывает комнаты на события вечеров и опросов
+func (s *RoomService) Register(bus *EventBus) {
+	bus.Subscribe("rooms", s.handle,
+		ports.EventMovieNightUpdated,
+		ports.EventMovieNightCancelled,
+		ports.EventRSVPChanged,
+		ports.EventPollCreated,
+		ports.EventBallotCast,
+		ports.EventPollClosed,
+	)
+}
+
+// SubscribeEvent -> поток вечера доступен тем же, кому виден вечер
+func (s *RoomService) SubscribeEvent(ctx context.Context, userID, eventID int, lastID string) (<-chan ports.RoomMessage, error) {
+	if _, err := visibleEvent(ctx, s.events, userID, eventID); err != nil {
+		return nil, err
+	}
+	return s.broker.Subscribe(ctx, eventRoom(eventID), lastID)
+}
+
+// SubscribePoll -> поток опроса доступен тем же, кому виден опрос
+func (s *RoomService) SubscribePoll(ctx context.Context, userID, pollID int, lastID string) (<-chan ports.RoomMessage, error) {
+	if _, err := accessiblePoll(ctx, s.polls, s.events, s.groups, userID, pollID); err != nil {
+		return nil, err
+	}
+	return s.broker.Subscribe(ctx, pollRoom(pollID), lastID)
+}
+
+func (s *RoomService) handle(ctx context.Context, e ports.DomainEvent) error {
+	switch e.Type {
+	case ports.EventMovieNightUpdated:
+		event, err := s.event(ctx, e.RefID)
+		if err != nil {
+			return err
+		}
+		return s.broker.Publish(ctx, eventRoom(e.RefID), ports.RoomEventUpdated, event)
+
+	case ports.EventMovieNightCancelled:
+		event, err := s.event(ctx, e.RefID)
+		if err != nil {
+			return err
+		}
+		return s.broker.Publish(ctx, eventRoom(e.RefID), ports.RoomEventCancelled, event)
+
+	case ports.EventRSVPChanged:
+		event, err := s.event(ctx, e.RefID)
+		if err != nil {
+			return err
+		}
+		update := &RSVPUpdate{Event: event, Promoted: []int{}}
+		update.UserID, _ = e.Payload["user_id"].(int)
+		update.GuestID, _ = e.Payload["guest_id"].(int)
+		update.RSVP, _ = e.Payload["rsvp"].(ports.RSVPStatus)
+		update.Removed, _ = e.Payload["removed"].(bool)
+		if promoted, _ := e.Payload["promoted"].([]int); promoted != nil {
+			update.Promoted = promoted
+		}
+		return s.broker.Publish(ctx, eventRoom(e.RefID), ports.RoomRSVP, update)
+
+	case ports.EventPollCreated, ports.EventBallotCast, ports.EventPollClosed:
+		return s.handlePoll(ctx, e)
+	}
+	return nil
+}
+
+func (s *RoomService) handlePoll(ctx context.Context, e ports.DomainEvent) error {
+	p, err := s.polls.GetPoll(ctx, e.RefID)
+	if err != nil {
+		return err
+	}
+
+	var msgType string
+	var data any
+	switch e.Type {
+	case ports.EventPollCreated:
+		msgType, data = ports.RoomPollCreated, p
+	case ports.EventBallotCast:
+		results, err := pollResults(ctx, s.polls, p)
+		if err != nil {
+			return err
+		}
+		vote := &VoteUpdate{PollID: p.ID, UserID: e.ActorID, Results: results}
+		vote.GuestID, _ = e.Payload["guest_id"].(int)
+		msgType, data = ports.RoomVote, vote
+	case ports.EventPollClosed:
+		results, err := pollResults(ctx, s.polls, p)
+		if err != nil {
+			return err
+		}
+		msgType, data = ports.RoomPollClosed, results
+	default:
+		return fmt.Errorf("unexpected event type %s", e.Type)
+	}
+
+	rooms := []string{pollRoom(p.ID)}
+	if p.EventID != nil {
+		rooms = append(rooms, eventRoom(*p.EventID))
+	}
+	var errList []error
+	for _, room := range rooms {
+		if err := s.broker.Publish(ctx, room, msgType, data); err != nil {
+			errList = append(errList, err)
+		}
+	}
+	return errors.Join(errList...)
+}
+
+// event -> вечер в том же виде, что отдает GET /events/{id}
+func (s *RoomService) event(ctx context.Context, id int) (*ports.Event, error) {
+	e, err := s.events.GetEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if e.Invitations, err = s.events.GetEventInvitations(ctx, id); err != nil {
+		return nil, err
+	}
+	if e.Guests, err = s.events.GetEventGuests(ctx, id); err != nil {
+		return nil, err
+	}
+	localizeEvent(e)
+	return e, nil
+}
+
+func eventRoom(id int) string {
+	return fmt.Sprintf("event:%d", id)
+}
+
+func pollRoom(id int) string {
+	return fmt.Sprintf("poll:%d", id)
+}
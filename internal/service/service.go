@@ -2,26 +2,44 @@ package service
 
 import (
 	"context"
+	"strings"
 
 	"github.com/turysbekovg/movie-planner/internal/ports" // ядро зависит от портов
 )
 
 // MovieService -> ядро
 type MovieService struct {
-	repo ports.MovieRepository
+	repo        ports.MovieRepository
+	watchlist   ports.WatchlistRepository
+	preferences ports.PreferenceRepository
 }
 
-func NewMovieService(repo ports.MovieRepository) *MovieService {
-	return &MovieService{repo: repo}
+func NewMovieService(repo ports.MovieRepository, watchlist ports.WatchlistRepository, preferences ports.PreferenceRepository) *MovieService {
+	return &MovieService{
+		repo:        repo,
+		watchlist:   watchlist,
+		preferences: preferences,
+	}
 }
 
 // FinalMovieData -> финальный ответ который возвращается пользователю
 type FinalMovieData struct {
 	ports.Movie
-	Advice string `json:"advice" example:"It is a very good choice! A high rated movie, which is recommended to watch."`
+	Advice  string   `json:"advice" example:"It is a very good choice! A high rated movie, which is recommended to watch."`
+	Reasons []Reason `json:"reasons"` // структурированная версия advice для UI
+	// InWatchlist -> только для авторизованных запросов, поэтому указатель
+	InWatchlist *bool `json:"in_watchlist,omitempty" example:"true"`
+}
+
+// MovieListItem -> фильм в списке с персональными флагами для авторизованного пользователя
+type MovieListItem struct {
+	ports.Movie
+	InWatchlist *bool `json:"in_watchlist,omitempty" example:"false"`
 }
 
-func (s *MovieService) GetMovieByID(ctx context.Context, id int) (*FinalMovieData, error) {
+// GetMovieByID -> viewerID == 0 означает анонимный запрос.
+// Персональные флаги считаются поверх общего (кэшируемого) фильма и в кэш не попадают.
+func (s *MovieService) GetMovieByID(ctx context.Context, id, viewerID int) (*FinalMovieData, error) {
 	movie, err := s.repo.GetMovieByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -38,13 +56,86 @@ func (s *MovieService) GetMovieByID(ctx context.Context, id int) (*FinalMovieDat
 
 	// Собираем финальную структуру для ответа
 	finalData := &FinalMovieData{
-		Movie:  *movie,
-		Advice: advice,
+		Movie:   *movie,
+		Advice:  advice,
+		Reasons: []Reason{ratingReason(movie.Rating)},
+	}
+
+	if viewerID != 0 {
+		watchlisted, err := s.watchlistedSet(ctx, viewerID, []*ports.Movie{movie})
+		if err != nil {
+			return nil, err
+		}
+		inWatchlist := watchlisted[movie.ID]
+		finalData.InWatchlist = &inWatchlist
 	}
 
 	return finalData, nil
 }
 
+// ListMovies -> все фильмы, query != "" -> только с этой подстрокой в названии.
+// Для авторизованного пользователя применяем его предпочтения и добавляем флаг in_watchlist
+func (s *MovieService) ListMovies(ctx context.Context, viewerID int, query string) ([]*MovieListItem, error) {
+	movies, err := s.repo.GetAllMovies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if query = strings.ToLower(strings.TrimSpace(query)); query != "" {
+		matched := make([]*ports.Movie, 0)
+		for _, m := range movies {
+			if strings.Contains(strings.ToLower(m.Title), query) {
+				matched = append(matched, m)
+			}
+		}
+		movies = matched
+	}
+
+	var watchlisted map[int]bool
+	if viewerID != 0 {
+		prefs, err := s.preferences.GetViewingPreferences(ctx, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		movies = filterByPreferences(movies, prefs)
+
+		watchlisted, err = s.watchlistedSet(ctx, viewerID, movies)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	items := make([]*MovieListItem, 0, len(movies))
+	for _, m := range movies {
+		item := &MovieListItem{Movie: *m}
+		if viewerID != 0 {
+			inWatchlist := watchlisted[m.ID]
+			item.InWatchlist = &inWatchlist
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (s *MovieService) watchlistedSet(ctx context.Context, userID int, movies []*ports.Movie) (map[int]bool, error) {
+	ids := make([]int, 0, len(movies))
+	for _, m := range movies {
+		ids = append(ids, m.ID)
+	}
+
+	watchlistedIDs, err := s.watchlist.FilterWatchlisted(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[int]bool, len(watchlistedIDs))
+	for _, id := range watchlistedIDs {
+		set[id] = true
+	}
+	return set, nil
+}
+
 func (s *MovieService) CreateMovie(ctx context.Context, movie *ports.Movie) (int, error) {
 	return s.repo.CreateMovie(ctx, movie)
 }
@@ -53,6 +144,11 @@ func (s *MovieService) GetAllMovies(ctx context.Context) ([]*ports.Movie, error)
 	return s.repo.GetAllMovies(ctx)
 }
 
+// ViewingPreferences -> предпочтения пользователя для фильтрации в других сервисах
+func (s *MovieService) ViewingPreferences(ctx context.Context, userID int) (*ports.ViewingPreferences, error) {
+	return s.preferences.GetViewingPreferences(ctx, userID)
+}
+
 func (s *MovieService) UpdateMovie(ctx context.Context, id int, movie *ports.Movie) error {
 	return s.repo.UpdateMovie(ctx, id, movie)
 }
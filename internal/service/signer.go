@@ -0,0 +1,84 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSignedToken -> подпись не сошлась, токен поврежден или истек
+var ErrInvalidSignedToken = errors.New("invalid or expired token")
+
+// signer -> подписывает короткие данные для ссылок (HMAC-SHA256).
+// Ключ выводится из общего секрета и назначения токена, поэтому токен
+// одного назначения нельзя использовать для другого (и как JWT он тоже не пройдет)
+type signer struct {
+	key []byte
+}
+
+func newSigner(secret, purpose string) *signer {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("movie-planner:" + purpose))
+	return &signer{key: mac.Sum(nil)}
+}
+
+type signedEnvelope struct {
+	Payload json.RawMessage `json:"p"`
+	Expires int64           `json:"e"`
+}
+
+// Sign -> base64url(JSON с данными и сроком) + "." + base64url(подпись)
+func (s *signer) Sign(payload any, expiresAt time.Time) (string, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal token payload: %w", err)
+	}
+	body, err := json.Marshal(signedEnvelope{Payload: raw, Expires: expiresAt.Unix()})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal token: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(body)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.mac(encoded)), nil
+}
+
+// Verify -> проверяет подпись и срок и распаковывает данные в dst
+func (s *signer) Verify(token string, now time.Time, dst any) error {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalidSignedToken
+	}
+
+	gotMAC, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(gotMAC, s.mac(encoded)) {
+		return ErrInvalidSignedToken
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidSignedToken
+	}
+	var env signedEnvelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return ErrInvalidSignedToken
+	}
+	if now.Unix() >= env.Expires {
+		return ErrInvalidSignedToken
+	}
+
+	if err := json.Unmarshal(env.Payload, dst); err != nil {
+		return ErrInvalidSignedToken
+	}
+	return nil
+}
+
+func (s *signer) mac(data string) []byte {
+	m := hmac.New(sha256.New, s.key)
+	m.Write([]byte(data))
+	return m.Sum(nil)
+}
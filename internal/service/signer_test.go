@@ -0,0 +1,122 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSecret = "test-secret"
+
+// tamper -> меняет данные в токене, оставляя старую подпись
+func tamper(t *testing.T, token string, replace func(body string) string) string {
+	t.Helper()
+	encoded, sig, _ := strings.Cut(token, ".")
+	body, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("failed to decode token: %v", err)
+	}
+	changed := replace(string(body))
+	if changed == string(body) {
+		t.Fatalf("token body %s was not changed", body)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(changed)) + "." + sig
+}
+
+// flipSignature -> портит один байт подписи
+func flipSignature(t *testing.T, token string) string {
+	t.Helper()
+	encoded, sig, _ := strings.Cut(token, ".")
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		t.Fatalf("failed to decode signature: %v", err)
+	}
+	mac[0] ^= 0xff
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(mac)
+}
+
+func TestSignerVerify(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	guests := newSigner(testSecret, "guest-invite")
+
+	sign := func(s *signer, payload guestLinkPayload, expiresAt time.Time) string {
+		token, err := s.Sign(payload, expiresAt)
+		if err != nil {
+			t.Fatalf("Sign() error: %v", err)
+		}
+		return token
+	}
+	valid := sign(guests, guestLinkPayload{EventID: 7, Version: 2}, now.Add(time.Hour))
+
+	tests := []struct {
+		name  string
+		token string
+		now   time.Time
+		want  *guestLinkPayload // nil -> ErrInvalidSignedToken
+	}{
+		{
+			name:  "valid signature",
+			token: valid,
+			now:   now,
+			want:  &guestLinkPayload{EventID: 7, Version: 2},
+		},
+		{
+			name:  "signed for a different purpose",
+			token: sign(newSigner(testSecret, "verify-email"), guestLinkPayload{EventID: 7, Version: 2}, now.Add(time.Hour)),
+			now:   now,
+		},
+		{
+			name:  "signed with a different secret",
+			token: sign(newSigner("other-secret", "guest-invite"), guestLinkPayload{EventID: 7, Version: 2}, now.Add(time.Hour)),
+			now:   now,
+		},
+		{
+			name:  "tampered payload",
+			token: tamper(t, valid, func(body string) string { return strings.Replace(body, `"eid":7`, `"eid":8`, 1) }),
+			now:   now,
+		},
+		{
+			name: "tampered expiry",
+			token: tamper(t, sign(guests, guestLinkPayload{EventID: 7}, now.Add(-time.Hour)), func(body string) string {
+				return strings.Replace(body, `"e":`, `"e":9`, 1)
+			}),
+			now: now,
+		},
+		{
+			name:  "tampered signature",
+			token: flipSignature(t, valid),
+			now:   now,
+		},
+		{
+			name:  "expired",
+			token: valid,
+			now:   now.Add(time.Hour),
+		},
+		{
+			name:  "not a token",
+			token: "garbage",
+			now:   now,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got guestLinkPayload
+			err := guests.Verify(tt.token, tt.now, &got)
+			if tt.want == nil {
+				if !errors.Is(err, ErrInvalidSignedToken) {
+					t.Errorf("Verify() error = %v, want ErrInvalidSignedToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error: %v", err)
+			}
+			if got != *tt.want {
+				t.Errorf("Verify() payload = %+v, want %+v", got, *tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	defaultFeedLimit = 20
+	maxFeedLimit     = 100
+)
+
+// FeedPage -> страница ленты. NextCursor пустой, если дальше записей нет
+type FeedPage struct {
+	Items      []*ports.Activity `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty" example:"1031"`
+}
+
+// SocialService -> подписки между пользователями и лента активности
+type SocialService struct {
+	repo       ports.SocialRepository
+	activities ports.ActivityRepository
+	users      ports.UserRepository
+	notifier   ports.Notifier
+}
+
+func NewSocialService(repo ports.SocialRepository, activities ports.ActivityRepository, users ports.UserRepository, notifier ports.Notifier) *SocialService {
+	return &SocialService{
+		repo:       repo,
+		activities: activities,
+		users:      users,
+		notifier:   notifier,
+	}
+}
+
+func (s *SocialService) Follow(ctx context.Context, followerID, followeeID int) error {
+	if followerID == followeeID {
+		return fmt.Errorf("%w: you cannot follow yourself", errs.ErrInvalidInput)
+	}
+	if err := s.repo.Follow(ctx, followerID, followeeID); err != nil {
+		return err
+	}
+
+	if follower, err := s.users.GetUserByID(ctx, followerID); err == nil {
+		notify(ctx, s.notifier, followeeID, ports.NotificationNewFollower,
+			fmt.Sprintf("%s started following you.", displayName(follower)),
+			map[string]any{"user_id": followerID})
+	}
+	return nil
+}
+
+func (s *SocialService) Unfollow(ctx context.Context, followerID, followeeID int) error {
+	return s.repo.Unfollow(ctx, followerID, followeeID)
+}
+
+func (s *SocialService) GetFollowers(ctx context.Context, userID int) ([]*ports.Follow, error) {
+	// Для несуществующего пользователя -> 404, а не пустой список
+	if _, err := s.users.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetFollowers(ctx, userID)
+}
+
+func (s *SocialService) GetFollowing(ctx context.Context, userID int) ([]*ports.Follow, error) {
+	if _, err := s.users.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetFollowing(ctx, userID)
+}
+
+// GetFeed -> активность подписок от новых к старым. cursor -> next_cursor предыдущей страницы
+func (s *SocialService) GetFeed(ctx context.Context, userID int, cursor string, limit int) (*FeedPage, error) {
+	var before int64
+	if cursor != "" {
+		v, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || v <= 0 {
+			return nil, fmt.Errorf("%w: invalid cursor", errs.ErrInvalidInput)
+		}
+		before = v
+	}
+	if limit <= 0 {
+		limit = defaultFeedLimit
+	}
+	if limit > maxFeedLimit {
+		limit = maxFeedLimit
+	}
+
+	// Берем на одну запись больше, чтобы понять, есть ли следующая страница
+	items, err := s.activities.GetFeed(ctx, userID, before, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &FeedPage{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.NextCursor = strconv.FormatInt(page.Items[limit-1].ID, 10)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+// Как был разрешен равный счет
+const (
+	TieBreakFirstChoices   = "first_choices"   // Борда: у кого больше первых мест
+	TieBreakPreviousRounds = "previous_rounds" // IRV: кто набрал меньше в предыдущих раундах
+	TieBreakCandidateOrder = "candidate_order" // по порядку кандидатов в опросе
+)
+
+// CandidateScore -> счет одного кандидата. Rank 1 -> победитель, одинаковых рангов нет:
+// равенство всегда разрешается правилами ниже
+type CandidateScore struct {
+	MovieID int `json:"movie_id" example:"12"`
+	Score   int `json:"score" example:"7"`
+	Rank    int `json:"rank" example:"1"`
+}
+
+// TallyRound -> один раунд IRV
+type TallyRound struct {
+	Round int `json:"round" example:"1"`
+	// Counts -> первые предпочтения среди оставшихся кандидатов, по убыванию
+	Counts     []CandidateScore `json:"counts"`
+	Exhausted  int              `json:"exhausted" example:"1"` // бюллетени, где не осталось живых кандидатов
+	Eliminated int              `json:"eliminated,omitempty" example:"7"`
+	Winner     int              `json:"winner,omitempty" example:"12"`
+	TieBreak   string           `json:"tie_break,omitempty" example:"previous_rounds"`
+}
+
+// TallyResult -> итог подсчета. Winner == 0 -> бюллетеней нет
+type TallyResult struct {
+	Method    ports.VotingMethod `json:"method" example:"irv"`
+	Ballots   int                `json:"ballots" example:"9"`
+	Winner    int                `json:"winner,omitempty" example:"12"`
+	Standings []CandidateScore   `json:"standings"`
+	Rounds    []TallyRound       `json:"rounds,omitempty"`
+	TieBreak  string             `json:"tie_break,omitempty" example:"candidate_order"`
+}
+
+// Tally -> детерминированный подсчет голосов. candidates -> ID фильмов в порядке опроса,
+// бюллетени уже проверены (только кандидаты опроса, без повторов).
+//
+// Правила:
+//   - plurality: один голос за фильм, побеждает больше всего голосов;
+//   - approval: голос за каждый отмеченный фильм, побеждает больше всего голосов;
+//   - borda: из n кандидатов место i (с нуля) дает n-1-i очков, неотмеченные -> 0 очков;
+//   - irv: в каждом раунде считаются первые предпочтения среди оставшихся кандидатов.
+//     Больше половины неисчерпанных бюллетеней -> победа, иначе выбывает один кандидат с наименьшим
+//     числом голосов. Когда остается один кандидат, он побеждает.
+//
+// Равный счет:
+//   - plurality и approval -> выше тот, кто раньше в списке кандидатов;
+//   - borda -> больше первых мест, затем раньше в списке кандидатов;
+//   - irv, кто выбывает -> сравниваем предыдущие раунды от последнего к первому, выбывает тот,
+//     у кого там было меньше; если везде поровну -> выбывает тот, кто позже в списке кандидатов.
+func Tally(method ports.VotingMethod, candidates []int, ballots [][]int) *TallyResult {
+	result := &TallyResult{Method: method, Ballots: len(ballots)}
+	// Без кандидатов считать нечего, победителя нет
+	if len(candidates) == 0 {
+		result.Standings = []CandidateScore{}
+		return result
+	}
+
+	switch method {
+	case ports.VotingIRV:
+		tallyIRV(result, candidates, ballots)
+	case ports.VotingBorda:
+		scores := make(map[int]int, len(candidates))
+		firsts := make(map[int]int, len(candidates))
+		for _, b := range ballots {
+			for i, id := range b {
+				scores[id] += len(candidates) - 1 - i
+			}
+			if len(b) > 0 {
+				firsts[b[0]]++
+			}
+		}
+		result.Standings, result.TieBreak = rankByScore(candidates, scores, firsts)
+	default:
+		// plurality и approval считаются одинаково: каждый отмеченный фильм -> один голос
+		scores := make(map[int]int, len(candidates))
+		for _, b := range ballots {
+			for _, id := range b {
+				scores[id]++
+			}
+		}
+		result.Standings, result.TieBreak = rankByScore(candidates, scores, nil)
+	}
+
+	// Без бюллетеней победителя нет, и равенство "разрешать" не нужно
+	if len(ballots) == 0 {
+		result.TieBreak = ""
+	} else if result.Winner == 0 && len(result.Standings) > 0 {
+		result.Winner = result.Standings[0].MovieID
+	}
+	return result
+}
+
+// rankByScore -> сортировка по очкам, затем по secondary (если есть), затем по порядку кандидатов.
+// Возвращает правило, которое понадобилось, чтобы определить победителя
+func rankByScore(candidates []int, scores, secondary map[int]int) ([]CandidateScore, string) {
+	order := candidateOrder(candidates)
+	standings := make([]CandidateScore, 0, len(candidates))
+	for _, id := range candidates {
+		standings = append(standings, CandidateScore{MovieID: id, Score: scores[id]})
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if secondary != nil && secondary[a.MovieID] != secondary[b.MovieID] {
+			return secondary[a.MovieID] > secondary[b.MovieID]
+		}
+		return order[a.MovieID] < order[b.MovieID]
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+	}
+
+	var tieBreak string
+	if len(standings) > 1 && standings[0].Score == standings[1].Score {
+		first, second := standings[0].MovieID, standings[1].MovieID
+		if secondary != nil && secondary[first] != secondary[second] {
+			tieBreak = TieBreakFirstChoices
+		} else {
+			tieBreak = TieBreakCandidateOrder
+		}
+	}
+	return standings, tieBreak
+}
+
+func tallyIRV(result *TallyResult, candidates []int, ballots [][]int) {
+	order := candidateOrder(candidates)
+	continuing := make(map[int]bool, len(candidates))
+	for _, id := range candidates {
+		continuing[id] = true
+	}
+	// history[id] -> голоса кандидата по раундам, для разрешения равенства при выбывании
+	history := make(map[int][]int, len(candidates))
+	eliminated := make([]int, 0, len(candidates))
+
+	for round := 1; len(continuing) > 0; round++ {
+		counts := make(map[int]int, len(continuing))
+		exhausted := 0
+		for _, b := range ballots {
+			top := 0
+			for _, id := range b {
+				if continuing[id] {
+					top = id
+					break
+				}
+			}
+			if top == 0 {
+				exhausted++
+				continue
+			}
+			counts[top]++
+		}
+
+		alive := make([]int, 0, len(continuing))
+		for _, id := range candidates {
+			if continuing[id] {
+				alive = append(alive, id)
+				history[id] = append(history[id], counts[id])
+			}
+		}
+		r := TallyRound{Round: round, Exhausted: exhausted}
+		r.Counts, _ = rankByScore(alive, counts, nil)
+
+		active := len(ballots) - exhausted
+		leader := r.Counts[0]
+		if len(ballots) > 0 && (leader.Score*2 > active || len(alive) == 1) {
+			r.Winner = leader.MovieID
+			result.Winner = leader.MovieID
+			result.Rounds = append(result.Rounds, r)
+			// Оставшиеся кандидаты идут в итог по счету последнего раунда. Добавляем с конца,
+			// потому что ниже список выбывших разворачивается
+			for i := len(r.Counts) - 1; i >= 0; i-- {
+				eliminated = append(eliminated, r.Counts[i].MovieID)
+			}
+			break
+		}
+		if len(ballots) == 0 {
+			// Нечего считать -> итоговый порядок совпадает с порядком кандидатов
+			result.Rounds = append(result.Rounds, r)
+			for i := len(candidates) - 1; i >= 0; i-- {
+				eliminated = append(eliminated, candidates[i])
+			}
+			break
+		}
+
+		loser, tieBreak := irvLoser(alive, counts, history, order)
+		r.Eliminated = loser
+		r.TieBreak = tieBreak
+		result.Rounds = append(result.Rounds, r)
+		delete(continuing, loser)
+		eliminated = append(eliminated, loser)
+	}
+
+	// Итог: победитель первым, дальше -> в обратном порядке выбывания
+	result.Standings = make([]CandidateScore, 0, len(eliminated))
+	last := result.Rounds[len(result.Rounds)-1]
+	finalScore := make(map[int]int, len(last.Counts))
+	for _, c := range last.Counts {
+		finalScore[c.MovieID] = c.Score
+	}
+	for i := len(eliminated) - 1; i >= 0; i-- {
+		id := eliminated[i]
+		score, ok := finalScore[id]
+		if !ok {
+			// Выбывшие кандидаты -> счет раунда, в котором они выбыли
+			score = history[id][len(history[id])-1]
+		}
+		result.Standings = append(result.Standings, CandidateScore{MovieID: id, Score: score, Rank: len(result.Standings) + 1})
+	}
+}
+
+// irvLoser -> кандидат с наименьшим числом голосов. Равенство -> смотрим предыдущие раунды
+// от последнего к первому, затем выбывает тот, кто позже в списке кандидатов
+func irvLoser(alive []int, counts map[int]int, history map[int][]int, order map[int]int) (int, string) {
+	lowest := counts[alive[0]]
+	for _, id := range alive {
+		lowest = min(lowest, counts[id])
+	}
+	tied := make([]int, 0)
+	for _, id := range alive {
+		if counts[id] == lowest {
+			tied = append(tied, id)
+		}
+	}
+	if len(tied) == 1 {
+		return tied[0], ""
+	}
+
+	// history текущего раунда уже записана, поэтому идем с предпоследнего элемента.
+	// Все живые кандидаты участвовали во всех раундах, длины историй равны
+	rounds := len(history[tied[0]])
+	for back := rounds - 2; back >= 0 && len(tied) > 1; back-- {
+		fewest := history[tied[0]][back]
+		for _, id := range tied {
+			fewest = min(fewest, history[id][back])
+		}
+		next := make([]int, 0, len(tied))
+		for _, id := range tied {
+			if history[id][back] == fewest {
+				next = append(next, id)
+			}
+		}
+		tied = next
+	}
+	if len(tied) == 1 {
+		return tied[0], TieBreakPreviousRounds
+	}
+
+	loser := tied[0]
+	for _, id := range tied {
+		if order[id] > order[loser] {
+			loser = id
+		}
+	}
+	return loser, TieBreakCandidateOrder
+}
+
+func candidateOrder(candidates []int) map[int]int {
+	order := make(map[int]int, len(candidates))
+	for i, id := range candidates {
+		order[id] = i
+	}
+	return order
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+func TestTally(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     ports.VotingMethod
+		candidates []int
+		ballots    [][]int
+		winner     int
+		standings  []CandidateScore
+		tieBreak   string
+	}{
+		{
+			name:       "plurality clear winner",
+			method:     ports.VotingPlurality,
+			candidates: []int{1, 2, 3},
+			ballots:    [][]int{{2}, {2}, {1}},
+			winner:     2,
+			standings:  []CandidateScore{{2, 2, 1}, {1, 1, 2}, {3, 0, 3}},
+		},
+		{
+			name:       "plurality tie -> earlier candidate",
+			method:     ports.VotingPlurality,
+			candidates: []int{1, 2, 3},
+			ballots:    [][]int{{2}, {1}},
+			winner:     1,
+			standings:  []CandidateScore{{1, 1, 1}, {2, 1, 2}, {3, 0, 3}},
+			tieBreak:   TieBreakCandidateOrder,
+		},
+		{
+			name:       "approval tie -> earlier candidate",
+			method:     ports.VotingApproval,
+			candidates: []int{5, 6, 7},
+			ballots:    [][]int{{6, 7}, {7, 6}, {5}},
+			winner:     6,
+			standings:  []CandidateScore{{6, 2, 1}, {7, 2, 2}, {5, 1, 3}},
+			tieBreak:   TieBreakCandidateOrder,
+		},
+		{
+			name:       "borda tie -> more first choices",
+			method:     ports.VotingBorda,
+			candidates: []int{1, 2, 3},
+			ballots:    [][]int{{1}, {2, 1}, {2, 1}},
+			winner:     2,
+			standings:  []CandidateScore{{2, 4, 1}, {1, 4, 2}, {3, 0, 3}},
+			tieBreak:   TieBreakFirstChoices,
+		},
+		{
+			name:       "borda tie with equal first choices -> earlier candidate",
+			method:     ports.VotingBorda,
+			candidates: []int{1, 2},
+			ballots:    [][]int{{1, 2}, {2, 1}},
+			winner:     1,
+			standings:  []CandidateScore{{1, 1, 1}, {2, 1, 2}},
+			tieBreak:   TieBreakCandidateOrder,
+		},
+		{
+			name:       "irv majority in the first round",
+			method:     ports.VotingIRV,
+			candidates: []int{1, 2, 3},
+			ballots:    [][]int{{1}, {1}, {2}},
+			winner:     1,
+			standings:  []CandidateScore{{1, 2, 1}, {2, 1, 2}, {3, 0, 3}},
+		},
+		{
+			name:       "irv empty ballot is exhausted",
+			method:     ports.VotingIRV,
+			candidates: []int{1, 2},
+			ballots:    [][]int{{}, {2}},
+			winner:     2,
+			standings:  []CandidateScore{{2, 1, 1}, {1, 0, 2}},
+		},
+		{
+			name:       "no ballots -> no winner",
+			method:     ports.VotingPlurality,
+			candidates: []int{1, 2},
+			ballots:    nil,
+			standings:  []CandidateScore{{1, 0, 1}, {2, 0, 2}},
+		},
+		{
+			name:       "irv without ballots keeps candidate order",
+			method:     ports.VotingIRV,
+			candidates: []int{1, 2},
+			ballots:    nil,
+			standings:  []CandidateScore{{1, 0, 1}, {2, 0, 2}},
+		},
+		{
+			name:       "irv without candidates",
+			method:     ports.VotingIRV,
+			candidates: nil,
+			ballots:    [][]int{{1}},
+			standings:  []CandidateScore{},
+		},
+		{
+			name:       "borda without candidates",
+			method:     ports.VotingBorda,
+			candidates: []int{},
+			ballots:    [][]int{{1}},
+			standings:  []CandidateScore{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Tally(tt.method, tt.candidates, tt.ballots)
+			if got.Winner != tt.winner {
+				t.Errorf("winner = %d, want %d", got.Winner, tt.winner)
+			}
+			if !reflect.DeepEqual(got.Standings, tt.standings) {
+				t.Errorf("standings = %v, want %v", got.Standings, tt.standings)
+			}
+			if got.TieBreak != tt.tieBreak {
+				t.Errorf("tie break = %q, want %q", got.TieBreak, tt.tieBreak)
+			}
+			if got.Ballots != len(tt.ballots) {
+				t.Errorf("ballots = %d, want %d", got.Ballots, len(tt.ballots))
+			}
+		})
+	}
+}
+
+func TestTallyIRVEliminationTies(t *testing.T) {
+	// Раунд 1: у 3 и 4 по одному голосу и предыдущих раундов нет -> выбывает 4, он позже в списке.
+	// Раунд 2: у 2 и 3 по два голоса, в первом раунде у 3 было меньше -> выбывает 3.
+	// Раунд 3: бюллетени 3 и 4 исчерпаны, у 1 больше половины оставшихся
+	ballots := [][]int{{1}, {1}, {1}, {2}, {2}, {3}, {4, 3}}
+	got := Tally(ports.VotingIRV, []int{1, 2, 3, 4}, ballots)
+
+	want := []struct {
+		eliminated, winner, exhausted int
+		tieBreak                      string
+	}{
+		{eliminated: 4, tieBreak: TieBreakCandidateOrder},
+		{eliminated: 3, tieBreak: TieBreakPreviousRounds},
+		{winner: 1, exhausted: 2},
+	}
+	if len(got.Rounds) != len(want) {
+		t.Fatalf("rounds = %d, want %d", len(got.Rounds), len(want))
+	}
+	for i, w := range want {
+		r := got.Rounds[i]
+		if r.Round != i+1 || r.Eliminated != w.eliminated || r.Winner != w.winner || r.Exhausted != w.exhausted || r.TieBreak != w.tieBreak {
+			t.Errorf("round %d = %+v, want %+v", i+1, r, w)
+		}
+	}
+
+	if got.Winner != 1 {
+		t.Errorf("winner = %d, want 1", got.Winner)
+	}
+	standings := []CandidateScore{{1, 3, 1}, {2, 2, 2}, {3, 2, 3}, {4, 1, 4}}
+	if !reflect.DeepEqual(got.Standings, standings) {
+		t.Errorf("standings = %v, want %v", got.Standings, standings)
+	}
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	maxEventTimeSlots = 50
+	maxSlotRangeDays  = 31
+)
+
+// SlotInput -> явно заданный вариант времени
+type SlotInput struct {
+	StartsAt time.Time
+	EndsAt   time.Time
+}
+
+// SlotRange -> варианты, сгенерированные по диапазону дат: каждый день с DayStart
+// с шагом Step, пока вариант длиной Duration помещается до DayEnd. Время -> в часовом поясе вечера
+type SlotRange struct {
+	From     string // 2006-01-02, включительно
+	To       string // 2006-01-02, включительно
+	DayStart string // 15:04
+	DayEnd   string // 15:04; пусто -> один вариант в день. Раньше DayStart -> окно переходит за полночь
+	Duration int    // минуты; 0 -> длина фильма вечера или 2 часа
+	Step     int    // минуты; 0 -> Duration
+}
+
+// SlotProposal -> хост предлагает либо конкретные варианты, либо диапазон (или и то, и другое)
+type SlotProposal struct {
+	Slots []SlotInput
+	Range *SlotRange
+}
+
+// RankedSlot -> вариант времени с подсчетом отметок участников
+type RankedSlot struct {
+	*ports.TimeSlot
+	Rank        int `json:"rank" example:"1"`
+	Available   int `json:"available" example:"4"`
+	IfNeedBe    int `json:"if_need_be" example:"1"`
+	Unavailable int `json:"unavailable" example:"1"`
+	NoResponse  int `json:"no_response" example:"0"`
+	// Attendance -> сколько участников смогут прийти (available + if_need_be)
+	Attendance int `json:"attendance" example:"5"`
+	// MustAttendMet -> все обязательные участники отметили available или if_need_be
+	MustAttendMet     bool               `json:"must_attend_met" example:"true"`
+	MissingMustAttend []int              `json:"missing_must_attend,omitempty" example:"3"`
+	MyAvailability    ports.Availability `json:"my_availability,omitempty" example:"available"`
+}
+
+// SlotRanking -> варианты от лучшего к худшему
+type SlotRanking struct {
+	EventID      int           `json:"event_id" example:"3"`
+	Participants []int         `json:"participants" example:"1,2,3"`
+	MustAttend   []int         `json:"must_attend" example:"3"`
+	Slots        []*RankedSlot `json:"slots"`
+}
+
+// GetTimeSlots -> варианты времени с рейтингом. mustAttend -> участники, без которых вечер не имеет смысла
+func (s *EventService) GetTimeSlots(ctx context.Context, userID, id int, mustAttend []int) (*SlotRanking, error) {
+	e, err := s.visibleEvent(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.slotRanking(ctx, e, userID, mustAttend)
+}
+
+// ProposeTimeSlots -> хост добавляет варианты времени. Повторы уже предложенных пропускаются
+func (s *EventService) ProposeTimeSlots(ctx context.Context, userID, id int, p SlotProposal) (*SlotRanking, error) {
+	e, err := s.hostedEvent(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if e.Status == ports.EventCancelled {
+		return nil, fmt.Errorf("%w: a cancelled event cannot be changed", errs.ErrConflict)
+	}
+
+	slots, err := proposedSlots(e, p, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.GetEventTimeSlots(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing)+len(slots) > maxEventTimeSlots {
+		return nil, fmt.Errorf("%w: an event can have at most %d time slots", errs.ErrInvalidInput, maxEventTimeSlots)
+	}
+
+	if err := s.repo.AddEventTimeSlots(ctx, id, slots); err != nil {
+		return nil, err
+	}
+
+	return s.slotRanking(ctx, e, userID, nil)
+}
+
+func (s *EventService) DeleteTimeSlot(ctx context.Context, userID, id, slotID int) error {
+	if _, err := s.hostedEvent(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteEventTimeSlot(ctx, id, slotID)
+}
+
+// SetAvailability -> участник отмечает, когда может. Отметки для вариантов, которых нет в answers, не меняются
+func (s *EventService) SetAvailability(ctx context.Context, userID, id int, answers map[int]ports.Availability) (*SlotRanking, error) {
+	e, err := s.visibleEvent(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if e.Status == ports.EventCancelled {
+		return nil, fmt.Errorf("%w: the event is cancelled", errs.ErrConflict)
+	}
+	if len(answers) == 0 {
+		return nil, fmt.Errorf("%w: answers must not be empty", errs.ErrInvalidInput)
+	}
+	for slotID, a := range answers {
+		switch a {
+		case ports.AvailabilityAvailable, ports.AvailabilityIfNeedBe, ports.AvailabilityUnavailable:
+		default:
+			return nil, fmt.Errorf("%w: availability for slot %d must be available, if_need_be or unavailable", errs.ErrInvalidInput, slotID)
+		}
+	}
+
+	if err := s.repo.SetSlotAvailability(ctx, id, userID, answers); err != nil {
+		return nil, err
+	}
+
+	return s.slotRanking(ctx, e, userID, nil)
+}
+
+// ChooseTimeSlot -> хост переносит начало вечера на выбранный вариант
+func (s *EventService) ChooseTimeSlot(ctx context.Context, userID, id, slotID int) (*ports.Event, error) {
+	if _, err := s.hostedEvent(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	slots, err := s.repo.GetEventTimeSlots(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, slot := range slots {
+		if slot.ID == slotID {
+			return s.UpdateEvent(ctx, userID, id, EventUpdate{StartsAt: &slot.StartsAt})
+		}
+	}
+	return nil, errs.ErrNotFound
+}
+
+func (s *EventService) slotRanking(ctx context.Context, e *ports.Event, userID int, mustAttend []int) (*SlotRanking, error) {
+	invitations, err := s.repo.GetEventInvitations(ctx, e.ID)
+	if err != nil {
+		return nil, err
+	}
+	// Участники -> хост и все приглашенные, кроме отказавшихся
+	participants := []int{e.Host.ID}
+	for _, inv := range invitations {
+		if inv.RSVP != ports.RSVPDeclined {
+			participants = append(participants, inv.User.ID)
+		}
+	}
+
+	mustAttend = uniqueInts(mustAttend)
+	isParticipant := make(map[int]bool, len(participants))
+	for _, id := range participants {
+		isParticipant[id] = true
+	}
+	for _, id := range mustAttend {
+		if !isParticipant[id] {
+			return nil, fmt.Errorf("%w: user %d is not a participant of this event", errs.ErrInvalidInput, id)
+		}
+	}
+
+	slots, err := s.repo.GetEventTimeSlots(ctx, e.ID)
+	if err != nil {
+		return nil, err
+	}
+	loc := eventLocation(e)
+	for _, slot := range slots {
+		slot.StartsAt = slot.StartsAt.In(loc)
+		slot.EndsAt = slot.EndsAt.In(loc)
+	}
+
+	return &SlotRanking{
+		EventID:      e.ID,
+		Participants: participants,
+		MustAttend:   mustAttend,
+		Slots:        rankSlots(slots, participants, mustAttend, userID),
+	}, nil
+}
+
+// rankSlots -> порядок вариантов:
+//  1. сначала те, где могут все обязательные участники;
+//  2. больше участников смогут прийти (available + if_need_be);
+//  3. больше тех, кому удобно (available);
+//  4. раньше по времени, затем по ID.
+//
+// Отметки тех, кто больше не участник (отказался, приглашение отозвано), не учитываются
+func rankSlots(slots []*ports.TimeSlot, participants, mustAttend []int, viewerID int) []*RankedSlot {
+	isParticipant := make(map[int]bool, len(participants))
+	for _, id := range participants {
+		isParticipant[id] = true
+	}
+
+	ranked := make([]*RankedSlot, 0, len(slots))
+	for _, slot := range slots {
+		r := &RankedSlot{TimeSlot: slot}
+		answers := make(map[int]ports.Availability, len(slot.Responses))
+		responses := make([]*ports.SlotResponse, 0, len(slot.Responses))
+		for _, resp := range slot.Responses {
+			if !isParticipant[resp.UserID] {
+				continue
+			}
+			responses = append(responses, resp)
+			answers[resp.UserID] = resp.Availability
+		}
+		slot.Responses = responses
+
+		for _, id := range participants {
+			switch answers[id] {
+			case ports.AvailabilityAvailable:
+				r.Available++
+			case ports.AvailabilityIfNeedBe:
+				r.IfNeedBe++
+			case ports.AvailabilityUnavailable:
+				r.Unavailable++
+			default:
+				r.NoResponse++
+			}
+		}
+		r.Attendance = r.Available + r.IfNeedBe
+		for _, id := range mustAttend {
+			if a := answers[id]; a != ports.AvailabilityAvailable && a != ports.AvailabilityIfNeedBe {
+				r.MissingMustAttend = append(r.MissingMustAttend, id)
+			}
+		}
+		r.MustAttendMet = len(r.MissingMustAttend) == 0
+		r.MyAvailability = answers[viewerID]
+		ranked = append(ranked, r)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.MustAttendMet != b.MustAttendMet {
+			return a.MustAttendMet
+		}
+		if a.Attendance != b.Attendance {
+			return a.Attendance > b.Attendance
+		}
+		if a.Available != b.Available {
+			return a.Available > b.Available
+		}
+		if !a.StartsAt.Equal(b.StartsAt) {
+			return a.StartsAt.Before(b.StartsAt)
+		}
+		return a.ID < b.ID
+	})
+	for i, r := range ranked {
+		r.Rank = i + 1
+	}
+	return ranked
+}
+
+// proposedSlots -> проверенные варианты из предложения хоста. Прошедшие варианты не принимаются
+func proposedSlots(e *ports.Event, p SlotProposal, now time.Time) ([]*ports.TimeSlot, error) {
+	slots := make([]*ports.TimeSlot, 0, len(p.Slots))
+	for _, in := range p.Slots {
+		if in.StartsAt.IsZero() || !in.EndsAt.After(in.StartsAt) {
+			return nil, fmt.Errorf("%w: each slot needs starts_at and a later ends_at", errs.ErrInvalidInput)
+		}
+		if !in.StartsAt.After(now) {
+			return nil, fmt.Errorf("%w: time slots must be in the future", errs.ErrInvalidInput)
+		}
+		slots = append(slots, &ports.TimeSlot{StartsAt: in.StartsAt, EndsAt: in.EndsAt})
+	}
+
+	if p.Range != nil {
+		generated, err := rangeSlots(e, *p.Range, now)
+		if err != nil {
+			return nil, err
+		}
+		slots = append(slots, generated...)
+	}
+
+	if len(slots) == 0 {
+		return nil, fmt.Errorf("%w: propose at least one time slot", errs.ErrInvalidInput)
+	}
+	if len(slots) > maxEventTimeSlots {
+		return nil, fmt.Errorf("%w: an event can have at most %d time slots", errs.ErrInvalidInput, maxEventTimeSlots)
+	}
+	return slots, nil
+}
+
+// rangeSlots -> варианты по диапазону дат в часовом поясе вечера. Уже прошедшие пропускаются
+func rangeSlots(e *ports.Event, r SlotRange, now time.Time) ([]*ports.TimeSlot, error) {
+	loc := eventLocation(e)
+	from, err := time.ParseInLocation("2006-01-02", r.From, loc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: range.from must be a date like 2006-01-02", errs.ErrInvalidInput)
+	}
+	to, err := time.ParseInLocation("2006-01-02", r.To, loc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: range.to must be a date like 2006-01-02", errs.ErrInvalidInput)
+	}
+	if to.Before(from) || to.Sub(from) >= maxSlotRangeDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: range must cover between 1 and %d days", errs.ErrInvalidInput, maxSlotRangeDays)
+	}
+
+	dayStart, err := time.Parse("15:04", r.DayStart)
+	if err != nil {
+		return nil, fmt.Errorf("%w: range.day_start must be a time like 19:00", errs.ErrInvalidInput)
+	}
+	var dayEnd time.Time
+	if r.DayEnd != "" {
+		if dayEnd, err = time.Parse("15:04", r.DayEnd); err != nil {
+			return nil, fmt.Errorf("%w: range.day_end must be a time like 23:00", errs.ErrInvalidInput)
+		}
+	}
+
+	duration := time.Duration(r.Duration) * time.Minute
+	if r.Duration == 0 {
+		duration = eventDuration(e)
+	}
+	step := time.Duration(r.Step) * time.Minute
+	if r.Step == 0 {
+		step = duration
+	}
+	if duration <= 0 || step <= 0 {
+		return nil, fmt.Errorf("%w: duration and step must be positive", errs.ErrInvalidInput)
+	}
+
+	slots := make([]*ports.TimeSlot, 0)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		start := time.Date(day.Year(), day.Month(), day.Day(), dayStart.Hour(), dayStart.Minute(), 0, 0, loc)
+		end := start.Add(duration)
+		if r.DayEnd != "" {
+			end = time.Date(day.Year(), day.Month(), day.Day(), dayEnd.Hour(), dayEnd.Minute(), 0, 0, loc)
+			if !end.After(start) {
+				end = end.AddDate(0, 0, 1)
+			}
+		}
+
+		for t := start; !t.Add(duration).After(end); t = t.Add(step) {
+			if !t.After(now) {
+				continue
+			}
+			slots = append(slots, &ports.TimeSlot{StartsAt: t, EndsAt: t.Add(duration)})
+			if len(slots) > maxEventTimeSlots {
+				return nil, fmt.Errorf("%w: the range produces more than %d time slots", errs.ErrInvalidInput, maxEventTimeSlots)
+			}
+		}
+	}
+
+	if len(slots) == 0 {
+		return nil, fmt.Errorf("%w: the range produces no future time slots", errs.ErrInvalidInput)
+	}
+	return slots, nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	availYes   = ports.AvailabilityAvailable
+	availMaybe = ports.AvailabilityIfNeedBe
+	availNo    = ports.AvailabilityUnavailable
+)
+
+// testSlot -> вариант через hours часов после 20:00 23 октября с отметками участников
+func testSlot(id int, hours int, answers map[int]ports.Availability) *ports.TimeSlot {
+	start := time.Date(2026, 10, 23, 20, 0, 0, 0, time.UTC).Add(time.Duration(hours) * time.Hour)
+	slot := &ports.TimeSlot{ID: id, StartsAt: start, EndsAt: start.Add(2 * time.Hour)}
+	for userID, a := range answers {
+		slot.Responses = append(slot.Responses, &ports.SlotResponse{UserID: userID, Availability: a})
+	}
+	return slot
+}
+
+func TestRankSlotsOrder(t *testing.T) {
+	tests := []struct {
+		name         string
+		slots        []*ports.TimeSlot
+		participants []int
+		mustAttend   []int
+		want         []int
+	}{
+		{
+			name: "must attend beats attendance",
+			slots: []*ports.TimeSlot{
+				testSlot(1, 0, map[int]ports.Availability{1: availYes, 2: availYes, 3: availYes}),
+				testSlot(2, 1, map[int]ports.Availability{1: availYes, 4: availMaybe}),
+			},
+			participants: []int{1, 2, 3, 4},
+			mustAttend:   []int{4},
+			want:         []int{2, 1},
+		},
+		{
+			name: "attendance beats comfort",
+			slots: []*ports.TimeSlot{
+				testSlot(1, 0, map[int]ports.Availability{1: availYes, 2: availYes, 3: availNo}),
+				testSlot(2, 1, map[int]ports.Availability{1: availMaybe, 2: availMaybe, 3: availMaybe}),
+			},
+			participants: []int{1, 2, 3},
+			want:         []int{2, 1},
+		},
+		{
+			name: "more available on equal attendance",
+			slots: []*ports.TimeSlot{
+				testSlot(1, 0, map[int]ports.Availability{1: availMaybe, 2: availYes}),
+				testSlot(2, 1, map[int]ports.Availability{1: availYes, 2: availYes}),
+			},
+			participants: []int{1, 2},
+			want:         []int{2, 1},
+		},
+		{
+			name: "full tie -> earlier start, then ID",
+			slots: []*ports.TimeSlot{
+				testSlot(3, 1, nil),
+				testSlot(2, 0, nil),
+				testSlot(1, 0, nil),
+			},
+			participants: []int{1},
+			want:         []int{1, 2, 3},
+		},
+		{
+			name: "answers of non-participants are ignored",
+			slots: []*ports.TimeSlot{
+				testSlot(1, 0, map[int]ports.Availability{5: availYes, 6: availYes, 1: availNo}),
+				testSlot(2, 1, map[int]ports.Availability{2: availYes}),
+			},
+			participants: []int{1, 2},
+			want:         []int{2, 1},
+		},
+		{
+			name:         "no slots",
+			slots:        nil,
+			participants: []int{1},
+			want:         []int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranked := rankSlots(tt.slots, tt.participants, tt.mustAttend, 1)
+			got := make([]int, len(ranked))
+			for i, r := range ranked {
+				got[i] = r.ID
+				if r.Rank != i+1 {
+					t.Errorf("slot %d rank = %d, want %d", r.ID, r.Rank, i+1)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankSlotsCounts(t *testing.T) {
+	// Участники 1-4 и обязательные 2 и 3. Пользователь 9 отказался, его отметка не считается
+	slot := testSlot(1, 0, map[int]ports.Availability{1: availYes, 2: availMaybe, 3: availNo, 9: availYes})
+	ranked := rankSlots([]*ports.TimeSlot{slot}, []int{1, 2, 3, 4}, []int{2, 3}, 2)
+
+	got := ranked[0]
+	want := RankedSlot{
+		TimeSlot:          slot,
+		Rank:              1,
+		Available:         1,
+		IfNeedBe:          1,
+		Unavailable:       1,
+		NoResponse:        1,
+		Attendance:        2,
+		MustAttendMet:     false,
+		MissingMustAttend: []int{3},
+		MyAvailability:    availMaybe,
+	}
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("ranked slot = %+v, want %+v", *got, want)
+	}
+	if len(slot.Responses) != 3 {
+		t.Errorf("responses = %d, want 3 without the non-participant", len(slot.Responses))
+	}
+}
@@ -3,29 +3,73 @@ package service
 import (
 	"context"
 	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/turysbekovg/movie-planner/internal/errs"
 	"github.com/turysbekovg/movie-planner/internal/ports"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	maxDisplayNameLength = 100
+	maxAvatarURLLength   = 2048
+)
+
+// localePattern -> упрощенный BCP 47: язык и необязательный регион (en, ru-KZ)
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+// ProfileUpdate -> частичное обновление профиля: nil означает "не менять"
+type ProfileUpdate struct {
+	DisplayName *string
+	Timezone    *string
+	Locale      *string
+	AvatarURL   *string
+}
+
 type UserService struct {
-	repo ports.UserRepository
+	repo       ports.UserRepository
+	unverified UnverifiedPolicy
+}
+
+func NewUserService(repo ports.UserRepository, unverified UnverifiedPolicy) *UserService {
+	return &UserService{
+		repo:       repo,
+		unverified: unverified,
+	}
+}
+
+// UnverifiedPolicy -> нужна middleware, которая ограничивает неподтвержденных пользователей
+func (s *UserService) UnverifiedPolicy() UnverifiedPolicy {
+	return s.unverified
 }
 
-func NewUserService(repo ports.UserRepository) *UserService {
-	return &UserService{repo: repo}
+// normalizeEmail -> адреса храним и ищем в нижнем регистре, чтобы A@x.com и a@x.com были одним аккаунтом
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
 
+// RegisterUser -> ErrConflict, если адрес уже занят. Клиенту об этом не сообщаем (см. AuthHandler.Register)
 func (s *UserService) RegisterUser(ctx context.Context, email, password string) (int, error) {
+	// Принимаем только "голый" адрес, без имени вида "Name <a@b.c>"
+	email = normalizeEmail(email)
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return 0, fmt.Errorf("%w: email is not valid", errs.ErrInvalidInput)
+	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	// Те же правила, что при смене и сбросе пароля
+	hashedPassword, err := hashPassword(password)
 	if err != nil {
-		return 0, fmt.Errorf("failed to hash password: %w", err)
+		return 0, err
 	}
 
 	user := &ports.User{
 		Email:        email,
-		PasswordHash: string(hashedPassword),
+		PasswordHash: hashedPassword,
 	}
 
 	// Сохраняем пользователя в базу через репозиторий
@@ -34,7 +78,7 @@ func (s *UserService) RegisterUser(ctx context.Context, email, password string)
 
 func (s *UserService) LoginUser(ctx context.Context, email, password string) (*ports.User, error) {
 	// Ищем юзера
-	user, err := s.repo.GetUserByEmail(ctx, email)
+	user, err := s.repo.GetUserByEmail(ctx, normalizeEmail(email))
 	if err != nil {
 		return nil, fmt.Errorf("invalid credentials")
 	}
@@ -46,6 +90,61 @@ func (s *UserService) LoginUser(ctx context.Context, email, password string) (*p
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
+	if s.unverified == UnverifiedBlock && !user.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+
 	// Если все в порядке, возвращаем пользователя
 	return user, nil
 }
+
+func (s *UserService) GetUserByID(ctx context.Context, id int) (*ports.User, error) {
+	return s.repo.GetUserByID(ctx, id)
+}
+
+func (s *UserService) UpdateProfile(ctx context.Context, id int, upd ProfileUpdate) (*ports.User, error) {
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if upd.DisplayName != nil {
+		name := strings.TrimSpace(*upd.DisplayName)
+		if utf8.RuneCountInString(name) > maxDisplayNameLength {
+			return nil, fmt.Errorf("%w: display_name must be at most %d characters", errs.ErrInvalidInput, maxDisplayNameLength)
+		}
+		user.DisplayName = name
+	}
+	if upd.Timezone != nil {
+		// time.LoadLocation принимает и пустую строку (UTC), и "Local" -> их не пускаем
+		if *upd.Timezone == "" || *upd.Timezone == "Local" {
+			return nil, fmt.Errorf("%w: timezone must be an IANA name like Asia/Almaty", errs.ErrInvalidInput)
+		}
+		if _, err := time.LoadLocation(*upd.Timezone); err != nil {
+			return nil, fmt.Errorf("%w: unknown timezone %q", errs.ErrInvalidInput, *upd.Timezone)
+		}
+		user.Timezone = *upd.Timezone
+	}
+	if upd.Locale != nil {
+		if !localePattern.MatchString(*upd.Locale) {
+			return nil, fmt.Errorf("%w: locale must look like en or ru-KZ", errs.ErrInvalidInput)
+		}
+		user.Locale = *upd.Locale
+	}
+	if upd.AvatarURL != nil {
+		avatar := strings.TrimSpace(*upd.AvatarURL)
+		if avatar != "" {
+			u, err := url.Parse(avatar)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(avatar) > maxAvatarURLLength {
+				return nil, fmt.Errorf("%w: avatar_url must be an http(s) URL", errs.ErrInvalidInput)
+			}
+		}
+		user.AvatarURL = avatar
+	}
+
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func (s *stubUsers) CreateUser(_ context.Context, u *ports.User) (int, error) {
+	if _, ok := s.users[u.Email]; ok {
+		return 0, errs.ErrConflict
+	}
+	u.ID = len(s.users) + 1
+	s.users[u.Email] = u
+	return u.ID, nil
+}
+
+func TestRegisterUserPasswordPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		wantErr  error
+	}{
+		{name: "shortest allowed", password: "12345678"},
+		{name: "longest allowed", password: strings.Repeat("p", 72)},
+		{name: "too short", password: "1234567", wantErr: errs.ErrInvalidInput},
+		// bcrypt молча обрезает все после 72 байт
+		{name: "longer than bcrypt accepts", password: strings.Repeat("p", 73), wantErr: errs.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := &stubUsers{users: map[string]*ports.User{}}
+			s := NewUserService(users, UnverifiedReadOnly)
+
+			_, err := s.RegisterUser(context.Background(), "aigerim@example.com", tt.password)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RegisterUser() error = %v, want %v", err, tt.wantErr)
+				}
+				if len(users.users) != 0 {
+					t.Errorf("user was created with a rejected password")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RegisterUser() error: %v", err)
+			}
+			u := users.users["aigerim@example.com"]
+			if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(tt.password)); err != nil {
+				t.Errorf("stored hash does not match the password: %v", err)
+			}
+		})
+	}
+}
+
+func TestEmailIsNormalized(t *testing.T) {
+	ctx := context.Background()
+	users := &stubUsers{users: map[string]*ports.User{}}
+	s := NewUserService(users, UnverifiedAllow)
+
+	id, err := s.RegisterUser(ctx, "  Aigerim@Example.COM ", "secret-123")
+	if err != nil {
+		t.Fatalf("RegisterUser() error: %v", err)
+	}
+	if _, ok := users.users["aigerim@example.com"]; !ok {
+		t.Fatalf("stored emails = %v, want aigerim@example.com", users.users)
+	}
+
+	// Тот же адрес в другом регистре -> тот же аккаунт
+	if _, err := s.RegisterUser(ctx, "aigerim@example.com", "secret-456"); !errors.Is(err, errs.ErrConflict) {
+		t.Errorf("second RegisterUser() error = %v, want ErrConflict", err)
+	}
+	u, err := s.LoginUser(ctx, "AIGERIM@example.com ", "secret-123")
+	if err != nil {
+		t.Fatalf("LoginUser() error: %v", err)
+	}
+	if u.ID != id {
+		t.Errorf("logged in as %d, want %d", u.ID, id)
+	}
+}
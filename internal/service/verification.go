@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+// UnverifiedPolicy -> что разрешено пользователю с неподтвержденным email
+type UnverifiedPolicy string
+
+const (
+	UnverifiedAllow    UnverifiedPolicy = "allow"     // все как у подтвержденных
+	UnverifiedReadOnly UnverifiedPolicy = "read-only" // вход есть, но изменяющие запросы запрещены
+	UnverifiedBlock    UnverifiedPolicy = "block"     // вход запрещен до подтверждения
+)
+
+// ErrEmailNotVerified -> действие требует подтвержденного email
+var ErrEmailNotVerified = errors.New("email is not verified")
+
+func ParseUnverifiedPolicy(s string) (UnverifiedPolicy, error) {
+	switch p := UnverifiedPolicy(s); p {
+	case UnverifiedAllow, UnverifiedReadOnly, UnverifiedBlock:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown unverified users policy %q (want allow, read-only or block)", s)
+	}
+}
+
+// verificationPayload -> то, что зашито в подписанную ссылку. Email нужен, чтобы
+// ссылка перестала работать, если адрес успеют поменять
+type verificationPayload struct {
+	UserID int    `json:"uid"`
+	Email  string `json:"email"`
+}
+
+// EmailVerificationService -> подписанные ссылки подтверждения email
+type EmailVerificationService struct {
+	users   ports.UserRepository
+	mailer  ports.Mailer
+	signer  *signer
+	baseURL string
+	ttl     time.Duration
+}
+
+func NewEmailVerificationService(users ports.UserRepository, mailer ports.Mailer, secretKey, baseURL string, ttl time.Duration) *EmailVerificationService {
+	return &EmailVerificationService{
+		users:   users,
+		mailer:  mailer,
+		signer:  newSigner(secretKey, "verify-email"),
+		baseURL: strings.TrimRight(baseURL, "/"),
+		ttl:     ttl,
+	}
+}
+
+func (s *EmailVerificationService) SendVerification(ctx context.Context, user *ports.User) error {
+	token, err := s.signer.Sign(verificationPayload{UserID: user.ID, Email: user.Email}, time.Now().Add(s.ttl))
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/auth/verify?token=%s", s.baseURL, url.QueryEscape(token))
+	body := fmt.Sprintf("Welcome to Movie Planner!\n\nPlease confirm your email address by opening this link (valid for %s):\n\n%s\n\n"+
+		"If you did not create an account, just ignore this email.", s.ttl, link)
+
+	return s.mailer.Send(ctx, ports.MailMessage{
+		To:      user.Email,
+		Subject: "Confirm your Movie Planner email",
+		Body:    body,
+	})
+}
+
+// ResendVerification -> как и сброс пароля, не выдает, существует ли адрес
+func (s *EmailVerificationService) ResendVerification(ctx context.Context, email string) error {
+	user, err := s.users.GetUserByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			log.Printf("Verification resend requested for unknown email")
+			return nil
+		}
+		return err
+	}
+	if user.EmailVerified {
+		return nil
+	}
+
+	return s.SendVerification(ctx, user)
+}
+
+// SendAlreadyRegistered -> повторная регистрация на занятый адрес отвечает так же, как новая,
+// а владелец адреса получает письмо вместо ссылки подтверждения
+func (s *EmailVerificationService) SendAlreadyRegistered(ctx context.Context, email string) error {
+	user, err := s.users.GetUserByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		return err
+	}
+
+	body := "Someone tried to create a Movie Planner account with this email address, but you already have one.\n\n" +
+		"If it was you, just log in. If you forgot your password, request a reset on the login page.\n\n" +
+		"If it wasn't you, just ignore this email."
+
+	return s.mailer.Send(ctx, ports.MailMessage{
+		To:      user.Email,
+		Subject: "You already have a Movie Planner account",
+		Body:    body,
+	})
+}
+
+func (s *EmailVerificationService) Verify(ctx context.Context, token string) error {
+	var payload verificationPayload
+	if err := s.signer.Verify(token, time.Now(), &payload); err != nil {
+		return fmt.Errorf("%w: verification link is invalid or expired", errs.ErrInvalidInput)
+	}
+
+	if err := s.users.SetEmailVerified(ctx, payload.UserID, payload.Email); err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			return fmt.Errorf("%w: verification link is invalid or expired", errs.ErrInvalidInput)
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/turysbekovg/movie-planner/internal/errs"
+	"github.com/turysbekovg/movie-planner/internal/ports"
+)
+
+const (
+	minWatchlistPriority     = 1
+	maxWatchlistPriority     = 5
+	defaultWatchlistPriority = 3
+	maxWatchlistNotesLength  = 1000
+)
+
+// WatchlistService -> личный список "хочу посмотреть"
+type WatchlistService struct {
+	repo ports.WatchlistRepository
+}
+
+func NewWatchlistService(repo ports.WatchlistRepository) *WatchlistService {
+	return &WatchlistService{repo: repo}
+}
+
+func (s *WatchlistService) AddToWatchlist(ctx context.Context, userID, movieID int, priority *int, notes string) (*ports.WatchlistItem, error) {
+	item := &ports.WatchlistItem{
+		UserID:   userID,
+		MovieID:  movieID,
+		Priority: defaultWatchlistPriority,
+		Notes:    strings.TrimSpace(notes),
+	}
+	if priority != nil {
+		item.Priority = *priority
+	}
+	if err := validateWatchlistItem(item); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.AddToWatchlist(ctx, item); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetWatchlistItem(ctx, userID, movieID)
+}
+
+// UpdateWatchlistItem -> частичное обновление: nil означает "не менять"
+func (s *WatchlistService) UpdateWatchlistItem(ctx context.Context, userID, movieID int, priority *int, notes *string) (*ports.WatchlistItem, error) {
+	item, err := s.repo.GetWatchlistItem(ctx, userID, movieID)
+	if err != nil {
+		return nil, err
+	}
+
+	if priority != nil {
+		item.Priority = *priority
+	}
+	if notes != nil {
+		item.Notes = strings.TrimSpace(*notes)
+	}
+	if err := validateWatchlistItem(item); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateWatchlistItem(ctx, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *WatchlistService) RemoveFromWatchlist(ctx context.Context, userID, movieID int) error {
+	return s.repo.RemoveFromWatchlist(ctx, userID, movieID)
+}
+
+// GetWatchlist -> sortBy: added | priority | rating, order: asc | desc.
+// По умолчанию новые сверху, для priority и rating -> сначала самые высокие.
+func (s *WatchlistService) GetWatchlist(ctx context.Context, userID int, sortBy, order string) ([]*ports.WatchlistItem, error) {
+	sort := ports.WatchlistSort(sortBy)
+	switch sort {
+	case "":
+		sort = ports.WatchlistSortAdded
+	case ports.WatchlistSortAdded, ports.WatchlistSortPriority, ports.WatchlistSortRating:
+	default:
+		return nil, fmt.Errorf("%w: sort must be one of added, priority, rating", errs.ErrInvalidInput)
+	}
+
+	var desc bool
+	switch order {
+	case "", "desc":
+		desc = true
+	case "asc":
+		desc = false
+	default:
+		return nil, fmt.Errorf("%w: order must be asc or desc", errs.ErrInvalidInput)
+	}
+
+	return s.repo.GetWatchlist(ctx, userID, sort, desc)
+}
+
+func validateWatchlistItem(item *ports.WatchlistItem) error {
+	if item.Priority < minWatchlistPriority || item.Priority > maxWatchlistPriority {
+		return fmt.Errorf("%w: priority must be between %d and %d", errs.ErrInvalidInput, minWatchlistPriority, maxWatchlistPriority)
+	}
+	if utf8.RuneCountInString(item.Notes) > maxWatchlistNotesLength {
+		return fmt.Errorf("%w: notes must be at most %d characters", errs.ErrInvalidInput, maxWatchlistNotesLength)
+	}
+	return nil
+}
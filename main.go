@@ -11,8 +11,11 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/turysbekovg/movie-planner/internal/adapters/cache"
+	"github.com/turysbekovg/movie-planner/internal/adapters/mailer"
+	"github.com/turysbekovg/movie-planner/internal/adapters/notifier"
 	"github.com/turysbekovg/movie-planner/internal/adapters/postgres" // Наш новый адаптер
 	handler "github.com/turysbekovg/movie-planner/internal/handler/http"
+	"github.com/turysbekovg/movie-planner/internal/ports"
 	"github.com/turysbekovg/movie-planner/internal/service"
 
 	"github.com/go-chi/chi/v5"
@@ -85,6 +88,26 @@ func connectToRedis() *redis.Client {
 	return rdb
 }
 
+// newMailer -> MAILER=log (по умолчанию) пишет письма в лог,
+// MAILER=spool складывает их .eml файлами в MAIL_SPOOL_DIR
+func newMailer() ports.Mailer {
+	switch os.Getenv("MAILER") {
+	case "spool":
+		dir := os.Getenv("MAIL_SPOOL_DIR")
+		if dir == "" {
+			dir = "./mail-spool"
+		}
+		m, err := mailer.NewSpoolMailer(dir)
+		if err != nil {
+			log.Fatalf("Unable to create spool mailer: %v", err)
+		}
+		log.Printf("Mail is written to %s", dir)
+		return m
+	default:
+		return mailer.NewLogMailer()
+	}
+}
+
 // @title           Movie Night Planner API
 // @version         1.0
 // @description     This is a sample server for a movie planner application.
@@ -102,6 +125,11 @@ func connectToRedis() *redis.Client {
 // @name Authorization
 // @description Type "Bearer" followed by a space and a JWT token.
 
+// @securityDefinitions.apikey GuestToken
+// @in header
+// @name X-Guest-Token
+// @description Guest session token from POST /guest/join.
+
 // @host      localhost:8080
 // @BasePath  /
 func main() {
@@ -126,25 +154,139 @@ func main() {
 	cacheAdapter := cache.NewRedisCacheAdapter(dbAdapter, redisClient, 5*time.Minute)
 
 	// Сервис для фильмов
-	movieSvc := service.NewMovieService(cacheAdapter)
+	movieSvc := service.NewMovieService(cacheAdapter, dbAdapter, dbAdapter)
 
 	// Обработчик для фильмов
 	movieHandler := handler.NewMovieHandler(movieSvc) // <<< ИЗМЕНЕНИЕ 2: Используем новый псевдоним
 
+	// Что можно неподтвержденным пользователям: allow, read-only (по умолчанию) или block
+	unverifiedPolicy := service.UnverifiedReadOnly
+	if v := os.Getenv("UNVERIFIED_USERS"); v != "" {
+		p, err := service.ParseUnverifiedPolicy(v)
+		if err != nil {
+			log.Fatalf("Invalid UNVERIFIED_USERS: %v", err)
+		}
+		unverifiedPolicy = p
+	}
+
 	// Сервис для пользователей
-	userSvc := service.NewUserService(dbAdapter)
+	userSvc := service.NewUserService(dbAdapter, unverifiedPolicy)
 
 	// Сервис для JWT
 	jwtSecretKey := "my_super_secret_key"
 	jwtTTL := 24 * time.Hour
-	authSvc := service.NewAuthSvc(jwtSecretKey, jwtTTL)
+	tokenRevoker := cache.NewRedisTokenRevoker(redisClient, jwtTTL)
+	authSvc := service.NewAuthSvc(jwtSecretKey, jwtTTL, tokenRevoker)
 
-	// Обработчик для аутентификации
-	authHandler := handler.NewAuthHandler(userSvc, authSvc) // <<< ИЗМЕНЕНИЕ 3: Используем новый псевдоним
+	// Адрес сервиса для ссылок в письмах
+	baseURL := os.Getenv("APP_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+
+	// Почта и подтверждение email
+	mailSender := newMailer()
+	verificationSvc := service.NewEmailVerificationService(dbAdapter, mailSender, jwtSecretKey, baseURL, 48*time.Hour)
+
+	// Смена/сброс пароля
+	passwordSvc := service.NewPasswordService(dbAdapter, dbAdapter, mailSender, authSvc, baseURL, time.Hour)
+	passwordHandler := handler.NewPasswordHandler(passwordSvc)
+
+	// Обработчик для профиля (/me)
+	userHandler := handler.NewUserHandler(userSvc)
+
+	// Сервис рекомендаций (collaborative filtering) и его фоновый пересчет
+	recommendationSvc := service.NewRecommendationService(dbAdapter, dbAdapter, cacheAdapter, dbAdapter)
+	go recommendationSvc.RunSimilarityJob(context.Background(), time.Hour)
+
+	recommendationHandler := handler.NewRecommendationHandler(recommendationSvc)
+
+	// Планировщик "что посмотреть сегодня" для группы
+	plannerSvc := service.NewPlannerService(movieSvc, dbAdapter, dbAdapter, dbAdapter, dbAdapter, dbAdapter, dbAdapter)
+	plannerHandler := handler.NewPlannerHandler(plannerSvc)
+
+	// Предпочтения просмотра (фильтр для списков, рекомендаций и планировщика)
+	preferenceSvc := service.NewPreferenceService(dbAdapter, cacheAdapter)
+	preferenceHandler := handler.NewPreferenceHandler(preferenceSvc)
+
+	// Уведомления: сохраняются в Postgres сразу, а письма ставятся в очередь,
+	// которую отправляет фоновая задача, поэтому запросы не ждут почту
+	notifierAdapter := notifier.NewFanoutNotifier(dbAdapter, dbAdapter, dbAdapter)
+	notificationSvc := service.NewNotificationService(dbAdapter, dbAdapter, mailSender)
+	go notificationSvc.RunEmailJob(context.Background(), 10*time.Second)
+	notificationHandler := handler.NewNotificationHandler(notificationSvc)
+
+	// Доменные события: сервисы публикуют, подписчики (лента активности, уведомления) реагируют
+	eventBus := service.NewEventBus()
+	service.NewActivityRecorder(dbAdapter).Register(eventBus)
+	service.NewNotificationDispatcher(notifierAdapter, dbAdapter, dbAdapter).Register(eventBus)
+
+	// Обновления вечеров и опросов в реальном времени (SSE). Между инстансами -> через Redis pub/sub,
+	// последние 500 сообщений комнаты хранятся сутки для переподключений с Last-Event-ID
+	roomBroker := cache.NewRedisRoomBroker(redisClient, 500, 24*time.Hour)
+	go roomBroker.Run(context.Background())
+	roomSvc := service.NewRoomService(roomBroker, dbAdapter, dbAdapter, dbAdapter)
+	roomSvc.Register(eventBus)
+	streamHandler := handler.NewStreamHandler(roomSvc)
+
+	// Пользовательские оценки и отзывы
+	ratingSvc := service.NewRatingService(dbAdapter, cacheAdapter, eventBus)
+	ratingHandler := handler.NewRatingHandler(ratingSvc)
+
+	// Личный список "хочу посмотреть"
+	watchlistSvc := service.NewWatchlistService(dbAdapter)
+	watchlistHandler := handler.NewWatchlistHandler(watchlistSvc)
+
+	// Дневник просмотров
+	diarySvc := service.NewDiaryService(dbAdapter, eventBus)
+	diaryHandler := handler.NewDiaryHandler(diarySvc)
+
+	// Пользовательские списки фильмов
+	listSvc := service.NewListService(dbAdapter, dbAdapter, eventBus, notifierAdapter)
+	listHandler := handler.NewListHandler(listSvc)
+
+	// Подписки и лента активности
+	socialSvc := service.NewSocialService(dbAdapter, dbAdapter, dbAdapter, notifierAdapter)
+	socialHandler := handler.NewSocialHandler(socialSvc)
+
+	// Киновечера и приглашения
+	eventSvc := service.NewEventService(dbAdapter, dbAdapter, notifierAdapter, eventBus)
+	eventHandler := handler.NewEventHandler(eventSvc)
+
+	// Напоминания о вечерах и выходе фильмов
+	reminderSvc := service.NewReminderService(dbAdapter, dbAdapter, notifierAdapter)
+	reminderSvc.Register(eventBus)
+	go reminderSvc.RunReminderJob(context.Background(), time.Minute)
+
+	// Вечера в календаре: .ics файлом и подписка по секретной ссылке
+	calendarSvc := service.NewCalendarService(dbAdapter, dbAdapter, baseURL)
+	calendarHandler := handler.NewCalendarHandler(calendarSvc)
+
+	// Группы и их общий список фильмов
+	groupSvc := service.NewGroupService(dbAdapter)
+	groupHandler := handler.NewGroupHandler(groupSvc)
+
+	// Опросы по выбору фильма
+	pollSvc := service.NewPollService(dbAdapter, dbAdapter, dbAdapter, eventBus)
+	go pollSvc.RunCloseJob(context.Background(), 30*time.Second)
+	pollHandler := handler.NewPollHandler(pollSvc)
+
+	// Гости без аккаунта по подписанной ссылке на один вечер
+	guestSvc := service.NewGuestService(dbAdapter, dbAdapter, dbAdapter, notifierAdapter, eventBus, jwtSecretKey, baseURL)
+	guestHandler := handler.NewGuestHandler(guestSvc)
+
+	// Обработчик для аутентификации (при регистрации можно сразу забрать активность гостя)
+	authHandler := handler.NewAuthHandler(userSvc, authSvc, verificationSvc, guestSvc) // <<< ИЗМЕНЕНИЕ 3: Используем новый псевдоним
+
+	// Выгрузка данных и удаление аккаунта (через 30 дней после запроса)
+	accountSvc := service.NewAccountService(dbAdapter, dbAdapter, dbAdapter, dbAdapter, dbAdapter, dbAdapter, dbAdapter, dbAdapter, dbAdapter, dbAdapter, dbAdapter, cacheAdapter, notifierAdapter, authSvc, eventSvc, pollSvc, 30*24*time.Hour)
+	go accountSvc.RunDeletionJob(context.Background(), time.Hour)
+	accountHandler := handler.NewAccountHandler(accountSvc)
 
 	// 3. Настройка роутера и запуск сервера
 	r := chi.NewRouter()
-	r.Use(middleware.Logger) // Используем логгер для всех запросов
+	r.Use(handler.RedactAccessToken) // токен из ?access_token= не должен попасть в лог
+	r.Use(middleware.Logger)         // Используем логгер для всех запросов
 
 	// Добавляем маршрут для Swagger UI
 	r.Get("/swagger/*", httpSwagger.Handler(
@@ -155,12 +297,158 @@ func main() {
 	r.Route("/auth", func(r chi.Router) {
 		r.Post("/register", authHandler.Register) // POST /auth/register
 		r.Post("/login", authHandler.Login)       // POST /auth/login
+
+		r.Get("/verify", authHandler.VerifyEmail)                // GET /auth/verify?token=
+		r.Post("/verify/resend", authHandler.ResendVerification) // POST /auth/verify/resend
+
+		r.Post("/password-reset", passwordHandler.RequestPasswordReset)         // POST /auth/password-reset
+		r.Get("/password-reset/confirm", passwordHandler.PasswordResetForm)     // GET /auth/password-reset/confirm?token= (страница из письма)
+		r.Post("/password-reset/confirm", passwordHandler.ConfirmPasswordReset) // POST /auth/password-reset/confirm
 	})
 
 	// Группа ПУБЛИЧНЫХ роутов для фильмов (только чтение)
 	r.Route("/movies", func(r chi.Router) {
-		r.Get("/", movieHandler.GetAllMovies)     // GET /movies
-		r.Get("/{id}", movieHandler.GetMovieByID) // GET /movies/123
+		// Токен необязателен: с ним в ответе появляется in_watchlist, а похожие фильмы проходят предпочтения
+		r.Use(handler.OptionalAuthMiddleware(authSvc))
+
+		r.Get("/", movieHandler.GetAllMovies)                          // GET /movies
+		r.Get("/{id}", movieHandler.GetMovieByID)                      // GET /movies/123
+		r.Get("/{id}/reviews", ratingHandler.GetReviews)               // GET /movies/123/reviews
+		r.Get("/{id}/similar", recommendationHandler.GetSimilarMovies) // GET /movies/123/similar?limit=10
+	})
+
+	// Списки фильмов: чтение доступно без токена, изменение -> только владельцу
+	r.Route("/lists", func(r chi.Router) {
+		r.Use(handler.OptionalAuthMiddleware(authSvc))
+
+		r.Get("/", listHandler.GetLists)                    // GET /lists?user=2
+		r.Get("/popular", listHandler.GetPopularLists)      // GET /lists/popular
+		r.Get("/shared/{token}", listHandler.GetSharedList) // GET /lists/shared/abc
+		r.Get("/{id}", listHandler.GetList)                 // GET /lists/1
+
+		r.Group(func(r chi.Router) {
+			r.Use(handler.AuthMiddleware(authSvc))
+			r.Use(handler.RequireVerifiedEmail(userSvc))
+
+			r.Post("/", listHandler.CreateList)                              // POST /lists
+			r.Patch("/{id}", listHandler.UpdateList)                         // PATCH /lists/1
+			r.Delete("/{id}", listHandler.DeleteList)                        // DELETE /lists/1
+			r.Post("/{id}/share", listHandler.ShareList)                     // POST /lists/1/share
+			r.Post("/{id}/clone", listHandler.CloneList)                     // POST /lists/1/clone
+			r.Post("/{id}/entries", listHandler.AddListEntry)                // POST /lists/1/entries
+			r.Patch("/{id}/entries/{movieID}", listHandler.UpdateListEntry)  // PATCH /lists/1/entries/5
+			r.Delete("/{id}/entries/{movieID}", listHandler.RemoveListEntry) // DELETE /lists/1/entries/5
+		})
+	})
+
+	// Киновечера видят только хост и приглашенные, поэтому все роуты требуют авторизации
+	r.Route("/events", func(r chi.Router) {
+		r.Use(handler.AuthMiddleware(authSvc))
+		r.Use(handler.RequireVerifiedEmail(userSvc))
+
+		r.Get("/", eventHandler.GetMyEvents)                                        // GET /events?from=&to=
+		r.Post("/", eventHandler.CreateEvent)                                       // POST /events
+		r.Get("/{id}", eventHandler.GetEvent)                                       // GET /events/1
+		r.Get("/{id}.ics", calendarHandler.GetEventICS)                             // GET /events/1.ics
+		r.Patch("/{id}", eventHandler.UpdateEvent)                                  // PATCH /events/1
+		r.Delete("/{id}", eventHandler.CancelEvent)                                 // DELETE /events/1
+		r.Put("/{id}/rsvp", eventHandler.RSVPEvent)                                 // PUT /events/1/rsvp
+		r.Post("/{id}/invitations", eventHandler.InviteToEvent)                     // POST /events/1/invitations
+		r.Delete("/{id}/invitations/{userID}", eventHandler.RemoveInvitation)       // DELETE /events/1/invitations/2
+		r.Get("/{id}/occurrences", eventHandler.GetOccurrences)                     // GET /events/1/occurrences?from=&to=
+		r.Patch("/{id}/occurrences/{occurrenceID}", eventHandler.UpdateOccurrence)  // PATCH /events/1/occurrences/20261023T150000Z?scope=this
+		r.Delete("/{id}/occurrences/{occurrenceID}", eventHandler.CancelOccurrence) // DELETE /events/1/occurrences/20261023T150000Z?scope=following
+		r.Get("/{id}/slots", eventHandler.GetTimeSlots)                             // GET /events/1/slots?must_attend=2,3
+		r.Post("/{id}/slots", eventHandler.ProposeTimeSlots)                        // POST /events/1/slots
+		r.Put("/{id}/slots/availability", eventHandler.SetAvailability)             // PUT /events/1/slots/availability
+		r.Delete("/{id}/slots/{slotID}", eventHandler.DeleteTimeSlot)               // DELETE /events/1/slots/4
+		r.Post("/{id}/slots/{slotID}/choose", eventHandler.ChooseTimeSlot)          // POST /events/1/slots/4/choose
+		r.Get("/{id}/polls", pollHandler.GetEventPolls)                             // GET /events/1/polls
+		r.Post("/{id}/polls", pollHandler.CreateEventPoll)                          // POST /events/1/polls
+		r.Post("/{id}/guest-links", guestHandler.CreateGuestLink)                   // POST /events/1/guest-links
+		r.Delete("/{id}/guest-links", guestHandler.RevokeGuestLinks)                // DELETE /events/1/guest-links
+		r.Delete("/{id}/guests/{guestID}", guestHandler.RemoveGuest)                // DELETE /events/1/guests/4
+	})
+
+	// Группу видят только ее участники
+	r.Route("/groups", func(r chi.Router) {
+		r.Use(handler.AuthMiddleware(authSvc))
+		r.Use(handler.RequireVerifiedEmail(userSvc))
+
+		r.Get("/", groupHandler.GetMyGroups)                                              // GET /groups
+		r.Post("/", groupHandler.CreateGroup)                                             // POST /groups
+		r.Post("/join", groupHandler.JoinGroup)                                           // POST /groups/join
+		r.Get("/{id}", groupHandler.GetGroup)                                             // GET /groups/1
+		r.Patch("/{id}", groupHandler.UpdateGroup)                                        // PATCH /groups/1
+		r.Delete("/{id}", groupHandler.DeleteGroup)                                       // DELETE /groups/1
+		r.Post("/{id}/invite-link", groupHandler.RotateInviteLink)                        // POST /groups/1/invite-link
+		r.Delete("/{id}/invite-link", groupHandler.DisableInviteLink)                     // DELETE /groups/1/invite-link
+		r.Put("/{id}/members/{userID}", groupHandler.SetMemberRole)                       // PUT /groups/1/members/2
+		r.Delete("/{id}/members/{userID}", groupHandler.RemoveMember)                     // DELETE /groups/1/members/2
+		r.Get("/{id}/watchlist", groupHandler.GetGroupWatchlist)                          // GET /groups/1/watchlist?unseen=true
+		r.Post("/{id}/watchlist", groupHandler.AddToGroupWatchlist)                       // POST /groups/1/watchlist
+		r.Delete("/{id}/watchlist/{movieID}", groupHandler.RemoveFromGroupWatchlist)      // DELETE /groups/1/watchlist/5
+		r.Put("/{id}/watchlist/{movieID}/vote", groupHandler.UpvoteGroupWatchlistItem)    // PUT /groups/1/watchlist/5/vote
+		r.Delete("/{id}/watchlist/{movieID}/vote", groupHandler.RemoveGroupWatchlistVote) // DELETE /groups/1/watchlist/5/vote
+		r.Get("/{id}/polls", pollHandler.GetGroupPolls)                                   // GET /groups/1/polls
+		r.Post("/{id}/polls", pollHandler.CreateGroupPoll)                                // POST /groups/1/polls
+	})
+
+	r.Route("/polls", func(r chi.Router) {
+		r.Use(handler.AuthMiddleware(authSvc))
+		r.Use(handler.RequireVerifiedEmail(userSvc))
+
+		r.Get("/{id}", pollHandler.GetPoll)                // GET /polls/1
+		r.Put("/{id}/ballot", pollHandler.CastBallot)      // PUT /polls/1/ballot
+		r.Post("/{id}/close", pollHandler.ClosePoll)       // POST /polls/1/close
+		r.Get("/{id}/results", pollHandler.GetPollResults) // GET /polls/1/results
+	})
+
+	// Гости: вход по ссылке без токена, остальное -> с токеном гостя (X-Guest-Token), только для его вечера
+	r.Route("/guest", func(r chi.Router) {
+		r.Get("/invites/{token}", guestHandler.PreviewInvite) // GET /guest/invites/abc
+		r.Post("/join", guestHandler.JoinAsGuest)             // POST /guest/join
+
+		r.Group(func(r chi.Router) {
+			r.Use(handler.GuestMiddleware(guestSvc))
+
+			r.Get("/event", guestHandler.GetGuestEvent)                    // GET /guest/event
+			r.Put("/rsvp", guestHandler.GuestRSVP)                         // PUT /guest/rsvp
+			r.Get("/polls", guestHandler.GetGuestPolls)                    // GET /guest/polls
+			r.Put("/polls/{id}/ballot", guestHandler.GuestCastBallot)      // PUT /guest/polls/1/ballot
+			r.Get("/polls/{id}/results", guestHandler.GetGuestPollResults) // GET /guest/polls/1/results
+		})
+	})
+
+	// Потоки обновлений (SSE) вечеров и опросов. Токен можно передать в ?access_token=
+	r.Group(func(r chi.Router) {
+		r.Use(handler.AccessTokenFromQuery)
+		r.Use(handler.AuthMiddleware(authSvc))
+		r.Use(handler.RequireVerifiedEmail(userSvc))
+
+		r.Get("/events/{id}/stream", streamHandler.StreamEvent) // GET /events/1/stream
+		r.Get("/polls/{id}/stream", streamHandler.StreamPoll)   // GET /polls/1/stream
+	})
+
+	// Подписка на календарь. Календари не умеют передавать токен, поэтому доступ -> по секретной ссылке
+	r.Get("/calendar/{token}.ics", calendarHandler.GetCalendarFeed) // GET /calendar/abc.ics
+
+	// Публичные списки подписчиков и подписок
+	r.Get("/users/{id}/followers", socialHandler.GetFollowers) // GET /users/2/followers
+	r.Get("/users/{id}/following", socialHandler.GetFollowing) // GET /users/2/following
+
+	// Управление аккаунтом доступно и с неподтвержденным email: тот, кто ошибся в адресе,
+	// должен иметь возможность удалить аккаунт, выгрузить данные, сменить пароль и отключить календарь
+	r.Group(func(r chi.Router) {
+		r.Use(handler.AuthMiddleware(authSvc))
+
+		r.Delete("/me", accountHandler.DeleteMe)         // DELETE /me
+		r.Post("/me/restore", accountHandler.RestoreMe)  // POST /me/restore
+		r.Get("/me/export", accountHandler.ExportMyData) // GET /me/export
+
+		r.Post("/me/password", passwordHandler.ChangePassword) // POST /me/password
+
+		r.Delete("/me/calendar-feed", calendarHandler.DeleteCalendarFeed) // DELETE /me/calendar-feed
 	})
 
 	// Группа ЗАЩИЩЕННЫХ роутов для фильмов (создание, изменение, удаление)
@@ -168,11 +456,59 @@ func main() {
 		// Применяем наше AuthMiddleware ко всем роутам внутри этой группы.
 		// Мы передаем в него authSvc, чтобы middleware мог проверять токены.
 		r.Use(handler.AuthMiddleware(authSvc))
+		// Неподтвержденный email ограничивает доступ согласно UNVERIFIED_USERS
+		r.Use(handler.RequireVerifiedEmail(userSvc))
 
 		// Роуты, которые теперь требуют валидный JWT.
 		r.Post("/movies", movieHandler.CreateMovie)        // POST /movies
 		r.Put("/movies/{id}", movieHandler.UpdateMovie)    // PUT /movies/123
 		r.Delete("/movies/{id}", movieHandler.DeleteMovie) // DELETE /movies/123
+
+		r.Post("/movies/{id}/ratings", ratingHandler.RateMovie)      // POST /movies/123/ratings
+		r.Put("/movies/{id}/ratings", ratingHandler.UpdateRating)    // PUT /movies/123/ratings
+		r.Delete("/movies/{id}/ratings", ratingHandler.DeleteRating) // DELETE /movies/123/ratings
+
+		r.Get("/me", userHandler.GetMe)      // GET /me
+		r.Patch("/me", userHandler.UpdateMe) // PATCH /me
+
+		r.Post("/me/calendar-feed", calendarHandler.CreateCalendarFeed) // POST /me/calendar-feed
+
+		r.Post("/me/guest-claim", guestHandler.ClaimGuest) // POST /me/guest-claim
+
+		r.Get("/me/recommendations", recommendationHandler.GetMyRecommendations) // GET /me/recommendations
+
+		r.Get("/me/preferences", preferenceHandler.GetMyPreferences)                       // GET /me/preferences
+		r.Put("/me/preferences", preferenceHandler.UpdateMyPreferences)                    // PUT /me/preferences
+		r.Post("/me/preferences/hidden-movies", preferenceHandler.HideMovie)               // POST /me/preferences/hidden-movies
+		r.Delete("/me/preferences/hidden-movies/{movieID}", preferenceHandler.UnhideMovie) // DELETE /me/preferences/hidden-movies/12
+
+		r.Get("/me/watchlist", watchlistHandler.GetWatchlist)                     // GET /me/watchlist
+		r.Post("/me/watchlist", watchlistHandler.AddToWatchlist)                  // POST /me/watchlist
+		r.Patch("/me/watchlist/{movieID}", watchlistHandler.UpdateWatchlistItem)  // PATCH /me/watchlist/123
+		r.Delete("/me/watchlist/{movieID}", watchlistHandler.RemoveFromWatchlist) // DELETE /me/watchlist/123
+
+		r.Route("/me/diary", func(r chi.Router) {
+			r.Get("/", diaryHandler.GetEntries)                      // GET /me/diary?from=&to=
+			r.Post("/", diaryHandler.CreateEntry)                    // POST /me/diary
+			r.Get("/calendar/{year}", diaryHandler.GetYear)          // GET /me/diary/calendar/2026
+			r.Get("/calendar/{year}/{month}", diaryHandler.GetMonth) // GET /me/diary/calendar/2026/10
+			r.Get("/watch-counts", diaryHandler.GetWatchCounts)      // GET /me/diary/watch-counts
+			r.Get("/{entryID}", diaryHandler.GetEntry)               // GET /me/diary/1
+			r.Put("/{entryID}", diaryHandler.UpdateEntry)            // PUT /me/diary/1
+			r.Delete("/{entryID}", diaryHandler.DeleteEntry)         // DELETE /me/diary/1
+		})
+
+		r.Post("/users/{id}/follow", socialHandler.FollowUser)     // POST /users/2/follow
+		r.Delete("/users/{id}/follow", socialHandler.UnfollowUser) // DELETE /users/2/follow
+		r.Get("/me/feed", socialHandler.GetMyFeed)                 // GET /me/feed?cursor=
+
+		r.Get("/me/notifications", notificationHandler.GetMyNotifications)                        // GET /me/notifications?unread=true
+		r.Post("/me/notifications/read", notificationHandler.MarkNotificationsRead)               // POST /me/notifications/read
+		r.Get("/me/notifications/preferences", notificationHandler.GetNotificationPreferences)    // GET /me/notifications/preferences
+		r.Put("/me/notifications/preferences", notificationHandler.UpdateNotificationPreferences) // PUT /me/notifications/preferences
+
+		r.Post("/plan/tonight", plannerHandler.PlanTonight)   // POST /plan/tonight
+		r.Post("/plan/marathon", plannerHandler.PlanMarathon) // POST /plan/marathon
 	})
 
 	log.Println("Starting server on http://localhost:8080")
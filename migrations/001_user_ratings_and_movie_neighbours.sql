@@ -0,0 +1,22 @@
+-- Оценки фильмов пользователями (одна оценка на пользователя и фильм)
+CREATE TABLE IF NOT EXISTS user_ratings (
+    user_id    INTEGER      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    movie_id   INTEGER      NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    rating     NUMERIC(3,1) NOT NULL CHECK (rating >= 0.5 AND rating <= 10),
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, movie_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_ratings_movie_id ON user_ratings (movie_id);
+
+-- Топ-K похожих фильмов, которые считает item-item collaborative filtering
+CREATE TABLE IF NOT EXISTS movie_neighbours (
+    movie_id     INTEGER          NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    neighbour_id INTEGER          NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    rank         INTEGER          NOT NULL,
+    score        DOUBLE PRECISION NOT NULL,
+    co_ratings   INTEGER          NOT NULL,
+    computed_at  TIMESTAMPTZ      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (movie_id, neighbour_id)
+);
@@ -0,0 +1,3 @@
+-- Длительность фильма в минутах и жанры (через запятую, как recommendations)
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS runtime_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS genres TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,10 @@
+-- Текст отзыва хранится рядом с оценкой (одна оценка/отзыв на пользователя и фильм)
+ALTER TABLE user_ratings ADD COLUMN IF NOT EXISTS review TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_user_ratings_reviews
+    ON user_ratings (movie_id, created_at DESC) WHERE review <> '';
+
+-- Средняя оценка пользователей и число голосов, рядом с редакционным rating.
+-- Пересчитывается в той же транзакции, что и изменение оценки.
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS community_rating NUMERIC(4,2) NOT NULL DEFAULT 0;
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS community_votes INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,11 @@
+-- Личный список "хочу посмотреть"
+CREATE TABLE IF NOT EXISTS watchlist_items (
+    user_id  INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    movie_id INTEGER     NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    priority SMALLINT    NOT NULL DEFAULT 3 CHECK (priority BETWEEN 1 AND 5),
+    notes    TEXT        NOT NULL DEFAULT '',
+    added_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, movie_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_watchlist_items_user_added ON watchlist_items (user_id, added_at DESC);
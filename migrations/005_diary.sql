@@ -0,0 +1,18 @@
+-- Дневник просмотров: когда, где и с какой оценкой смотрели фильм
+CREATE TABLE IF NOT EXISTS diary_entries (
+    id         SERIAL PRIMARY KEY,
+    user_id    INTEGER      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    movie_id   INTEGER      NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    watched_on DATE         NOT NULL,
+    rating     NUMERIC(3,1) CHECK (rating IS NULL OR (rating >= 0.5 AND rating <= 10)),
+    rewatch    BOOLEAN      NOT NULL DEFAULT FALSE,
+    location   TEXT         NOT NULL DEFAULT '',
+    notes      TEXT         NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Календарь (месяц/год) -> выборка по диапазону дат одного пользователя
+CREATE INDEX IF NOT EXISTS idx_diary_entries_user_watched_on ON diary_entries (user_id, watched_on);
+-- Счетчики просмотров конкретного фильма
+CREATE INDEX IF NOT EXISTS idx_diary_entries_user_movie ON diary_entries (user_id, movie_id);
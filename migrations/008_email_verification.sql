@@ -0,0 +1,10 @@
+-- Подтверждение email.
+-- Пользователи, зарегистрированные до появления проверки, считаются подтвержденными,
+-- иначе они потеряют доступ к записи при UNVERIFIED_USERS=read-only.
+-- Колонки добавляются с DEFAULT TRUE, поэтому уже существующие строки получают его при добавлении,
+-- а затем DEFAULT меняется для новых пользователей. При повторном запуске ADD COLUMN ничего не делает
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE users ALTER COLUMN email_verified SET DEFAULT FALSE;
+ALTER TABLE users ALTER COLUMN email_verified_at DROP DEFAULT;
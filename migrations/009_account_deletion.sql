@@ -0,0 +1,7 @@
+-- Удаление аккаунта с отложенным сроком. Пока срок не наступил, пользователь может передумать.
+-- Сами данные удаляются каскадом вместе со строкой users (все ссылки на users объявлены ON DELETE CASCADE)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_requested_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled ON users (deletion_scheduled_at)
+    WHERE deletion_scheduled_at IS NOT NULL;
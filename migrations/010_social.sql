@@ -0,0 +1,27 @@
+-- Подписки между пользователями
+CREATE TABLE IF NOT EXISTS user_follows (
+    follower_id INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    followee_id INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+-- Для списка подписчиков (по первичному ключу ищутся только подписки)
+CREATE INDEX IF NOT EXISTS idx_user_follows_followee ON user_follows (followee_id, created_at DESC);
+
+-- Лента активности. Пишется подписчиком доменных событий, а не обработчиками.
+-- ref_id -> на что ссылается запись (фильм для оценки, ID записи дневника, ID списка)
+CREATE TABLE IF NOT EXISTS activities (
+    id         BIGSERIAL PRIMARY KEY,
+    actor_id   INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    type       TEXT        NOT NULL,
+    movie_id   INTEGER REFERENCES movies (id) ON DELETE CASCADE,
+    ref_id     INTEGER     NOT NULL,
+    payload    JSONB       NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Лента = активность подписок, по убыванию id (id и есть курсор)
+CREATE INDEX IF NOT EXISTS idx_activities_actor ON activities (actor_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_activities_ref ON activities (actor_id, type, ref_id);
@@ -0,0 +1,30 @@
+-- Пользовательские списки фильмов
+CREATE TABLE IF NOT EXISTS user_lists (
+    id             SERIAL PRIMARY KEY,
+    owner_id       INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    title          TEXT        NOT NULL,
+    description    TEXT        NOT NULL DEFAULT '',
+    visibility     TEXT        NOT NULL DEFAULT 'private' CHECK (visibility IN ('private', 'unlisted', 'public')),
+    -- share_token -> доступ к unlisted-списку по ссылке. Владелец видит его всегда, поэтому храним как есть
+    share_token    TEXT UNIQUE,
+    cloned_from_id INTEGER REFERENCES user_lists (id) ON DELETE SET NULL,
+    clone_count    INTEGER     NOT NULL DEFAULT 0,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_lists_owner ON user_lists (owner_id, updated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_user_lists_popular ON user_lists (clone_count DESC, updated_at DESC)
+    WHERE visibility = 'public';
+
+-- Фильмы в списке. position -> 1..N без пропусков, меняется только под блокировкой строки списка
+CREATE TABLE IF NOT EXISTS list_entries (
+    list_id  INTEGER     NOT NULL REFERENCES user_lists (id) ON DELETE CASCADE,
+    movie_id INTEGER     NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    position INTEGER     NOT NULL,
+    note     TEXT        NOT NULL DEFAULT '',
+    added_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (list_id, movie_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_list_entries_position ON list_entries (list_id, position);
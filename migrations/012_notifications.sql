@@ -0,0 +1,23 @@
+-- Уведомления внутри приложения
+CREATE TABLE IF NOT EXISTS notifications (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    type       TEXT        NOT NULL,
+    message    TEXT        NOT NULL,
+    payload    JSONB       NOT NULL DEFAULT '{}',
+    read_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications (user_id, id DESC);
+-- Счетчик непрочитанных
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications (user_id) WHERE read_at IS NULL;
+
+-- Настройки по типам уведомлений. Нет строки -> значения по умолчанию из кода
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    type    TEXT    NOT NULL,
+    in_app  BOOLEAN NOT NULL,
+    email   BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, type)
+);
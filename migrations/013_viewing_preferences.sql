@@ -0,0 +1,15 @@
+-- Язык и возрастной рейтинг фильма, чтобы по ним можно было фильтровать
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS original_language TEXT NOT NULL DEFAULT '';
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS certification TEXT NOT NULL DEFAULT '';
+
+-- Предпочтения пользователя, которые скрывают фильмы из списков, рекомендаций и планировщика
+CREATE TABLE IF NOT EXISTS user_viewing_preferences (
+    user_id               INTEGER          PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    disliked_genres       TEXT[]           NOT NULL DEFAULT '{}',
+    max_runtime_minutes   INTEGER          NOT NULL DEFAULT 0, -- 0 -> без ограничения
+    min_rating            DOUBLE PRECISION NOT NULL DEFAULT 0,
+    preferred_languages   TEXT[]           NOT NULL DEFAULT '{}',
+    hidden_certifications TEXT[]           NOT NULL DEFAULT '{}',
+    hidden_movie_ids      INTEGER[]        NOT NULL DEFAULT '{}',
+    updated_at            TIMESTAMPTZ      NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,30 @@
+-- Киновечера: хост, время начала в своем часовом поясе, место и вместимость
+CREATE TABLE IF NOT EXISTS events (
+    id           SERIAL PRIMARY KEY,
+    host_id      INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    title        TEXT        NOT NULL,
+    description  TEXT        NOT NULL DEFAULT '',
+    starts_at    TIMESTAMPTZ NOT NULL,
+    -- timezone -> IANA-имя (Asia/Almaty), в нем показываем время и считаем "тот же час" при переходе на летнее время
+    timezone     TEXT        NOT NULL DEFAULT 'UTC',
+    location     TEXT        NOT NULL DEFAULT '',
+    capacity     INTEGER     NOT NULL DEFAULT 0 CHECK (capacity >= 0), -- 0 -> без ограничения
+    movie_id     INTEGER     REFERENCES movies (id) ON DELETE SET NULL,
+    status       TEXT        NOT NULL DEFAULT 'scheduled' CHECK (status IN ('scheduled', 'cancelled')),
+    cancelled_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_events_host ON events (host_id, starts_at);
+
+-- Приглашенные на киновечер пользователи
+CREATE TABLE IF NOT EXISTS event_invitations (
+    event_id   INTEGER     NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    user_id    INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    invited_by INTEGER     REFERENCES users (id) ON DELETE SET NULL,
+    invited_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (event_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_invitations_user ON event_invitations (user_id);
@@ -0,0 +1,9 @@
+-- Ответ приглашенного на киновечер. waitlisted -> хотел пойти, но мест не было;
+-- очередь идет по waitlisted_at (FIFO). Все изменения rsvp -> под блокировкой строки вечера
+ALTER TABLE event_invitations ADD COLUMN IF NOT EXISTS rsvp TEXT NOT NULL DEFAULT 'pending'
+    CHECK (rsvp IN ('pending', 'going', 'maybe', 'declined', 'waitlisted'));
+ALTER TABLE event_invitations ADD COLUMN IF NOT EXISTS responded_at TIMESTAMPTZ;
+ALTER TABLE event_invitations ADD COLUMN IF NOT EXISTS waitlisted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_event_invitations_waitlist ON event_invitations (event_id, waitlisted_at, user_id)
+    WHERE rsvp = 'waitlisted';
@@ -0,0 +1,32 @@
+-- Опросы "какой фильм смотрим" для киновечера
+CREATE TABLE IF NOT EXISTS polls (
+    id         SERIAL PRIMARY KEY,
+    event_id   INTEGER     REFERENCES events (id) ON DELETE CASCADE,
+    created_by INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    title      TEXT        NOT NULL,
+    method     TEXT        NOT NULL CHECK (method IN ('plurality', 'approval', 'irv', 'borda')),
+    opens_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    closes_at  TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (closes_at > opens_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_polls_event ON polls (event_id);
+
+-- Кандидаты опроса. position -> порядок в опросе, он же последнее правило при равном счете
+CREATE TABLE IF NOT EXISTS poll_candidates (
+    poll_id  INTEGER NOT NULL REFERENCES polls (id) ON DELETE CASCADE,
+    movie_id INTEGER NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (poll_id, movie_id)
+);
+
+-- Один бюллетень на пользователя. choices -> выбранные фильмы, для irv и borda -> в порядке предпочтения
+CREATE TABLE IF NOT EXISTS poll_ballots (
+    poll_id    INTEGER     NOT NULL REFERENCES polls (id) ON DELETE CASCADE,
+    user_id    INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    choices    INTEGER[]   NOT NULL,
+    cast_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (poll_id, user_id)
+);
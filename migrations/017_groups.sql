@@ -0,0 +1,46 @@
+-- Постоянные группы (семья, компания друзей), которые смотрят кино вместе
+CREATE TABLE IF NOT EXISTS groups (
+    id           SERIAL PRIMARY KEY,
+    name         TEXT        NOT NULL,
+    description  TEXT        NOT NULL DEFAULT '',
+    -- invite_token -> вступление по ссылке. NULL -> ссылка выключена. Админы видят его всегда, поэтому храним как есть
+    invite_token TEXT UNIQUE,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Участники. В каждой группе ровно один owner
+CREATE TABLE IF NOT EXISTS group_members (
+    group_id  INTEGER     NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    user_id   INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role      TEXT        NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'admin', 'member')),
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (group_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_members_user ON group_members (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_group_members_owner ON group_members (group_id) WHERE role = 'owner';
+
+-- Общий список "хотим посмотреть" группы
+CREATE TABLE IF NOT EXISTS group_watchlist_items (
+    group_id INTEGER     NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    movie_id INTEGER     NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    added_by INTEGER     REFERENCES users (id) ON DELETE SET NULL,
+    note     TEXT        NOT NULL DEFAULT '',
+    added_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (group_id, movie_id)
+);
+
+-- Голоса "за" фильм из общего списка, один на участника
+CREATE TABLE IF NOT EXISTS group_watchlist_votes (
+    group_id   INTEGER     NOT NULL,
+    movie_id   INTEGER     NOT NULL,
+    user_id    INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (group_id, movie_id, user_id),
+    FOREIGN KEY (group_id, movie_id) REFERENCES group_watchlist_items (group_id, movie_id) ON DELETE CASCADE
+);
+
+-- Опрос принадлежит либо вечеру, либо группе
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS group_id INTEGER REFERENCES groups (id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_polls_group ON polls (group_id);
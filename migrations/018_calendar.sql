@@ -0,0 +1,10 @@
+-- sequence -> номер версии вечера для iCalendar (SEQUENCE). Растет при каждом изменении и при отмене,
+-- чтобы календари поверх старой версии показали новую
+ALTER TABLE events ADD COLUMN IF NOT EXISTS sequence INTEGER NOT NULL DEFAULT 0;
+
+-- Подписка на календарь: одна ссылка на пользователя. Как и для сброса пароля, храним только хэш токена
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    user_id    INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    token_hash TEXT        NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,20 @@
+-- Варианты времени для вечера ("найти время"): хост предлагает, участники отмечают, когда могут
+CREATE TABLE IF NOT EXISTS event_time_slots (
+    id         SERIAL PRIMARY KEY,
+    event_id   INTEGER     NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    starts_at  TIMESTAMPTZ NOT NULL,
+    ends_at    TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at),
+    UNIQUE (event_id, starts_at, ends_at)
+);
+
+CREATE TABLE IF NOT EXISTS event_slot_availability (
+    slot_id      INTEGER     NOT NULL REFERENCES event_time_slots (id) ON DELETE CASCADE,
+    user_id      INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    availability TEXT        NOT NULL CHECK (availability IN ('available', 'if_need_be', 'unavailable')),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (slot_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_slot_availability_user ON event_slot_availability (user_id);
@@ -0,0 +1,32 @@
+-- guest_link_version -> версия гостевых ссылок вечера. Она зашита в подписанную ссылку,
+-- поэтому увеличение версии разом отключает все выданные ссылки
+ALTER TABLE events ADD COLUMN IF NOT EXISTS guest_link_version INTEGER NOT NULL DEFAULT 0;
+
+-- Гости без аккаунта, пришедшие по ссылке. Права гостя ограничены его вечером.
+-- Как и у приглашенных, waitlisted идет в общую очередь вечера по waitlisted_at.
+-- Храним только хэш токена гостя
+CREATE TABLE IF NOT EXISTS event_guests (
+    id            SERIAL PRIMARY KEY,
+    event_id      INTEGER     NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    display_name  TEXT        NOT NULL,
+    token_hash    TEXT        NOT NULL UNIQUE,
+    rsvp          TEXT        NOT NULL DEFAULT 'pending'
+        CHECK (rsvp IN ('pending', 'going', 'maybe', 'declined', 'waitlisted')),
+    responded_at  TIMESTAMPTZ,
+    waitlisted_at TIMESTAMPTZ,
+    joined_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_guests_event ON event_guests (event_id, joined_at);
+CREATE INDEX IF NOT EXISTS idx_event_guests_waitlist ON event_guests (event_id, waitlisted_at, id)
+    WHERE rsvp = 'waitlisted';
+
+-- Бюллетени гостей. Подсчет идет по бюллетеням пользователей и гостей вместе
+CREATE TABLE IF NOT EXISTS poll_guest_ballots (
+    poll_id    INTEGER     NOT NULL REFERENCES polls (id) ON DELETE CASCADE,
+    guest_id   INTEGER     NOT NULL REFERENCES event_guests (id) ON DELETE CASCADE,
+    choices    INTEGER[]   NOT NULL,
+    cast_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (poll_id, guest_id)
+);
@@ -0,0 +1,26 @@
+-- Повторяющиеся вечера: recurrence -> RRULE серии (FREQ=WEEKLY;BYDAY=FR), пусто -> разовый вечер.
+-- Повторения не хранятся, сервис разворачивает их из правила при запросе.
+-- recurrence_end -> начало последнего повторения (NULL -> серия бесконечна), чтобы отбирать серии по датам
+ALTER TABLE events ADD COLUMN IF NOT EXISTS recurrence TEXT NOT NULL DEFAULT '';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS recurrence_end TIMESTAMPTZ;
+
+-- Измененные и отмененные повторения серии. Повторение определяется исходным началом по правилу,
+-- NULL в полях -> как у всей серии. movie_set -> фильм заменен (movie_id NULL -> без фильма)
+CREATE TABLE IF NOT EXISTS event_occurrences (
+    event_id           INTEGER     NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    original_starts_at TIMESTAMPTZ NOT NULL,
+    cancelled_at       TIMESTAMPTZ,
+    title              TEXT,
+    description        TEXT,
+    location           TEXT,
+    starts_at          TIMESTAMPTZ,
+    movie_set          BOOLEAN     NOT NULL DEFAULT FALSE,
+    movie_id           INTEGER     REFERENCES movies (id) ON DELETE SET NULL,
+    -- sequence -> версия повторения для iCalendar, как events.sequence
+    sequence           INTEGER     NOT NULL DEFAULT 0,
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (event_id, original_starts_at)
+);
+
+-- Для повторений, перенесенных в запрошенный промежуток
+CREATE INDEX IF NOT EXISTS idx_event_occurrences_starts ON event_occurrences (starts_at) WHERE starts_at IS NOT NULL;
@@ -0,0 +1,32 @@
+-- Отложенные напоминания: "вечер начнется через 2 часа", "фильм из списка выходит сегодня".
+-- Фоновая задача забирает наступившие через FOR UPDATE SKIP LOCKED и помечает их sending с арендой до locked_until,
+-- поэтому экземпляров может быть несколько. Если экземпляр упал, после аренды напоминание заберет другой.
+-- target_at -> начало вечера (повторения) или начало дня выхода фильма по времени пользователя,
+-- expires_at -> после него напоминание отменяется без отправки
+CREATE TABLE IF NOT EXISTS reminders (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    kind         TEXT        NOT NULL CHECK (kind IN ('event_starting', 'watchlist_release')),
+    event_id     INTEGER     REFERENCES events (id) ON DELETE CASCADE,
+    movie_id     INTEGER     REFERENCES movies (id) ON DELETE CASCADE,
+    title        TEXT        NOT NULL DEFAULT '',
+    target_at    TIMESTAMPTZ NOT NULL,
+    due_at       TIMESTAMPTZ NOT NULL,
+    expires_at   TIMESTAMPTZ NOT NULL,
+    status       TEXT        NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sending', 'sent', 'cancelled', 'failed')),
+    locked_until TIMESTAMPTZ,
+    attempts     SMALLINT    NOT NULL DEFAULT 0,
+    last_error   TEXT        NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at      TIMESTAMPTZ
+);
+
+-- Одно живое напоминание на пользователя и вечер (фильм) с одним target_at:
+-- после переноса вечера отправленное не мешает запланировать новое, а повторное планирование не дублирует
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reminders_unique ON reminders (kind, user_id, COALESCE(event_id, 0), COALESCE(movie_id, 0), target_at)
+    WHERE status <> 'cancelled';
+
+CREATE INDEX IF NOT EXISTS idx_reminders_due ON reminders (due_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_reminders_lease ON reminders (locked_until) WHERE status = 'sending';
+-- Перенос вечера отменяет и ожидающие, и забранные на отправку напоминания
+CREATE INDEX IF NOT EXISTS idx_reminders_event ON reminders (event_id) WHERE status IN ('pending', 'sending');
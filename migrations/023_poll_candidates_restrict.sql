@@ -0,0 +1,5 @@
+-- Фильм-кандидат опроса больше не удаляется каскадом: иначе у опроса могут пропасть все кандидаты,
+-- а бюллетени начнут ссылаться на несуществующие варианты. Удалить такой фильм -> 409
+ALTER TABLE poll_candidates DROP CONSTRAINT IF EXISTS poll_candidates_movie_id_fkey;
+ALTER TABLE poll_candidates ADD CONSTRAINT poll_candidates_movie_id_fkey
+    FOREIGN KEY (movie_id) REFERENCES movies (id) ON DELETE RESTRICT;
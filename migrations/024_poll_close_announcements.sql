@@ -0,0 +1,17 @@
+-- closed_announced_at -> когда о закрытии опроса сообщили в потоки. Опросы закрываются сами по closes_at,
+-- фоновая задача находит закрытые без отметки и публикует poll.closed с итогами.
+-- Уже закрытые опросы отмечаем только при первом применении, чтобы не разослать старые итоги:
+-- миграции запускаются повторно, а недавно закрытые еще ждут задачу
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'polls' AND column_name = 'closed_announced_at'
+    ) THEN
+        ALTER TABLE polls ADD COLUMN closed_announced_at TIMESTAMPTZ;
+        UPDATE polls SET closed_announced_at = closes_at WHERE closes_at <= CURRENT_TIMESTAMP;
+    END IF;
+END
+$$;
+
+CREATE INDEX IF NOT EXISTS idx_polls_close_pending ON polls (closes_at) WHERE closed_announced_at IS NULL;
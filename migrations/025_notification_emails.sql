@@ -0,0 +1,20 @@
+-- Очередь писем об уведомлениях (outbox). Уведомление внутри приложения сохраняется сразу в запросе,
+-- а письмо, если пользователь включил почту для этого типа, ставится сюда. Отправляет фоновая задача:
+-- забирает письма через FOR UPDATE SKIP LOCKED и помечает их sending с арендой до locked_until,
+-- поэтому экземпляров может быть несколько, а запрос пользователя не ждет почту
+CREATE TABLE IF NOT EXISTS notification_emails (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    type         TEXT        NOT NULL,
+    message      TEXT        NOT NULL,
+    status       TEXT        NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sending', 'sent', 'failed')),
+    due_at       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMPTZ,
+    attempts     SMALLINT    NOT NULL DEFAULT 0,
+    last_error   TEXT        NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at      TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_emails_due ON notification_emails (due_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_notification_emails_lease ON notification_emails (locked_until) WHERE status = 'sending';
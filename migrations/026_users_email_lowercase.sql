@@ -0,0 +1,6 @@
+-- Email храним в нижнем регистре: регистрация, вход, повторное письмо и сброс пароля
+-- ищут адрес уже нормализованным. Старые адреса приводим к нему же, если это не создаст
+-- дубль: аккаунты, отличающиеся только регистром, оставляем как есть
+UPDATE users u SET email = lower(trim(u.email))
+WHERE u.email <> lower(trim(u.email))
+  AND NOT EXISTS (SELECT 1 FROM users o WHERE o.id <> u.id AND lower(trim(o.email)) = lower(trim(u.email)));